@@ -0,0 +1,63 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package v1
+
+import (
+	"k8s.io/utils/ptr"
+)
+
+// SetDefaults fills every unset field of the ClusterPolicySpec that has an effective
+// default with that default, so the stored object reflects what is deployed. The
+// enabled flags take the value their IsEnabled() helper reports for nil. It is
+// idempotent and never overrides a value set by the user.
+func (c *ClusterPolicySpec) SetDefaults() {
+	setEnabledDefault(&c.Driver.Enabled, c.Driver.IsEnabled())
+	setEnabledDefault(&c.Toolkit.Enabled, c.Toolkit.IsEnabled())
+	setEnabledDefault(&c.DevicePlugin.Enabled, c.DevicePlugin.IsEnabled())
+	setEnabledDefault(&c.DCGMExporter.Enabled, c.DCGMExporter.IsEnabled())
+	setEnabledDefault(&c.DCGM.Enabled, c.DCGM.IsEnabled())
+	setEnabledDefault(&c.NodeStatusExporter.Enabled, c.NodeStatusExporter.IsEnabled())
+	setEnabledDefault(&c.GPUFeatureDiscovery.Enabled, c.GPUFeatureDiscovery.IsEnabled())
+	setEnabledDefault(&c.MIGManager.Enabled, c.MIGManager.IsEnabled())
+	setEnabledDefault(&c.PSA.Enabled, c.PSA.IsEnabled())
+	setEnabledDefault(&c.SandboxWorkloads.Enabled, c.SandboxWorkloads.IsEnabled())
+	setEnabledDefault(&c.VFIOManager.Enabled, c.VFIOManager.IsEnabled())
+	setEnabledDefault(&c.SandboxDevicePlugin.Enabled, c.SandboxDevicePlugin.IsEnabled())
+	setEnabledDefault(&c.VGPUManager.Enabled, c.VGPUManager.IsEnabled())
+	setEnabledDefault(&c.VGPUDeviceManager.Enabled, c.VGPUDeviceManager.IsEnabled())
+	setEnabledDefault(&c.CDI.Enabled, c.CDI.IsEnabled())
+	setEnabledDefault(&c.KataManager.Enabled, c.KataManager.IsEnabled())
+	setEnabledDefault(&c.CCManager.Enabled, c.CCManager.IsEnabled())
+	setEnabledDefault(&c.KataSandboxDevicePlugin.Enabled, c.KataSandboxDevicePlugin.IsEnabled())
+	if c.GPUDirectStorage != nil {
+		setEnabledDefault(&c.GPUDirectStorage.Enabled, c.GPUDirectStorage.IsEnabled())
+	}
+	if c.GDRCopy != nil {
+		setEnabledDefault(&c.GDRCopy.Enabled, c.GDRCopy.IsEnabled())
+	}
+
+	if c.HostPaths.KubeletRootDir == "" {
+		c.HostPaths.KubeletRootDir = DefaultKubeletRootDir
+	}
+}
+
+// setEnabledDefault sets an unset enabled flag to its effective value
+func setEnabledDefault(enabled **bool, effective bool) {
+	if *enabled == nil {
+		*enabled = ptr.To(effective)
+	}
+}
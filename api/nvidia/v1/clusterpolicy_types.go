@@ -20,12 +20,15 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	kata_v1alpha1 "github.com/NVIDIA/k8s-kata-manager/api/v1alpha1/config"
 	upgrade_v1alpha1 "github.com/NVIDIA/k8s-operator-libs/api/upgrade/v1alpha1"
 	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/ptr"
 )
 
@@ -36,6 +39,20 @@ const (
 	ClusterPolicyCRDName = "ClusterPolicy"
 	// DefaultDCGMJobMappingDir is the default directory for DCGM Exporter HPC job mapping files
 	DefaultDCGMJobMappingDir = "/var/lib/dcgm-exporter/job-mapping"
+	// DefaultDriverUpgradeCanarySoakDuration is the default soak duration of the canary phase of driver upgrades
+	DefaultDriverUpgradeCanarySoakDuration = 30 * time.Minute
+	// DefaultDriverUpgradeHookTimeout is the default timeout of driver upgrade hooks
+	DefaultDriverUpgradeHookTimeout = 10 * time.Minute
+	// DefaultDriverUpgradeGPUWorkloadWaitTimeout is the default time driver upgrades wait for GPU workloads to finish
+	DefaultDriverUpgradeGPUWorkloadWaitTimeout = time.Hour
+	// DefaultManagedRolloutValidationTimeout is the default time the operator validator has to pass on a node rolled out
+	DefaultManagedRolloutValidationTimeout = 10 * time.Minute
+	// DefaultGPUHealthInterval is the default interval between two checks of the health of the GPU nodes
+	DefaultGPUHealthInterval = time.Minute
+	// DefaultGPUHealthEscalationAfter is the default time a GPU node stays unhealthy before being remediated
+	DefaultGPUHealthEscalationAfter = 10 * time.Minute
+	// DefaultKubeletRootDir is the default path of the kubelet root directory
+	DefaultKubeletRootDir = "/var/lib/kubelet"
 )
 
 // ClusterPolicySpec defines the desired state of ClusterPolicy
@@ -97,6 +114,9 @@ type ClusterPolicySpec struct {
 	HostPaths HostPathsSpec `json:"hostPaths,omitempty"`
 	// KataSandboxDevicePlugin component spec
 	KataSandboxDevicePlugin KataDevicePluginSpec `json:"kataSandboxDevicePlugin,omitempty"`
+	// GPUHealth defines the tainting and remediation of unhealthy GPU nodes
+	// +kubebuilder:validation:Optional
+	GPUHealth *GPUHealthSpec `json:"gpuHealth,omitempty"`
 }
 
 // Runtime defines container runtime type
@@ -325,10 +345,42 @@ type DaemonsetsSpec struct {
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Rolling update configuration for all DaemonSet pods"
 	RollingUpdate *RollingUpdateSpec `json:"rollingUpdate,omitempty"`
 
+	// Optional: ManagedRollout rolls the container toolkit, device plugin, MIG manager and DCGM
+	// DaemonSets out node by node instead of through their update strategy, the rollout only
+	// moving on once the operator validator passed on the nodes rolled out
+	// +kubebuilder:validation:Optional
+	ManagedRollout *ManagedRolloutSpec `json:"managedRollout,omitempty"`
+
 	// Optional: Set pod-level security context for all DaemonSet pods (applies as defaults to all containers)
 	PodSecurityContext *corev1.PodSecurityContext `json:"podSecurityContext,omitempty"`
 }
 
+// ManagedRolloutSpec describes the validation-gated rollout of operand DaemonSets. The pods of
+// a managed DaemonSet are replaced node by node: once the new pod of a node is ready, the
+// operator validator of the node is restarted, and the node is rolled out once the validator
+// passes again. The time the rollout of a node started at is kept in the
+// nvidia.com/<daemonset>.rollout-started-at node annotation until then.
+type ManagedRolloutSpec struct {
+	// Enabled indicates if managed rollouts are enabled
+	// +kubebuilder:validation:Optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// MaxUnavailable is the number of nodes a DaemonSet is rolled out on at a time. Value can be
+	// an absolute number (ex: 2) or a percentage of the nodes running the DaemonSet (ex: 10%).
+	// Absolute number is calculated from percentage by rounding up.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:XIntOrString
+	// +kubebuilder:default=1
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+
+	// ValidationTimeout is how long the operator validator has to pass on a node once the rollout
+	// of the node started. The rollout of the DaemonSet is halted when it does not, until a new
+	// revision of the DaemonSet is rolled out.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="10m"
+	ValidationTimeout *metav1.Duration `json:"validationTimeout,omitempty"`
+}
+
 // Deprecated: InitContainerSpec describes configuration for initContainer image used with all components
 type InitContainerSpec struct {
 	// Repository represents image repository path
@@ -367,6 +419,12 @@ type ValidatorSpec struct {
 	// CUDA validator spec
 	CUDA CUDAValidatorSpec `json:"cuda,omitempty"`
 
+	// P2P validator spec
+	P2P P2PValidatorSpec `json:"p2p,omitempty"`
+
+	// Revalidation spec
+	Revalidation RevalidationSpec `json:"revalidation,omitempty"`
+
 	// VfioPCI validator spec
 	VFIOPCI VFIOPCIValidatorSpec `json:"vfioPCI,omitempty"`
 
@@ -464,6 +522,79 @@ type CUDAValidatorSpec struct {
 	Env []EnvVar `json:"env,omitempty"`
 }
 
+// P2PValidatorSpec defines validator spec for the GPU peer-to-peer validation workload pod
+type P2PValidatorSpec struct {
+	// Enabled indicates if the peer-to-peer access and bandwidth between all GPUs of a node
+	// are validated
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Enable GPU peer-to-peer validation"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// MinBandwidthGBps is the minimum unidirectional peer-to-peer bandwidth, in GB/s, between
+	// each pair of GPUs of a node. 0 disables the bandwidth check.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	MinBandwidthGBps int32 `json:"minBandwidthGBps,omitempty"`
+
+	// MaxLatencyMicroseconds is the maximum peer-to-peer write latency, in microseconds,
+	// between each pair of GPUs of a node. 0 disables the latency check.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	MaxLatencyMicroseconds int32 `json:"maxLatencyMicroseconds,omitempty"`
+
+	// Optional: List of environment variables
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Environment Variables"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:com.tectonic.ui:text"
+	Env []EnvVar `json:"env,omitempty"`
+}
+
+// RevalidationFailureAction is the action taken on a node when a revalidation fails
+type RevalidationFailureAction string
+
+const (
+	// RevalidationFailureActionNone only reports failed revalidations
+	RevalidationFailureActionNone RevalidationFailureAction = "none"
+	// RevalidationFailureActionLabel labels nodes failing a revalidation
+	RevalidationFailureActionLabel RevalidationFailureAction = "label"
+	// RevalidationFailureActionTaint taints nodes failing a revalidation with NoSchedule
+	RevalidationFailureActionTaint RevalidationFailureAction = "taint"
+)
+
+// RevalidationSpec defines the periodic revalidation of components after their initial
+// validation on a node
+type RevalidationSpec struct {
+	// Enabled indicates if a sidecar of the validator periodically revalidates the components
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Enable periodic revalidation"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// IntervalSeconds is the interval between two revalidations
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=30
+	// +kubebuilder:default=300
+	IntervalSeconds int32 `json:"intervalSeconds,omitempty"`
+
+	// Components are the components revalidated: the driver with nvidia-smi, the number of
+	// healthy GPUs advertised by the device plugin and a CUDA sample. All by default.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:items:Enum=driver;plugin;cuda
+	// +listType=set
+	Components []string `json:"components,omitempty"`
+
+	// FailureAction is the action taken on the node when a revalidation fails, in addition
+	// to reporting the failure. The node is labeled or tainted with
+	// nvidia.com/gpu.revalidation.failed until all revalidations pass again.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=none;label;taint
+	// +kubebuilder:default=none
+	FailureAction RevalidationFailureAction `json:"failureAction,omitempty"`
+}
+
 // VFIOPCIValidatorSpec defines validator spec for NVIDIA VFIO-PCI device validation
 type VFIOPCIValidatorSpec struct {
 	// Optional: List of environment variables
@@ -606,7 +737,7 @@ type DriverSpec struct {
 	GPUDirectRDMA *GPUDirectRDMASpec `json:"rdma,omitempty"`
 
 	// Driver auto-upgrade settings
-	UpgradePolicy *upgrade_v1alpha1.DriverUpgradePolicySpec `json:"upgradePolicy,omitempty"`
+	UpgradePolicy *DriverUpgradePolicySpec `json:"upgradePolicy,omitempty"`
 
 	// NVIDIA Driver image repository
 	// +kubebuilder:validation:Optional
@@ -1332,6 +1463,201 @@ type KernelModuleConfigSpec struct {
 	Name string `json:"name,omitempty"`
 }
 
+// DriverUpgradePolicySpec describes policy configuration for automatic upgrades of the driver
+type DriverUpgradePolicySpec struct {
+	upgrade_v1alpha1.DriverUpgradePolicySpec `json:",inline"`
+
+	// Canary upgrades a set of canary nodes first and only upgrades the rest of the
+	// nodes once the driver is validated on all of them
+	// +kubebuilder:validation:Optional
+	Canary *DriverUpgradeCanarySpec `json:"canary,omitempty"`
+
+	// MaintenanceWindows are the recurring windows during which driver upgrades can start
+	// on nodes. Outside of them, upgrades already in progress complete but no new node
+	// upgrade starts. Upgrades can start at any time when no window is set.
+	// +kubebuilder:validation:Optional
+	MaintenanceWindows []DriverUpgradeMaintenanceWindow `json:"maintenanceWindows,omitempty"`
+
+	// Rollback puts the last driver image rolled out to all nodes back when too many
+	// nodes fail the upgrade. The driver image of the spec is patched, along with the
+	// nvidia.com/gpu-driver-rollback annotation recording the rollback. A GitOps tool reverting
+	// the patch puts the failed image back, which is not rolled back again: update the image
+	// in its source instead.
+	// +kubebuilder:validation:Optional
+	Rollback *DriverUpgradeRollbackSpec `json:"rollback,omitempty"`
+
+	// Hooks are run on each node at given stages of its driver upgrade
+	// +kubebuilder:validation:Optional
+	Hooks *DriverUpgradeHooksSpec `json:"hooks,omitempty"`
+
+	// Plan computes the driver upgrade and reports it in status.upgrade.plan instead of
+	// upgrading any node, whether AutoUpgrade is set or not
+	// +kubebuilder:validation:Optional
+	Plan bool `json:"plan,omitempty"`
+
+	// Topology upgrades the nodes one topology domain at a time, e.g. one availability zone
+	// or NVLink domain, a domain finishing its upgrade before the next one starts
+	// +kubebuilder:validation:Optional
+	Topology *DriverUpgradeTopologySpec `json:"topology,omitempty"`
+
+	// GPUWorkloadWait waits for the GPU workloads of a node to finish before the node is
+	// drained, only evicting the other pods of the node in the meantime
+	// +kubebuilder:validation:Optional
+	GPUWorkloadWait *DriverUpgradeGPUWorkloadWaitSpec `json:"gpuWorkloadWait,omitempty"`
+}
+
+// DriverUpgradeCanarySpec describes the canary phase of automatic driver upgrades
+type DriverUpgradeCanarySpec struct {
+	// NodeSelector selects the canary nodes among the nodes being upgraded
+	// +kubebuilder:validation:Optional
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector,omitempty"`
+
+	// Count is the number of canary nodes, picked in name order among the nodes matching
+	// NodeSelector. 0 means all of the matching nodes. Defaults to 1 when NodeSelector is not set.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	Count int `json:"count,omitempty"`
+
+	// SoakDuration is how long the driver has to stay validated on all canary nodes
+	// before the rest of the nodes are upgraded
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="30m"
+	SoakDuration *metav1.Duration `json:"soakDuration,omitempty"`
+}
+
+// DriverUpgradeMaintenanceWindow describes a recurring window during which driver upgrades can start on nodes
+type DriverUpgradeMaintenanceWindow struct {
+	// Schedule is the start of the window as a cron schedule with five fields:
+	// minute, hour, day of month, month and day of week, e.g. "0 2 * * sat"
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+
+	// Duration is how long the window stays open, e.g. "4h"
+	Duration metav1.Duration `json:"duration"`
+
+	// TimeZone is the IANA time zone the schedule is interpreted in, e.g. "Europe/Berlin".
+	// Defaults to UTC.
+	// +kubebuilder:validation:Optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// DriverUpgradeRollbackSpec describes the automatic rollback of failed driver upgrades
+type DriverUpgradeRollbackSpec struct {
+	// MaxFailedNodes is the number of nodes that can fail the upgrade before the driver is
+	// rolled back. Value can be an absolute number (ex: 2) or a percentage of the nodes
+	// running the driver (ex: 10%). Absolute number is calculated from percentage by rounding down.
+	// By default, the driver is rolled back as soon as the upgrade fails on a node.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:XIntOrString
+	// +kubebuilder:default=0
+	MaxFailedNodes *intstr.IntOrString `json:"maxFailedNodes,omitempty"`
+}
+
+// DriverUpgradeHookFailurePolicy is what to do when a driver upgrade hook fails
+type DriverUpgradeHookFailurePolicy string
+
+const (
+	// DriverUpgradeHookFail fails the driver upgrade of the node
+	DriverUpgradeHookFail DriverUpgradeHookFailurePolicy = "Fail"
+	// DriverUpgradeHookIgnore goes on with the driver upgrade of the node
+	DriverUpgradeHookIgnore DriverUpgradeHookFailurePolicy = "Ignore"
+)
+
+// DriverUpgradeHooksSpec describes the hooks run on each node during driver upgrades.
+// The result of a hook is kept in the nvidia.com/gpu-driver-upgrade-hook.<stage> node
+// annotation until the next upgrade of the node. A node whose upgrade failed because of
+// a hook stays in the upgrade-failed state until that annotation is removed.
+type DriverUpgradeHooksSpec struct {
+	// PreDrain is run once the node is cordoned, before waiting for its jobs and draining it
+	// +kubebuilder:validation:Optional
+	PreDrain *DriverUpgradeHook `json:"preDrain,omitempty"`
+
+	// PostValidation is run once the new driver is validated on the node, before the node is
+	// uncordoned. It is not run on nodes that were already cordoned when their upgrade started.
+	// +kubebuilder:validation:Optional
+	PostValidation *DriverUpgradeHook `json:"postValidation,omitempty"`
+}
+
+// DriverUpgradeHook describes a hook run on a node during its driver upgrade, either a Job
+// or a webhook
+// +kubebuilder:validation:XValidation:rule="has(self.job) != has(self.webhook)",message="exactly one of job and webhook must be set"
+type DriverUpgradeHook struct {
+	// Job is the template of the Job run for the node in the operator namespace. The
+	// NODE_NAME and DRIVER_UPGRADE_HOOK_STAGE environment variables are set in its containers.
+	// Its pods run with the nvidia-driver-upgrade-hook service account, which the operator grants
+	// no permissions. The hook succeeds when the Job completes.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Job *batchv1.JobTemplateSpec `json:"job,omitempty"`
+
+	// RunOnNode runs the pods of Job on the node being upgraded, even though it is cordoned
+	// +kubebuilder:validation:Optional
+	RunOnNode bool `json:"runOnNode,omitempty"`
+
+	// Webhook is called for the node. The hook succeeds when it answers with a 2xx status.
+	// +kubebuilder:validation:Optional
+	Webhook *DriverUpgradeWebhook `json:"webhook,omitempty"`
+
+	// Timeout is how long the hook can run before it fails. Webhooks are called in the
+	// background, the node being held back until they answer.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="10m"
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// FailurePolicy is what to do when the hook fails: Fail fails the upgrade of the node,
+	// Ignore goes on with it
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Fail;Ignore
+	// +kubebuilder:default=Fail
+	FailurePolicy DriverUpgradeHookFailurePolicy `json:"failurePolicy,omitempty"`
+}
+
+// DriverUpgradeWebhook describes a webhook called during driver upgrades
+type DriverUpgradeWebhook struct {
+	// URL is sent a POST request with a JSON body holding the stage of the upgrade, the
+	// name of the node and the custom resource its driver is configured by
+	// +kubebuilder:validation:Pattern=`^https?://`
+	URL string `json:"url"`
+}
+
+// DriverUpgradeTopologySpec describes how driver upgrades are batched across topology domains
+type DriverUpgradeTopologySpec struct {
+	// TopologyKey is the node label key whose value is the topology domain of a node, e.g.
+	// topology.kubernetes.io/zone, a rack label or nvidia.com/gpu.clique. Domains are
+	// upgraded in the order of their name, the nodes without the label last.
+	// +kubebuilder:validation:MinLength=1
+	TopologyKey string `json:"topologyKey"`
+
+	// MaxUnavailablePerDomain is the number of nodes of a domain that can be upgraded at a
+	// time, within the limits of maxParallelUpgrades and maxUnavailable
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	MaxUnavailablePerDomain int `json:"maxUnavailablePerDomain,omitempty"`
+}
+
+// DriverUpgradeGPUWorkloadWaitSpec describes how driver upgrades wait for the GPU workloads
+// of nodes to finish. GPU workloads are the running pods using NVIDIA GPUs, requested either
+// as extended resources or through DRA resource claims. The wait starts once the node is
+// cordoned and its pre-drain hook succeeded, and the time it started at is kept in the
+// nvidia.com/gpu-driver-upgrade-gpu-workload-wait.started-at node annotation. When drain is
+// enabled, the pods of the node matching its pod selector that are not waited for are
+// evicted right away.
+type DriverUpgradeGPUWorkloadWaitSpec struct {
+	// OwnerKinds restricts the GPU workloads waited for to the pods controlled by an object of
+	// one of these kinds, e.g. Job, PyTorchJob or MPIJob. All GPU workloads are waited for when empty.
+	// +kubebuilder:validation:Optional
+	OwnerKinds []string `json:"ownerKinds,omitempty"`
+
+	// Timeout is how long to wait for the GPU workloads to finish before the node is drained
+	// anyway. 0 waits until they finish.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="1h"
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
 // RollingUpdateSpec defines configuration for the rolling update of all DaemonSet pods
 type RollingUpdateSpec struct {
 	// +kubebuilder:validation:Optional
@@ -1809,6 +2135,68 @@ type CCManagerSpec struct {
 	HostNetwork *bool `json:"hostNetwork,omitempty"`
 }
 
+// GPUHealthSignal is a signal the health of the GPUs of a node is checked against
+type GPUHealthSignal string
+
+const (
+	// GPUHealthSignalValidation fails when a component of the operator validator failed on the node
+	GPUHealthSignalValidation GPUHealthSignal = "validation"
+	// GPUHealthSignalDCGM fails when dcgm-exporter reports a non zero value of a DCGM health field
+	GPUHealthSignalDCGM GPUHealthSignal = "dcgm"
+	// GPUHealthSignalDevicePlugin fails when the device plugin withdraws GPUs from the allocatable resources
+	GPUHealthSignalDevicePlugin GPUHealthSignal = "devicePlugin"
+)
+
+// GPUHealthSpec defines the tainting of the GPU nodes found unhealthy. A node failing any of
+// the signals is tainted with nvidia.com/gpu.unhealthy:NoSchedule until all the signals pass
+// again. The time the node was found unhealthy at is kept in the
+// nvidia.com/gpu.unhealthy-since node annotation.
+type GPUHealthSpec struct {
+	// Enabled indicates if unhealthy GPU nodes are tainted
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Enable tainting of unhealthy GPU nodes"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Signals are the signals the health of the nodes is checked against: the results of the
+	// operator validator, the DCGM health fields exported by dcgm-exporter and the GPUs the
+	// device plugin withdrew from the allocatable resources. All by default.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:items:Enum=validation;dcgm;devicePlugin
+	// +listType=set
+	Signals []GPUHealthSignal `json:"signals,omitempty"`
+
+	// DCGMFields are the fields exported by dcgm-exporter a GPU is unhealthy with a non zero
+	// value of. DCGM_FI_DEV_ROW_REMAP_FAILURE by default.
+	// +kubebuilder:validation:Optional
+	// +listType=set
+	DCGMFields []string `json:"dcgmFields,omitempty"`
+
+	// Interval is the interval between two checks of the nodes
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="1m"
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// Escalation remediates the nodes which stay unhealthy
+	// +kubebuilder:validation:Optional
+	Escalation *GPUHealthEscalationSpec `json:"escalation,omitempty"`
+}
+
+// GPUHealthEscalationSpec defines the remediation of the GPU nodes which stay unhealthy. Such
+// nodes are cordoned and their driver pod restarted, once. The nodes are uncordoned when they
+// are healthy again.
+type GPUHealthEscalationSpec struct {
+	// Enabled indicates if the nodes which stay unhealthy are remediated
+	// +kubebuilder:validation:Optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// After is how long a node stays unhealthy before being remediated
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="10m"
+	After *metav1.Duration `json:"after,omitempty"`
+}
+
 // VFIOManagerSpec defines the properties for deploying VFIO-PCI manager
 type VFIOManagerSpec struct {
 	// Enabled indicates if deployment of VFIO Manager is enabled
@@ -2017,6 +2405,235 @@ type ClusterPolicyStatus struct {
 	Namespace string `json:"namespace,omitempty"`
 	// Conditions is a list of conditions representing the ClusterPolicy's current state.
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Components lists the observed state of every operand state managed by the ClusterPolicy,
+	// in the order they are reconciled
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Components []ComponentStatus `json:"components,omitempty"`
+	// Upgrade reports the state of automatic driver upgrades
+	// +optional
+	Upgrade *DriverUpgradeStatus `json:"upgrade,omitempty"`
+	// Validation summarizes the reports of the last validations of the components on the
+	// nodes, as published by the node status exporter
+	// +optional
+	Validation *ValidationStatus `json:"validation,omitempty"`
+}
+
+// DriverUpgradeStatus defines the observed state of automatic driver upgrades
+type DriverUpgradeStatus struct {
+	// TargetVersion is the driver version the nodes are upgraded to
+	// +optional
+	TargetVersion string `json:"targetVersion,omitempty"`
+	// Nodes counts the nodes whose driver is managed by automatic upgrades, by upgrade state
+	// +optional
+	Nodes *DriverUpgradeNodeCounts `json:"nodes,omitempty"`
+	// StartTime is when the nodes started being upgraded to the target version. It is only
+	// set while the upgrade is in progress or failed on some nodes.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// EstimatedCompletionTime is when the upgrade is expected to complete, at the rate the
+	// nodes were upgraded at since the start time. It is only set once a node got upgraded.
+	// +optional
+	EstimatedCompletionTime *metav1.Time `json:"estimatedCompletionTime,omitempty"`
+	// FailedNodes lists the nodes the upgrade failed on
+	// +optional
+	FailedNodes []DriverUpgradeFailedNode `json:"failedNodes,omitempty"`
+	// NextMaintenanceWindow is the maintenance window open now or, if none is, the next
+	// one to open. It is only set when maintenance windows are configured.
+	// +optional
+	NextMaintenanceWindow *MaintenanceWindowStatus `json:"nextMaintenanceWindow,omitempty"`
+	// History lists the last driver images rolled out to all nodes, most recent first.
+	// The driver is rolled back to the first of them when an upgrade fails.
+	// +optional
+	History []DriverRevision `json:"history,omitempty"`
+	// LastRollback reports the last automatic rollback of the driver
+	// +optional
+	LastRollback *DriverRollbackStatus `json:"lastRollback,omitempty"`
+	// Plan is the driver upgrade computed in plan mode. It is only set when the upgrade
+	// policy has plan set.
+	// +optional
+	Plan *DriverUpgradePlan `json:"plan,omitempty"`
+}
+
+// DriverUpgradeNodeCounts counts the nodes managed by automatic driver upgrades by upgrade state
+type DriverUpgradeNodeCounts struct {
+	// Total is the number of nodes whose driver is managed by automatic upgrades
+	Total int32 `json:"total"`
+	// Pending is the number of nodes waiting for their upgrade to start
+	Pending int32 `json:"pending"`
+	// CordonRequired is the number of nodes being cordoned
+	CordonRequired int32 `json:"cordonRequired"`
+	// DrainRequired is the number of nodes waiting for their workloads to finish, or
+	// being drained
+	DrainRequired int32 `json:"drainRequired"`
+	// InProgress is the number of nodes whose driver is being restarted, or being
+	// uncordoned
+	InProgress int32 `json:"inProgress"`
+	// ValidationRequired is the number of nodes waiting for the new driver to be validated
+	ValidationRequired int32 `json:"validationRequired"`
+	// Done is the number of nodes running the target driver
+	Done int32 `json:"done"`
+	// Failed is the number of nodes the upgrade failed on
+	Failed int32 `json:"failed"`
+}
+
+// DriverUpgradeFailedNode is a node the driver upgrade failed on
+type DriverUpgradeFailedNode struct {
+	// Name of the node
+	Name string `json:"name"`
+	// Reason describes why the upgrade of the node failed
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// DriverRevision is a driver image, along with the driver configuration it was rolled out with
+type DriverRevision struct {
+	// Repository is the driver image repository
+	// +optional
+	Repository string `json:"repository,omitempty"`
+	// Image is the driver image name
+	// +optional
+	Image string `json:"image,omitempty"`
+	// Version is the driver image tag
+	// +optional
+	Version string `json:"version,omitempty"`
+	// ConfigDigests are the DRIVER_CONFIG_DIGEST values of the driver DaemonSets
+	ConfigDigests []string `json:"configDigests"`
+	// Time is when the driver was rolled out to all nodes
+	Time metav1.Time `json:"time"`
+}
+
+// DriverRollbackStatus describes an automatic rollback of the driver
+type DriverRollbackStatus struct {
+	// Time is when the driver was rolled back
+	Time metav1.Time `json:"time"`
+	// FailedNodes is the number of nodes the upgrade failed on
+	FailedNodes int32 `json:"failedNodes"`
+	// FailedConfigDigests are the DRIVER_CONFIG_DIGEST values of the driver DaemonSets
+	// the upgrade failed with
+	FailedConfigDigests []string `json:"failedConfigDigests"`
+	// Revision is the driver image the driver was rolled back to
+	Revision DriverRevision `json:"revision"`
+}
+
+// DriverUpgradePlan describes the driver upgrade of the nodes running an outdated driver,
+// as it would run if automatic upgrades were enabled and all nodes were available
+type DriverUpgradePlan struct {
+	// Time is when the plan was last changed
+	Time metav1.Time `json:"time"`
+	// NodesToUpgrade is the number of nodes whose driver pod does not run the current
+	// revision of its DaemonSet
+	NodesToUpgrade int32 `json:"nodesToUpgrade"`
+	// BatchSize is the number of nodes upgraded at once, given maxParallelUpgrades and
+	// maxUnavailable. 0 means maxUnavailable lets no node be upgraded.
+	BatchSize int32 `json:"batchSize"`
+	// Batches lists the nodes to upgrade, in the order they would be upgraded in
+	// +optional
+	Batches []DriverUpgradePlanBatch `json:"batches,omitempty"`
+}
+
+// DriverUpgradePlanBatch is a set of nodes upgraded at once
+type DriverUpgradePlanBatch struct {
+	// Canary is set for the batches of canary nodes, which are upgraded and soaked
+	// before the other nodes
+	// +optional
+	Canary bool `json:"canary,omitempty"`
+	// Domain is the topology domain of the nodes of the batch, when upgrades are batched
+	// by topology
+	// +optional
+	Domain string `json:"domain,omitempty"`
+	// Nodes are the nodes of the batch
+	Nodes []DriverUpgradePlanNode `json:"nodes"`
+}
+
+// DriverUpgradePlanNode describes the planned driver upgrade of a node
+type DriverUpgradePlanNode struct {
+	// Name is the name of the node
+	Name string `json:"name"`
+	// CurrentRevision is the controller revision hash of the driver pod of the node
+	// +optional
+	CurrentRevision string `json:"currentRevision,omitempty"`
+	// TargetRevision is the controller revision hash of the driver DaemonSet. It is empty
+	// when the driver pod is not owned by a driver DaemonSet anymore.
+	// +optional
+	TargetRevision string `json:"targetRevision,omitempty"`
+	// Evictions are the GPU pods the upgrade of the node would evict, as namespace/name
+	// +optional
+	Evictions []string `json:"evictions,omitempty"`
+}
+
+// ValidationStatus summarizes the reports of the last validations of the components on the nodes
+type ValidationStatus struct {
+	// DriverVersions lists the driver versions detected on the nodes by the validations
+	// +optional
+	DriverVersions []string `json:"driverVersions,omitempty"`
+	// Components summarizes the validations of each component, e.g. driver or toolkit
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Components []ComponentValidationStatus `json:"components,omitempty"`
+}
+
+// ComponentValidationStatus summarizes the last validations of a component on the nodes
+type ComponentValidationStatus struct {
+	// Name of the validated component
+	Name string `json:"name"`
+	// Passed is the number of nodes the last validation of the component passed on
+	Passed int32 `json:"passed"`
+	// Failed is the number of nodes the last validation of the component failed on
+	Failed int32 `json:"failed"`
+	// Running is the number of nodes the component is being validated on
+	Running int32 `json:"running"`
+	// FailedNodes lists the first nodes, by name, the last validation of the component failed on
+	// +optional
+	FailedNodes []ValidationFailedNode `json:"failedNodes,omitempty"`
+}
+
+// ValidationFailedNode is a node the validation of a component failed on
+type ValidationFailedNode struct {
+	// Name of the node
+	Name string `json:"name"`
+	// ErrorClass classifies the error the validation failed with: CommandFailed, Timeout,
+	// NotFound or ValidationFailed
+	// +optional
+	ErrorClass string `json:"errorClass,omitempty"`
+	// Message is the beginning of the error the validation failed with
+	// +optional
+	Message string `json:"message,omitempty"`
+	// CompletionTime is when the validation failed
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// MaintenanceWindowStatus is a single occurrence of a maintenance window
+type MaintenanceWindowStatus struct {
+	// Start is when the window opens
+	Start metav1.Time `json:"start"`
+	// End is when the window closes
+	End metav1.Time `json:"end"`
+}
+
+// ComponentStatus defines the observed state of a single operand state of the ClusterPolicy
+type ComponentStatus struct {
+	// Name of the state, e.g. state-driver
+	Name string `json:"name"`
+	// Enabled indicates if the state is enabled in the ClusterPolicy
+	Enabled bool `json:"enabled"`
+	// +kubebuilder:validation:Enum=ready;notReady;disabled
+	// State indicates status of the state
+	State State `json:"state"`
+	// DaemonSet is the name of the DaemonSet deployed by the state, if any.
+	// States deploying one DaemonSet per kernel or OS version report the common name prefix.
+	DaemonSet string `json:"daemonSet,omitempty"`
+	// DesiredNumberScheduled is the total number of nodes that should be running the DaemonSet pods
+	DesiredNumberScheduled int32 `json:"desiredNumberScheduled,omitempty"`
+	// NumberReady is the total number of nodes running a ready DaemonSet pod
+	NumberReady int32 `json:"numberReady,omitempty"`
+	// Image is the container image of the DaemonSet
+	Image string `json:"image,omitempty"`
+	// LastTransitionTime is the last time the state changed
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
 }
 
 // +genclient
@@ -2025,6 +2642,11 @@ type ClusterPolicyStatus struct {
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Cluster
 // +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.state`,priority=0
+// +kubebuilder:printcolumn:name="Upgrade Target",type=string,JSONPath=`.status.upgrade.targetVersion`,priority=0
+// +kubebuilder:printcolumn:name="Upgraded",type=integer,JSONPath=`.status.upgrade.nodes.done`,priority=0
+// +kubebuilder:printcolumn:name="Nodes",type=integer,JSONPath=`.status.upgrade.nodes.total`,priority=0
+// +kubebuilder:printcolumn:name="Failed",type=integer,JSONPath=`.status.upgrade.nodes.failed`,priority=0
+// +kubebuilder:printcolumn:name="Upgrade ETA",type=date,JSONPath=`.status.upgrade.estimatedCompletionTime`,priority=1
 // +kubebuilder:printcolumn:name="Age",type=string,JSONPath=`.metadata.creationTimestamp`,priority=0
 
 // ClusterPolicy is the Schema for the clusterpolicies API
@@ -2217,6 +2839,132 @@ func (d *DriverSpec) IsAutoUpgradeEnabled() bool {
 	return d.UpgradePolicy.AutoUpgrade
 }
 
+// GetCanary returns the canary phase settings of driver upgrades, or nil if there is no canary phase
+func (d *DriverSpec) GetCanary() *DriverUpgradeCanarySpec {
+	if d.UpgradePolicy == nil {
+		return nil
+	}
+	return d.UpgradePolicy.Canary
+}
+
+// GetMaintenanceWindows returns the maintenance windows of driver upgrades
+func (d *DriverSpec) GetMaintenanceWindows() []DriverUpgradeMaintenanceWindow {
+	if d.UpgradePolicy == nil {
+		return nil
+	}
+	return d.UpgradePolicy.MaintenanceWindows
+}
+
+// GetRollback returns the automatic rollback settings of driver upgrades, or nil if rollback is disabled
+func (d *DriverSpec) GetRollback() *DriverUpgradeRollbackSpec {
+	if d.UpgradePolicy == nil {
+		return nil
+	}
+	return d.UpgradePolicy.Rollback
+}
+
+// GetHooks returns the hooks run during driver upgrades
+func (d *DriverSpec) GetHooks() *DriverUpgradeHooksSpec {
+	if d.UpgradePolicy == nil {
+		return nil
+	}
+	return d.UpgradePolicy.Hooks
+}
+
+// IsUpgradePlanEnabled returns true if the driver upgrade is only planned
+func (d *DriverSpec) IsUpgradePlanEnabled() bool {
+	return d.UpgradePolicy != nil && d.UpgradePolicy.Plan
+}
+
+// GetUpgradeTopology returns the topology driver upgrades are batched by, or nil if they are not
+func (d *DriverSpec) GetUpgradeTopology() *DriverUpgradeTopologySpec {
+	if d.UpgradePolicy == nil {
+		return nil
+	}
+	return d.UpgradePolicy.Topology
+}
+
+// IsManagedRolloutEnabled returns true if the operand DaemonSets are rolled out by the operator
+func (d *DaemonsetsSpec) IsManagedRolloutEnabled() bool {
+	if d.ManagedRollout == nil || d.ManagedRollout.Enabled == nil {
+		return false
+	}
+	return *d.ManagedRollout.Enabled
+}
+
+// GetMaxUnavailable returns the number of nodes a DaemonSet is rolled out on at a time
+func (r *ManagedRolloutSpec) GetMaxUnavailable() *intstr.IntOrString {
+	if r.MaxUnavailable == nil {
+		maxUnavailable := intstr.FromInt32(1)
+		return &maxUnavailable
+	}
+	return r.MaxUnavailable
+}
+
+// GetValidationTimeout returns how long the operator validator has to pass on a node rolled out
+func (r *ManagedRolloutSpec) GetValidationTimeout() time.Duration {
+	if r.ValidationTimeout == nil {
+		return DefaultManagedRolloutValidationTimeout
+	}
+	return r.ValidationTimeout.Duration
+}
+
+// GetUpgradeGPUWorkloadWait returns how driver upgrades wait for GPU workloads, or nil if they do not
+func (d *DriverSpec) GetUpgradeGPUWorkloadWait() *DriverUpgradeGPUWorkloadWaitSpec {
+	if d.UpgradePolicy == nil {
+		return nil
+	}
+	return d.UpgradePolicy.GPUWorkloadWait
+}
+
+// GetCount returns the number of canary nodes, 0 meaning all of the nodes matching the node selector
+func (c *DriverUpgradeCanarySpec) GetCount() int {
+	if c.Count == 0 && c.NodeSelector == nil {
+		return 1
+	}
+	return c.Count
+}
+
+// GetSoakDuration returns how long the driver has to stay validated on the canary nodes
+func (c *DriverUpgradeCanarySpec) GetSoakDuration() time.Duration {
+	if c.SoakDuration == nil {
+		return DefaultDriverUpgradeCanarySoakDuration
+	}
+	return c.SoakDuration.Duration
+}
+
+// GetMaxUnavailablePerDomain returns the number of nodes of a topology domain that can be upgraded at a time
+func (t *DriverUpgradeTopologySpec) GetMaxUnavailablePerDomain() int {
+	if t.MaxUnavailablePerDomain <= 0 {
+		return 1
+	}
+	return t.MaxUnavailablePerDomain
+}
+
+// GetTimeout returns how long to wait for the GPU workloads of a node to finish, 0 meaning no limit
+func (w *DriverUpgradeGPUWorkloadWaitSpec) GetTimeout() time.Duration {
+	if w.Timeout == nil {
+		return DefaultDriverUpgradeGPUWorkloadWaitTimeout
+	}
+	return w.Timeout.Duration
+}
+
+// GetTimeout returns how long the driver upgrade hook can run before it fails
+func (h *DriverUpgradeHook) GetTimeout() time.Duration {
+	if h.Timeout == nil {
+		return DefaultDriverUpgradeHookTimeout
+	}
+	return h.Timeout.Duration
+}
+
+// GetFailurePolicy returns what to do when the driver upgrade hook fails
+func (h *DriverUpgradeHook) GetFailurePolicy() DriverUpgradeHookFailurePolicy {
+	if h.FailurePolicy == "" {
+		return DriverUpgradeHookFail
+	}
+	return h.FailurePolicy
+}
+
 // IsEnabled returns true if device-plugin is enabled(default) through gpu-operator
 func (p *DevicePluginSpec) IsEnabled() bool {
 	if p.Enabled == nil {
@@ -2502,6 +3250,94 @@ func (c *CCManagerSpec) IsEnabled() bool {
 	return *c.Enabled
 }
 
+// IsEnabled returns true if the peer-to-peer validation of the GPUs of the nodes is enabled
+func (p *P2PValidatorSpec) IsEnabled() bool {
+	if p.Enabled == nil {
+		return false
+	}
+	return *p.Enabled
+}
+
+// IsEnabled returns true if the periodic revalidation of the components is enabled
+func (r *RevalidationSpec) IsEnabled() bool {
+	if r.Enabled == nil {
+		return false
+	}
+	return *r.Enabled
+}
+
+// GetIntervalSeconds returns the interval between two revalidations
+func (r *RevalidationSpec) GetIntervalSeconds() int32 {
+	if r.IntervalSeconds <= 0 {
+		return 300
+	}
+	return r.IntervalSeconds
+}
+
+// GetComponents returns the components to revalidate
+func (r *RevalidationSpec) GetComponents() []string {
+	if len(r.Components) == 0 {
+		return []string{"driver", "plugin", "cuda"}
+	}
+	return r.Components
+}
+
+// GetFailureAction returns the action taken on nodes failing a revalidation
+func (r *RevalidationSpec) GetFailureAction() RevalidationFailureAction {
+	if r.FailureAction == "" {
+		return RevalidationFailureActionNone
+	}
+	return r.FailureAction
+}
+
+// IsEnabled returns true if unhealthy GPU nodes are tainted
+func (g *GPUHealthSpec) IsEnabled() bool {
+	if g == nil || g.Enabled == nil {
+		return false
+	}
+	return *g.Enabled
+}
+
+// GetSignals returns the signals the health of the nodes is checked against
+func (g *GPUHealthSpec) GetSignals() []GPUHealthSignal {
+	if len(g.Signals) == 0 {
+		return []GPUHealthSignal{GPUHealthSignalValidation, GPUHealthSignalDCGM, GPUHealthSignalDevicePlugin}
+	}
+	return g.Signals
+}
+
+// GetDCGMFields returns the DCGM fields a GPU is unhealthy with a non zero value of
+func (g *GPUHealthSpec) GetDCGMFields() []string {
+	if len(g.DCGMFields) == 0 {
+		return []string{"DCGM_FI_DEV_ROW_REMAP_FAILURE"}
+	}
+	return g.DCGMFields
+}
+
+// GetInterval returns the interval between two checks of the nodes
+func (g *GPUHealthSpec) GetInterval() time.Duration {
+	if g.Interval == nil || g.Interval.Duration <= 0 {
+		return DefaultGPUHealthInterval
+	}
+	return g.Interval.Duration
+}
+
+// IsEscalationEnabled returns true if the nodes which stay unhealthy are remediated
+func (g *GPUHealthSpec) IsEscalationEnabled() bool {
+	if g.Escalation == nil || g.Escalation.Enabled == nil {
+		return false
+	}
+	return *g.Escalation.Enabled
+}
+
+// GetEscalationAfter returns how long a node stays unhealthy before being remediated
+func (g *GPUHealthSpec) GetEscalationAfter() time.Duration {
+	if g.Escalation == nil || g.Escalation.After == nil {
+		return DefaultGPUHealthEscalationAfter
+	}
+	return g.Escalation.After.Duration
+}
+
 // +kubebuilder:object:generate=false
 type ConfigWithName interface {
 	GetName() string
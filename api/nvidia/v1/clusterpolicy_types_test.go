@@ -21,6 +21,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
 )
 
 func TestImagePath(t *testing.T) {
@@ -72,3 +73,27 @@ func TestImagePath(t *testing.T) {
 		assert.ErrorContains(t, err, "invalid type to construct image path")
 	})
 }
+
+func TestClusterPolicySpecSetDefaults(t *testing.T) {
+	spec := ClusterPolicySpec{
+		Toolkit:   ToolkitSpec{Enabled: ptr.To(false)},
+		GDRCopy:   &GDRCopySpec{},
+		HostPaths: HostPathsSpec{KubeletRootDir: "/data/kubelet"},
+	}
+	spec.SetDefaults()
+
+	assert.Equal(t, ptr.To(true), spec.Driver.Enabled)
+	assert.Equal(t, ptr.To(false), spec.Toolkit.Enabled, "user value must be kept")
+	assert.Equal(t, ptr.To(true), spec.DevicePlugin.Enabled)
+	assert.Equal(t, ptr.To(true), spec.DCGM.Enabled)
+	assert.Equal(t, ptr.To(false), spec.NodeStatusExporter.Enabled)
+	assert.Equal(t, ptr.To(true), spec.CDI.Enabled)
+	assert.Equal(t, ptr.To(false), spec.VGPUManager.Enabled)
+	assert.Equal(t, ptr.To(false), spec.GDRCopy.Enabled)
+	assert.Nil(t, spec.GPUDirectStorage, "an omitted optional block stays omitted")
+	assert.Equal(t, "/data/kubelet", spec.HostPaths.KubeletRootDir)
+
+	spec = ClusterPolicySpec{}
+	spec.SetDefaults()
+	assert.Equal(t, DefaultKubeletRootDir, spec.HostPaths.KubeletRootDir)
+}
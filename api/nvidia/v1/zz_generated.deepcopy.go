@@ -22,11 +22,12 @@ package v1
 
 import (
 	"github.com/NVIDIA/k8s-kata-manager/api/v1alpha1/config"
-	"github.com/NVIDIA/k8s-operator-libs/api/upgrade/v1alpha1"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -220,6 +221,11 @@ func (in *ClusterPolicySpec) DeepCopyInto(out *ClusterPolicySpec) {
 	in.CCManager.DeepCopyInto(&out.CCManager)
 	out.HostPaths = in.HostPaths
 	in.KataSandboxDevicePlugin.DeepCopyInto(&out.KataSandboxDevicePlugin)
+	if in.GPUHealth != nil {
+		in, out := &in.GPUHealth, &out.GPUHealth
+		*out = new(GPUHealthSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterPolicySpec.
@@ -242,6 +248,23 @@ func (in *ClusterPolicyStatus) DeepCopyInto(out *ClusterPolicyStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]ComponentStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(DriverUpgradeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Validation != nil {
+		in, out := &in.Validation, &out.Validation
+		*out = new(ValidationStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterPolicyStatus.
@@ -294,6 +317,44 @@ func (in *ComponentCommonSpec) DeepCopy() *ComponentCommonSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentStatus) DeepCopyInto(out *ComponentStatus) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentStatus.
+func (in *ComponentStatus) DeepCopy() *ComponentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentValidationStatus) DeepCopyInto(out *ComponentValidationStatus) {
+	*out = *in
+	if in.FailedNodes != nil {
+		in, out := &in.FailedNodes, &out.FailedNodes
+		*out = make([]ValidationFailedNode, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentValidationStatus.
+func (in *ComponentValidationStatus) DeepCopy() *ComponentValidationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentValidationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ContainerProbeSpec) DeepCopyInto(out *ContainerProbeSpec) {
 	*out = *in
@@ -530,6 +591,11 @@ func (in *DaemonsetsSpec) DeepCopyInto(out *DaemonsetsSpec) {
 		*out = new(RollingUpdateSpec)
 		**out = **in
 	}
+	if in.ManagedRollout != nil {
+		in, out := &in.ManagedRollout, &out.ManagedRollout
+		*out = new(ManagedRolloutSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.PodSecurityContext != nil {
 		in, out := &in.PodSecurityContext, &out.PodSecurityContext
 		*out = new(corev1.PodSecurityContext)
@@ -692,6 +758,49 @@ func (in *DriverRepoConfigSpec) DeepCopy() *DriverRepoConfigSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriverRevision) DeepCopyInto(out *DriverRevision) {
+	*out = *in
+	if in.ConfigDigests != nil {
+		in, out := &in.ConfigDigests, &out.ConfigDigests
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriverRevision.
+func (in *DriverRevision) DeepCopy() *DriverRevision {
+	if in == nil {
+		return nil
+	}
+	out := new(DriverRevision)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriverRollbackStatus) DeepCopyInto(out *DriverRollbackStatus) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.FailedConfigDigests != nil {
+		in, out := &in.FailedConfigDigests, &out.FailedConfigDigests
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Revision.DeepCopyInto(&out.Revision)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriverRollbackStatus.
+func (in *DriverRollbackStatus) DeepCopy() *DriverRollbackStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DriverRollbackStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DriverSpec) DeepCopyInto(out *DriverSpec) {
 	*out = *in
@@ -737,7 +846,7 @@ func (in *DriverSpec) DeepCopyInto(out *DriverSpec) {
 	}
 	if in.UpgradePolicy != nil {
 		in, out := &in.UpgradePolicy, &out.UpgradePolicy
-		*out = new(v1alpha1.DriverUpgradePolicySpec)
+		*out = new(DriverUpgradePolicySpec)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.ImagePullSecrets != nil {
@@ -803,6 +912,373 @@ func (in *DriverSpec) DeepCopy() *DriverSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriverUpgradeCanarySpec) DeepCopyInto(out *DriverUpgradeCanarySpec) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SoakDuration != nil {
+		in, out := &in.SoakDuration, &out.SoakDuration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriverUpgradeCanarySpec.
+func (in *DriverUpgradeCanarySpec) DeepCopy() *DriverUpgradeCanarySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DriverUpgradeCanarySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriverUpgradeFailedNode) DeepCopyInto(out *DriverUpgradeFailedNode) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriverUpgradeFailedNode.
+func (in *DriverUpgradeFailedNode) DeepCopy() *DriverUpgradeFailedNode {
+	if in == nil {
+		return nil
+	}
+	out := new(DriverUpgradeFailedNode)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriverUpgradeGPUWorkloadWaitSpec) DeepCopyInto(out *DriverUpgradeGPUWorkloadWaitSpec) {
+	*out = *in
+	if in.OwnerKinds != nil {
+		in, out := &in.OwnerKinds, &out.OwnerKinds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriverUpgradeGPUWorkloadWaitSpec.
+func (in *DriverUpgradeGPUWorkloadWaitSpec) DeepCopy() *DriverUpgradeGPUWorkloadWaitSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DriverUpgradeGPUWorkloadWaitSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriverUpgradeHook) DeepCopyInto(out *DriverUpgradeHook) {
+	*out = *in
+	if in.Job != nil {
+		in, out := &in.Job, &out.Job
+		*out = new(batchv1.JobTemplateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(DriverUpgradeWebhook)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriverUpgradeHook.
+func (in *DriverUpgradeHook) DeepCopy() *DriverUpgradeHook {
+	if in == nil {
+		return nil
+	}
+	out := new(DriverUpgradeHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriverUpgradeHooksSpec) DeepCopyInto(out *DriverUpgradeHooksSpec) {
+	*out = *in
+	if in.PreDrain != nil {
+		in, out := &in.PreDrain, &out.PreDrain
+		*out = new(DriverUpgradeHook)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PostValidation != nil {
+		in, out := &in.PostValidation, &out.PostValidation
+		*out = new(DriverUpgradeHook)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriverUpgradeHooksSpec.
+func (in *DriverUpgradeHooksSpec) DeepCopy() *DriverUpgradeHooksSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DriverUpgradeHooksSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriverUpgradeMaintenanceWindow) DeepCopyInto(out *DriverUpgradeMaintenanceWindow) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriverUpgradeMaintenanceWindow.
+func (in *DriverUpgradeMaintenanceWindow) DeepCopy() *DriverUpgradeMaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(DriverUpgradeMaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriverUpgradeNodeCounts) DeepCopyInto(out *DriverUpgradeNodeCounts) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriverUpgradeNodeCounts.
+func (in *DriverUpgradeNodeCounts) DeepCopy() *DriverUpgradeNodeCounts {
+	if in == nil {
+		return nil
+	}
+	out := new(DriverUpgradeNodeCounts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriverUpgradePlan) DeepCopyInto(out *DriverUpgradePlan) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.Batches != nil {
+		in, out := &in.Batches, &out.Batches
+		*out = make([]DriverUpgradePlanBatch, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriverUpgradePlan.
+func (in *DriverUpgradePlan) DeepCopy() *DriverUpgradePlan {
+	if in == nil {
+		return nil
+	}
+	out := new(DriverUpgradePlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriverUpgradePlanBatch) DeepCopyInto(out *DriverUpgradePlanBatch) {
+	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]DriverUpgradePlanNode, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriverUpgradePlanBatch.
+func (in *DriverUpgradePlanBatch) DeepCopy() *DriverUpgradePlanBatch {
+	if in == nil {
+		return nil
+	}
+	out := new(DriverUpgradePlanBatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriverUpgradePlanNode) DeepCopyInto(out *DriverUpgradePlanNode) {
+	*out = *in
+	if in.Evictions != nil {
+		in, out := &in.Evictions, &out.Evictions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriverUpgradePlanNode.
+func (in *DriverUpgradePlanNode) DeepCopy() *DriverUpgradePlanNode {
+	if in == nil {
+		return nil
+	}
+	out := new(DriverUpgradePlanNode)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriverUpgradePolicySpec) DeepCopyInto(out *DriverUpgradePolicySpec) {
+	*out = *in
+	in.DriverUpgradePolicySpec.DeepCopyInto(&out.DriverUpgradePolicySpec)
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(DriverUpgradeCanarySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]DriverUpgradeMaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+	if in.Rollback != nil {
+		in, out := &in.Rollback, &out.Rollback
+		*out = new(DriverUpgradeRollbackSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Hooks != nil {
+		in, out := &in.Hooks, &out.Hooks
+		*out = new(DriverUpgradeHooksSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Topology != nil {
+		in, out := &in.Topology, &out.Topology
+		*out = new(DriverUpgradeTopologySpec)
+		**out = **in
+	}
+	if in.GPUWorkloadWait != nil {
+		in, out := &in.GPUWorkloadWait, &out.GPUWorkloadWait
+		*out = new(DriverUpgradeGPUWorkloadWaitSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriverUpgradePolicySpec.
+func (in *DriverUpgradePolicySpec) DeepCopy() *DriverUpgradePolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DriverUpgradePolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriverUpgradeRollbackSpec) DeepCopyInto(out *DriverUpgradeRollbackSpec) {
+	*out = *in
+	if in.MaxFailedNodes != nil {
+		in, out := &in.MaxFailedNodes, &out.MaxFailedNodes
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriverUpgradeRollbackSpec.
+func (in *DriverUpgradeRollbackSpec) DeepCopy() *DriverUpgradeRollbackSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DriverUpgradeRollbackSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriverUpgradeStatus) DeepCopyInto(out *DriverUpgradeStatus) {
+	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = new(DriverUpgradeNodeCounts)
+		**out = **in
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.EstimatedCompletionTime != nil {
+		in, out := &in.EstimatedCompletionTime, &out.EstimatedCompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.FailedNodes != nil {
+		in, out := &in.FailedNodes, &out.FailedNodes
+		*out = make([]DriverUpgradeFailedNode, len(*in))
+		copy(*out, *in)
+	}
+	if in.NextMaintenanceWindow != nil {
+		in, out := &in.NextMaintenanceWindow, &out.NextMaintenanceWindow
+		*out = new(MaintenanceWindowStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]DriverRevision, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastRollback != nil {
+		in, out := &in.LastRollback, &out.LastRollback
+		*out = new(DriverRollbackStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Plan != nil {
+		in, out := &in.Plan, &out.Plan
+		*out = new(DriverUpgradePlan)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriverUpgradeStatus.
+func (in *DriverUpgradeStatus) DeepCopy() *DriverUpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DriverUpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriverUpgradeTopologySpec) DeepCopyInto(out *DriverUpgradeTopologySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriverUpgradeTopologySpec.
+func (in *DriverUpgradeTopologySpec) DeepCopy() *DriverUpgradeTopologySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DriverUpgradeTopologySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriverUpgradeWebhook) DeepCopyInto(out *DriverUpgradeWebhook) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriverUpgradeWebhook.
+func (in *DriverUpgradeWebhook) DeepCopy() *DriverUpgradeWebhook {
+	if in == nil {
+		return nil
+	}
+	out := new(DriverUpgradeWebhook)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DriverValidatorSpec) DeepCopyInto(out *DriverValidatorSpec) {
 	*out = *in
@@ -978,6 +1454,71 @@ func (in *GPUFeatureDiscoverySpec) DeepCopy() *GPUFeatureDiscoverySpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GPUHealthEscalationSpec) DeepCopyInto(out *GPUHealthEscalationSpec) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.After != nil {
+		in, out := &in.After, &out.After
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUHealthEscalationSpec.
+func (in *GPUHealthEscalationSpec) DeepCopy() *GPUHealthEscalationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GPUHealthEscalationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GPUHealthSpec) DeepCopyInto(out *GPUHealthSpec) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Signals != nil {
+		in, out := &in.Signals, &out.Signals
+		*out = make([]GPUHealthSignal, len(*in))
+		copy(*out, *in)
+	}
+	if in.DCGMFields != nil {
+		in, out := &in.DCGMFields, &out.DCGMFields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Escalation != nil {
+		in, out := &in.Escalation, &out.Escalation
+		*out = new(GPUHealthEscalationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUHealthSpec.
+func (in *GPUHealthSpec) DeepCopy() *GPUHealthSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GPUHealthSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HostPathsSpec) DeepCopyInto(out *HostPathsSpec) {
 	*out = *in
@@ -1230,6 +1771,53 @@ func (in *MPSConfig) DeepCopy() *MPSConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindowStatus) DeepCopyInto(out *MaintenanceWindowStatus) {
+	*out = *in
+	in.Start.DeepCopyInto(&out.Start)
+	in.End.DeepCopyInto(&out.End)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindowStatus.
+func (in *MaintenanceWindowStatus) DeepCopy() *MaintenanceWindowStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindowStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedRolloutSpec) DeepCopyInto(out *ManagedRolloutSpec) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.ValidationTimeout != nil {
+		in, out := &in.ValidationTimeout, &out.ValidationTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedRolloutSpec.
+func (in *ManagedRolloutSpec) DeepCopy() *ManagedRolloutSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedRolloutSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeStatusExporterSpec) DeepCopyInto(out *NodeStatusExporterSpec) {
 	*out = *in
@@ -1331,6 +1919,31 @@ func (in *OperatorSpec) DeepCopy() *OperatorSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *P2PValidatorSpec) DeepCopyInto(out *P2PValidatorSpec) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]EnvVar, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new P2PValidatorSpec.
+func (in *P2PValidatorSpec) DeepCopy() *P2PValidatorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(P2PValidatorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PSASpec) DeepCopyInto(out *PSASpec) {
 	*out = *in
@@ -1420,6 +2033,31 @@ func (in *ResourceRequirements) DeepCopy() *ResourceRequirements {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RevalidationSpec) DeepCopyInto(out *RevalidationSpec) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RevalidationSpec.
+func (in *RevalidationSpec) DeepCopy() *RevalidationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RevalidationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RollingUpdateSpec) DeepCopyInto(out *RollingUpdateSpec) {
 	*out = *in
@@ -1830,6 +2468,52 @@ func (in *VGPUManagerValidatorSpec) DeepCopy() *VGPUManagerValidatorSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ValidationFailedNode) DeepCopyInto(out *ValidationFailedNode) {
+	*out = *in
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValidationFailedNode.
+func (in *ValidationFailedNode) DeepCopy() *ValidationFailedNode {
+	if in == nil {
+		return nil
+	}
+	out := new(ValidationFailedNode)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ValidationStatus) DeepCopyInto(out *ValidationStatus) {
+	*out = *in
+	if in.DriverVersions != nil {
+		in, out := &in.DriverVersions, &out.DriverVersions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]ComponentValidationStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValidationStatus.
+func (in *ValidationStatus) DeepCopy() *ValidationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ValidationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ValidatorSpec) DeepCopyInto(out *ValidatorSpec) {
 	*out = *in
@@ -1837,6 +2521,8 @@ func (in *ValidatorSpec) DeepCopyInto(out *ValidatorSpec) {
 	in.Toolkit.DeepCopyInto(&out.Toolkit)
 	in.Driver.DeepCopyInto(&out.Driver)
 	in.CUDA.DeepCopyInto(&out.CUDA)
+	in.P2P.DeepCopyInto(&out.P2P)
+	in.Revalidation.DeepCopyInto(&out.Revalidation)
 	in.VFIOPCI.DeepCopyInto(&out.VFIOPCI)
 	in.VGPUManager.DeepCopyInto(&out.VGPUManager)
 	in.VGPUDevices.DeepCopyInto(&out.VGPUDevices)
@@ -0,0 +1,79 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package v1alpha1
+
+import (
+	"k8s.io/utils/ptr"
+
+	nvidiav1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1"
+)
+
+const (
+	// DefaultGPUsHealthcheckPort is the default gRPC health service port of the gpus
+	// kubelet-plugin container
+	DefaultGPUsHealthcheckPort = int32(51516)
+	// DefaultComputeDomainsHealthcheckPort is the default gRPC health service port of the
+	// computeDomains kubelet-plugin container
+	DefaultComputeDomainsHealthcheckPort = int32(51515)
+)
+
+// SetDefaults fills every unset field of the GPUClusterSpec that has an effective
+// default with that default, so the stored object reflects what is deployed. It is
+// idempotent and never overrides a value set by the user.
+func (s *GPUClusterSpec) SetDefaults() {
+	// Unlike ClusterPolicy, an omitted dcgm block or an omitted enabled field means the
+	// standalone hostengine is not deployed.
+	if s.DCGM == nil {
+		s.DCGM = &nvidiav1.DCGMSpec{}
+	}
+	if s.DCGM.Enabled == nil {
+		s.DCGM.Enabled = ptr.To(false)
+	}
+
+	// An omitted dcgmExporter block means dcgm-exporter is not deployed, while an
+	// omitted enabled field inside a present block means it is.
+	if s.DCGMExporter == nil {
+		s.DCGMExporter = &nvidiav1.DCGMExporterSpec{Enabled: ptr.To(false)}
+	}
+	if s.DCGMExporter.Enabled == nil {
+		s.DCGMExporter.Enabled = ptr.To(true)
+	}
+
+	if s.DRADriver.ComputeDomains.Enabled == nil {
+		s.DRADriver.ComputeDomains.Enabled = ptr.To(true)
+	}
+	s.DRADriver.GPUs.KubeletPlugin.setHealthcheckDefaults(DefaultGPUsHealthcheckPort)
+	s.DRADriver.ComputeDomains.KubeletPlugin.setHealthcheckDefaults(DefaultComputeDomainsHealthcheckPort)
+
+	if s.HostPaths.KubeletRootDir == "" {
+		s.HostPaths.KubeletRootDir = nvidiav1.DefaultKubeletRootDir
+	}
+}
+
+// setHealthcheckDefaults enables the gRPC health service and sets its port to
+// defaultPort unless configured otherwise. A disabled health service gets no port.
+func (k *DRADriverKubeletPluginSpec) setHealthcheckDefaults(defaultPort int32) {
+	if k.Healthcheck == nil {
+		k.Healthcheck = &DRADriverHealthcheckSpec{}
+	}
+	if k.Healthcheck.Enabled == nil {
+		k.Healthcheck.Enabled = ptr.To(true)
+	}
+	if *k.Healthcheck.Enabled && k.Healthcheck.Port == nil {
+		k.Healthcheck.Port = ptr.To(defaultPort)
+	}
+}
@@ -0,0 +1,75 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+
+	nvidiav1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1"
+)
+
+func TestGPUClusterSpecSetDefaults(t *testing.T) {
+	spec := GPUClusterSpec{}
+	spec.SetDefaults()
+
+	require.Equal(t, &nvidiav1.DCGMSpec{Enabled: ptr.To(false)}, spec.DCGM)
+	require.Equal(t, &nvidiav1.DCGMExporterSpec{Enabled: ptr.To(false)}, spec.DCGMExporter)
+	require.Equal(t, ptr.To(true), spec.DRADriver.ComputeDomains.Enabled)
+	require.Equal(t, &DRADriverHealthcheckSpec{Enabled: ptr.To(true), Port: ptr.To(int32(51516))},
+		spec.DRADriver.GPUs.KubeletPlugin.Healthcheck)
+	require.Equal(t, &DRADriverHealthcheckSpec{Enabled: ptr.To(true), Port: ptr.To(int32(51515))},
+		spec.DRADriver.ComputeDomains.KubeletPlugin.Healthcheck)
+	require.Equal(t, "/var/lib/kubelet", spec.HostPaths.KubeletRootDir)
+
+	// Defaulting is idempotent
+	defaulted := spec.DeepCopy()
+	spec.SetDefaults()
+	require.Equal(t, defaulted, &spec)
+}
+
+func TestGPUClusterSpecSetDefaultsKeepsUserValues(t *testing.T) {
+	spec := GPUClusterSpec{
+		DCGM:         &nvidiav1.DCGMSpec{Enabled: ptr.To(true)},
+		DCGMExporter: &nvidiav1.DCGMExporterSpec{},
+		DRADriver: DRADriverSpec{
+			GPUs: DRADriverGPUsSpec{KubeletPlugin: DRADriverKubeletPluginSpec{
+				Healthcheck: &DRADriverHealthcheckSpec{Enabled: ptr.To(false)},
+			}},
+			ComputeDomains: DRADriverComputeDomainsSpec{
+				Enabled: ptr.To(false),
+				KubeletPlugin: DRADriverKubeletPluginSpec{
+					Healthcheck: &DRADriverHealthcheckSpec{Port: ptr.To(int32(6000))},
+				},
+			},
+		},
+		HostPaths: HostPathsSpec{KubeletRootDir: "/data/kubelet"},
+	}
+	spec.SetDefaults()
+
+	require.Equal(t, ptr.To(true), spec.DCGM.Enabled)
+	// A present dcgmExporter block without enabled is enabled
+	require.Equal(t, ptr.To(true), spec.DCGMExporter.Enabled)
+	require.Equal(t, ptr.To(false), spec.DRADriver.ComputeDomains.Enabled)
+	// A disabled health service gets no port
+	require.Equal(t, &DRADriverHealthcheckSpec{Enabled: ptr.To(false)}, spec.DRADriver.GPUs.KubeletPlugin.Healthcheck)
+	require.Equal(t, &DRADriverHealthcheckSpec{Enabled: ptr.To(true), Port: ptr.To(int32(6000))},
+		spec.DRADriver.ComputeDomains.KubeletPlugin.Healthcheck)
+	require.Equal(t, "/data/kubelet", spec.HostPaths.KubeletRootDir)
+}
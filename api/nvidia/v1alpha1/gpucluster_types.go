@@ -38,12 +38,13 @@ type GPUClusterSpec struct {
 
 	// DCGM defines the spec for the standalone NVIDIA DCGM hostengine. Disabled by default;
 	// when disabled, dcgm-exporter uses its embedded nv-hostengine. NOTE: the reused enabled
-	// field carries no server-side default and its IsEnabled() treats nil as enabled, so the
-	// controller must default nil enabled to disabled here.
+	// field's IsEnabled() treats nil as enabled, so the defaulting webhook (and the controller,
+	// for objects admitted without it) sets nil enabled to false here.
 	DCGM *nvidiav1.DCGMSpec `json:"dcgm,omitempty"`
 
-	// DCGMExporter defines the spec for NVIDIA DCGM Exporter. Enabled by default, but the
-	// reused enabled field carries no server-side default; the controller defaults nil enabled.
+	// DCGMExporter defines the spec for NVIDIA DCGM Exporter. Enabled by default when the
+	// block is present; the defaulting webhook sets nil enabled to true, and an omitted
+	// block to a disabled one.
 	DCGMExporter *nvidiav1.DCGMExporterSpec `json:"dcgmExporter,omitempty"`
 
 	// HostPaths defines the host paths used in host-path volumes for various components.
@@ -52,6 +53,11 @@ type GPUClusterSpec struct {
 	// Daemonsets defines the common configuration applied to all DaemonSets deployed
 	// by the GPUCluster controller.
 	Daemonsets nvidiav1.DaemonsetsSpec `json:"daemonsets,omitempty"`
+
+	// GPUHealth defines the tainting and remediation of unhealthy GPU nodes. The device
+	// plugin signal does not apply to the DRA stack.
+	// +kubebuilder:validation:Optional
+	GPUHealth *nvidiav1.GPUHealthSpec `json:"gpuHealth,omitempty"`
 }
 
 // DRADriverSpec defines the spec for the NVIDIA DRA driver stack. There is no top-level
@@ -39,6 +39,7 @@ var (
 func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion, &NVIDIADriver{}, &NVIDIADriverList{})
 	scheme.AddKnownTypes(SchemeGroupVersion, &GPUCluster{}, &GPUClusterList{})
+	scheme.AddKnownTypes(SchemeGroupVersion, &NodeUpgradeHistory{}, &NodeUpgradeHistoryList{})
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
 }
@@ -0,0 +1,115 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	NodeUpgradeHistoryCRDName = "NodeUpgradeHistory"
+)
+
+// NodeUpgradeResult is the outcome of the driver upgrade of a node
+// +kubebuilder:validation:Enum=InProgress;Succeeded;Failed
+type NodeUpgradeResult string
+
+const (
+	// NodeUpgradeInProgress indicates the driver upgrade of the node is still in progress
+	NodeUpgradeInProgress NodeUpgradeResult = "InProgress"
+	// NodeUpgradeSucceeded indicates the driver upgrade of the node is done
+	NodeUpgradeSucceeded NodeUpgradeResult = "Succeeded"
+	// NodeUpgradeFailed indicates the driver upgrade of the node failed
+	NodeUpgradeFailed NodeUpgradeResult = "Failed"
+)
+
+// NodeUpgradeTransition is a change of the driver upgrade state of a node
+type NodeUpgradeTransition struct {
+	// State is the driver upgrade state the node moved to
+	State string `json:"state"`
+	// Time is when the node was seen in State for the first time
+	Time metav1.Time `json:"time"`
+}
+
+// NodeUpgradeRecord describes a driver upgrade of a node
+type NodeUpgradeRecord struct {
+	// Owner is the custom resource the driver of the node is configured by, as kind/name
+	// +optional
+	Owner string `json:"owner,omitempty"`
+	// FromImage is the driver image the node ran before the upgrade
+	// +optional
+	FromImage string `json:"fromImage,omitempty"`
+	// ToImage is the driver image the node is upgraded to
+	// +optional
+	ToImage string `json:"toImage,omitempty"`
+	// Result is the outcome of the upgrade
+	Result NodeUpgradeResult `json:"result"`
+	// State is the current driver upgrade state of the node, or the last one if the
+	// upgrade is over
+	// +optional
+	State string `json:"state,omitempty"`
+	// StartTime is when the upgrade of the node started, as it left upgrade-required
+	StartTime metav1.Time `json:"startTime"`
+	// CompletionTime is when the upgrade of the node succeeded or failed
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// DrainDuration is how long the drain of the node took
+	// +optional
+	DrainDuration *metav1.Duration `json:"drainDuration,omitempty"`
+	// Message describes why the upgrade failed
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Transitions lists the driver upgrade states the node went through, in order
+	// +optional
+	Transitions []NodeUpgradeTransition `json:"transitions,omitempty"`
+}
+
+// NodeUpgradeHistoryStatus defines the observed state of NodeUpgradeHistory
+type NodeUpgradeHistoryStatus struct {
+	// Records lists the latest driver upgrades of the node, most recent first
+	// +optional
+	Records []NodeUpgradeRecord `json:"records,omitempty"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster,shortName={"nuh"}
+//+kubebuilder:printcolumn:name="Result",type=string,JSONPath=`.status.records[0].result`,priority=0
+//+kubebuilder:printcolumn:name="State",type=string,JSONPath=`.status.records[0].state`,priority=0
+//+kubebuilder:printcolumn:name="Drain",type=string,JSONPath=`.status.records[0].drainDuration`,priority=0
+//+kubebuilder:printcolumn:name="Started",type=date,JSONPath=`.status.records[0].startTime`,priority=0
+//+kubebuilder:printcolumn:name="To",type=string,JSONPath=`.status.records[0].toImage`,priority=1
+
+// NodeUpgradeHistory is the Schema for the nodeupgradehistories API. It is named after
+// the node whose driver upgrades it records, and is maintained by the upgrade controller.
+type NodeUpgradeHistory struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status NodeUpgradeHistoryStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// NodeUpgradeHistoryList contains a list of NodeUpgradeHistory
+type NodeUpgradeHistoryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NodeUpgradeHistory `json:"items"`
+}
@@ -28,6 +28,7 @@ import (
 
 	upgrade_v1alpha1 "github.com/NVIDIA/k8s-operator-libs/api/upgrade/v1alpha1"
 
+	nvidiav1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1"
 	"github.com/NVIDIA/gpu-operator/internal/consts"
 	"github.com/NVIDIA/gpu-operator/internal/image"
 )
@@ -512,6 +513,42 @@ type NVIDIADriverStatus struct {
 	Namespace string `json:"namespace,omitempty"`
 	// Conditions is a list of conditions representing the NVIDIADriver's current state.
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// NodePools reports the driver rollout of each node pool this instance deploys to.
+	// A node pool is the set of nodes sharing an OS release, and a kernel version when
+	// precompiled drivers are used; each node pool is served by its own DaemonSet.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	NodePools []NodePoolStatus `json:"nodePools,omitempty"`
+	// Upgrade reports the state of automatic driver upgrades
+	// +optional
+	Upgrade *DriverUpgradeStatus `json:"upgrade,omitempty"`
+}
+
+type DriverUpgradeStatus = nvidiav1.DriverUpgradeStatus
+
+// NodePoolStatus defines the observed state of the driver in a single node pool
+type NodePoolStatus struct {
+	// Name of the node pool
+	Name string `json:"name"`
+	// OSTag identifies the operating system of the nodes in the pool, e.g. ubuntu22.04
+	OSTag string `json:"osTag"`
+	// Kernel is the kernel version of the nodes in the pool. It is only set when
+	// precompiled drivers are used.
+	// +optional
+	Kernel string `json:"kernel,omitempty"`
+	// DaemonSet is the name of the driver DaemonSet deployed to the pool
+	DaemonSet string `json:"daemonSet"`
+	// Image is the resolved driver image deployed to the pool
+	Image string `json:"image"`
+	// DesiredNumberScheduled is the number of nodes that should be running the driver pod
+	DesiredNumberScheduled int32 `json:"desiredNumberScheduled"`
+	// NumberReady is the number of nodes running a ready driver pod
+	NumberReady int32 `json:"numberReady"`
+	// ConfigDigest is the digest of the driver install configuration of the pool.
+	// A change of the digest requires the driver to be reinstalled on the nodes.
+	// +optional
+	ConfigDigest string `json:"configDigest,omitempty"`
 }
 
 // +genclient
@@ -521,6 +558,11 @@ type NVIDIADriverStatus struct {
 //+kubebuilder:resource:scope=Cluster,shortName={"nvd","nvdriver","nvdrivers"}
 //+kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.state`,priority=0
 //+kubebuilder:printcolumn:name="Default",type=boolean,JSONPath=`.spec.default`,priority=0
+//+kubebuilder:printcolumn:name="Upgrade Target",type=string,JSONPath=`.status.upgrade.targetVersion`,priority=0
+//+kubebuilder:printcolumn:name="Upgraded",type=integer,JSONPath=`.status.upgrade.nodes.done`,priority=0
+//+kubebuilder:printcolumn:name="Nodes",type=integer,JSONPath=`.status.upgrade.nodes.total`,priority=0
+//+kubebuilder:printcolumn:name="Failed",type=integer,JSONPath=`.status.upgrade.nodes.failed`,priority=0
+//+kubebuilder:printcolumn:name="Upgrade ETA",type=date,JSONPath=`.status.upgrade.estimatedCompletionTime`,priority=1
 //+kubebuilder:printcolumn:name="Age",type=string,JSONPath=`.metadata.creationTimestamp`,priority=0
 
 // NVIDIADriver is the Schema for the nvidiadrivers API
@@ -812,11 +854,48 @@ type DriverUpgradePolicySpec struct {
 	PodDeletion       *PodDeletionSpec       `json:"podDeletion,omitempty"`
 	WaitForCompletion *WaitForCompletionSpec `json:"waitForCompletion,omitempty"`
 	DrainSpec         *DrainSpec             `json:"drain,omitempty"`
+	// Canary upgrades a set of canary nodes first and only upgrades the rest of the
+	// nodes once the driver is validated on all of them.
+	// +optional
+	Canary *DriverUpgradeCanarySpec `json:"canary,omitempty"`
+	// MaintenanceWindows are the recurring windows during which driver upgrades can start
+	// on nodes. Outside of them, upgrades already in progress complete but no new node
+	// upgrade starts. Upgrades can start at any time when no window is set.
+	// +optional
+	MaintenanceWindows []DriverUpgradeMaintenanceWindow `json:"maintenanceWindows,omitempty"`
+	// Rollback puts the last driver image rolled out to all nodes back when too many
+	// nodes fail the upgrade. The driver image of the spec is patched, along with the
+	// nvidia.com/gpu-driver-rollback annotation recording the rollback. A GitOps tool reverting
+	// the patch puts the failed image back, which is not rolled back again: update the image
+	// in its source instead.
+	// +optional
+	Rollback *DriverUpgradeRollbackSpec `json:"rollback,omitempty"`
+	// Hooks are run on each node at given stages of its driver upgrade.
+	// +optional
+	Hooks *DriverUpgradeHooksSpec `json:"hooks,omitempty"`
+	// Plan computes the driver upgrade and reports it in status.upgrade.plan instead of
+	// upgrading any node, whether AutoUpgrade is set or not.
+	// +optional
+	Plan bool `json:"plan,omitempty"`
+	// Topology upgrades the nodes one topology domain at a time, e.g. one availability zone
+	// or NVLink domain, a domain finishing its upgrade before the next one starts.
+	// +optional
+	Topology *DriverUpgradeTopologySpec `json:"topology,omitempty"`
+	// GPUWorkloadWait waits for the GPU workloads of a node to finish before the node is
+	// drained, only evicting the other pods of the node in the meantime.
+	// +optional
+	GPUWorkloadWait *DriverUpgradeGPUWorkloadWaitSpec `json:"gpuWorkloadWait,omitempty"`
 }
 
 type PodDeletionSpec = upgrade_v1alpha1.PodDeletionSpec
 type WaitForCompletionSpec = upgrade_v1alpha1.WaitForCompletionSpec
 type DrainSpec = upgrade_v1alpha1.DrainSpec
+type DriverUpgradeCanarySpec = nvidiav1.DriverUpgradeCanarySpec
+type DriverUpgradeMaintenanceWindow = nvidiav1.DriverUpgradeMaintenanceWindow
+type DriverUpgradeRollbackSpec = nvidiav1.DriverUpgradeRollbackSpec
+type DriverUpgradeHooksSpec = nvidiav1.DriverUpgradeHooksSpec
+type DriverUpgradeTopologySpec = nvidiav1.DriverUpgradeTopologySpec
+type DriverUpgradeGPUWorkloadWaitSpec = nvidiav1.DriverUpgradeGPUWorkloadWaitSpec
 
 // GetUpgradePolicyWithDefaults returns the upgrade policy for this driver
 // with default values applied for any unset fields.
@@ -857,6 +936,59 @@ func (s *NVIDIADriverSpec) GetUpgradePolicyWithDefaults() *upgrade_v1alpha1.Driv
 	return result
 }
 
+// GetUpgradeCanary returns the canary phase settings of driver upgrades, or nil if there is no canary phase
+func (s *NVIDIADriverSpec) GetUpgradeCanary() *DriverUpgradeCanarySpec {
+	if s.UpgradePolicy == nil {
+		return nil
+	}
+	return s.UpgradePolicy.Canary
+}
+
+// GetUpgradeMaintenanceWindows returns the maintenance windows of driver upgrades
+func (s *NVIDIADriverSpec) GetUpgradeMaintenanceWindows() []DriverUpgradeMaintenanceWindow {
+	if s.UpgradePolicy == nil {
+		return nil
+	}
+	return s.UpgradePolicy.MaintenanceWindows
+}
+
+// GetUpgradeRollback returns the automatic rollback settings of driver upgrades, or nil if rollback is disabled
+func (s *NVIDIADriverSpec) GetUpgradeRollback() *DriverUpgradeRollbackSpec {
+	if s.UpgradePolicy == nil {
+		return nil
+	}
+	return s.UpgradePolicy.Rollback
+}
+
+// GetUpgradeHooks returns the hooks run during driver upgrades
+func (s *NVIDIADriverSpec) GetUpgradeHooks() *DriverUpgradeHooksSpec {
+	if s.UpgradePolicy == nil {
+		return nil
+	}
+	return s.UpgradePolicy.Hooks
+}
+
+// GetUpgradeTopology returns the topology driver upgrades are batched by, or nil if they are not
+func (s *NVIDIADriverSpec) GetUpgradeTopology() *DriverUpgradeTopologySpec {
+	if s.UpgradePolicy == nil {
+		return nil
+	}
+	return s.UpgradePolicy.Topology
+}
+
+// GetUpgradeGPUWorkloadWait returns how driver upgrades wait for GPU workloads, or nil if they do not
+func (s *NVIDIADriverSpec) GetUpgradeGPUWorkloadWait() *DriverUpgradeGPUWorkloadWaitSpec {
+	if s.UpgradePolicy == nil {
+		return nil
+	}
+	return s.UpgradePolicy.GPUWorkloadWait
+}
+
+// IsUpgradePlanEnabled returns true if the driver upgrade is only planned
+func (s *NVIDIADriverSpec) IsUpgradePlanEnabled() bool {
+	return s.UpgradePolicy != nil && s.UpgradePolicy.Plan
+}
+
 func getDefaultUpgradePolicySpec() *upgrade_v1alpha1.DriverUpgradePolicySpec {
 	return &upgrade_v1alpha1.DriverUpgradePolicySpec{
 		AutoUpgrade:         true,
@@ -0,0 +1,141 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package v1beta1
+
+import (
+	"strconv"
+
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/NVIDIA/gpu-operator/api/nvidia/v1alpha1"
+)
+
+// UseOpenKernelModulesAnnotation preserves the deprecated v1alpha1 useOpenKernelModules
+// field, which has no v1beta1 counterpart, across a round trip through v1beta1
+const UseOpenKernelModulesAnnotation = "nvidia.com/v1alpha1-use-open-kernel-modules"
+
+// ConvertTo converts this NVIDIADriver to the v1alpha1 hub version
+func (src *NVIDIADriver) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.NVIDIADriver)
+	in := src.DeepCopy()
+
+	dst.ObjectMeta = in.ObjectMeta
+	dst.Status = in.Status
+
+	dst.Spec = v1alpha1.NVIDIADriverSpec{
+		Default:               in.Spec.Default,
+		DriverType:            in.Spec.DriverType,
+		UsePrecompiled:        in.Spec.UsePrecompiled,
+		KernelModuleType:      in.Spec.KernelModuleType,
+		StartupProbe:          in.Spec.StartupProbe,
+		LivenessProbe:         in.Spec.LivenessProbe,
+		ReadinessProbe:        in.Spec.ReadinessProbe,
+		GPUDirectRDMA:         in.Spec.GPUDirectRDMA,
+		GPUDirectStorage:      in.Spec.GPUDirectStorage,
+		GDRCopy:               in.Spec.GDRCopy,
+		Repository:            in.Spec.Repository,
+		Image:                 in.Spec.Image,
+		Version:               in.Spec.Version,
+		ImagePullPolicy:       in.Spec.ImagePullPolicy,
+		ImagePullSecrets:      in.Spec.ImagePullSecrets,
+		Manager:               in.Spec.Manager,
+		Resources:             in.Spec.Resources,
+		Args:                  in.Spec.Args,
+		Env:                   in.Spec.Env,
+		RepoConfig:            in.Spec.RepoConfig,
+		CertConfig:            in.Spec.CertConfig,
+		LicensingConfig:       in.Spec.LicensingConfig,
+		VirtualTopologyConfig: in.Spec.VirtualTopologyConfig,
+		KernelModuleConfig:    in.Spec.KernelModuleConfig,
+		SecretEnv:             in.Spec.SecretEnv,
+		UpgradePolicy:         in.Spec.UpgradePolicy,
+		NodeSelector:          in.Spec.NodeSelector,
+		NodeAffinity:          in.Spec.NodeAffinity,
+		Labels:                in.Spec.Labels,
+		Annotations:           in.Spec.Annotations,
+		Tolerations:           in.Spec.Tolerations,
+		PriorityClassName:     in.Spec.PriorityClassName,
+		PodSecurityContext:    in.Spec.PodSecurityContext,
+		HostNetwork:           in.Spec.HostNetwork,
+	}
+
+	if value, ok := dst.Annotations[UseOpenKernelModulesAnnotation]; ok {
+		if useOpenKernelModules, err := strconv.ParseBool(value); err == nil {
+			dst.Spec.UseOpenKernelModules = &useOpenKernelModules
+		}
+		delete(dst.Annotations, UseOpenKernelModulesAnnotation)
+		if len(dst.Annotations) == 0 {
+			dst.Annotations = nil
+		}
+	}
+
+	return nil
+}
+
+// ConvertFrom converts the v1alpha1 hub version to this NVIDIADriver
+func (dst *NVIDIADriver) ConvertFrom(srcRaw conversion.Hub) error {
+	in := srcRaw.(*v1alpha1.NVIDIADriver).DeepCopy()
+
+	dst.ObjectMeta = in.ObjectMeta
+	dst.Status = in.Status
+
+	dst.Spec = NVIDIADriverSpec{
+		Default:               in.Spec.Default,
+		DriverType:            in.Spec.DriverType,
+		UsePrecompiled:        in.Spec.UsePrecompiled,
+		KernelModuleType:      in.Spec.KernelModuleType,
+		StartupProbe:          in.Spec.StartupProbe,
+		LivenessProbe:         in.Spec.LivenessProbe,
+		ReadinessProbe:        in.Spec.ReadinessProbe,
+		GPUDirectRDMA:         in.Spec.GPUDirectRDMA,
+		GPUDirectStorage:      in.Spec.GPUDirectStorage,
+		GDRCopy:               in.Spec.GDRCopy,
+		Repository:            in.Spec.Repository,
+		Image:                 in.Spec.Image,
+		Version:               in.Spec.Version,
+		ImagePullPolicy:       in.Spec.ImagePullPolicy,
+		ImagePullSecrets:      in.Spec.ImagePullSecrets,
+		Manager:               in.Spec.Manager,
+		Resources:             in.Spec.Resources,
+		Args:                  in.Spec.Args,
+		Env:                   in.Spec.Env,
+		RepoConfig:            in.Spec.RepoConfig,
+		CertConfig:            in.Spec.CertConfig,
+		LicensingConfig:       in.Spec.LicensingConfig,
+		VirtualTopologyConfig: in.Spec.VirtualTopologyConfig,
+		KernelModuleConfig:    in.Spec.KernelModuleConfig,
+		SecretEnv:             in.Spec.SecretEnv,
+		UpgradePolicy:         in.Spec.UpgradePolicy,
+		NodeSelector:          in.Spec.NodeSelector,
+		NodeAffinity:          in.Spec.NodeAffinity,
+		Labels:                in.Spec.Labels,
+		Annotations:           in.Spec.Annotations,
+		Tolerations:           in.Spec.Tolerations,
+		PriorityClassName:     in.Spec.PriorityClassName,
+		PodSecurityContext:    in.Spec.PodSecurityContext,
+		HostNetwork:           in.Spec.HostNetwork,
+	}
+
+	if in.Spec.UseOpenKernelModules != nil {
+		if dst.Annotations == nil {
+			dst.Annotations = map[string]string{}
+		}
+		dst.Annotations[UseOpenKernelModulesAnnotation] = strconv.FormatBool(*in.Spec.UseOpenKernelModules)
+	}
+
+	return nil
+}
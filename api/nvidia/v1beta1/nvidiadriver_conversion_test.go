@@ -0,0 +1,140 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/diff"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+	"sigs.k8s.io/randfill"
+
+	"github.com/NVIDIA/gpu-operator/api/nvidia/v1alpha1"
+)
+
+const roundTripIterations = 1000
+
+func TestNVIDIADriverIsConvertible(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	require.NoError(t, AddToScheme(scheme))
+
+	// the conversion webhook is only served when every version is convertible through the hub
+	convertible, err := conversion.IsConvertible(scheme, &NVIDIADriver{})
+	require.NoError(t, err)
+	require.True(t, convertible)
+}
+
+func newFiller(f *randfill.Filler) *randfill.Filler {
+	// keep the generated objects small enough for the iterations to stay fast
+	return f.NilChance(0.2).NumElements(0, 3).MaxDepth(8)
+}
+
+// roundTripHub converts a v1alpha1 object to v1beta1 and back
+func roundTripHub(t *testing.T, hub *v1alpha1.NVIDIADriver) {
+	t.Helper()
+
+	spoke := &NVIDIADriver{}
+	require.NoError(t, spoke.ConvertFrom(hub.DeepCopy()))
+	got := &v1alpha1.NVIDIADriver{}
+	require.NoError(t, spoke.ConvertTo(got))
+
+	if !apiequality.Semantic.DeepEqual(hub, got) {
+		t.Fatalf("v1alpha1 -> v1beta1 -> v1alpha1 round trip is lossy:\n%s", diff.Diff(hub, got))
+	}
+}
+
+// roundTripSpoke converts a v1beta1 object to v1alpha1 and back
+func roundTripSpoke(t *testing.T, spoke *NVIDIADriver) {
+	t.Helper()
+
+	hub := &v1alpha1.NVIDIADriver{}
+	require.NoError(t, spoke.DeepCopy().ConvertTo(hub))
+	got := &NVIDIADriver{}
+	require.NoError(t, got.ConvertFrom(hub))
+
+	if !apiequality.Semantic.DeepEqual(spoke, got) {
+		t.Fatalf("v1beta1 -> v1alpha1 -> v1beta1 round trip is lossy:\n%s", diff.Diff(spoke, got))
+	}
+}
+
+func TestNVIDIADriverRoundTrip(t *testing.T) {
+	f := newFiller(randfill.NewWithSeed(1))
+	for i := 0; i < roundTripIterations; i++ {
+		hub := &v1alpha1.NVIDIADriver{}
+		f.Fill(hub)
+		// the type meta is set by the caller of the conversion, not by the conversion itself
+		hub.TypeMeta = metav1.TypeMeta{}
+		roundTripHub(t, hub)
+
+		spoke := &NVIDIADriver{}
+		f.Fill(spoke)
+		spoke.TypeMeta = metav1.TypeMeta{}
+		delete(spoke.Annotations, UseOpenKernelModulesAnnotation)
+		roundTripSpoke(t, spoke)
+	}
+}
+
+func FuzzNVIDIADriverRoundTrip(f *testing.F) {
+	f.Add([]byte("nvidia-driver"))
+	f.Add([]byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		filler := newFiller(randfill.NewFromGoFuzz(data))
+
+		hub := &v1alpha1.NVIDIADriver{}
+		filler.Fill(hub)
+		hub.TypeMeta = metav1.TypeMeta{}
+		roundTripHub(t, hub)
+
+		spoke := &NVIDIADriver{}
+		filler.Fill(spoke)
+		spoke.TypeMeta = metav1.TypeMeta{}
+		delete(spoke.Annotations, UseOpenKernelModulesAnnotation)
+		roundTripSpoke(t, spoke)
+	})
+}
+
+func TestNVIDIADriverConversion(t *testing.T) {
+	hub := &v1alpha1.NVIDIADriver{
+		ObjectMeta: metav1.ObjectMeta{Name: "default"},
+		Spec: v1alpha1.NVIDIADriverSpec{
+			UseOpenKernelModules: ptr.To(true),
+			KernelModuleType:     "open",
+			GPUDirectStorage:     &v1alpha1.GPUDirectStorageSpec{Enabled: ptr.To(true)},
+			UpgradePolicy:        &v1alpha1.DriverUpgradePolicySpec{AutoUpgrade: true, MaxParallelUpgrades: 2},
+		},
+	}
+
+	spoke := &NVIDIADriver{}
+	require.NoError(t, spoke.ConvertFrom(hub))
+	require.Equal(t, map[string]string{UseOpenKernelModulesAnnotation: "true"}, spoke.Annotations)
+	require.Equal(t, "open", spoke.Spec.KernelModuleType)
+	require.Equal(t, hub.Spec.GPUDirectStorage, spoke.Spec.GPUDirectStorage)
+	require.Equal(t, hub.Spec.UpgradePolicy, spoke.Spec.UpgradePolicy)
+	// the source object must not be modified
+	require.Nil(t, hub.Annotations)
+
+	got := &v1alpha1.NVIDIADriver{}
+	require.NoError(t, spoke.ConvertTo(got))
+	require.Equal(t, hub, got)
+	require.Equal(t, map[string]string{UseOpenKernelModulesAnnotation: "true"}, spoke.Annotations)
+}
@@ -0,0 +1,278 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/NVIDIA/gpu-operator/api/nvidia/v1alpha1"
+)
+
+// NVIDIADriverSpec defines the desired state of NVIDIADriver.
+// Unlike v1alpha1, it no longer carries the deprecated useOpenKernelModules field;
+// kernelModuleType is the only way to select the kernel module type.
+// The CEL validation allows non-default drivers to use nodeSelector, but requires
+// default drivers to leave nodeSelector unset or empty.
+// +kubebuilder:validation:XValidation:rule="has(self.default) && self.default ? !has(self.nodeSelector) || size(self.nodeSelector) == 0 : true",message="default NVIDIADriver must not use nodeSelector"
+type NVIDIADriverSpec struct {
+	// Default indicates that this NVIDIADriver acts as the fallback driver daemon set manager for GPU nodes
+	// that do not match any non-default NVIDIADriver nodeSelector.
+	// +kubebuilder:default=false
+	Default bool `json:"default"`
+
+	// +kubebuilder:validation:Enum=gpu;vgpu;vgpu-host-manager
+	// +kubebuilder:default=gpu
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="driverType is an immutable field. Please create a new NvidiaDriver resource instead when you want to change this setting."
+	DriverType DriverType `json:"driverType"`
+
+	// UsePrecompiled indicates if deployment of NVIDIA Driver using pre-compiled modules is enabled
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Enable NVIDIA Driver deployment using pre-compiled modules"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="usePrecompiled is an immutable field. Please create a new NvidiaDriver resource instead when you want to change this setting."
+	UsePrecompiled *bool `json:"usePrecompiled,omitempty"`
+
+	// KernelModuleType represents the type of driver kernel modules to be used when installing the GPU driver.
+	// Accepted values are auto, proprietary and open. NOTE: If auto is chosen, it means that the recommended kernel module
+	// type is chosen based on the GPU devices on the host and the driver branch used
+	// +kubebuilder:validation:Enum=auto;open;proprietary
+	// +kubebuilder:default=auto
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Kernel Module Type"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.description="Kernel Module Type"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:select:auto,urn:alm:descriptor:com.tectonic.ui:select:open,urn:alm:descriptor:com.tectonic.ui:select:proprietary"
+	KernelModuleType string `json:"kernelModuleType,omitempty"`
+
+	// NVIDIA Driver container startup probe settings
+	StartupProbe *ContainerProbeSpec `json:"startupProbe,omitempty"`
+
+	// NVIDIA Driver container liveness probe settings
+	LivenessProbe *ContainerProbeSpec `json:"livenessProbe,omitempty"`
+
+	// NVIDIA Driver container readiness probe settings
+	ReadinessProbe *ContainerProbeSpec `json:"readinessProbe,omitempty"`
+
+	// GPUDirectRDMA defines the spec for NVIDIA Peer Memory driver
+	GPUDirectRDMA *GPUDirectRDMASpec `json:"rdma,omitempty"`
+
+	// GPUDirectStorage defines the spec for GDS driver
+	GPUDirectStorage *GPUDirectStorageSpec `json:"gds,omitempty"`
+
+	// GDRCopy defines the spec for GDRCopy driver
+	GDRCopy *GDRCopySpec `json:"gdrcopy,omitempty"`
+
+	// NVIDIA Driver repository
+	// +kubebuilder:validation:Optional
+	Repository string `json:"repository,omitempty"`
+
+	// NVIDIA Driver container image name
+	// +kubebuilder:default=nvcr.io/nvidia/driver
+	Image string `json:"image"`
+
+	// NVIDIA Driver version (or just branch for precompiled drivers)
+	// +kubebuilder:validation:Optional
+	Version string `json:"version,omitempty"`
+
+	// Image pull policy
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Image Pull Policy"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:imagePullPolicy"
+	ImagePullPolicy string `json:"imagePullPolicy,omitempty"`
+
+	// Image pull secrets
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Image pull secrets"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:io.kubernetes:Secret"
+	ImagePullSecrets []string `json:"imagePullSecrets,omitempty"`
+
+	// Manager represents configuration for NVIDIA Driver Manager initContainer
+	Manager DriverManagerSpec `json:"manager,omitempty"`
+
+	// Optional: Define resources requests and limits for each pod
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Resource Requirements"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:com.tectonic.ui:resourceRequirements"
+	Resources *ResourceRequirements `json:"resources,omitempty"`
+
+	// Optional: List of arguments
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Arguments"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:com.tectonic.ui:text"
+	Args []string `json:"args,omitempty"`
+
+	// Optional: List of environment variables
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Environment Variables"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:com.tectonic.ui:text"
+	Env []EnvVar `json:"env,omitempty"`
+
+	// Optional: Custom repo configuration for NVIDIA Driver container
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Custom Repo Configuration For NVIDIA Driver Container"
+	RepoConfig *DriverRepoConfigSpec `json:"repoConfig,omitempty"`
+
+	// Optional: Custom certificates configuration for NVIDIA Driver container
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Custom Certificates Configuration For NVIDIA Driver Container"
+	CertConfig *DriverCertConfigSpec `json:"certConfig,omitempty"`
+
+	// Optional: Licensing configuration for NVIDIA vGPU licensing
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Licensing Configuration For NVIDIA vGPU Driver Container"
+	LicensingConfig *DriverLicensingConfigSpec `json:"licensingConfig,omitempty"`
+
+	// Optional: Virtual Topology Daemon configuration for NVIDIA vGPU drivers
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Custom Virtual Topology Daemon Configuration For vGPU Driver Container"
+	VirtualTopologyConfig *VirtualTopologyConfigSpec `json:"virtualTopologyConfig,omitempty"`
+
+	// Optional: Kernel module configuration parameters for the NVIDIA Driver
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Kernel module configuration parameters for the NVIDIA driver"
+	KernelModuleConfig *KernelModuleConfigSpec `json:"kernelModuleConfig,omitempty"`
+
+	// Optional: SecretEnv represents the name of the Kubernetes Secret with secret environment variables for the NVIDIA Driver
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Name of the Kubernetes Secret with secret environment variables for the NVIDIA Driver"
+	SecretEnv string `json:"secretEnv,omitempty"`
+
+	// UpgradePolicy allows to control automatic upgrade of the driver on nodes
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Driver Upgrade Policy"
+	UpgradePolicy *DriverUpgradePolicySpec `json:"upgradePolicy,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// NodeSelector specifies a selector for installation of NVIDIA driver
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Affinity specifies node affinity rules for driver pods
+	NodeAffinity *corev1.NodeAffinity `json:"nodeAffinity,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Optional: Map of string keys and values that can be used to organize and categorize
+	// (scope and select) objects. May match selectors of replication controllers
+	// and services.
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Optional: Annotations is an unstructured key value map stored with a resource that may be
+	// set by external tools to store and retrieve arbitrary metadata. They are not
+	// queryable and should be preserved when modifying objects.
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Optional: Set tolerations
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Tolerations"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:io.kubernetes:Tolerations"
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Optional: Set priorityClassName
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="PriorityClassName"
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Optional: Set pod-level security context for driver pod
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="PodSecurityContext"
+	PodSecurityContext *corev1.PodSecurityContext `json:"podSecurityContext,omitempty"`
+
+	// HostNetwork indicates whether the Driver pod uses the host's network namespace.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Enable hostNetwork for NVIDIA Driver"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
+	HostNetwork *bool `json:"hostNetwork,omitempty"`
+}
+
+// The nested NVIDIADriver types did not change between v1alpha1 and v1beta1, so they are
+// shared with v1alpha1 to keep both versions in lockstep.
+type (
+	ResourceRequirements      = v1alpha1.ResourceRequirements
+	DriverManagerSpec         = v1alpha1.DriverManagerSpec
+	EnvVar                    = v1alpha1.EnvVar
+	ContainerProbeSpec        = v1alpha1.ContainerProbeSpec
+	GPUDirectStorageSpec      = v1alpha1.GPUDirectStorageSpec
+	GPUDirectRDMASpec         = v1alpha1.GPUDirectRDMASpec
+	GDRCopySpec               = v1alpha1.GDRCopySpec
+	KernelModuleConfigSpec    = v1alpha1.KernelModuleConfigSpec
+	VirtualTopologyConfigSpec = v1alpha1.VirtualTopologyConfigSpec
+	DriverCertConfigSpec      = v1alpha1.DriverCertConfigSpec
+	DriverRepoConfigSpec      = v1alpha1.DriverRepoConfigSpec
+	DriverLicensingConfigSpec = v1alpha1.DriverLicensingConfigSpec
+	DriverUpgradePolicySpec   = v1alpha1.DriverUpgradePolicySpec
+	DriverType                = v1alpha1.DriverType
+	State                     = v1alpha1.State
+	NVIDIADriverStatus        = v1alpha1.NVIDIADriverStatus
+	NodePoolStatus            = v1alpha1.NodePoolStatus
+)
+
+const (
+	// GPU driver type
+	GPU = v1alpha1.GPU
+	// VGPU guest driver type
+	VGPU = v1alpha1.VGPU
+	// VGPUHostManager specifies vgpu host manager type
+	VGPUHostManager = v1alpha1.VGPUHostManager
+)
+
+const (
+	// Ready indicates that the NVIDIA driver managed by this instance is ready
+	Ready = v1alpha1.Ready
+	// NotReady indicates that the NVIDIA driver managed by this instance is not ready
+	NotReady = v1alpha1.NotReady
+	// Disabled indicates if the state is disabled in ClusterPolicy
+	Disabled = v1alpha1.Disabled
+)
+
+// +genclient
+// +genclient:nonNamespaced
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:storageversion
+//+kubebuilder:resource:scope=Cluster,shortName={"nvd","nvdriver","nvdrivers"}
+//+kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.state`,priority=0
+//+kubebuilder:printcolumn:name="Default",type=boolean,JSONPath=`.spec.default`,priority=0
+//+kubebuilder:printcolumn:name="Upgrade Target",type=string,JSONPath=`.status.upgrade.targetVersion`,priority=0
+//+kubebuilder:printcolumn:name="Upgraded",type=integer,JSONPath=`.status.upgrade.nodes.done`,priority=0
+//+kubebuilder:printcolumn:name="Nodes",type=integer,JSONPath=`.status.upgrade.nodes.total`,priority=0
+//+kubebuilder:printcolumn:name="Failed",type=integer,JSONPath=`.status.upgrade.nodes.failed`,priority=0
+//+kubebuilder:printcolumn:name="Upgrade ETA",type=date,JSONPath=`.status.upgrade.estimatedCompletionTime`,priority=1
+//+kubebuilder:printcolumn:name="Age",type=string,JSONPath=`.metadata.creationTimestamp`,priority=0
+
+// NVIDIADriver is the Schema for the nvidiadrivers API
+type NVIDIADriver struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NVIDIADriverSpec   `json:"spec,omitempty"`
+	Status NVIDIADriverStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// NVIDIADriverList contains a list of NVIDIADriver
+type NVIDIADriverList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NVIDIADriver `json:"items"`
+}
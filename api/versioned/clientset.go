@@ -24,6 +24,7 @@ import (
 
 	nvidiav1 "github.com/NVIDIA/gpu-operator/api/versioned/typed/nvidia/v1"
 	nvidiav1alpha1 "github.com/NVIDIA/gpu-operator/api/versioned/typed/nvidia/v1alpha1"
+	nvidiav1beta1 "github.com/NVIDIA/gpu-operator/api/versioned/typed/nvidia/v1beta1"
 	discovery "k8s.io/client-go/discovery"
 	rest "k8s.io/client-go/rest"
 	flowcontrol "k8s.io/client-go/util/flowcontrol"
@@ -33,6 +34,7 @@ type Interface interface {
 	Discovery() discovery.DiscoveryInterface
 	NvidiaV1() nvidiav1.NvidiaV1Interface
 	NvidiaV1alpha1() nvidiav1alpha1.NvidiaV1alpha1Interface
+	NvidiaV1beta1() nvidiav1beta1.NvidiaV1beta1Interface
 }
 
 // Clientset contains the clients for groups.
@@ -40,6 +42,7 @@ type Clientset struct {
 	*discovery.DiscoveryClient
 	nvidiaV1       *nvidiav1.NvidiaV1Client
 	nvidiaV1alpha1 *nvidiav1alpha1.NvidiaV1alpha1Client
+	nvidiaV1beta1  *nvidiav1beta1.NvidiaV1beta1Client
 }
 
 // NvidiaV1 retrieves the NvidiaV1Client
@@ -52,6 +55,11 @@ func (c *Clientset) NvidiaV1alpha1() nvidiav1alpha1.NvidiaV1alpha1Interface {
 	return c.nvidiaV1alpha1
 }
 
+// NvidiaV1beta1 retrieves the NvidiaV1beta1Client
+func (c *Clientset) NvidiaV1beta1() nvidiav1beta1.NvidiaV1beta1Interface {
+	return c.nvidiaV1beta1
+}
+
 // Discovery retrieves the DiscoveryClient
 func (c *Clientset) Discovery() discovery.DiscoveryInterface {
 	if c == nil {
@@ -104,6 +112,10 @@ func NewForConfigAndClient(c *rest.Config, httpClient *http.Client) (*Clientset,
 	if err != nil {
 		return nil, err
 	}
+	cs.nvidiaV1beta1, err = nvidiav1beta1.NewForConfigAndClient(&configShallowCopy, httpClient)
+	if err != nil {
+		return nil, err
+	}
 
 	cs.DiscoveryClient, err = discovery.NewDiscoveryClientForConfigAndClient(&configShallowCopy, httpClient)
 	if err != nil {
@@ -127,6 +139,7 @@ func New(c rest.Interface) *Clientset {
 	var cs Clientset
 	cs.nvidiaV1 = nvidiav1.New(c)
 	cs.nvidiaV1alpha1 = nvidiav1alpha1.New(c)
+	cs.nvidiaV1beta1 = nvidiav1beta1.New(c)
 
 	cs.DiscoveryClient = discovery.NewDiscoveryClient(c)
 	return &cs
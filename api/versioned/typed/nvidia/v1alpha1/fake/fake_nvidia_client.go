@@ -36,6 +36,10 @@ func (c *FakeNvidiaV1alpha1) NVIDIADrivers() v1alpha1.NVIDIADriverInterface {
 	return newFakeNVIDIADrivers(c)
 }
 
+func (c *FakeNvidiaV1alpha1) NodeUpgradeHistories() v1alpha1.NodeUpgradeHistoryInterface {
+	return newFakeNodeUpgradeHistories(c)
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeNvidiaV1alpha1) RESTClient() rest.Interface {
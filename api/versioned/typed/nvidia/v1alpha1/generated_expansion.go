@@ -21,3 +21,5 @@ package v1alpha1
 type GPUClusterExpansion interface{}
 
 type NVIDIADriverExpansion interface{}
+
+type NodeUpgradeHistoryExpansion interface{}
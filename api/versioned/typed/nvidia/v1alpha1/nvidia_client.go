@@ -30,6 +30,7 @@ type NvidiaV1alpha1Interface interface {
 	RESTClient() rest.Interface
 	GPUClustersGetter
 	NVIDIADriversGetter
+	NodeUpgradeHistoriesGetter
 }
 
 // NvidiaV1alpha1Client is used to interact with features provided by the nvidia group.
@@ -45,6 +46,10 @@ func (c *NvidiaV1alpha1Client) NVIDIADrivers() NVIDIADriverInterface {
 	return newNVIDIADrivers(c)
 }
 
+func (c *NvidiaV1alpha1Client) NodeUpgradeHistories() NodeUpgradeHistoryInterface {
+	return newNodeUpgradeHistories(c)
+}
+
 // NewForConfig creates a new NvidiaV1alpha1Client for the given config.
 // NewForConfig is equivalent to NewForConfigAndClient(c, httpClient),
 // where httpClient was generated with rest.HTTPClientFor(c).
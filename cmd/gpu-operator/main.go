@@ -49,11 +49,14 @@ import (
 
 	clusterpolicyv1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1"
 	nvidiav1alpha1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1alpha1"
+	nvidiav1beta1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1beta1"
 	"github.com/NVIDIA/gpu-operator/controllers"
 	"github.com/NVIDIA/gpu-operator/controllers/clusterinfo"
 	"github.com/NVIDIA/gpu-operator/internal/consts"
+	"github.com/NVIDIA/gpu-operator/internal/drift"
 	"github.com/NVIDIA/gpu-operator/internal/info"
 	"github.com/NVIDIA/gpu-operator/internal/predicates"
+	"github.com/NVIDIA/gpu-operator/internal/utils"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -67,6 +70,7 @@ func init() {
 	utilruntime.Must(clusterpolicyv1.AddToScheme(scheme))
 	utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
 	utilruntime.Must(nvidiav1alpha1.AddToScheme(scheme))
+	utilruntime.Must(nvidiav1beta1.AddToScheme(scheme))
 	utilruntime.Must(promv1.AddToScheme(scheme))
 	utilruntime.Must(secv1.Install(scheme))
 	utilruntime.Must(apiconfigv1.Install(scheme))
@@ -79,6 +83,9 @@ func main() {
 	var leaderElectionNamespace string
 	var probeAddr string
 	var renewDeadline time.Duration
+	var enableWebhooks bool
+	var driftDetectionInterval time.Duration
+	var driftPolicy string
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
@@ -93,6 +100,15 @@ func main() {
 			"Only enabled when the --leader-elect flag is set. "+
 			"If undefined, the renew deadline defaults to the controller-runtime manager's default RenewDeadline. "+
 			"By setting this option, the LeaseDuration is also set as RenewDealine + 5s.")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", false,
+		"Enable the admission webhooks for the operator CRDs and the NVIDIADriver conversion webhook. "+
+			"Requires a serving certificate mounted in the webhook server's certificate directory.")
+	flag.DurationVar(&driftDetectionInterval, "drift-detection-interval", 0,
+		"Set the interval (e.g. \"5m\") between two checks of the operator managed objects for changes made out of band. "+
+			"Drift detection is disabled unless set.")
+	flag.StringVar(&driftPolicy, "drift-policy", string(drift.PolicyReport),
+		"Set what happens to the operator managed objects that drifted from their desired state: "+
+			"\"report\" only reports them, \"correct\" also restores their desired state.")
 
 	opts := zap.Options{
 		StacktraceLevel: zapcore.PanicLevel,
@@ -103,6 +119,12 @@ func main() {
 	logger := zap.New(zap.UseFlagOptions(&opts))
 	ctrl.SetLogger(logger)
 
+	parsedDriftPolicy, err := drift.ParsePolicy(driftPolicy)
+	if err != nil {
+		ctrl.Log.Error(err, "invalid --drift-policy")
+		os.Exit(1)
+	}
+
 	ctrl.Log.Info(fmt.Sprintf("version: %s", info.GetVersionString()))
 
 	metricsOptions := metricsserver.Options{
@@ -161,9 +183,12 @@ func main() {
 	setupLog.Info("initializing operator metrics")
 	operatorMetrics := controllers.InitOperatorMetrics()
 
+	// the controllers applying the operands share the record of their upgraded managed fields
+	applyClient := utils.WithManagedFieldsCache(mgr.GetClient())
+
 	if err = (&controllers.ClusterPolicyReconciler{
 		Namespace:       operatorNamespace,
-		Client:          mgr.GetClient(),
+		Client:          applyClient,
 		Log:             ctrl.Log.WithName("controllers").WithName("ClusterPolicy"),
 		Scheme:          mgr.GetScheme(),
 		OperatorMetrics: operatorMetrics,
@@ -175,6 +200,7 @@ func main() {
 	// setup upgrade controller
 	upgrade.SetDriverName("gpu")
 	upgradeLogger := ctrl.Log.WithName("controllers").WithName("Upgrade")
+	gpuPodFilter := gpuPodSpecFilter(ctx, mgr.GetAPIReader())
 	clusterUpgradeStateManager, err := upgrade.NewClusterUpgradeStateManager(
 		upgradeLogger,
 		mgr.GetConfig(),
@@ -188,7 +214,7 @@ func main() {
 		os.Exit(1)
 	}
 	clusterUpgradeStateManager = clusterUpgradeStateManager.
-		WithPodDeletionEnabled(gpuPodSpecFilter(ctx, mgr.GetAPIReader())).
+		WithPodDeletionEnabled(gpuPodFilter).
 		WithValidationEnabled("app=nvidia-operator-validator").
 		WithRestartOnlyPredicate(predicates.DriverPodRestartOnly(upgradeLogger))
 
@@ -199,6 +225,8 @@ func main() {
 		StateManager:      clusterUpgradeStateManager,
 		OperatorMetrics:   operatorMetrics,
 		OperatorNamespace: operatorNamespace,
+		APIReader:         mgr.GetAPIReader(),
+		GPUPodFilter:      gpuPodFilter,
 	}).SetupWithManager(ctx, mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Upgrade")
 		os.Exit(1)
@@ -216,7 +244,7 @@ func main() {
 
 	if err = (&controllers.NVIDIADriverReconciler{
 		Namespace:   operatorNamespace,
-		Client:      mgr.GetClient(),
+		Client:      applyClient,
 		Scheme:      mgr.GetScheme(),
 		ClusterInfo: clusterInfo,
 	}).SetupWithManager(ctx, mgr); err != nil {
@@ -234,15 +262,56 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err = (&controllers.GPUHealthReconciler{
+		Namespace: operatorNamespace,
+		Client:    mgr.GetClient(),
+		Scheme:    mgr.GetScheme(),
+		Log:       ctrl.Log.WithName("controllers").WithName("GPUHealth"),
+	}).SetupWithManager(ctx, mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GPUHealth")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.ValidationStatusReconciler{
+		Namespace: operatorNamespace,
+		Client:    mgr.GetClient(),
+		Scheme:    mgr.GetScheme(),
+		Log:       ctrl.Log.WithName("controllers").WithName("ValidationStatus"),
+	}).SetupWithManager(ctx, mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ValidationStatus")
+		os.Exit(1)
+	}
+
 	if err = (&controllers.GPUClusterReconciler{
 		Namespace:   operatorNamespace,
-		Client:      mgr.GetClient(),
+		Client:      applyClient,
 		Scheme:      mgr.GetScheme(),
 		ClusterInfo: clusterInfo,
 	}).SetupWithManager(ctx, mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "GPUCluster")
 		os.Exit(1)
 	}
+
+	if driftDetectionInterval > 0 {
+		if err = (&controllers.DriftReconciler{
+			Namespace:       operatorNamespace,
+			Client:          applyClient,
+			Scheme:          mgr.GetScheme(),
+			ClusterInfo:     clusterInfo,
+			OperatorMetrics: operatorMetrics,
+			Policy:          parsedDriftPolicy,
+			Interval:        driftDetectionInterval,
+		}).SetupWithManager(ctx, mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "Drift")
+			os.Exit(1)
+		}
+	}
+	if enableWebhooks {
+		if err = controllers.SetupWebhooksWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhooks")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 	if err := mgr.AddHealthzCheck("health", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
@@ -0,0 +1,245 @@
+/**
+# Copyright (c), NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package diff
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/NVIDIA/gpu-operator/cmd/gpuop-cfg/render"
+	"github.com/NVIDIA/gpu-operator/controllers"
+)
+
+type command struct {
+	logger *logrus.Logger
+}
+
+type options struct {
+	old             string
+	new             string
+	kubeconfig      string
+	failOnReinstall bool
+	cluster         render.ClusterOptions
+}
+
+// NewCommand constructs a diff command with the specified logger
+func NewCommand(logger *logrus.Logger) *cli.Command {
+	c := command{
+		logger: logger,
+	}
+	return c.build()
+}
+
+// build creates the CLI command
+func (m command) build() *cli.Command {
+	opts := options{}
+
+	// Create the 'diff' command
+	c := cli.Command{
+		Name:  "diff",
+		Usage: "Show the changes to the objects the GPU Operator deploys between two versions of a ClusterPolicy, NVIDIADriver or GPUCluster, and the driver reinstalls they trigger",
+		Description: `Both versions are rendered as with the 'render' command and the objects are compared field by field.
+If --old is not given, the new version is compared against the objects currently deployed in the cluster
+of the kubeconfig. A change of a driver DaemonSet reinstalls the driver on its nodes only if it changes the
+DRIVER_CONFIG_DIGEST of the pod template, any other change restarts the driver pods in place.`,
+		Before: func(c context.Context, cli *cli.Command) (context.Context, error) {
+			return c, m.validateFlags(c, &opts)
+		},
+		Action: func(c context.Context, cli *cli.Command) error {
+			return m.run(c, &opts)
+		},
+	}
+
+	c.Flags = []cli.Flag{
+		&cli.StringFlag{
+			Name:        "old",
+			Usage:       "Specify the file containing the current ClusterPolicy, NVIDIADriver or GPUCluster yaml. If not set, the objects deployed in the cluster are compared against",
+			Destination: &opts.old,
+		},
+		&cli.StringFlag{
+			Name:        "new",
+			Usage:       "Specify the file containing the updated ClusterPolicy, NVIDIADriver or GPUCluster yaml. If this is '-' the file is read from STDIN",
+			Value:       "-",
+			Destination: &opts.new,
+		},
+		&cli.StringFlag{
+			Name:        "kubeconfig",
+			Usage:       "Specify the kubeconfig of the cluster to compare against when --old is not set. Nodes and cluster properties not given by --nodes and --cluster-info are read from this cluster",
+			Sources:     cli.EnvVars("KUBECONFIG"),
+			Destination: &opts.kubeconfig,
+		},
+		&cli.BoolFlag{
+			Name:        "fail-on-reinstall",
+			Usage:       "Exit with an error if the changes reinstall the driver",
+			Destination: &opts.failOnReinstall,
+		},
+	}
+	c.Flags = append(c.Flags, opts.cluster.Flags()...)
+
+	return &c
+}
+
+func (m command) validateFlags(ctx context.Context, opts *options) error {
+	if opts.old == "-" && opts.new == "-" {
+		return fmt.Errorf("only one of --old and --new can be read from STDIN")
+	}
+	if opts.old != "" && opts.cluster.Nodes == "" && opts.cluster.ClusterInfo == "" {
+		return fmt.Errorf("at least one of --nodes and --cluster-info must be specified with --old")
+	}
+	if opts.cluster.Namespace == "" {
+		return fmt.Errorf("--namespace must not be empty")
+	}
+	return nil
+}
+
+func (m command) run(ctx context.Context, opts *options) error {
+	newCR, err := render.LoadCustomResource(opts.new)
+	if err != nil {
+		return fmt.Errorf("failed to load new custom resource: %v", err)
+	}
+
+	renderOpts, err := opts.cluster.RenderOptions()
+	if err != nil {
+		return err
+	}
+	renderOpts.Logger = render.NewControllerLogger(m.logger)
+
+	var oldObjs, newObjs []*unstructured.Unstructured
+	if opts.old != "" {
+		oldObjs, newObjs, err = m.renderBoth(ctx, opts.old, newCR, renderOpts)
+	} else {
+		oldObjs, newObjs, err = m.renderAgainstCluster(ctx, opts, newCR, renderOpts)
+	}
+	if err != nil {
+		return err
+	}
+
+	diffs, unchanged := diffObjects(oldObjs, newObjs)
+	reinstalls := printDiffs(os.Stdout, diffs, unchanged)
+	if opts.failOnReinstall && reinstalls > 0 {
+		return fmt.Errorf("the changes reinstall the driver of %d DaemonSet(s)", reinstalls)
+	}
+	return nil
+}
+
+// renderBoth renders the objects of the custom resource in the old file and of newCR
+func (m command) renderBoth(ctx context.Context, oldPath string, newCR client.Object, renderOpts controllers.RenderOptions) ([]*unstructured.Unstructured, []*unstructured.Unstructured, error) {
+	oldCR, err := render.LoadCustomResource(oldPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load old custom resource: %v", err)
+	}
+	if reflect.TypeOf(oldCR) != reflect.TypeOf(newCR) {
+		return nil, nil, fmt.Errorf("cannot compare %T with %T", oldCR, newCR)
+	}
+
+	oldObjs, err := controllers.Render(ctx, oldCR, renderOpts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to render old objects: %v", err)
+	}
+	newObjs, err := controllers.Render(ctx, newCR, renderOpts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to render new objects: %v", err)
+	}
+	return oldObjs, newObjs, nil
+}
+
+// renderAgainstCluster renders the objects of newCR for the cluster of the kubeconfig
+// and returns them along with the objects currently deployed there
+func (m command) renderAgainstCluster(ctx context.Context, opts *options, newCR client.Object, renderOpts controllers.RenderOptions) ([]*unstructured.Unstructured, []*unstructured.Unstructured, error) {
+	config, err := getRESTConfig(opts.kubeconfig)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get kubeconfig: %v", err)
+	}
+	c, err := client.New(config, client.Options{Scheme: controllers.NewRenderScheme()})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create client: %v", err)
+	}
+
+	discoverInfo := opts.cluster.ClusterInfo == ""
+	discoverNodes := opts.cluster.ClusterInfo == "" && opts.cluster.Nodes == ""
+	if err := discoverRenderOptions(ctx, config, c, &renderOpts, discoverInfo, discoverNodes); err != nil {
+		return nil, nil, err
+	}
+
+	// The names of the NVIDIADriver objects and the owner references are derived
+	// from the UID of the custom resource
+	current := newCR.DeepCopyObject().(client.Object)
+	err = c.Get(ctx, client.ObjectKeyFromObject(newCR), current)
+	switch {
+	case apierrors.IsNotFound(err):
+		m.logger.Infof("%T %s does not exist in the cluster", newCR, newCR.GetName())
+	case err != nil:
+		return nil, nil, fmt.Errorf("failed to get %T %s: %v", newCR, newCR.GetName(), err)
+	default:
+		newCR.SetUID(current.GetUID())
+	}
+
+	newObjs, err := controllers.Render(ctx, newCR, renderOpts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to render objects: %v", err)
+	}
+	liveObjs, err := getLiveObjects(ctx, c, opts.cluster.Namespace, newObjs, newCR)
+	if err != nil {
+		return nil, nil, err
+	}
+	return liveObjs, newObjs, nil
+}
+
+// printDiffs writes the diffs to w and returns the number of driver DaemonSets that
+// reinstall the driver
+func printDiffs(w io.Writer, diffs []objectDiff, unchanged int) int {
+	var added, removed, changed, reinstalls int
+	for _, d := range diffs {
+		switch {
+		case d.Old == nil:
+			added++
+			fmt.Fprintf(w, "+ %s\n", d.Key)
+		case d.New == nil:
+			removed++
+			fmt.Fprintf(w, "- %s\n", d.Key)
+		default:
+			changed++
+			fmt.Fprintf(w, "~ %s\n", d.Key)
+			for _, change := range d.Changes {
+				fmt.Fprintf(w, "    %s\n", change)
+			}
+		}
+		if d.Driver != nil {
+			fmt.Fprintf(w, "  ! %s\n", d.Driver)
+		}
+		if d.Driver.Reinstall() {
+			reinstalls++
+		}
+	}
+
+	fmt.Fprintf(w, "\n%d added, %d removed, %d changed, %d unchanged\n", added, removed, changed, unchanged)
+	if reinstalls > 0 {
+		fmt.Fprintf(w, "%d driver DaemonSet(s) reinstall the driver on their nodes\n", reinstalls)
+	} else {
+		fmt.Fprintf(w, "No driver reinstall\n")
+	}
+	return reinstalls
+}
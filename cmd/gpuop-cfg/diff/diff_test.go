@@ -0,0 +1,271 @@
+/**
+# Copyright (c), NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package diff
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	driverconfig "github.com/NVIDIA/gpu-operator/internal/config"
+	"github.com/NVIDIA/gpu-operator/internal/consts"
+	"github.com/NVIDIA/gpu-operator/internal/utils"
+)
+
+func TestNewCommand(t *testing.T) {
+	cmd := NewCommand(logrus.New())
+
+	require.NotNil(t, cmd)
+	assert.Equal(t, "diff", cmd.Name)
+	assert.NotEmpty(t, cmd.Usage)
+
+	names := []string{}
+	for _, flag := range cmd.Flags {
+		names = append(names, flag.Names()...)
+	}
+	assert.ElementsMatch(t, []string{"old", "new", "kubeconfig", "fail-on-reinstall", "nodes", "cluster-info", "namespace", "assets-dir", "manifests-dir"}, names)
+}
+
+func newConfigMap(t *testing.T, name string, data map[string]string) *unstructured.Unstructured {
+	t.Helper()
+	obj, err := utils.ToUnstructured(&corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "gpu-operator"},
+		Data:       data,
+	})
+	require.NoError(t, err)
+	return obj
+}
+
+func newDriverDaemonSet(t *testing.T, image string, labels map[string]string) *unstructured.Unstructured {
+	t.Helper()
+	spec := corev1.PodSpec{
+		InitContainers: []corev1.Container{{
+			Name:  "k8s-driver-manager",
+			Image: "nvcr.io/nvidia/cloud-native/k8s-driver-manager:v0.8.0",
+		}},
+		Containers: []corev1.Container{{
+			Name:  "nvidia-driver-ctr",
+			Image: image,
+			Env:   []corev1.EnvVar{{Name: "NVIDIA_VISIBLE_DEVICES", Value: "void"}},
+		}},
+	}
+	digest := fakeConfigDigest(&spec)
+	for _, containers := range [][]corev1.Container{spec.InitContainers, spec.Containers} {
+		containers[0].Env = append(containers[0].Env, corev1.EnvVar{Name: driverconfig.DriverConfigDigestEnvName, Value: digest})
+	}
+
+	obj, err := utils.ToUnstructured(&appsv1.DaemonSet{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "DaemonSet"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "nvidia-driver-daemonset",
+			Namespace:   "gpu-operator",
+			Labels:      labels,
+			Annotations: map[string]string{consts.NvidiaAnnotationHashKey: image + labels["team"]},
+		},
+		Spec: appsv1.DaemonSetSpec{Template: corev1.PodTemplateSpec{Spec: spec}},
+	})
+	require.NoError(t, err)
+	return obj
+}
+
+// fakeConfigDigest stands in for the digest the operator computes, which only needs to
+// differ when the install state does
+func fakeConfigDigest(spec *corev1.PodSpec) string {
+	return driverconfig.DriverInstallStateFromPodSpec(spec).DriverImage
+}
+
+func TestDiffObjects(t *testing.T) {
+	oldObjs := []*unstructured.Unstructured{
+		newConfigMap(t, "unchanged", map[string]string{"a": "1"}),
+		newConfigMap(t, "changed", map[string]string{"a": "1", "b": "2"}),
+		newConfigMap(t, "removed", nil),
+	}
+	newObjs := []*unstructured.Unstructured{
+		newConfigMap(t, "added", nil),
+		newConfigMap(t, "changed", map[string]string{"a": "2", "c": "3"}),
+		newConfigMap(t, "unchanged", map[string]string{"a": "1"}),
+	}
+
+	diffs, unchanged := diffObjects(oldObjs, newObjs)
+	require.Equal(t, 1, unchanged)
+	require.Len(t, diffs, 3)
+
+	assert.Equal(t, "ConfigMap gpu-operator/added", diffs[0].Key.String())
+	assert.Nil(t, diffs[0].Old)
+
+	assert.Equal(t, "ConfigMap gpu-operator/changed", diffs[1].Key.String())
+	assert.Equal(t, []fieldChange{
+		{Path: "data.a", Old: "1", New: "2"},
+		{Path: "data.b", Old: "2"},
+		{Path: "data.c", New: "3"},
+	}, diffs[1].Changes)
+
+	assert.Equal(t, "ConfigMap gpu-operator/removed", diffs[2].Key.String())
+	assert.Nil(t, diffs[2].New)
+	for _, d := range diffs {
+		assert.Nil(t, d.Driver)
+	}
+}
+
+func TestDiffValues(t *testing.T) {
+	old := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{consts.NvidiaAnnotationHashKey: "1", "nvidia.com/foo": "a"},
+		},
+		"containers": []interface{}{
+			map[string]interface{}{"name": "a", "image": "a:1"},
+			map[string]interface{}{"name": "b", "image": "b:1"},
+		},
+		"args": []interface{}{"--x", "--y"},
+		"cmd":  []interface{}{"run"},
+	}
+	new := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{consts.NvidiaAnnotationHashKey: "2", "nvidia.com/foo": "b"},
+		},
+		"containers": []interface{}{
+			map[string]interface{}{"name": "b", "image": "b:2"},
+			map[string]interface{}{"name": "a", "image": "a:1"},
+		},
+		"args": []interface{}{"--x", "--z"},
+		"cmd":  []interface{}{"run", "--once"},
+	}
+
+	var changes []fieldChange
+	diffValues("", old, new, &changes)
+	assert.Equal(t, []string{
+		`args[1]: "--y" -> "--z"`,
+		`cmd: ["run"] -> ["run","--once"]`,
+		`containers[b].image: "b:1" -> "b:2"`,
+		`metadata.annotations["nvidia.com/foo"]: "a" -> "b"`,
+	}, changeStrings(changes))
+}
+
+func changeStrings(changes []fieldChange) []string {
+	var s []string
+	for _, c := range changes {
+		s = append(s, c.String())
+	}
+	return s
+}
+
+func TestDriverChange(t *testing.T) {
+	oldDS := newDriverDaemonSet(t, "nvcr.io/nvidia/driver:570.172.08", nil)
+
+	// A label change restarts the driver pods without reinstalling the driver
+	diffs, _ := diffObjects([]*unstructured.Unstructured{oldDS},
+		[]*unstructured.Unstructured{newDriverDaemonSet(t, "nvcr.io/nvidia/driver:570.172.08", map[string]string{"team": "gpu"})})
+	require.Len(t, diffs, 1)
+	assert.Equal(t, []string{`metadata.labels: added {"team":"gpu"}`}, changeStrings(diffs[0].Changes))
+	require.NotNil(t, diffs[0].Driver)
+	assert.False(t, diffs[0].Driver.Reinstall())
+	assert.Contains(t, diffs[0].Driver.String(), "no driver reinstall")
+
+	// A new driver version reinstalls the driver
+	diffs, _ = diffObjects([]*unstructured.Unstructured{oldDS},
+		[]*unstructured.Unstructured{newDriverDaemonSet(t, "nvcr.io/nvidia/driver:580.65.06", nil)})
+	require.Len(t, diffs, 1)
+	require.True(t, diffs[0].Driver.Reinstall())
+	assert.Equal(t, []string{"DriverImage"}, diffs[0].Driver.ChangedFields)
+	assert.Contains(t, diffs[0].Driver.String(), "DRIVER REINSTALL")
+
+	// Adding and removing driver DaemonSets is reported, but is not a reinstall
+	diffs, _ = diffObjects(nil, []*unstructured.Unstructured{oldDS})
+	require.Len(t, diffs, 1)
+	assert.True(t, diffs[0].Driver.Added)
+	assert.False(t, diffs[0].Driver.Reinstall())
+	diffs, _ = diffObjects([]*unstructured.Unstructured{oldDS}, nil)
+	require.Len(t, diffs, 1)
+	assert.True(t, diffs[0].Driver.Removed)
+	assert.False(t, diffs[0].Driver.Reinstall())
+}
+
+func TestPrintDiffs(t *testing.T) {
+	oldObjs := []*unstructured.Unstructured{
+		newDriverDaemonSet(t, "nvcr.io/nvidia/driver:570.172.08", nil),
+		newConfigMap(t, "removed", nil),
+	}
+	newObjs := []*unstructured.Unstructured{
+		newDriverDaemonSet(t, "nvcr.io/nvidia/driver:580.65.06", nil),
+		newConfigMap(t, "added", nil),
+	}
+	diffs, unchanged := diffObjects(oldObjs, newObjs)
+
+	out := &bytes.Buffer{}
+	reinstalls := printDiffs(out, diffs, unchanged)
+	assert.Equal(t, 1, reinstalls)
+	assert.Equal(t, `~ DaemonSet gpu-operator/nvidia-driver-daemonset
+    spec.template.spec.containers[nvidia-driver-ctr].env[DRIVER_CONFIG_DIGEST].value: "nvcr.io/nvidia/driver:570.172.08" -> "nvcr.io/nvidia/driver:580.65.06"
+    spec.template.spec.containers[nvidia-driver-ctr].image: "nvcr.io/nvidia/driver:570.172.08" -> "nvcr.io/nvidia/driver:580.65.06"
+    spec.template.spec.initContainers[k8s-driver-manager].env[DRIVER_CONFIG_DIGEST].value: "nvcr.io/nvidia/driver:570.172.08" -> "nvcr.io/nvidia/driver:580.65.06"
+  ! DRIVER REINSTALL on every node of this DaemonSet: DRIVER_CONFIG_DIGEST nvcr.io/nvidia/driver:570.172.08 -> nvcr.io/nvidia/driver:580.65.06 (changed: DriverImage)
++ ConfigMap gpu-operator/added
+- ConfigMap gpu-operator/removed
+
+1 added, 1 removed, 1 changed, 0 unchanged
+1 driver DaemonSet(s) reinstall the driver on their nodes
+`, out.String())
+}
+
+func TestPrune(t *testing.T) {
+	live := map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "ds", "resourceVersion": "12", "uid": "abc"},
+		"spec": map[string]interface{}{
+			"revisionHistoryLimit": int64(10),
+			"containers": []interface{}{
+				map[string]interface{}{"name": "a", "image": "a:1", "terminationMessagePath": "/dev/termination-log"},
+				map[string]interface{}{"name": "b", "image": "b:1"},
+			},
+			"tolerations": []interface{}{
+				map[string]interface{}{"key": "x", "operator": "Exists"},
+			},
+		},
+		"status": map[string]interface{}{"numberReady": int64(1)},
+	}
+	desired := map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "ds"},
+		"spec": map[string]interface{}{
+			"containers": []interface{}{
+				map[string]interface{}{"name": "a", "image": "a:2"},
+			},
+			"tolerations": []interface{}{
+				map[string]interface{}{"key": "x"},
+			},
+		},
+	}
+
+	assert.Equal(t, map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "ds"},
+		"spec": map[string]interface{}{
+			"containers": []interface{}{
+				map[string]interface{}{"name": "a", "image": "a:1"},
+				map[string]interface{}{"name": "b", "image": "b:1"},
+			},
+			"tolerations": []interface{}{
+				map[string]interface{}{"key": "x"},
+			},
+		},
+	}, prune(live, desired))
+}
@@ -0,0 +1,106 @@
+/**
+# Copyright (c), NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	driverconfig "github.com/NVIDIA/gpu-operator/internal/config"
+)
+
+// driverChange describes how a change of a driver DaemonSet affects the driver
+// installed on its nodes. The driver is only reinstalled when the DRIVER_CONFIG_DIGEST
+// of the pod template changes, any other change restarts the driver pods in place.
+type driverChange struct {
+	Added     bool
+	Removed   bool
+	OldDigest string
+	NewDigest string
+	// ChangedFields are the DriverInstallState fields that differ between the old
+	// and the new pod template
+	ChangedFields []string
+}
+
+// newDriverChange returns the driver change for the old and new version of an
+// object, or nil if the object is not a driver DaemonSet. Either version may be nil.
+func newDriverChange(old, new *unstructured.Unstructured) *driverChange {
+	oldSpec := daemonSetPodSpec(old)
+	newSpec := daemonSetPodSpec(new)
+
+	change := &driverChange{
+		Added:     oldSpec == nil,
+		Removed:   newSpec == nil,
+		OldDigest: driverconfig.DriverConfigDigestFromPodSpec(oldSpec),
+		NewDigest: driverconfig.DriverConfigDigestFromPodSpec(newSpec),
+	}
+	if change.OldDigest == "" && change.NewDigest == "" {
+		return nil
+	}
+	if oldSpec != nil && newSpec != nil {
+		change.ChangedFields = driverconfig.DriverInstallStateFromPodSpec(oldSpec).
+			ChangedFields(driverconfig.DriverInstallStateFromPodSpec(newSpec))
+	}
+	return change
+}
+
+// Reinstall returns whether the change reinstalls the driver on the nodes of an
+// existing driver DaemonSet
+func (c *driverChange) Reinstall() bool {
+	return c != nil && !c.Added && !c.Removed && c.OldDigest != c.NewDigest
+}
+
+func (c *driverChange) String() string {
+	switch {
+	case c.Added:
+		return "driver is installed on the nodes selected by this DaemonSet"
+	case c.Removed:
+		return "driver is removed from the nodes selected by this DaemonSet"
+	case !c.Reinstall():
+		return fmt.Sprintf("no driver reinstall: %s is unchanged", driverconfig.DriverConfigDigestEnvName)
+	}
+	reason := "install configuration not visible in the pod template changed"
+	if len(c.ChangedFields) > 0 {
+		reason = "changed: " + strings.Join(c.ChangedFields, ", ")
+	}
+	return fmt.Sprintf("DRIVER REINSTALL on every node of this DaemonSet: %s %s -> %s (%s)",
+		driverconfig.DriverConfigDigestEnvName, formatDigest(c.OldDigest), formatDigest(c.NewDigest), reason)
+}
+
+func formatDigest(digest string) string {
+	if digest == "" {
+		return "<none>"
+	}
+	return digest
+}
+
+// daemonSetPodSpec returns the pod spec of obj if it is a DaemonSet
+func daemonSetPodSpec(obj *unstructured.Unstructured) *corev1.PodSpec {
+	if obj == nil || obj.GroupVersionKind() != appsv1.SchemeGroupVersion.WithKind("DaemonSet") {
+		return nil
+	}
+	ds := &appsv1.DaemonSet{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, ds); err != nil {
+		return nil
+	}
+	return &ds.Spec.Template.Spec
+}
@@ -0,0 +1,195 @@
+/**
+# Copyright (c), NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package diff
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlconfig "sigs.k8s.io/controller-runtime/pkg/client/config"
+
+	"github.com/NVIDIA/gpu-operator/controllers"
+	"github.com/NVIDIA/gpu-operator/controllers/clusterinfo"
+	"github.com/NVIDIA/gpu-operator/internal/fieldpath"
+)
+
+// getRESTConfig returns the config to connect to the cluster, from the kubeconfig
+// file if one is given and following the usual kubeconfig resolution otherwise
+func getRESTConfig(kubeconfig string) (*rest.Config, error) {
+	if kubeconfig != "" {
+		return clientcmd.BuildConfigFromFlags("", kubeconfig)
+	}
+	return ctrlconfig.GetConfig()
+}
+
+// discoverRenderOptions fills in the cluster properties of opts that were not given
+// on the command line from the cluster itself
+func discoverRenderOptions(ctx context.Context, config *rest.Config, c client.Client, opts *controllers.RenderOptions, discoverInfo bool, discoverNodes bool) error {
+	if discoverInfo {
+		discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+		if err != nil {
+			return fmt.Errorf("failed to create discovery client: %v", err)
+		}
+		version, err := discoveryClient.ServerVersion()
+		if err != nil {
+			return fmt.Errorf("failed to get the kubernetes version: %v", err)
+		}
+		opts.KubernetesVersion = version.GitVersion
+
+		info, err := clusterinfo.New(ctx, clusterinfo.WithKubernetesConfig(config), clusterinfo.WithOneShot(true))
+		if err != nil {
+			return fmt.Errorf("failed to get cluster info: %v", err)
+		}
+		if opts.ContainerRuntime, err = info.GetContainerRuntime(); err != nil {
+			return err
+		}
+		if opts.OpenshiftVersion, err = info.GetOpenshiftVersion(); err != nil {
+			return err
+		}
+		if opts.OpenshiftVersion != "" {
+			opts.OpenshiftDriverToolkitImages = info.GetOpenshiftDriverToolkitImages()
+			if opts.OpenshiftProxySpec, err = info.GetOpenshiftProxySpec(); err != nil {
+				return err
+			}
+		}
+		gvr, supported, err := info.GetDRAResourceGVR()
+		if err != nil {
+			return err
+		}
+		opts.DRAResourceAPIVersion = ""
+		if supported {
+			opts.DRAResourceAPIVersion = gvr.GroupVersion().String()
+		}
+	}
+
+	if discoverNodes {
+		nodes := &corev1.NodeList{}
+		if err := c.List(ctx, nodes); err != nil {
+			return fmt.Errorf("failed to list nodes: %v", err)
+		}
+		opts.Nodes = nodes.Items
+	}
+	return nil
+}
+
+// getLiveObjects returns the objects currently deployed in the cluster for the
+// desired objects, pruned to the fields set in the desired objects. Objects that do
+// not exist are left out. Driver DaemonSets owned by the custom resource that are no
+// longer desired are returned as well, so that their removal is reported.
+func getLiveObjects(ctx context.Context, c client.Client, namespace string, desired []*unstructured.Unstructured, owner client.Object) ([]*unstructured.Unstructured, error) {
+	var live []*unstructured.Unstructured
+	desiredKeys := map[objectKey]bool{}
+	for _, obj := range desired {
+		desiredKeys[keyOf(obj)] = true
+
+		current := &unstructured.Unstructured{}
+		current.SetGroupVersionKind(obj.GroupVersionKind())
+		err := c.Get(ctx, client.ObjectKeyFromObject(obj), current)
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s: %v", keyOf(obj), err)
+		}
+		current.Object = prune(current.Object, obj.Object).(map[string]interface{})
+		live = append(live, current)
+	}
+
+	if owner.GetUID() == "" {
+		return live, nil
+	}
+	daemonSets := &unstructured.UnstructuredList{}
+	daemonSets.SetGroupVersionKind(appsv1.SchemeGroupVersion.WithKind("DaemonSetList"))
+	if err := c.List(ctx, daemonSets, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list DaemonSets: %v", err)
+	}
+	for i := range daemonSets.Items {
+		ds := &daemonSets.Items[i]
+		if desiredKeys[keyOf(ds)] || !ownedBy(ds, owner) || newDriverChange(ds, nil) == nil {
+			continue
+		}
+		live = append(live, ds)
+	}
+	return live, nil
+}
+
+func ownedBy(obj client.Object, owner client.Object) bool {
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.UID == owner.GetUID() {
+			return true
+		}
+	}
+	return false
+}
+
+// prune returns the parts of the live value that are set in the desired value, which
+// leaves out the status and the fields defaulted by the API server. List elements
+// missing from the desired value are kept, so that their removal shows in the diff.
+func prune(live, desired interface{}) interface{} {
+	switch liveValue := live.(type) {
+	case map[string]interface{}:
+		desiredValue, ok := desired.(map[string]interface{})
+		if !ok {
+			return live
+		}
+		pruned := map[string]interface{}{}
+		for key, value := range liveValue {
+			if desiredField, ok := desiredValue[key]; ok {
+				pruned[key] = prune(value, desiredField)
+			}
+		}
+		return pruned
+	case []interface{}:
+		desiredValue, ok := desired.([]interface{})
+		if !ok {
+			return live
+		}
+		liveNames, liveNamed := fieldpath.ElementNames(liveValue)
+		desiredNames, desiredNamed := fieldpath.ElementNames(desiredValue)
+		pruned := make([]interface{}, len(liveValue))
+		switch {
+		case liveNamed && desiredNamed:
+			desiredByName := map[string]interface{}{}
+			for i, name := range desiredNames {
+				desiredByName[name] = desiredValue[i]
+			}
+			for i, name := range liveNames {
+				pruned[i] = liveValue[i]
+				if desiredElem, ok := desiredByName[name]; ok {
+					pruned[i] = prune(liveValue[i], desiredElem)
+				}
+			}
+		case len(liveValue) == len(desiredValue):
+			for i := range liveValue {
+				pruned[i] = prune(liveValue[i], desiredValue[i])
+			}
+		default:
+			copy(pruned, liveValue)
+		}
+		return pruned
+	}
+	return live
+}
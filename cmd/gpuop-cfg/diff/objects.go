@@ -0,0 +1,188 @@
+/**
+# Copyright (c), NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/NVIDIA/gpu-operator/internal/consts"
+	"github.com/NVIDIA/gpu-operator/internal/fieldpath"
+)
+
+// ignoredPaths are the fields left out of the diff. The hash annotation is derived
+// from the rest of the object, so it changes whenever anything else does.
+var ignoredPaths = map[string]bool{
+	fmt.Sprintf("metadata.annotations[%q]", consts.NvidiaAnnotationHashKey): true,
+}
+
+// objectKey identifies an object across the old and the new set of objects
+type objectKey struct {
+	gvk       schema.GroupVersionKind
+	namespace string
+	name      string
+}
+
+func keyOf(obj *unstructured.Unstructured) objectKey {
+	return objectKey{gvk: obj.GroupVersionKind(), namespace: obj.GetNamespace(), name: obj.GetName()}
+}
+
+func (k objectKey) String() string {
+	if k.namespace == "" {
+		return fmt.Sprintf("%s %s", k.gvk.Kind, k.name)
+	}
+	return fmt.Sprintf("%s %s/%s", k.gvk.Kind, k.namespace, k.name)
+}
+
+// fieldChange is a change of a single field. A nil Old or New means the field is
+// absent from the old or the new object.
+type fieldChange struct {
+	Path string
+	Old  interface{}
+	New  interface{}
+}
+
+func (c fieldChange) String() string {
+	switch {
+	case c.Old == nil:
+		return fmt.Sprintf("%s: added %s", c.Path, formatValue(c.New))
+	case c.New == nil:
+		return fmt.Sprintf("%s: removed %s", c.Path, formatValue(c.Old))
+	}
+	return fmt.Sprintf("%s: %s -> %s", c.Path, formatValue(c.Old), formatValue(c.New))
+}
+
+// objectDiff is the difference between the old and the new version of an object.
+// Old is nil for an added object and New is nil for a removed one.
+type objectDiff struct {
+	Key     objectKey
+	Old     *unstructured.Unstructured
+	New     *unstructured.Unstructured
+	Changes []fieldChange
+	Driver  *driverChange
+}
+
+// diffObjects matches the old and the new objects by kind, namespace and name and
+// returns the objects that were added, removed or changed, along with the number of
+// unchanged objects. Added and changed objects are listed in the order of
+// newObjs, followed by the removed objects in the order of oldObjs.
+func diffObjects(oldObjs, newObjs []*unstructured.Unstructured) ([]objectDiff, int) {
+	oldByKey := map[objectKey]*unstructured.Unstructured{}
+	for _, obj := range oldObjs {
+		oldByKey[keyOf(obj)] = obj
+	}
+
+	var diffs []objectDiff
+	unchanged := 0
+	seen := map[objectKey]bool{}
+	for _, newObj := range newObjs {
+		key := keyOf(newObj)
+		seen[key] = true
+		d := objectDiff{Key: key, Old: oldByKey[key], New: newObj}
+		if d.Old != nil {
+			diffValues("", d.Old.Object, d.New.Object, &d.Changes)
+			if len(d.Changes) == 0 {
+				unchanged++
+				continue
+			}
+		}
+		d.Driver = newDriverChange(d.Old, d.New)
+		diffs = append(diffs, d)
+	}
+	for _, oldObj := range oldObjs {
+		key := keyOf(oldObj)
+		if seen[key] {
+			continue
+		}
+		diffs = append(diffs, objectDiff{Key: key, Old: oldObj, Driver: newDriverChange(oldObj, nil)})
+	}
+	return diffs, unchanged
+}
+
+// diffValues appends the changes between old and new, found at path, to changes.
+// Lists whose elements all carry a unique name, such as containers, env or volumes,
+// are compared by name rather than by position.
+func diffValues(path string, old, new interface{}, changes *[]fieldChange) {
+	if ignoredPaths[path] {
+		return
+	}
+	switch {
+	case old == nil && new == nil:
+		return
+	case old == nil || new == nil:
+		*changes = append(*changes, fieldChange{Path: path, Old: old, New: new})
+		return
+	}
+
+	switch oldValue := old.(type) {
+	case map[string]interface{}:
+		newValue, ok := new.(map[string]interface{})
+		if !ok {
+			break
+		}
+		for _, key := range fieldpath.SortedKeys(oldValue, newValue) {
+			diffValues(fieldpath.Join(path, key), oldValue[key], newValue[key], changes)
+		}
+		return
+	case []interface{}:
+		newValue, ok := new.([]interface{})
+		if !ok {
+			break
+		}
+		oldNames, oldNamed := fieldpath.ElementNames(oldValue)
+		newNames, newNamed := fieldpath.ElementNames(newValue)
+		if oldNamed && newNamed {
+			oldByName := map[string]interface{}{}
+			for i, name := range oldNames {
+				oldByName[name] = oldValue[i]
+			}
+			newByName := map[string]interface{}{}
+			for i, name := range newNames {
+				newByName[name] = newValue[i]
+				diffValues(fieldpath.Element(path, name), oldByName[name], newValue[i], changes)
+			}
+			for i, name := range oldNames {
+				if _, ok := newByName[name]; !ok {
+					diffValues(fieldpath.Element(path, name), oldValue[i], nil, changes)
+				}
+			}
+			return
+		}
+		if len(oldValue) == len(newValue) {
+			for i := range oldValue {
+				diffValues(fieldpath.Index(path, i), oldValue[i], newValue[i], changes)
+			}
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(old, new) {
+		*changes = append(*changes, fieldChange{Path: path, Old: old, New: new})
+	}
+}
+
+func formatValue(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
@@ -23,6 +23,8 @@ import (
 	log "github.com/sirupsen/logrus"
 	cli "github.com/urfave/cli/v3"
 
+	"github.com/NVIDIA/gpu-operator/cmd/gpuop-cfg/diff"
+	"github.com/NVIDIA/gpu-operator/cmd/gpuop-cfg/render"
 	"github.com/NVIDIA/gpu-operator/cmd/gpuop-cfg/validate"
 )
 
@@ -66,6 +68,8 @@ func main() {
 	// Define the subcommands
 	c.Commands = []*cli.Command{
 		validate.NewCommand(logger),
+		render.NewCommand(logger),
+		diff.NewCommand(logger),
 	}
 
 	err := c.Run(context.Background(), os.Args)
@@ -0,0 +1,229 @@
+/**
+# Copyright (c), NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package render
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/urfave/cli/v3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	v1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1"
+	"github.com/NVIDIA/gpu-operator/api/nvidia/v1alpha1"
+	"github.com/NVIDIA/gpu-operator/api/nvidia/v1beta1"
+	"github.com/NVIDIA/gpu-operator/controllers"
+	"github.com/NVIDIA/gpu-operator/internal/state"
+)
+
+const defaultKubernetesVersion = "v1.33.0"
+
+// ClusterOptions holds the flags describing the cluster the objects are rendered for.
+// They are shared by the commands rendering custom resources.
+type ClusterOptions struct {
+	Nodes        string
+	ClusterInfo  string
+	Namespace    string
+	AssetsDir    string
+	ManifestsDir string
+}
+
+// clusterInfo describes the cluster the objects are rendered for, in place of the
+// properties the operator discovers from the API server
+type clusterInfo struct {
+	KubernetesVersion            string              `json:"kubernetesVersion,omitempty"`
+	ContainerRuntime             string              `json:"containerRuntime,omitempty"`
+	OpenshiftVersion             string              `json:"openshiftVersion,omitempty"`
+	OpenshiftDriverToolkitImages map[string]string   `json:"openshiftDriverToolkitImages,omitempty"`
+	OpenshiftProxy               *configv1.ProxySpec `json:"openshiftProxy,omitempty"`
+	DRAResourceAPIVersion        string              `json:"draResourceAPIVersion,omitempty"`
+	Nodes                        []corev1.Node       `json:"nodes,omitempty"`
+}
+
+// Flags returns the CLI flags setting the cluster options
+func (o *ClusterOptions) Flags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:        "nodes",
+			Usage:       "Specify a file containing the nodes of the cluster, as a NodeList, a List or a stream of Node documents",
+			Destination: &o.Nodes,
+		},
+		&cli.StringFlag{
+			Name:        "cluster-info",
+			Usage:       "Specify a file describing the cluster: kubernetesVersion, containerRuntime, openshiftVersion, openshiftDriverToolkitImages, openshiftProxy, draResourceAPIVersion and optionally its nodes",
+			Destination: &o.ClusterInfo,
+		},
+		&cli.StringFlag{
+			Name:        "namespace",
+			Usage:       "Specify the namespace the GPU Operator is installed in",
+			Value:       "gpu-operator",
+			Destination: &o.Namespace,
+		},
+		&cli.StringFlag{
+			Name:        "assets-dir",
+			Usage:       "Specify the directory containing the ClusterPolicy state assets",
+			Value:       controllers.DefaultAssetsDir,
+			Destination: &o.AssetsDir,
+		},
+		&cli.StringFlag{
+			Name:        "manifests-dir",
+			Usage:       "Specify the directory containing the NVIDIADriver and GPUCluster state manifests",
+			Value:       state.DefaultManifestsDir,
+			Destination: &o.ManifestsDir,
+		},
+	}
+}
+
+// LoadCustomResource reads the custom resource to render from path, converting an
+// NVIDIADriver to the version the controller reconciles. If path is '-' the
+// custom resource is read from STDIN.
+func LoadCustomResource(path string) (client.Object, error) {
+	contents, err := getContents(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %v", err)
+	}
+
+	typeMeta := metav1.TypeMeta{}
+	if err := yaml.Unmarshal(contents, &typeMeta); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal spec: %v", err)
+	}
+
+	var cr client.Object
+	switch typeMeta.GroupVersionKind() {
+	case v1.SchemeGroupVersion.WithKind("ClusterPolicy"):
+		cr = &v1.ClusterPolicy{}
+	case v1alpha1.SchemeGroupVersion.WithKind("NVIDIADriver"):
+		cr = &v1alpha1.NVIDIADriver{}
+	case v1beta1.SchemeGroupVersion.WithKind("NVIDIADriver"):
+		cr = &v1beta1.NVIDIADriver{}
+	case v1alpha1.SchemeGroupVersion.WithKind("GPUCluster"):
+		cr = &v1alpha1.GPUCluster{}
+	default:
+		return nil, fmt.Errorf("unsupported kind %q in apiVersion %q", typeMeta.Kind, typeMeta.APIVersion)
+	}
+	if err := yaml.Unmarshal(contents, cr); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal spec: %v", err)
+	}
+
+	if driver, ok := cr.(*v1beta1.NVIDIADriver); ok {
+		hub := &v1alpha1.NVIDIADriver{}
+		if err := driver.ConvertTo(hub); err != nil {
+			return nil, fmt.Errorf("failed to convert NVIDIADriver to %s: %v", v1alpha1.SchemeGroupVersion, err)
+		}
+		cr = hub
+	}
+	return cr, nil
+}
+
+// RenderOptions builds the render options from the cluster info and nodes files
+func (o ClusterOptions) RenderOptions() (controllers.RenderOptions, error) {
+	info := clusterInfo{}
+	if o.ClusterInfo != "" {
+		contents, err := getContents(o.ClusterInfo)
+		if err != nil {
+			return controllers.RenderOptions{}, fmt.Errorf("failed to read cluster info file: %v", err)
+		}
+		if err := yaml.UnmarshalStrict(contents, &info); err != nil {
+			return controllers.RenderOptions{}, fmt.Errorf("failed to unmarshal cluster info: %v", err)
+		}
+	}
+	if info.KubernetesVersion == "" {
+		info.KubernetesVersion = defaultKubernetesVersion
+	}
+
+	nodes := info.Nodes
+	if o.Nodes != "" {
+		contents, err := getContents(o.Nodes)
+		if err != nil {
+			return controllers.RenderOptions{}, fmt.Errorf("failed to read nodes file: %v", err)
+		}
+		fileNodes, err := loadNodes(contents)
+		if err != nil {
+			return controllers.RenderOptions{}, fmt.Errorf("failed to load nodes: %v", err)
+		}
+		nodes = append(nodes, fileNodes...)
+	}
+
+	return controllers.RenderOptions{
+		Namespace:                    o.Namespace,
+		AssetsDir:                    o.AssetsDir,
+		ManifestsDir:                 o.ManifestsDir,
+		Nodes:                        nodes,
+		KubernetesVersion:            info.KubernetesVersion,
+		ContainerRuntime:             info.ContainerRuntime,
+		OpenshiftVersion:             info.OpenshiftVersion,
+		OpenshiftDriverToolkitImages: info.OpenshiftDriverToolkitImages,
+		OpenshiftProxySpec:           info.OpenshiftProxy,
+		DRAResourceAPIVersion:        info.DRAResourceAPIVersion,
+	}, nil
+}
+
+// loadNodes reads the nodes from a stream of YAML or JSON documents, each either a
+// Node or a list of Nodes such as the output of 'kubectl get nodes -o yaml'
+func loadNodes(contents []byte) ([]corev1.Node, error) {
+	var nodes []corev1.Node
+	decoder := k8syaml.NewYAMLOrJSONDecoder(bytes.NewReader(contents), 4096)
+	for {
+		doc := map[string]interface{}{}
+		err := decoder.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(doc) == 0 {
+			continue
+		}
+
+		data, err := yaml.Marshal(doc)
+		if err != nil {
+			return nil, err
+		}
+		kind, _ := doc["kind"].(string)
+		switch {
+		case kind == "Node":
+			node := corev1.Node{}
+			if err := yaml.Unmarshal(data, &node); err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, node)
+		case strings.HasSuffix(kind, "List"):
+			list := corev1.NodeList{}
+			if err := yaml.Unmarshal(data, &list); err != nil {
+				return nil, err
+			}
+			for _, node := range list.Items {
+				if node.Kind != "" && node.Kind != "Node" {
+					return nil, fmt.Errorf("unexpected %s %q in %s", node.Kind, node.Name, kind)
+				}
+				nodes = append(nodes, node)
+			}
+		default:
+			return nil, fmt.Errorf("unexpected kind %q, expected Node or a list of Nodes", kind)
+		}
+	}
+	return nodes, nil
+}
@@ -0,0 +1,172 @@
+/**
+# Copyright (c), NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package render
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/NVIDIA/gpu-operator/controllers"
+)
+
+type command struct {
+	logger *logrus.Logger
+}
+
+type options struct {
+	input   string
+	output  string
+	cluster ClusterOptions
+}
+
+// NewCommand constructs a render command with the specified logger
+func NewCommand(logger *logrus.Logger) *cli.Command {
+	c := command{
+		logger: logger,
+	}
+	return c.build()
+}
+
+// build creates the CLI command
+func (m command) build() *cli.Command {
+	opts := options{}
+
+	// Create the 'render' command
+	c := cli.Command{
+		Name:  "render",
+		Usage: "Render the objects the GPU Operator deploys for a ClusterPolicy, NVIDIADriver or GPUCluster without a cluster",
+		Before: func(c context.Context, cli *cli.Command) (context.Context, error) {
+			return c, m.validateFlags(c, &opts)
+		},
+		Action: func(c context.Context, cli *cli.Command) error {
+			return m.run(c, &opts)
+		},
+	}
+
+	c.Flags = []cli.Flag{
+		&cli.StringFlag{
+			Name:        "input",
+			Usage:       "Specify the input file containing the ClusterPolicy, NVIDIADriver or GPUCluster yaml. If this is '-' the file is read from STDIN",
+			Value:       "-",
+			Destination: &opts.input,
+		},
+	}
+	c.Flags = append(c.Flags, opts.cluster.Flags()...)
+	c.Flags = append(c.Flags,
+		&cli.StringFlag{
+			Name:        "output",
+			Usage:       "Specify the file the rendered objects are written to. If this is '-' the objects are written to STDOUT",
+			Value:       "-",
+			Destination: &opts.output,
+		},
+	)
+
+	return &c
+}
+
+func (m command) validateFlags(ctx context.Context, opts *options) error {
+	if opts.cluster.Nodes == "" && opts.cluster.ClusterInfo == "" {
+		return fmt.Errorf("at least one of --nodes and --cluster-info must be specified")
+	}
+	if opts.cluster.Namespace == "" {
+		return fmt.Errorf("--namespace must not be empty")
+	}
+	return nil
+}
+
+func (m command) run(ctx context.Context, opts *options) error {
+	objs, err := m.render(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	contents, err := Marshal(objs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal rendered objects: %v", err)
+	}
+
+	if opts.output == "-" {
+		_, err = os.Stdout.Write(contents)
+		return err
+	}
+	return os.WriteFile(opts.output, contents, 0o644)
+}
+
+// render renders the objects for the custom resource in opts.input
+func (m command) render(ctx context.Context, opts *options) ([]*unstructured.Unstructured, error) {
+	cr, err := LoadCustomResource(opts.input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load custom resource: %v", err)
+	}
+
+	renderOpts, err := opts.cluster.RenderOptions()
+	if err != nil {
+		return nil, err
+	}
+	renderOpts.Logger = NewControllerLogger(m.logger)
+
+	objs, err := controllers.Render(ctx, cr, renderOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render objects: %v", err)
+	}
+	m.logger.Debugf("Rendered %d objects", len(objs))
+	return objs, nil
+}
+
+// NewControllerLogger returns a logger forwarding the controller logs to logger at
+// debug level
+func NewControllerLogger(logger *logrus.Logger) logr.Logger {
+	if !logger.IsLevelEnabled(logrus.DebugLevel) {
+		return logr.Discard()
+	}
+	return funcr.New(func(prefix, args string) {
+		logger.Debugf("%s %s", prefix, args)
+	}, funcr.Options{Verbosity: 1})
+}
+
+// Marshal returns objs as a stream of YAML documents
+func Marshal(objs []*unstructured.Unstructured) ([]byte, error) {
+	var contents []byte
+	for i, obj := range objs {
+		doc, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s %s: %v", obj.GetKind(), obj.GetName(), err)
+		}
+		if i > 0 {
+			contents = append(contents, []byte("---\n")...)
+		}
+		contents = append(contents, doc...)
+	}
+	return contents, nil
+}
+
+func getContents(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+
+	return os.ReadFile(path)
+}
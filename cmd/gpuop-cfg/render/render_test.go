@@ -0,0 +1,177 @@
+/**
+# Copyright (c), NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	v1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1"
+	"github.com/NVIDIA/gpu-operator/api/nvidia/v1alpha1"
+)
+
+func TestNewCommand(t *testing.T) {
+	cmd := NewCommand(logrus.New())
+
+	require.NotNil(t, cmd)
+	assert.Equal(t, "render", cmd.Name)
+	assert.NotEmpty(t, cmd.Usage)
+
+	names := []string{}
+	for _, flag := range cmd.Flags {
+		names = append(names, flag.Names()...)
+	}
+	assert.ElementsMatch(t, []string{"input", "nodes", "cluster-info", "namespace", "assets-dir", "manifests-dir", "output"}, names)
+}
+
+func writeFile(t *testing.T, name string, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0o600))
+	return path
+}
+
+func TestLoadCustomResource(t *testing.T) {
+	tests := []struct {
+		description string
+		manifest    string
+		expected    interface{}
+		expectedErr string
+	}{
+		{
+			description: "clusterpolicy",
+			manifest:    "apiVersion: nvidia.com/v1\nkind: ClusterPolicy\nmetadata:\n  name: cluster-policy\n",
+			expected:    &v1.ClusterPolicy{},
+		},
+		{
+			description: "nvidiadriver v1alpha1",
+			manifest:    "apiVersion: nvidia.com/v1alpha1\nkind: NVIDIADriver\nmetadata:\n  name: default\nspec:\n  version: 580.65.06\n",
+			expected:    &v1alpha1.NVIDIADriver{},
+		},
+		{
+			description: "nvidiadriver v1beta1 is converted",
+			manifest:    "apiVersion: nvidia.com/v1beta1\nkind: NVIDIADriver\nmetadata:\n  name: default\nspec:\n  version: 580.65.06\n",
+			expected:    &v1alpha1.NVIDIADriver{},
+		},
+		{
+			description: "gpucluster",
+			manifest:    "apiVersion: nvidia.com/v1alpha1\nkind: GPUCluster\nmetadata:\n  name: gpu-cluster\n",
+			expected:    &v1alpha1.GPUCluster{},
+		},
+		{
+			description: "unsupported kind",
+			manifest:    "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\n",
+			expectedErr: `unsupported kind "ConfigMap" in apiVersion "v1"`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			cr, err := LoadCustomResource(writeFile(t, "cr.yaml", tc.manifest))
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.IsType(t, tc.expected, cr)
+			assert.NotEmpty(t, cr.GetName())
+			if driver, ok := cr.(*v1alpha1.NVIDIADriver); ok {
+				assert.Equal(t, "580.65.06", driver.Spec.Version)
+			}
+		})
+	}
+}
+
+func TestClusterOptionsRenderOptions(t *testing.T) {
+	clusterInfo := `kubernetesVersion: v1.34.1
+openshiftVersion: "4.19"
+draResourceAPIVersion: resource.k8s.io/v1
+nodes:
+- metadata:
+    name: node-a
+`
+	nodes := `apiVersion: v1
+kind: Node
+metadata:
+  name: node-b
+---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: Node
+  metadata:
+    name: node-c
+`
+	opts := ClusterOptions{
+		Namespace:   "gpu-operator",
+		ClusterInfo: writeFile(t, "cluster-info.yaml", clusterInfo),
+		Nodes:       writeFile(t, "nodes.yaml", nodes),
+	}
+	renderOpts, err := opts.RenderOptions()
+	require.NoError(t, err)
+
+	assert.Equal(t, "v1.34.1", renderOpts.KubernetesVersion)
+	assert.Equal(t, "4.19", renderOpts.OpenshiftVersion)
+	assert.Equal(t, "resource.k8s.io/v1", renderOpts.DRAResourceAPIVersion)
+	assert.Equal(t, "gpu-operator", renderOpts.Namespace)
+	require.Len(t, renderOpts.Nodes, 3)
+	assert.Equal(t, "node-a", renderOpts.Nodes[0].Name)
+	assert.Equal(t, "node-b", renderOpts.Nodes[1].Name)
+	assert.Equal(t, "node-c", renderOpts.Nodes[2].Name)
+
+	// Cluster properties default to a plain Kubernetes cluster
+	opts = ClusterOptions{Nodes: writeFile(t, "nodes.yaml", nodes)}
+	renderOpts, err = opts.RenderOptions()
+	require.NoError(t, err)
+	assert.Equal(t, defaultKubernetesVersion, renderOpts.KubernetesVersion)
+	assert.Empty(t, renderOpts.OpenshiftVersion)
+
+	opts = ClusterOptions{ClusterInfo: writeFile(t, "cluster-info.yaml", "kubernetesVersoin: v1.34.1\n")}
+	_, err = opts.RenderOptions()
+	require.ErrorContains(t, err, "failed to unmarshal cluster info")
+
+	opts = ClusterOptions{Nodes: writeFile(t, "nodes.yaml", "apiVersion: v1\nkind: Pod\nmetadata:\n  name: pod\n")}
+	_, err = opts.RenderOptions()
+	require.ErrorContains(t, err, `unexpected kind "Pod"`)
+}
+
+func TestMarshal(t *testing.T) {
+	objs := []*unstructured.Unstructured{
+		{Object: map[string]interface{}{"apiVersion": "v1", "kind": "ServiceAccount", "metadata": map[string]interface{}{"name": "a"}}},
+		{Object: map[string]interface{}{"apiVersion": "v1", "kind": "ServiceAccount", "metadata": map[string]interface{}{"name": "b"}}},
+	}
+
+	contents, err := Marshal(objs)
+	require.NoError(t, err)
+	assert.Equal(t, `apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: a
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: b
+`, string(contents))
+}
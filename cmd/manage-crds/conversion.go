@@ -0,0 +1,140 @@
+/*
+Copyright (c), NVIDIA CORPORATION.  All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	sigsyaml "sigs.k8s.io/yaml"
+)
+
+// conversionWebhookPath is the path the operator serves the conversion webhook on
+const conversionWebhookPath = "/convert"
+
+// newConversionWebhook returns the conversion webhook served by the service, given as
+// <namespace>/<name>, and trusted with the CA bundle in caFile
+func newConversionWebhook(service string, caFile string) (*apiextensionsv1.WebhookConversion, error) {
+	namespace, name, ok := strings.Cut(service, "/")
+	if !ok || namespace == "" || name == "" {
+		return nil, fmt.Errorf("invalid conversion webhook service %q, expected <namespace>/<name>", service)
+	}
+	caBundle, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the conversion webhook CA bundle: %w", err)
+	}
+	path := conversionWebhookPath
+	return &apiextensionsv1.WebhookConversion{
+		ClientConfig: &apiextensionsv1.WebhookClientConfig{
+			Service:  &apiextensionsv1.ServiceReference{Namespace: namespace, Name: name, Path: &path},
+			CABundle: caBundle,
+		},
+		ConversionReviewVersions: []string{"v1"},
+	}, nil
+}
+
+// withConversionWebhook returns the CRD files of paths, the CRDs serving several versions
+// converted by webhook. The files holding such CRDs are rewritten into dir, so that the
+// CRDs are applied with their conversion strategy in a single update; the API server would
+// otherwise store objects in the new storage version without converting them.
+func withConversionWebhook(paths []string, dir string, webhook *apiextensionsv1.WebhookConversion) ([]string, error) {
+	var files []string
+	for _, p := range paths {
+		err := filepath.WalkDir(p, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && (filepath.Ext(path) == ".yaml" || filepath.Ext(path) == ".yml") {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to walk path %s: %w", p, err)
+		}
+	}
+
+	result := make([]string, 0, len(files))
+	for i, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		converted, changed, err := setConversionWebhook(data, webhook)
+		if err != nil {
+			return nil, fmt.Errorf("failed to set the conversion webhook of the CRDs in %s: %w", file, err)
+		}
+		if !changed {
+			result = append(result, file)
+			continue
+		}
+		out := filepath.Join(dir, fmt.Sprintf("%d-%s", i, filepath.Base(file)))
+		if err := os.WriteFile(out, converted, 0600); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", out, err)
+		}
+		logger.Debugf("Set the conversion webhook of the CRDs in %s", file)
+		result = append(result, out)
+	}
+	return result, nil
+}
+
+// setConversionWebhook sets webhook as the conversion of the CRDs serving several versions
+// in the YAML documents of data. It returns the documents, and true if any CRD was changed.
+func setConversionWebhook(data []byte, webhook *apiextensionsv1.WebhookConversion) ([]byte, bool, error) {
+	var docs [][]byte
+	changed := false
+	reader := yaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(data)))
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		doc = bytes.TrimSpace(doc)
+		if len(doc) == 0 {
+			continue
+		}
+
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		if err := yaml.Unmarshal(doc, crd); err != nil || crd.Kind != "CustomResourceDefinition" || len(crd.Spec.Versions) < 2 {
+			docs = append(docs, doc)
+			continue
+		}
+		crd.Spec.Conversion = &apiextensionsv1.CustomResourceConversion{
+			Strategy: apiextensionsv1.WebhookConverter,
+			Webhook:  webhook,
+		}
+		doc, err = sigsyaml.Marshal(crd)
+		if err != nil {
+			return nil, false, err
+		}
+		docs = append(docs, bytes.TrimSpace(doc))
+		changed = true
+	}
+	return bytes.Join(docs, []byte("\n---\n")), changed, nil
+}
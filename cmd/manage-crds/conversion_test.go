@@ -0,0 +1,86 @@
+/*
+Copyright (c), NVIDIA CORPORATION.  All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/yaml"
+)
+
+func TestWithConversionWebhook(t *testing.T) {
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.crt")
+	require.NoError(t, os.WriteFile(caFile, []byte("ca"), 0600))
+
+	singleVersion := filepath.Join(dir, "clusterpolicies.yaml")
+	require.NoError(t, os.WriteFile(singleVersion, []byte(`apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: clusterpolicies.nvidia.com
+spec:
+  group: nvidia.com
+  versions:
+  - name: v1
+    served: true
+    storage: true
+`), 0600))
+	multiVersion := filepath.Join(dir, "nvidiadrivers.yaml")
+	require.NoError(t, os.WriteFile(multiVersion, []byte(`apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: nvidiadrivers.nvidia.com
+spec:
+  group: nvidia.com
+  versions:
+  - name: v1alpha1
+    served: true
+    storage: false
+  - name: v1beta1
+    served: true
+    storage: true
+`), 0600))
+
+	webhook, err := newConversionWebhook("gpu-operator/gpu-operator-webhook", caFile)
+	require.NoError(t, err)
+	_, err = newConversionWebhook("gpu-operator-webhook", caFile)
+	require.Error(t, err)
+
+	out := t.TempDir()
+	paths, err := withConversionWebhook([]string{singleVersion, multiVersion}, out, webhook)
+	require.NoError(t, err)
+	require.Len(t, paths, 2)
+	// CRDs serving a single version are applied as is
+	require.Equal(t, singleVersion, paths[0])
+	require.Equal(t, out, filepath.Dir(paths[1]))
+
+	data, err := os.ReadFile(paths[1])
+	require.NoError(t, err)
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	require.NoError(t, yaml.Unmarshal(data, crd))
+	require.Equal(t, "nvidiadrivers.nvidia.com", crd.Name)
+	require.Len(t, crd.Spec.Versions, 2)
+	require.Equal(t, apiextensionsv1.WebhookConverter, crd.Spec.Conversion.Strategy)
+	require.Equal(t, "gpu-operator", crd.Spec.Conversion.Webhook.ClientConfig.Service.Namespace)
+	require.Equal(t, "gpu-operator-webhook", crd.Spec.Conversion.Webhook.ClientConfig.Service.Name)
+	require.Equal(t, "/convert", *crd.Spec.Conversion.Webhook.ClientConfig.Service.Path)
+	require.Equal(t, []byte("ca"), crd.Spec.Conversion.Webhook.ClientConfig.CABundle)
+}
@@ -33,6 +33,11 @@ var logger = log.New()
 type config struct {
 	Debug     bool
 	crdsPaths []string
+	crdNames  []string
+	// conversionWebhookService and conversionWebhookCAFile configure the conversion
+	// webhook of the applied CRDs serving several versions
+	conversionWebhookService string
+	conversionWebhookCAFile  string
 }
 
 func main() {
@@ -81,7 +86,18 @@ func main() {
 		{
 			Name:  "apply",
 			Usage: "Apply CRDs from the specified path",
-			Flags: commonFlags,
+			Flags: append([]cli.Flag{
+				&cli.StringFlag{
+					Name:        "conversion-webhook-service",
+					Usage:       "Service, as <namespace>/<name>, serving the conversion webhook of the CRDs serving several versions",
+					Destination: &config.conversionWebhookService,
+				},
+				&cli.StringFlag{
+					Name:        "conversion-webhook-ca-file",
+					Usage:       "Path to the CA bundle the API server verifies the conversion webhook with",
+					Destination: &config.conversionWebhookCAFile,
+				},
+			}, commonFlags...),
 			Action: func(ctx context.Context, cli *cli.Command) error {
 				return runApply(ctx, config)
 			},
@@ -94,6 +110,21 @@ func main() {
 				return runDelete(ctx, config)
 			},
 		},
+		{
+			Name:  "migrate",
+			Usage: "Rewrite all objects of the specified CRDs in their current storage version and prune the stored versions",
+			Flags: []cli.Flag{
+				&cli.StringSliceFlag{
+					Name:        "crd",
+					Usage:       "Name of the CRD to migrate, e.g. nvidiadrivers.nvidia.com (can be specified multiple times)",
+					Required:    true,
+					Destination: &config.crdNames,
+				},
+			},
+			Action: func(ctx context.Context, cli *cli.Command) error {
+				return runMigrate(ctx, config)
+			},
+		},
 	}
 
 	err := c.Run(context.Background(), os.Args)
@@ -107,6 +138,22 @@ func runApply(ctx context.Context, cfg config) error {
 	paths := cfg.crdsPaths
 	logger.Infof("Applying CRDs from %d path(s): %v", len(paths), paths)
 
+	if cfg.conversionWebhookService != "" {
+		webhook, err := newConversionWebhook(cfg.conversionWebhookService, cfg.conversionWebhookCAFile)
+		if err != nil {
+			return err
+		}
+		dir, err := os.MkdirTemp("", "crds")
+		if err != nil {
+			return fmt.Errorf("failed to create a temporary directory: %w", err)
+		}
+		defer os.RemoveAll(dir)
+		paths, err = withConversionWebhook(paths, dir, webhook)
+		if err != nil {
+			return err
+		}
+	}
+
 	if err := crdutil.ProcessCRDs(ctx, crdutil.CRDOperationApply, paths...); err != nil {
 		return fmt.Errorf("failed to apply CRDs: %w", err)
 	}
@@ -0,0 +1,147 @@
+/*
+Copyright (c), NVIDIA CORPORATION.  All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/retry"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlconfig "sigs.k8s.io/controller-runtime/pkg/client/config"
+)
+
+// migrateListLimit is the page size used when listing the objects to rewrite
+const migrateListLimit = 100
+
+func runMigrate(ctx context.Context, cfg config) error {
+	scheme := runtime.NewScheme()
+	if err := apiextensionsv1.AddToScheme(scheme); err != nil {
+		return fmt.Errorf("failed to add CRD types to scheme: %w", err)
+	}
+	restConfig, err := ctrlconfig.GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to get kubeconfig: %w", err)
+	}
+	k8sClient, err := ctrlclient.New(restConfig, ctrlclient.Options{Scheme: scheme})
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	for _, name := range cfg.crdNames {
+		if err := migrateStorageVersion(ctx, k8sClient, name); err != nil {
+			return fmt.Errorf("failed to migrate CRD %s: %w", name, err)
+		}
+	}
+
+	logger.Info("Successfully migrated CRDs")
+	return nil
+}
+
+// migrateStorageVersion rewrites every object of the named CRD so that the API server
+// persists it in the current storage version, then drops all other versions from the
+// CRD's status.storedVersions. This is what allows a version to be removed from the
+// CRD in a later release without orphaning objects still stored in it.
+func migrateStorageVersion(ctx context.Context, c ctrlclient.Client, name string) error {
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	if err := c.Get(ctx, ctrlclient.ObjectKey{Name: name}, crd); err != nil {
+		return fmt.Errorf("failed to get CRD: %w", err)
+	}
+
+	storageVersion := ""
+	for _, version := range crd.Spec.Versions {
+		if version.Storage {
+			storageVersion = version.Name
+			break
+		}
+	}
+	if storageVersion == "" {
+		return fmt.Errorf("no storage version found")
+	}
+
+	if len(crd.Status.StoredVersions) == 1 && crd.Status.StoredVersions[0] == storageVersion {
+		logger.Infof("CRD %s is already fully stored in version %s", name, storageVersion)
+		return nil
+	}
+
+	gvk := schema.GroupVersionKind{
+		Group:   crd.Spec.Group,
+		Version: storageVersion,
+		Kind:    crd.Spec.Names.ListKind,
+	}
+	logger.Infof("Migrating %s objects to storage version %s (stored versions: %v)", crd.Spec.Names.Plural, storageVersion, crd.Status.StoredVersions)
+
+	count := 0
+	listOpts := []ctrlclient.ListOption{ctrlclient.Limit(migrateListLimit)}
+	for {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk)
+		if err := c.List(ctx, list, listOpts...); err != nil {
+			return fmt.Errorf("failed to list %s: %w", crd.Spec.Names.Plural, err)
+		}
+
+		for i := range list.Items {
+			if err := rewriteObject(ctx, c, &list.Items[i]); err != nil {
+				return err
+			}
+			count++
+		}
+
+		if list.GetContinue() == "" {
+			break
+		}
+		listOpts = []ctrlclient.ListOption{ctrlclient.Limit(migrateListLimit), ctrlclient.Continue(list.GetContinue())}
+	}
+	logger.Infof("Rewrote %d %s object(s)", count, crd.Spec.Names.Plural)
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := c.Get(ctx, ctrlclient.ObjectKey{Name: name}, crd); err != nil {
+			return err
+		}
+		crd.Status.StoredVersions = []string{storageVersion}
+		return c.Status().Update(ctx, crd)
+	})
+}
+
+// rewriteObject issues an unchanged update of obj, which makes the API server re-encode
+// it in the storage version. Objects deleted in the meantime are skipped.
+func rewriteObject(ctx context.Context, c ctrlclient.Client, obj *unstructured.Unstructured) error {
+	key := ctrlclient.ObjectKeyFromObject(obj)
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		err := c.Update(ctx, obj)
+		if apierrors.IsConflict(err) {
+			if getErr := c.Get(ctx, key, obj); getErr != nil {
+				return getErr
+			}
+		}
+		return err
+	})
+	if apierrors.IsNotFound(err) {
+		logger.Debugf("%s %s was deleted during migration", obj.GetKind(), key)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to rewrite %s %s: %w", obj.GetKind(), key, err)
+	}
+	logger.Debugf("Rewrote %s %s", obj.GetKind(), key)
+	return nil
+}
@@ -0,0 +1,107 @@
+/*
+Copyright (c), NVIDIA CORPORATION.  All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	nvidiav1alpha1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1alpha1"
+	nvidiav1beta1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1beta1"
+)
+
+func newNVIDIADriverCRD(storedVersions ...string) *apiextensionsv1.CustomResourceDefinition {
+	return &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "nvidiadrivers.nvidia.com"},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: "nvidia.com",
+			Names: apiextensionsv1.CustomResourceDefinitionNames{
+				Plural:   "nvidiadrivers",
+				Kind:     "NVIDIADriver",
+				ListKind: "NVIDIADriverList",
+			},
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: "v1alpha1", Served: true},
+				{Name: "v1beta1", Served: true, Storage: true},
+			},
+		},
+		Status: apiextensionsv1.CustomResourceDefinitionStatus{StoredVersions: storedVersions},
+	}
+}
+
+func TestMigrateStorageVersion(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, apiextensionsv1.AddToScheme(scheme))
+	require.NoError(t, nvidiav1alpha1.AddToScheme(scheme))
+	require.NoError(t, nvidiav1beta1.AddToScheme(scheme))
+
+	crd := newNVIDIADriverCRD("v1alpha1", "v1beta1")
+	var drivers []ctrlclient.Object
+	for _, name := range []string{"default", "pool-a", "pool-b"} {
+		drivers = append(drivers, &nvidiav1beta1.NVIDIADriver{ObjectMeta: metav1.ObjectMeta{Name: name}})
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(crd).
+		WithObjects(drivers...).
+		WithStatusSubresource(crd).
+		Build()
+
+	resourceVersions := map[string]string{}
+	for _, driver := range drivers {
+		existing := &nvidiav1beta1.NVIDIADriver{}
+		require.NoError(t, c.Get(t.Context(), ctrlclient.ObjectKeyFromObject(driver), existing))
+		resourceVersions[existing.Name] = existing.ResourceVersion
+	}
+
+	require.NoError(t, migrateStorageVersion(t.Context(), c, crd.Name))
+
+	got := &apiextensionsv1.CustomResourceDefinition{}
+	require.NoError(t, c.Get(t.Context(), ctrlclient.ObjectKeyFromObject(crd), got))
+	require.Equal(t, []string{"v1beta1"}, got.Status.StoredVersions)
+
+	for _, driver := range drivers {
+		rewritten := &nvidiav1beta1.NVIDIADriver{}
+		require.NoError(t, c.Get(t.Context(), ctrlclient.ObjectKeyFromObject(driver), rewritten))
+		require.NotEqual(t, resourceVersions[rewritten.Name], rewritten.ResourceVersion, "%s was not rewritten", rewritten.Name)
+	}
+
+	// A fully migrated CRD is left untouched
+	resourceVersion := got.ResourceVersion
+	require.NoError(t, migrateStorageVersion(t.Context(), c, crd.Name))
+	require.NoError(t, c.Get(t.Context(), ctrlclient.ObjectKeyFromObject(crd), got))
+	require.Equal(t, resourceVersion, got.ResourceVersion)
+}
+
+func TestMigrateStorageVersionErrors(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, apiextensionsv1.AddToScheme(scheme))
+
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+	require.ErrorContains(t, migrateStorageVersion(t.Context(), c, "nvidiadrivers.nvidia.com"), "failed to get CRD")
+
+	crd := newNVIDIADriverCRD("v1alpha1")
+	crd.Spec.Versions[1].Storage = false
+	c = fake.NewClientBuilder().WithScheme(scheme).WithObjects(crd).Build()
+	require.EqualError(t, migrateStorageVersion(t.Context(), c, crd.Name), "no storage version found")
+}
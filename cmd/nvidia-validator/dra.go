@@ -0,0 +1,343 @@
+/*
+ * Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/NVIDIA/go-nvlib/pkg/nvpci"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/NVIDIA/gpu-operator/controllers/clusterinfo"
+)
+
+const (
+	// draDriverName is the name of the NVIDIA DRA driver publishing the GPUs in ResourceSlices
+	draDriverName = "gpu.nvidia.com"
+	// draDeviceClassName is the DeviceClass of the GPUs published by the NVIDIA DRA driver
+	draDeviceClassName = "gpu.nvidia.com"
+	// draValidatorDaemonsetName is the name of the validator DaemonSet of the DRA stack
+	draValidatorDaemonsetName = "nvidia-dra-validator"
+	// draWorkloadClaimName is the name of the resource claim in the DRA cuda validation pod
+	draWorkloadClaimName = "gpu"
+)
+
+func (d *DRA) validate() error {
+	// delete status file is already present
+	err := deleteStatusFile(outputDirFlag + "/" + draStatusFile)
+	if err != nil {
+		return err
+	}
+
+	kubeConfig, err := rest.InClusterConfig()
+	if err != nil {
+		log.Errorf("Error getting config cluster - %s\n", err.Error())
+		return err
+	}
+
+	kubeClient, err := kubernetes.NewForConfig(kubeConfig)
+	if err != nil {
+		log.Errorf("Error getting k8s client - %s\n", err.Error())
+		return err
+	}
+	d.kubeClient = kubeClient
+
+	dynamicClient, err := dynamic.NewForConfig(kubeConfig)
+	if err != nil {
+		log.Errorf("Error getting k8s dynamic client - %s\n", err.Error())
+		return err
+	}
+	d.dynamicClient = dynamicClient
+
+	info, err := clusterinfo.New(d.ctx, clusterinfo.WithKubernetesConfig(kubeConfig), clusterinfo.WithOneShot(false))
+	if err != nil {
+		return fmt.Errorf("error getting cluster info: %w", err)
+	}
+	gvr, supported, err := info.GetDRAResourceGVR()
+	if err != nil {
+		return fmt.Errorf("error detecting the resource.k8s.io API version: %w", err)
+	}
+	if !supported {
+		return fmt.Errorf("dynamic resource allocation is not supported by the cluster")
+	}
+	d.resourceAPI = gvr.GroupVersion()
+	log.Infof("Using %s for DRA validation", d.resourceAPI)
+
+	err = runCheckFunc("dra-resourceslices", d.validateResourceSlices)
+	if err != nil {
+		return err
+	}
+
+	if withWorkloadFlag {
+		// workload test
+		err = runCheckFunc("dra-workload", d.runWorkload)
+		if err != nil {
+			return err
+		}
+	}
+
+	// create dra status file
+	err = createStatusFile(outputDirFlag + "/" + draStatusFile)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// validateResourceSlices checks the GPUs of the node are published by the NVIDIA DRA driver
+func (d *DRA) validateResourceSlices() error {
+	ctx := d.ctx
+
+	// every GPU of the node is expected to be published as at least one device
+	gpus, err := nvpci.New().GetGPUs()
+	if err != nil {
+		log.Warnf("error getting NVIDIA PCI devices, expecting at least one published device: %v", err)
+	}
+	expected := max(len(gpus), 1)
+
+	opts := meta_v1.ListOptions{FieldSelector: fields.Set{
+		"spec.nodeName": nodeNameFlag,
+		"spec.driver":   draDriverName,
+	}.AsSelector().String()}
+
+	var devices int
+	for retry := 1; retry <= gpuResourceDiscoveryWaitRetries; retry++ {
+		slices, err := d.dynamicClient.Resource(d.resourceAPI.WithResource("resourceslices")).List(ctx, opts)
+		if err != nil {
+			return fmt.Errorf("unable to list the ResourceSlices of node %s: %w", nodeNameFlag, err)
+		}
+		devices = countResourceSliceDevices(slices.Items)
+		if devices >= expected {
+			log.Infof("Found %d devices published by %s for %d GPUs on the node", devices, draDriverName, len(gpus))
+			return nil
+		}
+		log.Infof("GPUs are not yet published in ResourceSlices, %d devices found, retry %d", devices, retry)
+		time.Sleep(gpuResourceDiscoveryIntervalSeconds * time.Second)
+	}
+	return fmt.Errorf("%d devices published by %s in the ResourceSlices of node %s, expected at least %d", devices, draDriverName, nodeNameFlag, expected)
+}
+
+func (d *DRA) runWorkload() error {
+	ctx := d.ctx
+
+	// load podSpec
+	pod, err := loadPodSpec(draWorkloadPodSpecPath)
+	if err != nil {
+		return err
+	}
+	pod.Namespace = namespaceFlag
+	image := os.Getenv(validatorImageEnvName)
+	pod.Spec.Containers[0].Image = image
+	pod.Spec.InitContainers[0].Image = image
+
+	imagePullPolicy := os.Getenv(validatorImagePullPolicyEnvName)
+	if imagePullPolicy != "" {
+		pod.Spec.Containers[0].ImagePullPolicy = corev1.PullPolicy(imagePullPolicy)
+		pod.Spec.InitContainers[0].ImagePullPolicy = corev1.PullPolicy(imagePullPolicy)
+	}
+
+	if os.Getenv(validatorImagePullSecretsEnvName) != "" {
+		pullSecrets := strings.Split(os.Getenv(validatorImagePullSecretsEnvName), ",")
+		for _, secret := range pullSecrets {
+			pod.Spec.ImagePullSecrets = append(pod.Spec.ImagePullSecrets, corev1.LocalObjectReference{Name: secret})
+		}
+	}
+	if os.Getenv(validatorRuntimeClassEnvName) != "" {
+		runtimeClass := os.Getenv(validatorRuntimeClassEnvName)
+		pod.Spec.RuntimeClassName = &runtimeClass
+	}
+
+	validatorDaemonset, err := d.kubeClient.AppsV1().DaemonSets(namespaceFlag).Get(ctx, draValidatorDaemonsetName, meta_v1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to retrieve the dra validator daemonset: %w", err)
+	}
+
+	// update owner reference
+	pod.SetOwnerReferences(validatorDaemonset.OwnerReferences)
+	// set pod tolerations
+	pod.Spec.Tolerations = validatorDaemonset.Spec.Template.Spec.Tolerations
+	// the resource claim is allocated by the scheduler, so the pod is pinned to the current
+	// node through its affinity instead of its node name
+	pod.Spec.Affinity = draWorkloadNodeAffinity(nodeNameFlag)
+
+	opts := meta_v1.ListOptions{LabelSelector: labels.Set{"app": draValidatorLabelValue}.AsSelector().String()}
+
+	// check if dra workload pod is already running and cleanup, its resource claim is
+	// garbage collected along with it.
+	podList, err := d.kubeClient.CoreV1().Pods(namespaceFlag).List(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("cannot list existing validation pods: %s", err)
+	}
+
+	propagation := meta_v1.DeletePropagationBackground
+	gracePeriod := int64(0)
+	deleteOptions := meta_v1.DeleteOptions{PropagationPolicy: &propagation, GracePeriodSeconds: &gracePeriod}
+	for i := range podList.Items {
+		if draWorkloadPodNode(&podList.Items[i]) != nodeNameFlag {
+			continue
+		}
+		err = d.kubeClient.CoreV1().Pods(namespaceFlag).Delete(ctx, podList.Items[i].Name, deleteOptions)
+		if err != nil {
+			return fmt.Errorf("cannot delete previous validation pod: %s", err)
+		}
+	}
+
+	// name the pod upfront, so that it references a resource claim of the same name
+	pod.Name = pod.GenerateName + utilrand.String(5)
+	claimName := pod.Name
+	for i := range pod.Spec.ResourceClaims {
+		if pod.Spec.ResourceClaims[i].Name == draWorkloadClaimName {
+			pod.Spec.ResourceClaims[i].ResourceClaimName = &claimName
+		}
+	}
+
+	newPod, err := d.kubeClient.CoreV1().Pods(namespaceFlag).Create(ctx, pod, meta_v1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create dra validation pod %s, err %+v", pod.Name, err)
+	}
+
+	// the pod stays pending until its resource claim exists and is allocated
+	claim := newDRAWorkloadClaim(d.resourceAPI, newPod)
+	_, err = d.dynamicClient.Resource(d.resourceAPI.WithResource("resourceclaims")).Namespace(namespaceFlag).Create(ctx, claim, meta_v1.CreateOptions{})
+	if err != nil {
+		if deleteErr := d.kubeClient.CoreV1().Pods(namespaceFlag).Delete(ctx, newPod.Name, deleteOptions); deleteErr != nil {
+			log.Warnf("failed to delete dra validation pod %s: %v", newPod.Name, deleteErr)
+		}
+		return fmt.Errorf("failed to create resource claim %s, err %+v", claimName, err)
+	}
+
+	// make sure it's available
+	err = waitForPod(ctx, d.kubeClient, newPod.Name, namespaceFlag)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// newDRAWorkloadClaim returns the resource claim of one GPU for the DRA cuda validation pod,
+// in the layout of the given resource.k8s.io version. The claim is owned by the pod, so it
+// is deleted along with it.
+func newDRAWorkloadClaim(resourceAPI schema.GroupVersion, pod *corev1.Pod) *unstructured.Unstructured {
+	// like the claim of the validator itself, adminAccess avoids blocking on GPUs already
+	// in use by workloads
+	request := map[string]interface{}{
+		"deviceClassName": draDeviceClassName,
+		"allocationMode":  "ExactCount",
+		"count":           int64(1),
+		"adminAccess":     true,
+	}
+	// the request fields moved under 'exactly' after v1beta1
+	if resourceAPI.Version != "v1beta1" {
+		request = map[string]interface{}{"exactly": request}
+	}
+	request["name"] = draWorkloadClaimName
+
+	claim := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"devices": map[string]interface{}{
+				"requests": []interface{}{request},
+			},
+		},
+	}}
+	claim.SetAPIVersion(resourceAPI.String())
+	claim.SetKind("ResourceClaim")
+	claim.SetName(pod.Name)
+	claim.SetNamespace(pod.Namespace)
+	claim.SetLabels(map[string]string{"app": draValidatorLabelValue})
+	claim.SetOwnerReferences([]meta_v1.OwnerReference{{
+		APIVersion: "v1",
+		Kind:       "Pod",
+		Name:       pod.Name,
+		UID:        pod.UID,
+	}})
+	return claim
+}
+
+// countResourceSliceDevices returns the number of devices published in the ResourceSlices,
+// ignoring the slices of outdated generations of a pool
+func countResourceSliceDevices(slices []unstructured.Unstructured) int {
+	generations := map[string]int64{}
+	for _, slice := range slices {
+		pool, _, _ := unstructured.NestedString(slice.Object, "spec", "pool", "name")
+		generation, _, _ := unstructured.NestedInt64(slice.Object, "spec", "pool", "generation")
+		if current, ok := generations[pool]; !ok || generation > current {
+			generations[pool] = generation
+		}
+	}
+
+	count := 0
+	for _, slice := range slices {
+		pool, _, _ := unstructured.NestedString(slice.Object, "spec", "pool", "name")
+		generation, _, _ := unstructured.NestedInt64(slice.Object, "spec", "pool", "generation")
+		if generation != generations[pool] {
+			continue
+		}
+		devices, _, _ := unstructured.NestedSlice(slice.Object, "spec", "devices")
+		count += len(devices)
+	}
+	return count
+}
+
+// draWorkloadNodeAffinity returns the affinity pinning the DRA cuda validation pod to a node
+func draWorkloadNodeAffinity(nodeName string) *corev1.Affinity {
+	return &corev1.Affinity{
+		NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+					MatchFields: []corev1.NodeSelectorRequirement{{
+						Key:      "metadata.name",
+						Operator: corev1.NodeSelectorOpIn,
+						Values:   []string{nodeName},
+					}},
+				}},
+			},
+		},
+	}
+}
+
+// draWorkloadPodNode returns the node a DRA cuda validation pod runs on, or is pinned to
+// while it is not scheduled yet
+func draWorkloadPodNode(pod *corev1.Pod) string {
+	if pod.Spec.NodeName != "" {
+		return pod.Spec.NodeName
+	}
+	if pod.Spec.Affinity == nil || pod.Spec.Affinity.NodeAffinity == nil ||
+		pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return ""
+	}
+	for _, term := range pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		for _, field := range term.MatchFields {
+			if field.Key == "metadata.name" && field.Operator == corev1.NodeSelectorOpIn && len(field.Values) == 1 {
+				return field.Values[0]
+			}
+		}
+	}
+	return ""
+}
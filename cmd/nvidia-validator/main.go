@@ -40,8 +40,10 @@ import (
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer/json"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
@@ -80,6 +82,21 @@ type CUDA struct {
 	kubeClient kubernetes.Interface
 }
 
+// P2P represents spec to run the GPU peer-to-peer workload
+type P2P struct {
+	ctx        context.Context
+	kubeClient kubernetes.Interface
+}
+
+// DRA represents spec to validate the GPUs of the node through Dynamic Resource Allocation
+type DRA struct {
+	ctx           context.Context
+	kubeClient    kubernetes.Interface
+	dynamicClient dynamic.Interface
+	// resourceAPI is the served resource.k8s.io group version
+	resourceAPI schema.GroupVersion
+}
+
 // Plugin component
 type Plugin struct {
 	ctx        context.Context
@@ -125,6 +142,7 @@ var (
 	kubeconfigFlag                  string
 	nodeNameFlag                    string
 	namespaceFlag                   string
+	podNameFlag                     string
 	withWaitFlag                    bool
 	withWorkloadFlag                bool
 	componentFlag                   string
@@ -139,6 +157,10 @@ var (
 	driverInstallDirFlag            string
 	driverInstallDirCtrPathFlag     string
 	driverValidationSkipGPUInitFlag bool
+	p2pMinBandwidthFlag             int
+	p2pMaxLatencyFlag               int
+	revalidationIntervalSecondsFlag int
+	revalidationComponentsFlag      string
 )
 
 // defaultGPUWorkloadConfig is "vm-passthrough" unless
@@ -152,10 +174,14 @@ const (
 	defaultSleepIntervalSeconds = 5
 	// defaultMetricsPort indicates the port on which the metrics will be exposed.
 	defaultMetricsPort = 0
+	// defaultRevalidationIntervalSeconds indicates the default interval in seconds between two revalidations
+	defaultRevalidationIntervalSeconds = 300
 	// hostDevCharPath indicates the path in the container where the host '/dev/char' directory is mounted to
 	hostDevCharPath = "/host-dev-char"
 	// nvidiaModuleRefcntPath is the path to check if the nvidia kernel module is loaded
 	nvidiaModuleRefcntPath = "/sys/module/nvidia/refcnt"
+	// nvidiaModuleVersionPath is the path to the version of the loaded nvidia kernel module
+	nvidiaModuleVersionPath = "/sys/module/nvidia/version"
 	// defaultDriverInstallDir indicates the default path on the host where the driver container installation is made available
 	defaultDriverInstallDir = "/run/nvidia/driver"
 	// defaultDriverInstallDirCtrPath indicates the default path where the NVIDIA driver install dir is mounted in the container
@@ -177,6 +203,10 @@ const (
 	pluginStatusFile = "plugin-ready"
 	// cudaStatusFile indicates status file for cuda readiness
 	cudaStatusFile = "cuda-ready"
+	// p2pStatusFile indicates status file for GPU peer-to-peer readiness
+	p2pStatusFile = "p2p-ready"
+	// draStatusFile indicates status file for DRA readiness
+	draStatusFile = "dra-ready"
 	// mofedStatusFile indicates status file for mofed driver readiness
 	mofedStatusFile = "mofed-ready"
 	// vfioPCIStatusFile indicates status file for vfio-pci driver readiness
@@ -209,6 +239,10 @@ const (
 	pluginWorkloadPodSpecPath = "/opt/validator/manifests/plugin-workload-validation.yaml"
 	// cudaWorkloadPodSpecPath indicates path to cuda validation pod definition
 	cudaWorkloadPodSpecPath = "/opt/validator/manifests/cuda-workload-validation.yaml"
+	// p2pWorkloadPodSpecPath indicates path to GPU peer-to-peer validation pod definition
+	p2pWorkloadPodSpecPath = "/opt/validator/manifests/p2p-workload-validation.yaml"
+	// draWorkloadPodSpecPath indicates path to DRA cuda validation pod definition
+	draWorkloadPodSpecPath = "/opt/validator/manifests/dra-workload-validation.yaml"
 	// validatorImageEnvName indicates env name for validator image passed
 	validatorImageEnvName = "VALIDATOR_IMAGE"
 	// validatorImagePullPolicyEnvName indicates env name for validator image pull policy passed
@@ -219,6 +253,14 @@ const (
 	validatorRuntimeClassEnvName = "VALIDATOR_RUNTIME_CLASS"
 	// cudaValidatorLabelValue represents label for cuda workload validation pod
 	cudaValidatorLabelValue = "nvidia-cuda-validator"
+	// p2pValidatorLabelValue represents label for GPU peer-to-peer workload validation pod
+	p2pValidatorLabelValue = "nvidia-p2p-validator"
+	// p2pMinBandwidthEnvName indicates env name for the minimum peer-to-peer bandwidth between GPUs in GB/s
+	p2pMinBandwidthEnvName = "P2P_MIN_BANDWIDTH_GBPS"
+	// p2pMaxLatencyEnvName indicates env name for the maximum peer-to-peer latency between GPUs in microseconds
+	p2pMaxLatencyEnvName = "P2P_MAX_LATENCY_US"
+	// draValidatorLabelValue represents label for DRA cuda workload validation pod and its resource claim
+	draValidatorLabelValue = "nvidia-dra-cuda-validator"
 	// pluginValidatorLabelValue represents label for device-plugin workload validation pod
 	pluginValidatorLabelValue = "nvidia-device-plugin-validator"
 	// MellanoxDeviceLabelKey represents NFD label name for Mellanox devices
@@ -288,6 +330,13 @@ func main() {
 			Destination: &namespaceFlag,
 			Sources:     cli.EnvVars("OPERATOR_NAMESPACE"),
 		},
+		&cli.StringFlag{
+			Name:        "pod-name",
+			Value:       "",
+			Usage:       "the name of the validator pod, the revalidation failures and the validation summary are reported on it",
+			Destination: &podNameFlag,
+			Sources:     cli.EnvVars("POD_NAME"),
+		},
 		&cli.BoolFlag{
 			Name:        "with-wait",
 			Aliases:     []string{"w"},
@@ -395,6 +444,34 @@ func main() {
 			Destination: &driverValidationSkipGPUInitFlag,
 			Sources:     cli.EnvVars("DRIVER_VALIDATION_SKIP_GPU_INIT"),
 		},
+		&cli.IntFlag{
+			Name:        "p2p-min-bandwidth-gbps",
+			Value:       0,
+			Usage:       "minimum unidirectional peer-to-peer bandwidth in GB/s between each pair of GPUs. 0 means disabled.",
+			Destination: &p2pMinBandwidthFlag,
+			Sources:     cli.EnvVars(p2pMinBandwidthEnvName),
+		},
+		&cli.IntFlag{
+			Name:        "p2p-max-latency-us",
+			Value:       0,
+			Usage:       "maximum peer-to-peer write latency in microseconds between each pair of GPUs. 0 means disabled.",
+			Destination: &p2pMaxLatencyFlag,
+			Sources:     cli.EnvVars(p2pMaxLatencyEnvName),
+		},
+		&cli.IntFlag{
+			Name:        "revalidation-interval-seconds",
+			Value:       defaultRevalidationIntervalSeconds,
+			Usage:       "interval in seconds between two revalidations of the components",
+			Destination: &revalidationIntervalSecondsFlag,
+			Sources:     cli.EnvVars("REVALIDATION_INTERVAL_SECONDS"),
+		},
+		&cli.StringFlag{
+			Name:        "revalidation-components",
+			Value:       "driver,plugin,cuda",
+			Usage:       "comma separated list of the components to revalidate",
+			Destination: &revalidationComponentsFlag,
+			Sources:     cli.EnvVars("REVALIDATION_COMPONENTS"),
+		},
 	}
 
 	// Log version info
@@ -437,9 +514,37 @@ func validateFlags(ctx context.Context, cli *cli.Command) (context.Context, erro
 			return ctx, fmt.Errorf("invalid -ns <namespace> flag: must not be empty string for plugin validation")
 		}
 	}
+	if componentFlag == "dra" {
+		if nodeNameFlag == "" {
+			return ctx, fmt.Errorf("invalid -n <node-name> flag: must not be empty string for dra validation")
+		}
+		if namespaceFlag == "" {
+			return ctx, fmt.Errorf("invalid -ns <namespace> flag: must not be empty string for dra validation")
+		}
+	}
 	if componentFlag == "cuda" && namespaceFlag == "" {
 		return ctx, fmt.Errorf("invalid -ns <namespace> flag: must not be empty string for cuda validation")
 	}
+	if componentFlag == "p2p" && namespaceFlag == "" {
+		return ctx, fmt.Errorf("invalid -ns <namespace> flag: must not be empty string for p2p validation")
+	}
+	if p2pMinBandwidthFlag < 0 || p2pMaxLatencyFlag < 0 {
+		return ctx, fmt.Errorf("invalid p2p thresholds: must not be negative")
+	}
+	if componentFlag == "revalidation" {
+		if nodeNameFlag == "" {
+			return ctx, fmt.Errorf("invalid -n <node-name> flag: must not be empty string for revalidation")
+		}
+		if revalidationIntervalSecondsFlag <= 0 {
+			return ctx, fmt.Errorf("invalid revalidation interval: must be positive")
+		}
+		if _, err := parseRevalidationComponents(revalidationComponentsFlag); err != nil {
+			return ctx, fmt.Errorf("invalid revalidation components: %w", err)
+		}
+		if namespaceFlag == "" || podNameFlag == "" {
+			return ctx, fmt.Errorf("invalid -ns <namespace> or --pod-name flag: must not be empty string for revalidation")
+		}
+	}
 	if componentFlag == "metrics" {
 		if metricsPort == defaultMetricsPort {
 			return ctx, fmt.Errorf("invalid -p <port> flag: must not be empty or 0 for the metrics component")
@@ -463,10 +568,18 @@ func isValidComponent() bool {
 		fallthrough
 	case "cuda":
 		fallthrough
+	case "p2p":
+		fallthrough
+	case "p2p-workload":
+		fallthrough
 	case "metrics":
 		fallthrough
+	case "revalidation":
+		fallthrough
 	case "plugin":
 		fallthrough
+	case "dra":
+		fallthrough
 	case "mofed":
 		fallthrough
 	case "vfio-pci":
@@ -555,35 +668,35 @@ func validateComponent(ctx context.Context, componentFlag string) error {
 		driver := &Driver{
 			ctx: ctx,
 		}
-		err := driver.validate()
+		err := runReportedValidation(componentFlag, driver.validate)
 		if err != nil {
 			return fmt.Errorf("error validating driver installation: %w", err)
 		}
 		return nil
 	case NVIDIAFS:
 		nvidiaFs := &NvidiaFs{}
-		err := nvidiaFs.validate()
+		err := runReportedValidation(componentFlag, nvidiaFs.validate)
 		if err != nil {
 			return fmt.Errorf("error validating nvidia-fs driver installation: %w", err)
 		}
 		return nil
 	case GDRCOPY:
 		gdrcopy := &GDRCopy{}
-		err := gdrcopy.validate()
+		err := runReportedValidation(componentFlag, gdrcopy.validate)
 		if err != nil {
 			return fmt.Errorf("error validating gdrcopy driver installation: %w", err)
 		}
 		return nil
 	case NVIDIAPEERMEM:
 		nvidiaPeermem := &NvidiaPeermem{}
-		err := nvidiaPeermem.validate()
+		err := runReportedValidation(componentFlag, nvidiaPeermem.validate)
 		if err != nil {
 			return fmt.Errorf("error validating nvidia-peermem driver installation: %w", err)
 		}
 		return nil
 	case "toolkit":
 		toolkit := &Toolkit{}
-		err := toolkit.validate()
+		err := runReportedValidation(componentFlag, toolkit.validate)
 		if err != nil {
 			return fmt.Errorf("error validating toolkit installation: %w", err)
 		}
@@ -592,25 +705,50 @@ func validateComponent(ctx context.Context, componentFlag string) error {
 		cuda := &CUDA{
 			ctx: ctx,
 		}
-		err := cuda.validate()
+		err := runReportedValidation(componentFlag, cuda.validate)
 		if err != nil {
 			return fmt.Errorf("error validating cuda workload: %w", err)
 		}
 		return nil
+	case "p2p":
+		p2p := &P2P{
+			ctx: ctx,
+		}
+		err := runReportedValidation(componentFlag, p2p.validate)
+		if err != nil {
+			return fmt.Errorf("error validating GPU peer-to-peer workload: %w", err)
+		}
+		return nil
+	case "p2p-workload":
+		// run by the p2p validation workload pod, which is reported by the p2p component
+		err := runP2PWorkload()
+		if err != nil {
+			return fmt.Errorf("error running GPU peer-to-peer workload: %w", err)
+		}
+		return nil
 	case "plugin":
 		plugin := &Plugin{
 			ctx: ctx,
 		}
-		err := plugin.validate()
+		err := runReportedValidation(componentFlag, plugin.validate)
 		if err != nil {
 			return fmt.Errorf("error validating plugin installation: %w", err)
 		}
 		return nil
+	case "dra":
+		dra := &DRA{
+			ctx: ctx,
+		}
+		err := runReportedValidation(componentFlag, dra.validate)
+		if err != nil {
+			return fmt.Errorf("error validating DRA workload: %w", err)
+		}
+		return nil
 	case "mofed":
 		mofed := &MOFED{
 			ctx: ctx,
 		}
-		err := mofed.validate()
+		err := runReportedValidation(componentFlag, mofed.validate)
 		if err != nil {
 			return fmt.Errorf("error validating MOFED driver installation: %s", err)
 		}
@@ -624,11 +762,20 @@ func validateComponent(ctx context.Context, componentFlag string) error {
 			return fmt.Errorf("error running validation-metrics exporter: %s", err)
 		}
 		return nil
+	case "revalidation":
+		revalidation := &Revalidation{
+			ctx: ctx,
+		}
+		err := revalidation.run()
+		if err != nil {
+			return fmt.Errorf("error revalidating components: %w", err)
+		}
+		return nil
 	case "vfio-pci":
 		vfioPCI := &VfioPCI{
 			ctx: ctx,
 		}
-		err := vfioPCI.validate()
+		err := runReportedValidation(componentFlag, vfioPCI.validate)
 		if err != nil {
 			return fmt.Errorf("error validating vfio-pci driver installation: %w", err)
 		}
@@ -637,7 +784,7 @@ func validateComponent(ctx context.Context, componentFlag string) error {
 		vGPUManager := &VGPUManager{
 			ctx: ctx,
 		}
-		err := vGPUManager.validate()
+		err := runReportedValidation(componentFlag, vGPUManager.validate)
 		if err != nil {
 			return fmt.Errorf("error validating vGPU Manager installation: %w", err)
 		}
@@ -646,7 +793,7 @@ func validateComponent(ctx context.Context, componentFlag string) error {
 		vGPUDevices := &VGPUDevices{
 			ctx: ctx,
 		}
-		err := vGPUDevices.validate()
+		err := runReportedValidation(componentFlag, vGPUDevices.validate)
 		if err != nil {
 			return fmt.Errorf("error validating vGPU devices: %s", err)
 		}
@@ -655,7 +802,7 @@ func validateComponent(ctx context.Context, componentFlag string) error {
 		CCManager := &CCManager{
 			ctx: ctx,
 		}
-		err := CCManager.validate()
+		err := runReportedValidation(componentFlag, CCManager.validate)
 		if err != nil {
 			return fmt.Errorf("error validating CC Manager installation: %w", err)
 		}
@@ -666,23 +813,13 @@ func validateComponent(ctx context.Context, componentFlag string) error {
 }
 
 func runCommand(command string, args []string, silent bool) error {
-	cmd := exec.Command(command, args...)
-	if !silent {
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
-	}
-	return cmd.Run()
+	return runCheck(exec.Command(command, args...), silent)
 }
 
 func runCommandWithWait(command string, args []string, sleepSeconds int, silent bool) error {
 	for {
-		cmd := exec.Command(command, args...)
-		if !silent {
-			cmd.Stdout = os.Stdout
-			cmd.Stderr = os.Stderr
-		}
 		fmt.Printf("running command %s with args %v\n", command, args)
-		err := cmd.Run()
+		err := runCheck(exec.Command(command, args...), silent)
 		if err != nil {
 			log.Warningf("error running command: %v", err)
 			fmt.Printf("command failed, retrying after %d seconds\n", sleepSeconds)
@@ -820,11 +957,7 @@ func validateDriverContainer(silent bool, driverManagedByOperator bool) error {
 		cmd := exec.Command(nvidiaSMIPath, nvidiaSMIArgs()...)
 		// In order for nvidia-smi to run, we need to update LD_PRELOAD to include the path to libnvidia-ml.so.1.
 		cmd.Env = utils.SetEnvVar(os.Environ(), "LD_PRELOAD", utils.PrependPathListEnvvar("LD_PRELOAD", driverLibraryPath))
-		if !silent {
-			cmd.Stdout = os.Stdout
-			cmd.Stderr = os.Stderr
-		}
-		return cmd.Run()
+		return runCheck(cmd, silent)
 	}
 
 	for {
@@ -925,7 +1058,9 @@ func (d *Driver) validate() error {
 		return err
 	}
 
-	err = createDevCharSymlinks(driverInfo, disableDevCharSymlinkCreation)
+	err = runCheckFunc("create-dev-char-symlinks", func() error {
+		return createDevCharSymlinks(driverInfo, disableDevCharSymlinkCreation)
+	})
 	if err != nil {
 		msg := strings.Join([]string{
 			"Failed to create symlinks under /dev/char that point to all possible NVIDIA character devices.",
@@ -1192,14 +1327,14 @@ func (p *Plugin) validate() error {
 	// update k8s client for the plugin
 	p.setKubeClient(kubeClient)
 
-	err = p.validateGPUResource()
+	err = runCheckFunc("gpu-resources", p.validateGPUResource)
 	if err != nil {
 		return err
 	}
 
 	if withWorkloadFlag {
 		// workload test
-		err = p.runWorkload()
+		err = runCheckFunc("plugin-workload", p.runWorkload)
 		if err != nil {
 			return err
 		}
@@ -1543,7 +1678,7 @@ func (c *CUDA) validate() error {
 
 	if withWorkloadFlag {
 		// workload test
-		err = c.runWorkload()
+		err = runCheckFunc("cuda-workload", c.runWorkload)
 		if err != nil {
 			return err
 		}
@@ -1669,7 +1804,7 @@ func (v *VfioPCI) validate() error {
 		return err
 	}
 
-	err = v.runValidation()
+	err = runCheckFunc("vfio-pci-binding", v.runValidation)
 	if err != nil {
 		return err
 	}
@@ -1742,7 +1877,10 @@ func (v *VGPUManager) validate() error {
 	}
 
 	log.Info("Waiting for parent devices to be available...")
-	if err := waitForParentDevices(ctx, defaultVGPUReadinessTimeout); err != nil {
+	err = runCheckFunc("parent-devices", func() error {
+		return waitForParentDevices(ctx, defaultVGPUReadinessTimeout)
+	})
+	if err != nil {
 		return fmt.Errorf("vGPU Manager parent devices not ready: %w", err)
 	}
 
@@ -1950,7 +2088,7 @@ func (v *VGPUDevices) validate() error {
 		return err
 	}
 
-	err = v.runValidation()
+	err = runCheckFunc("vgpu-devices", v.runValidation)
 	if err != nil {
 		return err
 	}
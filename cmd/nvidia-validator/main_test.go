@@ -18,6 +18,8 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"maps"
 	"os"
 	"path/filepath"
@@ -28,6 +30,13 @@ import (
 	"github.com/NVIDIA/go-nvlib/pkg/nvmdev"
 	"github.com/NVIDIA/go-nvlib/pkg/nvpci"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/NVIDIA/gpu-operator/internal/validation"
 )
 
 func TestResolveHostNvidiaSMI(t *testing.T) {
@@ -200,6 +209,21 @@ func Test_isValidComponent(t *testing.T) {
 			component: "cuda",
 			want:      true,
 		},
+		{
+			name:      "valid p2p component",
+			component: "p2p",
+			want:      true,
+		},
+		{
+			name:      "valid dra component",
+			component: "dra",
+			want:      true,
+		},
+		{
+			name:      "valid p2p-workload component",
+			component: "p2p-workload",
+			want:      true,
+		},
 		{
 			name:      "valid plugin component",
 			component: "plugin",
@@ -355,6 +379,7 @@ UNKNOWN_FEATURE: true`,
 			// Create a temporary directory for the test
 			tmpDir := t.TempDir()
 			testStatusFile := tmpDir + "/.driver-ctr-ready"
+			setOutputDir(t, tmpDir)
 
 			// Create the status file if needed
 			if tt.createFile {
@@ -379,6 +404,53 @@ UNKNOWN_FEATURE: true`,
 	}
 }
 
+// setOutputDir sets the output directory of the validations to dir for the duration of the test
+func setOutputDir(t *testing.T, dir string) {
+	outputDir := outputDirFlag
+	outputDirFlag = dir
+	t.Cleanup(func() {
+		outputDirFlag = outputDir
+	})
+}
+
+func TestRunReportedValidation(t *testing.T) {
+	dir := t.TempDir()
+	setOutputDir(t, dir)
+
+	err := runReportedValidation("toolkit", func() error {
+		if err := runCommand(shell, []string{"-c", "echo checking devices"}, true); err != nil {
+			return err
+		}
+		return runCommand(shell, []string{"-c", "echo no devices found >&2; exit 9"}, true)
+	})
+	require.Error(t, err)
+
+	reports, err := validation.ReadReports(dir)
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+	report := reports[0]
+	require.Equal(t, "toolkit", report.Component)
+	require.Equal(t, validation.ResultFailed, report.Result)
+	require.Equal(t, validation.ErrorClassCommandFailed, report.ErrorClass)
+	require.Equal(t, "exit status 9", report.Error)
+	require.NotNil(t, report.CompletionTime)
+	require.Len(t, report.Checks, 2)
+	require.True(t, report.Checks[0].Passed)
+	require.Equal(t, "checking devices\n", report.Checks[0].Output)
+	require.False(t, report.Checks[1].Passed)
+	require.Equal(t, "no devices found\n", report.Checks[1].Output)
+	require.Nil(t, activeReport, "the report is no longer active once the validation completed")
+}
+
+func TestOutputExcerpt(t *testing.T) {
+	output := &outputExcerpt{max: 8}
+	_, err := output.Write([]byte("0123456789"))
+	require.NoError(t, err)
+	_, err = output.Write([]byte("abc"))
+	require.NoError(t, err)
+	require.Equal(t, "56789abc", output.String())
+}
+
 func newTestPF(totalVFs, numVFs uint64) *nvpci.NvidiaPCIDevice {
 	return &nvpci.NvidiaPCIDevice{
 		SriovInfo: nvpci.SriovInfo{
@@ -485,3 +557,328 @@ func TestMdevParentDevicesExist(t *testing.T) {
 	require.NoError(t, mock.AddMockA100Parent("0000:3b:00.0", 0))
 	require.True(t, mdevParentDevicesExist(mock))
 }
+
+const testP2PTestOutput = `[P2P (Peer-to-Peer) GPU Bandwidth Latency Test]
+Device: 0, NVIDIA H100 80GB HBM3, pciBusID: 18, pciDeviceID: 0, pciDomainID:0
+Device: 1, NVIDIA H100 80GB HBM3, pciBusID: 2a, pciDeviceID: 0, pciDomainID:0
+Device: 2, NVIDIA H100 80GB HBM3, pciBusID: 3a, pciDeviceID: 0, pciDomainID:0
+Device=0 CAN Access Peer Device=1
+Device=0 CAN Access Peer Device=2
+Device=1 CAN Access Peer Device=0
+Device=1 CANNOT Access Peer Device=2
+Device=2 CAN Access Peer Device=0
+Device=2 CAN Access Peer Device=1
+
+***NOTE: In case a device doesn't have P2P access to other one, it falls back to normal memcopy procedure.
+So you can see lesser Bandwidth (GB/s) and unstable Latency (us) in those cases.
+
+P2P Connectivity Matrix
+     D\D     0     1     2
+     0	     1     1     1
+     1	     1     1     0
+     2	     1     1     1
+Unidirectional P2P=Disabled Bandwidth Matrix (GB/s)
+   D\D     0      1      2
+     0 2493.51  40.12  40.02
+     1  40.33 2516.52  40.11
+     2  40.20  40.16 2519.21
+Unidirectional P2P=Enabled Bandwidth (P2P Writes) Matrix (GB/s)
+   D\D     0      1      2
+     0 2488.68 370.44 372.91
+     1 371.03 2520.10  39.95
+     2 372.45 371.67 2518.88
+P2P=Disabled Latency Matrix (us)
+   GPU     0      1      2
+     0   2.27  15.36  14.91
+     1  14.88   2.32  15.02
+     2  14.96  15.11   2.29
+
+   CPU     0      1      2
+     0   2.61   7.22   7.08
+     1   7.19   2.53   7.11
+     2   7.04   7.10   2.58
+P2P=Enabled Latency (P2P Writes) Matrix (us)
+   GPU     0      1      2
+     0   2.26   2.56   2.55
+     1   2.57   2.31  15.04
+     2   2.53   2.56   2.30
+
+   CPU     0      1      2
+     0   2.60   2.05   2.04
+     1   2.03   2.54   7.10
+     2   2.06   2.04   2.57
+
+NOTE: The CUDA Samples are not meant for performance measurements. Results may vary when GPU Boost is enabled.
+`
+
+func TestParseP2PTestOutput(t *testing.T) {
+	results, err := parseP2PTestOutput(testP2PTestOutput)
+	require.NoError(t, err)
+	require.Equal(t, 3, results.devices)
+	require.Equal(t, [][2]int{{1, 2}}, results.noAccess)
+	require.Equal(t, []float64{371.03, 2520.10, 39.95}, results.bandwidth[1])
+	require.Equal(t, []float64{2.57, 2.31, 15.04}, results.latency[1])
+
+	_, err = parseP2PTestOutput(strings.Split(testP2PTestOutput, "P2P=Disabled Latency")[0])
+	require.Error(t, err)
+
+	results, err = parseP2PTestOutput("Device: 0, NVIDIA H100 80GB HBM3, pciBusID: 18, pciDeviceID: 0, pciDomainID:0\n")
+	require.NoError(t, err)
+	require.Equal(t, 1, results.devices)
+}
+
+func TestP2PResultsCheck(t *testing.T) {
+	results, err := parseP2PTestOutput(testP2PTestOutput)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		description      string
+		minBandwidth     float64
+		maxLatency       float64
+		expectedFailures []string
+	}{
+		{
+			description:      "no thresholds",
+			expectedFailures: []string{"GPU 1 cannot access peer GPU 2"},
+		},
+		{
+			description:  "thresholds",
+			minBandwidth: 300,
+			maxLatency:   5,
+			expectedFailures: []string{
+				"GPU 1 cannot access peer GPU 2",
+				"GPU 1 to GPU 2 bandwidth 39.95 GB/s is below 300 GB/s",
+				"GPU 1 to GPU 2 latency 15.04 us is above 5 us",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			require.Equal(t, tc.expectedFailures, results.check(tc.minBandwidth, tc.maxLatency))
+		})
+	}
+
+	results.noAccess = nil
+	results.bandwidth[1][2] = 370.12
+	results.latency[1][2] = 2.58
+	require.Empty(t, results.check(300, 5))
+	require.Equal(t, "passed between 3 GPUs, lowest bandwidth 370.12 GB/s, highest latency 2.58 us", results.summary())
+}
+
+func TestP2PCheckRun(t *testing.T) {
+	singleDeviceOutput := "Device: 0, NVIDIA A100-SXM4-80GB MIG 1g.10gb, pciBusID: 18, pciDeviceID: 0, pciDomainID:0\n"
+
+	testCases := []struct {
+		description     string
+		gpus            int
+		migEnabled      bool
+		output          string
+		expectedMessage string
+		expectError     bool
+	}{
+		{
+			description:     "single GPU",
+			gpus:            1,
+			expectedMessage: "skipped, 1 GPU found",
+		},
+		{
+			description:     "MIG enabled",
+			gpus:            3,
+			migEnabled:      true,
+			expectedMessage: "skipped, not applicable with MIG enabled",
+		},
+		{
+			description:     "single GPU visible to CUDA",
+			gpus:            3,
+			output:          singleDeviceOutput,
+			expectedMessage: "skipped, not applicable with 1 GPU visible",
+		},
+		{
+			description: "missing GPUs",
+			gpus:        4,
+			output:      testP2PTestOutput,
+			expectError: true,
+		},
+		{
+			description:     "failures",
+			gpus:            3,
+			output:          testP2PTestOutput,
+			expectedMessage: "GPU 1 cannot access peer GPU 2",
+			expectError:     true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			testRan := false
+			c := &p2pCheck{
+				countGPUs:  func() (int, error) { return tc.gpus, nil },
+				migEnabled: func() (bool, error) { return tc.migEnabled, nil },
+				runTest: func() (string, error) {
+					testRan = true
+					return tc.output, nil
+				},
+			}
+			message, err := c.run()
+			require.Equal(t, tc.expectedMessage, message)
+			require.Equal(t, tc.expectError, err != nil)
+			require.Equal(t, tc.output != "", testRan)
+		})
+	}
+}
+
+func TestParseMIGMode(t *testing.T) {
+	require.False(t, parseMIGMode("[N/A]\n[N/A]\n"))
+	require.False(t, parseMIGMode("Disabled\nDisabled\n"))
+	require.True(t, parseMIGMode("Disabled\nEnabled\n"))
+}
+
+func TestNewDRAWorkloadClaim(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: meta_v1.ObjectMeta{Name: "nvidia-dra-cuda-validator-abcde", Namespace: "gpu-operator", UID: "1234"}}
+
+	testCases := []struct {
+		description     string
+		resourceAPI     schema.GroupVersion
+		expectedRequest map[string]interface{}
+	}{
+		{
+			description: "v1",
+			resourceAPI: schema.GroupVersion{Group: "resource.k8s.io", Version: "v1"},
+			expectedRequest: map[string]interface{}{
+				"name": "gpu",
+				"exactly": map[string]interface{}{
+					"deviceClassName": "gpu.nvidia.com",
+					"allocationMode":  "ExactCount",
+					"count":           int64(1),
+					"adminAccess":     true,
+				},
+			},
+		},
+		{
+			description: "v1beta1",
+			resourceAPI: schema.GroupVersion{Group: "resource.k8s.io", Version: "v1beta1"},
+			expectedRequest: map[string]interface{}{
+				"name":            "gpu",
+				"deviceClassName": "gpu.nvidia.com",
+				"allocationMode":  "ExactCount",
+				"count":           int64(1),
+				"adminAccess":     true,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			claim := newDRAWorkloadClaim(tc.resourceAPI, pod)
+			require.Equal(t, tc.resourceAPI.String(), claim.GetAPIVersion())
+			require.Equal(t, "ResourceClaim", claim.GetKind())
+			require.Equal(t, pod.Name, claim.GetName())
+			require.Equal(t, pod.Namespace, claim.GetNamespace())
+			require.Equal(t, pod.UID, claim.GetOwnerReferences()[0].UID)
+
+			requests, _, err := unstructured.NestedSlice(claim.Object, "spec", "devices", "requests")
+			require.NoError(t, err)
+			require.Equal(t, []interface{}{tc.expectedRequest}, requests)
+		})
+	}
+}
+
+func TestCountResourceSliceDevices(t *testing.T) {
+	newSlice := func(pool string, generation int64, devices int) unstructured.Unstructured {
+		deviceList := make([]interface{}, devices)
+		for i := range deviceList {
+			deviceList[i] = map[string]interface{}{"name": fmt.Sprintf("gpu-%d", i)}
+		}
+		return unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"pool":    map[string]interface{}{"name": pool, "generation": generation},
+				"devices": deviceList,
+			},
+		}}
+	}
+
+	require.Equal(t, 0, countResourceSliceDevices(nil))
+	require.Equal(t, 8, countResourceSliceDevices([]unstructured.Unstructured{
+		newSlice("node-a", 1, 4),
+		newSlice("node-a", 1, 4),
+	}))
+	// slices of an outdated generation of the pool are ignored
+	require.Equal(t, 2, countResourceSliceDevices([]unstructured.Unstructured{
+		newSlice("node-a", 1, 4),
+		newSlice("node-a", 2, 2),
+		newSlice("node-b", 0, 0),
+	}))
+}
+
+func TestDRAWorkloadPodNode(t *testing.T) {
+	pod := &corev1.Pod{}
+	require.Empty(t, draWorkloadPodNode(pod))
+
+	pod.Spec.Affinity = draWorkloadNodeAffinity("node-a")
+	require.Equal(t, "node-a", draWorkloadPodNode(pod))
+
+	pod.Spec.NodeName = "node-b"
+	require.Equal(t, "node-b", draWorkloadPodNode(pod))
+}
+
+func TestCountGPUResourceHealth(t *testing.T) {
+	node := &corev1.Node{Status: corev1.NodeStatus{
+		Capacity: corev1.ResourceList{
+			corev1.ResourceCPU:       resource.MustParse("64"),
+			"nvidia.com/gpu":         resource.MustParse("4"),
+			"nvidia.com/mig-1g.10gb": resource.MustParse("7"),
+		},
+		Allocatable: corev1.ResourceList{
+			corev1.ResourceCPU:       resource.MustParse("63"),
+			"nvidia.com/gpu":         resource.MustParse("3"),
+			"nvidia.com/mig-1g.10gb": resource.MustParse("7"),
+		},
+	}}
+	capacity, allocatable := countGPUResourceHealth(node)
+	require.EqualValues(t, 11, capacity)
+	require.EqualValues(t, 10, allocatable)
+}
+
+func TestRevalidate(t *testing.T) {
+	dir := t.TempDir()
+	setOutputDir(t, dir)
+	driverStatus := "IS_HOST_DRIVER=true\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, driverStatusFile), []byte(driverStatus), 0600))
+
+	var driverErr error
+	var reported [][]string
+	r := &Revalidation{
+		components: []string{"driver"},
+		checks: map[string]revalidationCheck{
+			"driver": {name: "nvidia-smi", run: func() (string, error) { return "", driverErr }},
+		},
+		failed: map[string]bool{},
+		reportFailures: func(failed []string) error {
+			reported = append(reported, failed)
+			return nil
+		},
+	}
+
+	// passing revalidations do not update the report, the failures are reported once
+	r.revalidate()
+	r.revalidate()
+	require.NoFileExists(t, filepath.Join(dir, validation.ReportFileName("driver")))
+	require.Equal(t, [][]string{nil}, reported)
+
+	driverErr = errors.New("Unable to determine the device handle for GPU0000:3B:00.0: Unknown Error")
+	r.revalidate()
+	// the status file other operands wait on is kept
+	data, err := os.ReadFile(filepath.Join(dir, driverStatusFile))
+	require.NoError(t, err)
+	require.Equal(t, driverStatus, string(data))
+	reports, err := validation.ReadReports(dir)
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+	require.Equal(t, validation.ResultFailed, reports[0].Result)
+	require.Equal(t, [][]string{nil, {"driver"}}, reported)
+
+	driverErr = nil
+	r.revalidate()
+	reports, err = validation.ReadReports(dir)
+	require.NoError(t, err)
+	require.Equal(t, validation.ResultPassed, reports[0].Result)
+	require.Equal(t, [][]string{nil, {"driver"}, nil}, reported)
+}
@@ -19,11 +19,13 @@ package main
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"os"
 	"os/exec"
 	"strings"
+	"sync"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -32,8 +34,12 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+
+	"github.com/NVIDIA/gpu-operator/internal/validation"
 )
 
 const (
@@ -57,6 +63,7 @@ type NodeMetrics struct {
 	toolkitReady promcli.Gauge
 	pluginReady  promcli.Gauge
 	cudaReady    promcli.Gauge
+	p2pReady     promcli.Gauge
 
 	driverValidation            promcli.Gauge
 	driverValidationLastSuccess promcli.Gauge
@@ -65,6 +72,13 @@ type NodeMetrics struct {
 	pluginValidationLastSuccess promcli.Gauge
 
 	nvidiaPciDevices promcli.Gauge
+
+	validationResult         *promcli.GaugeVec
+	validationCompletionTime *promcli.GaugeVec
+
+	// reports are the last validation reports read from the output directory
+	reportsLock sync.Mutex
+	reports     []*validation.Report
 }
 
 // NewNodeMetrics creates a NodeMetrics with its Prometheus metrics objects initialized (and automatically registered by promauto)
@@ -112,6 +126,14 @@ func NewNodeMetrics(ctx context.Context, port int) NodeMetrics {
 			[]string{"node"},
 		).WithLabelValues(nodeNameFlag),
 
+		p2pReady: promauto.NewGaugeVec(
+			promcli.GaugeOpts{
+				Name: "gpu_operator_node_p2p_ready",
+				Help: "1 if the peer-to-peer validation of the GPUs of the local node passed, 0 otherwise",
+			},
+			[]string{"node"},
+		).WithLabelValues(nodeNameFlag),
+
 		driverValidation: promauto.NewGaugeVec(
 			promcli.GaugeOpts{
 				Name: "gpu_operator_node_driver_validation",
@@ -151,6 +173,22 @@ func NewNodeMetrics(ctx context.Context, port int) NodeMetrics {
 			},
 			[]string{"node"},
 		).WithLabelValues(nodeNameFlag),
+
+		validationResult: promauto.NewGaugeVec(
+			promcli.GaugeOpts{
+				Name: "gpu_operator_node_validation_result",
+				Help: "1 if the last validation of the component passed on the local node, 0 if it failed, -1 while it runs",
+			},
+			[]string{"node", "component", "error_class"},
+		).MustCurryWith(promcli.Labels{"node": nodeNameFlag}),
+
+		validationCompletionTime: promauto.NewGaugeVec(
+			promcli.GaugeOpts{
+				Name: "gpu_operator_node_validation_completion_ts_seconds",
+				Help: "timestamp (in seconds) of the completion of the last validation of the component on the local node",
+			},
+			[]string{"node", "component"},
+		).MustCurryWith(promcli.Labels{"node": nodeNameFlag}),
 	}
 }
 
@@ -245,6 +283,104 @@ func (nm *NodeMetrics) watchDriverValidation() {
 	}
 }
 
+// watchValidationReports exposes the validation reports of the output directory, and
+// publishes their summary on the node status exporter pod for the operator
+func (nm *NodeMetrics) watchValidationReports() {
+	var kubeClient kubernetes.Interface
+	kubeConfig, err := rest.InClusterConfig()
+	if err == nil {
+		kubeClient, err = kubernetes.NewForConfig(kubeConfig)
+	}
+	if err == nil && (namespaceFlag == "" || podNameFlag == "") {
+		err = fmt.Errorf("the namespace or name of the pod is not set")
+	}
+	if err != nil {
+		log.Errorf("metrics: Validation reports: not publishing the summary on the pod: %v", err)
+		kubeClient = nil
+	}
+
+	published := ""
+	for {
+		reports, err := validation.ReadReports(outputDirFlag)
+		if err != nil {
+			log.Errorf("metrics: Validation reports: could not read the reports: %v", err)
+		} else {
+			nm.setValidationReports(reports)
+			if kubeClient != nil {
+				published = nm.publishValidationSummary(kubeClient, reports, published)
+			}
+		}
+		time.Sleep(statusFileCheckDelaySeconds * time.Second)
+	}
+}
+
+// setValidationReports updates the validation metrics with reports
+func (nm *NodeMetrics) setValidationReports(reports []*validation.Report) {
+	nm.reportsLock.Lock()
+	nm.reports = reports
+	nm.reportsLock.Unlock()
+
+	nm.validationResult.Reset()
+	nm.validationCompletionTime.Reset()
+	for _, report := range reports {
+		result := -1.0
+		switch report.Result {
+		case validation.ResultPassed:
+			result = 1
+		case validation.ResultFailed:
+			result = 0
+		}
+		nm.validationResult.WithLabelValues(report.Component, string(report.ErrorClass)).Set(result)
+		if report.CompletionTime != nil {
+			nm.validationCompletionTime.WithLabelValues(report.Component).Set(float64(report.CompletionTime.Unix()))
+		}
+	}
+}
+
+// publishValidationSummary sets the summary of reports as an annotation of the node status
+// exporter pod, if it differs from the published one. It returns the summary published on
+// the pod.
+func (nm *NodeMetrics) publishValidationSummary(kubeClient kubernetes.Interface, reports []*validation.Report, published string) string {
+	summary, err := json.Marshal(validation.Summarize(reports))
+	if err != nil {
+		log.Errorf("metrics: Validation reports: could not encode the summary: %v", err)
+		return published
+	}
+	if string(summary) == published {
+		return published
+	}
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"annotations": map[string]string{validation.SummaryAnnotation: string(summary)},
+		},
+	})
+	if err != nil {
+		log.Errorf("metrics: Validation reports: could not encode the pod patch: %v", err)
+		return published
+	}
+	_, err = kubeClient.CoreV1().Pods(namespaceFlag).Patch(nm.ctx, podNameFlag, types.MergePatchType, patch, meta_v1.PatchOptions{})
+	if err != nil {
+		log.Errorf("metrics: Validation reports: could not publish the summary on the pod: %v", err)
+		return published
+	}
+	return string(summary)
+}
+
+// serveValidationReports serves the last validation reports as JSON
+func (nm *NodeMetrics) serveValidationReports(w http.ResponseWriter, _ *http.Request) {
+	nm.reportsLock.Lock()
+	reports := nm.reports
+	nm.reportsLock.Unlock()
+	if reports == nil {
+		reports = []*validation.Report{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(reports); err != nil {
+		log.Errorf("metrics: Validation reports: could not serve the reports: %v", err)
+	}
+}
+
 func runLsPCI() (string, error) {
 	var out bytes.Buffer
 
@@ -304,13 +440,16 @@ func (nm *NodeMetrics) Run() error {
 	go nm.watchStatusFile(&nm.toolkitReady, toolkitStatusFile)
 	go nm.watchStatusFile(&nm.pluginReady, pluginStatusFile)
 	go nm.watchStatusFile(&nm.cudaReady, cudaStatusFile)
+	go nm.watchStatusFile(&nm.p2pReady, p2pStatusFile)
 
 	go nm.watchDriverValidation()
 	go nm.watchDevicePluginValidation()
 	go nm.watchNVIDIAPCI()
+	go nm.watchValidationReports()
 
 	log.Printf("Running the metrics server, listening on :%d/metrics", nm.port)
 	http.Handle("/metrics", promhttp.Handler())
+	http.HandleFunc("/validations", nm.serveValidationReports)
 
 	server := &http.Server{
 		Addr:        fmt.Sprintf(":%d", nm.port),
@@ -0,0 +1,429 @@
+/*
+ * Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/NVIDIA/go-nvlib/pkg/nvpci"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/NVIDIA/gpu-operator/internal/validation"
+)
+
+const (
+	// p2pTestCommand is the CUDA sample measuring the peer-to-peer access, bandwidth and
+	// latency between all GPUs of the node
+	p2pTestCommand = "p2pBandwidthLatencyTest"
+	// p2pWorkloadContainerName is the name of the container running the peer-to-peer test
+	// in the p2p validation workload pod
+	p2pWorkloadContainerName = "p2p-validation"
+	// p2pTerminationMessagePath is the path of the termination message of the container
+	// running the peer-to-peer test, which holds the result of the test
+	p2pTerminationMessagePath = "/dev/termination-log"
+
+	p2pBandwidthMatrixTitle = "Unidirectional P2P=Enabled Bandwidth (P2P Writes) Matrix (GB/s)"
+	p2pLatencyMatrixTitle   = "P2P=Enabled Latency (P2P Writes) Matrix (us)"
+)
+
+func (p *P2P) validate() error {
+	// delete status file is already present
+	err := deleteStatusFile(outputDirFlag + "/" + p2pStatusFile)
+	if err != nil {
+		return err
+	}
+
+	// deploy workload pod for p2p validation
+	kubeConfig, err := rest.InClusterConfig()
+	if err != nil {
+		log.Errorf("Error getting config cluster - %s\n", err.Error())
+		return err
+	}
+
+	kubeClient, err := kubernetes.NewForConfig(kubeConfig)
+	if err != nil {
+		log.Errorf("Error getting k8s client - %s\n", err.Error())
+		return err
+	}
+
+	p.kubeClient = kubeClient
+
+	if withWorkloadFlag {
+		// workload test
+		err = runCheckFunc("p2p-workload", p.runWorkload)
+		if err != nil {
+			return err
+		}
+	}
+
+	// create p2p status file
+	err = createStatusFile(outputDirFlag + "/" + p2pStatusFile)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (p *P2P) runWorkload() error {
+	ctx := p.ctx
+
+	// load podSpec
+	pod, err := loadPodSpec(p2pWorkloadPodSpecPath)
+	if err != nil {
+		return err
+	}
+	pod.Namespace = namespaceFlag
+	image := os.Getenv(validatorImageEnvName)
+	pod.Spec.Containers[0].Image = image
+	pod.Spec.InitContainers[0].Image = image
+
+	imagePullPolicy := os.Getenv(validatorImagePullPolicyEnvName)
+	if imagePullPolicy != "" {
+		pod.Spec.Containers[0].ImagePullPolicy = corev1.PullPolicy(imagePullPolicy)
+		pod.Spec.InitContainers[0].ImagePullPolicy = corev1.PullPolicy(imagePullPolicy)
+	}
+
+	if os.Getenv(validatorImagePullSecretsEnvName) != "" {
+		pullSecrets := strings.Split(os.Getenv(validatorImagePullSecretsEnvName), ",")
+		for _, secret := range pullSecrets {
+			pod.Spec.ImagePullSecrets = append(pod.Spec.ImagePullSecrets, corev1.LocalObjectReference{Name: secret})
+		}
+	}
+	if os.Getenv(validatorRuntimeClassEnvName) != "" {
+		runtimeClass := os.Getenv(validatorRuntimeClassEnvName)
+		pod.Spec.RuntimeClassName = &runtimeClass
+	}
+
+	// pass the thresholds to the peer-to-peer test
+	pod.Spec.InitContainers[0].Env = append(pod.Spec.InitContainers[0].Env,
+		corev1.EnvVar{Name: p2pMinBandwidthEnvName, Value: strconv.Itoa(p2pMinBandwidthFlag)},
+		corev1.EnvVar{Name: p2pMaxLatencyEnvName, Value: strconv.Itoa(p2pMaxLatencyFlag)},
+	)
+
+	validatorDaemonset, err := p.kubeClient.AppsV1().DaemonSets(namespaceFlag).Get(ctx, "nvidia-operator-validator", meta_v1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to retrieve the operator validator daemonset: %w", err)
+	}
+
+	// update owner reference
+	pod.SetOwnerReferences(validatorDaemonset.OwnerReferences)
+	// set pod tolerations
+	pod.Spec.Tolerations = validatorDaemonset.Spec.Template.Spec.Tolerations
+	// update podSpec with node name, so it will just run on current node
+	pod.Spec.NodeName = nodeNameFlag
+
+	opts := meta_v1.ListOptions{LabelSelector: labels.Set{"app": p2pValidatorLabelValue}.AsSelector().String(),
+		FieldSelector: fields.Set{"spec.nodeName": nodeNameFlag}.AsSelector().String()}
+
+	// check if p2p workload pod is already running and cleanup.
+	podList, err := p.kubeClient.CoreV1().Pods(namespaceFlag).List(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("cannot list existing validation pods: %s", err)
+	}
+
+	if podList != nil && len(podList.Items) > 0 {
+		propagation := meta_v1.DeletePropagationBackground
+		gracePeriod := int64(0)
+		options := meta_v1.DeleteOptions{PropagationPolicy: &propagation, GracePeriodSeconds: &gracePeriod}
+		err = p.kubeClient.CoreV1().Pods(namespaceFlag).Delete(ctx, podList.Items[0].Name, options)
+		if err != nil {
+			return fmt.Errorf("cannot delete previous validation pod: %s", err)
+		}
+	}
+
+	// wait for p2p workload pod to be ready.
+	newPod, err := p.kubeClient.CoreV1().Pods(namespaceFlag).Create(ctx, pod, meta_v1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create p2p validation pod %s, err %+v", pod.Name, err)
+	}
+
+	// make sure it's available
+	err = waitForPod(ctx, p.kubeClient, newPod.Name, namespaceFlag)
+	// the peer-to-peer test leaves its result in the termination message of its container
+	if finishedPod, getErr := p.kubeClient.CoreV1().Pods(namespaceFlag).Get(ctx, newPod.Name, meta_v1.GetOptions{}); getErr == nil {
+		if message := p2pWorkloadMessage(finishedPod); message != "" {
+			if err != nil {
+				return fmt.Errorf("%w: %s", err, message)
+			}
+			log.Infof("p2p validation: %s", message)
+		}
+	}
+	return err
+}
+
+// p2pWorkloadMessage returns the termination message of the last run of the peer-to-peer
+// test in pod
+func p2pWorkloadMessage(pod *corev1.Pod) string {
+	for _, status := range pod.Status.InitContainerStatuses {
+		if status.Name != p2pWorkloadContainerName {
+			continue
+		}
+		if status.State.Terminated != nil && status.State.Terminated.Message != "" {
+			return strings.TrimSpace(status.State.Terminated.Message)
+		}
+		if status.LastTerminationState.Terminated != nil {
+			return strings.TrimSpace(status.LastTerminationState.Terminated.Message)
+		}
+	}
+	return ""
+}
+
+// runP2PWorkload runs the peer-to-peer test between all GPUs of the node and checks its
+// results against the thresholds. It runs in the p2p validation workload pod.
+func runP2PWorkload() error {
+	message, err := newP2PCheck().run()
+	if message == "" && err != nil {
+		message = err.Error()
+	}
+	if len(message) > validation.MaxOutputBytes {
+		message = message[:validation.MaxOutputBytes]
+	}
+	if writeErr := os.WriteFile(p2pTerminationMessagePath, []byte(message), 0644); writeErr != nil {
+		log.Warnf("failed to write the p2p validation result: %v", writeErr)
+	}
+	return err
+}
+
+// p2pCheck runs the peer-to-peer test, through functions replaced in tests
+type p2pCheck struct {
+	// countGPUs returns the number of NVIDIA GPUs on the PCI bus
+	countGPUs func() (int, error)
+	// migEnabled returns true if MIG is enabled on any GPU
+	migEnabled func() (bool, error)
+	// runTest runs the peer-to-peer test and returns its output
+	runTest func() (string, error)
+}
+
+func newP2PCheck() *p2pCheck {
+	return &p2pCheck{
+		countGPUs: func() (int, error) {
+			gpus, err := nvpci.New().GetGPUs()
+			return len(gpus), err
+		},
+		migEnabled: isMIGEnabled,
+		runTest: func() (string, error) {
+			output := &bytes.Buffer{}
+			cmd := exec.Command(p2pTestCommand)
+			cmd.Stdout = io.MultiWriter(os.Stdout, output)
+			cmd.Stderr = os.Stderr
+			err := cmd.Run()
+			return output.String(), err
+		},
+	}
+}
+
+// run runs the peer-to-peer test and returns a description of its result. The test does
+// not apply to nodes with MIG enabled, where CUDA only sees a single MIG device, nor to
+// nodes where less than two GPUs are visible to CUDA.
+func (c *p2pCheck) run() (string, error) {
+	gpus, err := c.countGPUs()
+	if err != nil {
+		return "", fmt.Errorf("error getting NVIDIA PCI devices: %w", err)
+	}
+	if gpus < 2 {
+		log.Infof("Skipping p2p validation, %d GPU found on the node", gpus)
+		return fmt.Sprintf("skipped, %d GPU found", gpus), nil
+	}
+
+	migEnabled, err := c.migEnabled()
+	if err != nil {
+		return "", fmt.Errorf("error getting the MIG mode of the GPUs: %w", err)
+	}
+	if migEnabled {
+		log.Info("Skipping p2p validation, MIG is enabled on the node")
+		return "skipped, not applicable with MIG enabled", nil
+	}
+
+	output, err := c.runTest()
+	if err != nil {
+		return "", fmt.Errorf("error running %s: %w", p2pTestCommand, err)
+	}
+
+	results, err := parseP2PTestOutput(output)
+	if err != nil {
+		return "", err
+	}
+	if results.devices < 2 {
+		log.Infof("Skipping p2p validation, %d GPU visible to CUDA", results.devices)
+		return fmt.Sprintf("skipped, not applicable with %d GPU visible", results.devices), nil
+	}
+	if results.devices != gpus {
+		return "", fmt.Errorf("%s found %d GPUs, expected %d", p2pTestCommand, results.devices, gpus)
+	}
+	failures := results.check(float64(p2pMinBandwidthFlag), float64(p2pMaxLatencyFlag))
+	if len(failures) > 0 {
+		return strings.Join(failures, "; "), fmt.Errorf("p2p validation failed between %d GPUs", results.devices)
+	}
+	return results.summary(), nil
+}
+
+// isMIGEnabled returns true if nvidia-smi reports MIG enabled on any GPU
+func isMIGEnabled() (bool, error) {
+	output, err := exec.Command("nvidia-smi", "--query-gpu=mig.mode.current", "--format=csv,noheader").Output()
+	if err != nil {
+		return false, err
+	}
+	return parseMIGMode(string(output)), nil
+}
+
+// parseMIGMode returns true if any line of the mig.mode.current nvidia-smi query is Enabled.
+// GPUs not supporting MIG report [N/A].
+func parseMIGMode(output string) bool {
+	for _, line := range strings.Split(output, "\n") {
+		if strings.TrimSpace(line) == "Enabled" {
+			return true
+		}
+	}
+	return false
+}
+
+// p2pResults are the results of the peer-to-peer test
+type p2pResults struct {
+	devices int
+	// noAccess are the pairs of GPUs where the first cannot access the second as a peer
+	noAccess [][2]int
+	// bandwidth is the unidirectional P2P write bandwidth between GPUs, in GB/s
+	bandwidth [][]float64
+	// latency is the P2P write latency between GPUs, in microseconds
+	latency [][]float64
+}
+
+// parseP2PTestOutput parses the output of the p2pBandwidthLatencyTest CUDA sample
+func parseP2PTestOutput(output string) (*p2pResults, error) {
+	results := &p2pResults{}
+	lines := strings.Split(output, "\n")
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "Device: "):
+			results.devices++
+		case strings.HasPrefix(line, "Device=") && strings.Contains(line, "Access Peer"):
+			var device, peer int
+			var access string
+			if _, err := fmt.Sscanf(line, "Device=%d %s Access Peer Device=%d", &device, &access, &peer); err != nil {
+				return nil, fmt.Errorf("unexpected peer access line %q: %w", line, err)
+			}
+			if access != "CAN" {
+				results.noAccess = append(results.noAccess, [2]int{device, peer})
+			}
+		case line == p2pBandwidthMatrixTitle:
+			results.bandwidth = parseP2PMatrix(lines[i+1:])
+		case line == p2pLatencyMatrixTitle:
+			results.latency = parseP2PMatrix(lines[i+1:])
+		}
+	}
+	if results.devices < 2 {
+		return results, nil
+	}
+	if len(results.bandwidth) != results.devices {
+		return nil, fmt.Errorf("found %d rows in the P2P bandwidth matrix, expected %d", len(results.bandwidth), results.devices)
+	}
+	if len(results.latency) != results.devices {
+		return nil, fmt.Errorf("found %d rows in the P2P latency matrix, expected %d", len(results.latency), results.devices)
+	}
+	return results, nil
+}
+
+// parseP2PMatrix parses the matrix starting with a header row at the beginning of lines
+func parseP2PMatrix(lines []string) [][]float64 {
+	var matrix [][]float64
+	for i, line := range lines {
+		if i == 0 {
+			// header row
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			break
+		}
+		if _, err := strconv.Atoi(fields[0]); err != nil {
+			break
+		}
+		row := make([]float64, 0, len(fields)-1)
+		for _, field := range fields[1:] {
+			value, err := strconv.ParseFloat(field, 64)
+			if err != nil {
+				return matrix
+			}
+			row = append(row, value)
+		}
+		matrix = append(matrix, row)
+	}
+	return matrix
+}
+
+// check returns the failures of the peer-to-peer access, and of the bandwidth and latency
+// between GPUs against minBandwidth and maxLatency, if not 0
+func (r *p2pResults) check(minBandwidth, maxLatency float64) []string {
+	var failures []string
+	for _, pair := range r.noAccess {
+		failures = append(failures, fmt.Sprintf("GPU %d cannot access peer GPU %d", pair[0], pair[1]))
+	}
+	for i := range r.bandwidth {
+		for j := range r.bandwidth[i] {
+			if i == j {
+				continue
+			}
+			if minBandwidth > 0 && r.bandwidth[i][j] < minBandwidth {
+				failures = append(failures, fmt.Sprintf("GPU %d to GPU %d bandwidth %.2f GB/s is below %.0f GB/s", i, j, r.bandwidth[i][j], minBandwidth))
+			}
+		}
+	}
+	for i := range r.latency {
+		for j := range r.latency[i] {
+			if i == j {
+				continue
+			}
+			if maxLatency > 0 && r.latency[i][j] > maxLatency {
+				failures = append(failures, fmt.Sprintf("GPU %d to GPU %d latency %.2f us is above %.0f us", i, j, r.latency[i][j], maxLatency))
+			}
+		}
+	}
+	return failures
+}
+
+// summary returns the lowest bandwidth and highest latency between GPUs
+func (r *p2pResults) summary() string {
+	minBandwidth, maxLatency := -1.0, -1.0
+	for i := range r.bandwidth {
+		for j := range r.bandwidth[i] {
+			if i != j && (minBandwidth < 0 || r.bandwidth[i][j] < minBandwidth) {
+				minBandwidth = r.bandwidth[i][j]
+			}
+		}
+	}
+	for i := range r.latency {
+		for j := range r.latency[i] {
+			if i != j && r.latency[i][j] > maxLatency {
+				maxLatency = r.latency[i][j]
+			}
+		}
+	}
+	return fmt.Sprintf("passed between %d GPUs, lowest bandwidth %.2f GB/s, highest latency %.2f us", r.devices, minBandwidth, maxLatency)
+}
@@ -0,0 +1,128 @@
+/*
+ * Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/NVIDIA/gpu-operator/internal/utils"
+	"github.com/NVIDIA/gpu-operator/internal/validation"
+)
+
+// activeReport is the report of the component being validated, nil when no validation
+// is reported. Components validated as part of another, like nvidia-fs with the driver,
+// have their own report.
+var activeReport *validation.Report
+
+// runReportedValidation runs validate, the validation of component, and writes its report
+// to the output directory while it runs and once it completes
+func runReportedValidation(component string, validate func() error) error {
+	report := validation.NewReport(component, nodeNameFlag, time.Now())
+	parent := activeReport
+	activeReport = report
+	defer func() {
+		activeReport = parent
+	}()
+
+	writeReport(report)
+	err := validate()
+	report.DriverVersion = detectDriverVersion()
+	report.Complete(time.Now(), err)
+	writeReport(report)
+	return err
+}
+
+// writeReport writes report to the output directory. Failures are logged, reports do not
+// fail validations.
+func writeReport(report *validation.Report) {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		log.Warnf("failed to encode the %s validation report: %v", report.Component, err)
+		return
+	}
+	path := filepath.Join(outputDirFlag, validation.ReportFileName(report.Component))
+	if err := utils.WriteFileAtomically(path, string(data)+"\n"); err != nil {
+		log.Warnf("failed to write the %s validation report: %v", report.Component, err)
+	}
+}
+
+// detectDriverVersion returns the version of the NVIDIA kernel module loaded on the node,
+// or "" if it is not loaded
+func detectDriverVersion() string {
+	data, err := os.ReadFile(nvidiaModuleVersionPath)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// runCheck runs cmd and records it as a check of the active report, with the end of
+// its output. The output is also written to the standard streams unless silent.
+func runCheck(cmd *exec.Cmd, silent bool) error {
+	output := &outputExcerpt{max: validation.MaxOutputBytes}
+	if silent {
+		cmd.Stdout = output
+		cmd.Stderr = output
+	} else {
+		cmd.Stdout = io.MultiWriter(os.Stdout, output)
+		cmd.Stderr = io.MultiWriter(os.Stderr, output)
+	}
+	start := time.Now()
+	err := cmd.Run()
+	if activeReport != nil {
+		activeReport.AddCheck(strings.Join(cmd.Args, " "), start, time.Now(), output.String(), err)
+		writeReport(activeReport)
+	}
+	return err
+}
+
+// runCheckFunc runs check and records it as the check name of the active report
+func runCheckFunc(name string, check func() error) error {
+	start := time.Now()
+	err := check()
+	if activeReport != nil {
+		activeReport.AddCheck(name, start, time.Now(), "", err)
+		writeReport(activeReport)
+	}
+	return err
+}
+
+// outputExcerpt is a writer keeping the last max bytes written to it
+type outputExcerpt struct {
+	max int
+	buf []byte
+}
+
+func (o *outputExcerpt) Write(p []byte) (int, error) {
+	o.buf = append(o.buf, p...)
+	if len(o.buf) > o.max {
+		o.buf = o.buf[len(o.buf)-o.max:]
+	}
+	return len(p), nil
+}
+
+func (o *outputExcerpt) String() string {
+	return string(o.buf)
+}
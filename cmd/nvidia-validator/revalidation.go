@@ -0,0 +1,229 @@
+/*
+ * Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/NVIDIA/gpu-operator/internal/validation"
+)
+
+// revalidationCheck is the check of a revalidated component. It returns the output of the check.
+type revalidationCheck struct {
+	name string
+	run  func() (string, error)
+}
+
+// Revalidation represents spec to periodically revalidate components after their validation
+type Revalidation struct {
+	ctx        context.Context
+	kubeClient kubernetes.Interface
+	// checks are the checks of the revalidated components, by component
+	checks map[string]revalidationCheck
+	// components are the revalidated components, in order
+	components []string
+	// failed are the components which failed their last revalidation
+	failed map[string]bool
+	// reportFailures reports the components failing their revalidation, annotatePod if unset
+	reportFailures func(failed []string) error
+	// reported is nil until the failing components are reported
+	reported *string
+}
+
+// revalidationComponents are the components which can be revalidated
+var revalidationComponents = map[string]bool{
+	"driver": true,
+	"plugin": true,
+	"cuda":   true,
+}
+
+// parseRevalidationComponents returns the components of the comma separated list value
+func parseRevalidationComponents(value string) ([]string, error) {
+	var components []string
+	for _, component := range strings.Split(value, ",") {
+		component = strings.TrimSpace(component)
+		if component == "" {
+			continue
+		}
+		if !revalidationComponents[component] {
+			return nil, fmt.Errorf("component %s cannot be revalidated", component)
+		}
+		components = append(components, component)
+	}
+	return components, nil
+}
+
+func (r *Revalidation) run() error {
+	components, err := parseRevalidationComponents(revalidationComponentsFlag)
+	if err != nil {
+		return err
+	}
+	r.components = components
+
+	kubeConfig, err := rest.InClusterConfig()
+	if err != nil {
+		return fmt.Errorf("error getting cluster config - %w", err)
+	}
+	r.kubeClient, err = kubernetes.NewForConfig(kubeConfig)
+	if err != nil {
+		return fmt.Errorf("error getting k8s client - %w", err)
+	}
+
+	r.checks = map[string]revalidationCheck{
+		"driver": {name: "nvidia-smi", run: func() (string, error) { return runRevalidationCommand("nvidia-smi") }},
+		"plugin": {name: "gpu-resources-health", run: r.checkGPUResources},
+		"cuda":   {name: "vectorAdd", run: func() (string, error) { return runRevalidationCommand("vectorAdd") }},
+	}
+	r.reportFailures = r.annotatePod
+	r.failed = map[string]bool{}
+
+	interval := time.Duration(revalidationIntervalSecondsFlag) * time.Second
+	log.Infof("Revalidating %s every %s", strings.Join(r.components, ","), interval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.ctx.Done():
+			return nil
+		case <-ticker.C:
+			r.revalidate()
+		}
+	}
+}
+
+// revalidate revalidates the components. The report of a component is updated when its
+// revalidation starts failing or passes again, and the failing components are reported on
+// the validator pod, the operator marks the node. The status files of the components are
+// left in place: the other operands wait on them, and a revalidation may fail on a GPU busy
+// with workloads.
+func (r *Revalidation) revalidate() {
+	var failedComponents []string
+	for _, component := range r.components {
+		check := r.checks[component]
+		report := validation.NewReport(component, nodeNameFlag, time.Now())
+		start := time.Now()
+		output, err := check.run()
+		report.AddCheck(check.name, start, time.Now(), output, err)
+		report.DriverVersion = detectDriverVersion()
+		report.Complete(time.Now(), err)
+
+		failed := err != nil
+		if failed {
+			failedComponents = append(failedComponents, component)
+		}
+		if failed == r.failed[component] {
+			continue
+		}
+		r.failed[component] = failed
+		writeReport(report)
+		if failed {
+			log.Errorf("%s revalidation failed: %v", component, err)
+		} else {
+			log.Infof("%s revalidation passed", component)
+		}
+	}
+
+	// the failures are always reported once, the pod may keep the failures reported by a
+	// previous run of the container
+	reported := strings.Join(failedComponents, ",")
+	if r.reported != nil && *r.reported == reported {
+		return
+	}
+	if err := r.reportFailures(failedComponents); err != nil {
+		log.Warnf("failed to report the revalidation failures on pod %s: %v", podNameFlag, err)
+		return
+	}
+	r.reported = &reported
+}
+
+// annotatePod sets the failed components as an annotation of the validator pod, or removes
+// the annotation if none failed
+func (r *Revalidation) annotatePod(failed []string) error {
+	var value any
+	if len(failed) > 0 {
+		value = strings.Join(failed, ",")
+	}
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"annotations": map[string]any{validation.RevalidationFailedAnnotation: value},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = r.kubeClient.CoreV1().Pods(namespaceFlag).Patch(r.ctx, podNameFlag, types.MergePatchType, patch, meta_v1.PatchOptions{})
+	return err
+}
+
+// checkGPUResources checks that all the GPU resources of the node are healthy, the device
+// plugin withdraws unhealthy GPUs from the allocatable resources
+func (r *Revalidation) checkGPUResources() (string, error) {
+	node, err := getNode(r.ctx, r.kubeClient)
+	if err != nil {
+		return "", fmt.Errorf("unable to fetch node by name %s to check for GPU resources: %w", nodeNameFlag, err)
+	}
+	capacity, allocatable := countGPUResourceHealth(node)
+	output := fmt.Sprintf("%d of %d GPU resources allocatable", allocatable, capacity)
+	if capacity == 0 {
+		return output, fmt.Errorf("no GPU resources advertised by the node")
+	}
+	if allocatable < capacity {
+		return output, fmt.Errorf("%d of %d GPU resources are unhealthy", capacity-allocatable, capacity)
+	}
+	return output, nil
+}
+
+// countGPUResourceHealth returns the capacity and the allocatable number of GPU resources
+// of node
+func countGPUResourceHealth(node *corev1.Node) (capacity int64, allocatable int64) {
+	isGPUResource := func(name corev1.ResourceName) bool {
+		return strings.HasPrefix(string(name), migGPUResourcePrefix) || strings.HasPrefix(string(name), genericGPUResourceType)
+	}
+	for name, quantity := range node.Status.Capacity {
+		if isGPUResource(name) {
+			capacity += quantity.Value()
+		}
+	}
+	for name, quantity := range node.Status.Allocatable {
+		if isGPUResource(name) {
+			allocatable += quantity.Value()
+		}
+	}
+	return capacity, allocatable
+}
+
+// runRevalidationCommand runs command and returns the end of its output
+func runRevalidationCommand(command string) (string, error) {
+	output := &outputExcerpt{max: validation.MaxOutputBytes}
+	cmd := exec.Command(command)
+	cmd.Stdout = output
+	cmd.Stderr = output
+	err := cmd.Run()
+	return output.String(), err
+}
@@ -18,6 +18,7 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -71,3 +72,19 @@ func resolveActiveConfig(ctx context.Context, c client.Reader) (*gpuv1.ClusterPo
 
 	return getSingletonClusterPolicy(clusterPolicies.Items), gpuCluster, nil
 }
+
+// errConflictingGPUCluster is wrapped by checkConflictingGPUCluster when a GPUCluster exists.
+var errConflictingGPUCluster = errors.New("ClusterPolicy and GPUCluster cannot co-exist")
+
+// checkConflictingGPUCluster returns an error wrapping errConflictingGPUCluster when a
+// GPUCluster is present, since a ClusterPolicy cannot be reconciled alongside it.
+func checkConflictingGPUCluster(ctx context.Context, c client.Reader) error {
+	gpuClusters := &nvidiav1alpha1.GPUClusterList{}
+	if err := c.List(ctx, gpuClusters); err != nil {
+		return fmt.Errorf("failed to list GPUCluster objects: %w", err)
+	}
+	if len(gpuClusters.Items) > 0 {
+		return fmt.Errorf("conflicting GPUCluster resource %q detected; %w", gpuClusters.Items[0].Name, errConflictingGPUCluster)
+	}
+	return nil
+}
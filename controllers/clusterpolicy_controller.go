@@ -18,6 +18,7 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -47,6 +48,7 @@ import (
 	nvidiav1alpha1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1alpha1"
 	"github.com/NVIDIA/gpu-operator/internal/conditions"
 	"github.com/NVIDIA/gpu-operator/internal/consts"
+	"github.com/NVIDIA/gpu-operator/internal/pause"
 )
 
 const (
@@ -83,7 +85,7 @@ type ClusterPolicyReconciler struct {
 // +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch
 // +kubebuilder:rbac:groups=route.openshift.io,resources=routes,verbs=get;list;watch;create;update;patch
 // +kubebuilder:rbac:groups=image.openshift.io,resources=imagestreams,verbs=get;list;watch
-// +kubebuilder:rbac:groups=node.k8s.io,resources=runtimeclasses,verbs=get;list;create;update;watch;delete
+// +kubebuilder:rbac:groups=node.k8s.io,resources=runtimeclasses,verbs=get;list;create;update;patch;watch;delete
 // +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
@@ -132,12 +134,10 @@ func (r *ClusterPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	}
 
 	// TODO: remove the below code block once both ClusterPolicy and GPUCluster can co-exist
-	gpuClusters := &nvidiav1alpha1.GPUClusterList{}
-	if err := r.List(ctx, gpuClusters); err != nil {
-		return ctrl.Result{}, fmt.Errorf("failed to list GPUCluster objects: %w", err)
-	}
-	if len(gpuClusters.Items) > 0 {
-		err := fmt.Errorf("conflicting GPUCluster resource %q detected; ClusterPolicy and GPUCluster cannot co-exist", gpuClusters.Items[0].Name)
+	if err := checkConflictingGPUCluster(ctx, r.Client); err != nil {
+		if !errors.Is(err, errConflictingGPUCluster) {
+			return ctrl.Result{}, err
+		}
 		r.Log.Error(err, "only one CR may be present at a time")
 		updateCRState(ctx, r, req.NamespacedName, gpuv1.NotReady)
 		if condErr := r.conditionUpdater.SetConditionsError(ctx, instance, conditions.ReconcileFailed, err.Error()); condErr != nil {
@@ -171,12 +171,14 @@ func (r *ClusterPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	overallStatus := gpuv1.Ready
 	statesNotReady := []string{}
 	notReadyReasons := []string{}
+	componentStates := []gpuv1.State{}
 	for {
 		status, statusError := clusterPolicyCtrl.step()
 		if statusError != nil {
 			clusterPolicyCtrl.operatorMetrics.reconciliationStatus.Set(reconciliationStatusNotReady)
 			clusterPolicyCtrl.operatorMetrics.reconciliationFailed.Inc()
 			updateCRState(ctx, r, req.NamespacedName, gpuv1.NotReady)
+			updateCRComponents(ctx, r, req.NamespacedName, append(componentStates, gpuv1.NotReady))
 			if condErr := r.conditionUpdater.SetConditionsError(ctx, instance, conditions.ReconcileFailed, fmt.Sprintf("Failed to reconcile %s: %s", clusterPolicyCtrl.stateNames[clusterPolicyCtrl.idx], statusError.Error())); condErr != nil {
 				r.Log.Error(condErr, "failed to set condition")
 			}
@@ -187,6 +189,7 @@ func (r *ClusterPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 			overallStatus = gpuv1.NotReady
 			statesNotReady = append(statesNotReady, clusterPolicyCtrl.stateNames[clusterPolicyCtrl.idx-1])
 		}
+		componentStates = append(componentStates, status)
 		r.Log.Info("ClusterPolicy step completed",
 			"state:", clusterPolicyCtrl.stateNames[clusterPolicyCtrl.idx-1],
 			"status", status)
@@ -195,6 +198,7 @@ func (r *ClusterPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 			break
 		}
 	}
+	updateCRComponents(ctx, r, req.NamespacedName, componentStates)
 
 	if clusterPolicyCtrl.singleton.Spec.Driver.UseNvidiaDriverCRDType() {
 		upgradeIncomplete, err := r.nvidiaDriverUpgradeIncomplete(ctx)
@@ -463,7 +467,10 @@ func (r *ClusterPolicyReconciler) SetupWithManager(ctx context.Context, mgr ctrl
 		mgr.GetCache(),
 		&gpuv1.ClusterPolicy{},
 		&handler.TypedEnqueueRequestForObject[*gpuv1.ClusterPolicy]{},
-		predicate.TypedGenerationChangedPredicate[*gpuv1.ClusterPolicy]{},
+		predicate.Or[*gpuv1.ClusterPolicy](
+			predicate.TypedGenerationChangedPredicate[*gpuv1.ClusterPolicy]{},
+			pause.AnnotationChangedPredicate[*gpuv1.ClusterPolicy](),
+		),
 	),
 	)
 	if err != nil {
@@ -514,19 +521,23 @@ func (r *ClusterPolicyReconciler) SetupWithManager(ctx context.Context, mgr ctrl
 	// IndexField(): https://github.com/kubernetes-sigs/controller-runtime/blob/main/pkg/cache/informer_cache.go#L204
 	//   GetInformer(): https://github.com/kubernetes-sigs/controller-runtime/blob/main/pkg/cache/informer_cache.go#L168
 	//     GVKForObject(): https://github.com/kubernetes-sigs/controller-runtime/blob/main/pkg/client/apiutil/apimachinery.go#L113
-	if err := mgr.GetFieldIndexer().IndexField(ctx, &appsv1.DaemonSet{}, clusterPolicyControllerIndexKey, func(rawObj client.Object) []string {
-		ds := rawObj.(*appsv1.DaemonSet)
-		owner := metav1.GetControllerOf(ds)
-		if owner == nil {
-			return nil
-		}
-		if owner.APIVersion != gpuv1.SchemeGroupVersion.String() || owner.Kind != "ClusterPolicy" {
-			return nil
-		}
-		return []string{owner.Name}
-	}); err != nil {
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &appsv1.DaemonSet{}, clusterPolicyControllerIndexKey, clusterPolicyDaemonSetIndexer); err != nil {
 		return fmt.Errorf("failed to add index key: %w", err)
 	}
 
 	return nil
 }
+
+// clusterPolicyDaemonSetIndexer indexes DaemonSets by the name of the ClusterPolicy
+// controlling them
+func clusterPolicyDaemonSetIndexer(rawObj client.Object) []string {
+	ds := rawObj.(*appsv1.DaemonSet)
+	owner := metav1.GetControllerOf(ds)
+	if owner == nil {
+		return nil
+	}
+	if owner.APIVersion != gpuv1.SchemeGroupVersion.String() || owner.Kind != "ClusterPolicy" {
+		return nil
+	}
+	return []string{owner.Name}
+}
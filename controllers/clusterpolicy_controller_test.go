@@ -25,6 +25,7 @@ import (
 	"github.com/go-logr/logr"
 	promcli "github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -462,11 +463,40 @@ func TestClusterPolicyBlockedByGPUCluster(t *testing.T) {
 	require.Equal(t, gpuv1.Ready, clusterPolicyState(t, c, cp.Name))
 }
 
+func TestClusterPolicyReconcileComponents(t *testing.T) {
+	cp := clusterPolicyForUpgradeTest(false)
+	r, c, _ := newClusterPolicyUpgradeTestReconciler(t, cp)
+	clusterPolicyCtrl.stateNames = []string{"state-ready", "state-not-ready", "state-disabled"}
+	clusterPolicyCtrl.controls = []controlFunc{
+		{func(ClusterPolicyController) (gpuv1.State, error) { return gpuv1.Ready, nil }},
+		{func(ClusterPolicyController) (gpuv1.State, error) { return gpuv1.NotReady, nil }},
+		{func(ClusterPolicyController) (gpuv1.State, error) { return gpuv1.Disabled, nil }},
+	}
+
+	_, err := r.Reconcile(t.Context(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cp)})
+	require.NoError(t, err)
+
+	got := &gpuv1.ClusterPolicy{}
+	require.NoError(t, c.Get(t.Context(), client.ObjectKeyFromObject(cp), got))
+	require.Len(t, got.Status.Components, 3)
+	for i, expected := range []gpuv1.ComponentStatus{
+		{Name: "state-ready", Enabled: true, State: gpuv1.Ready},
+		{Name: "state-not-ready", Enabled: true, State: gpuv1.NotReady},
+		{Name: "state-disabled", Enabled: false, State: gpuv1.Disabled},
+	} {
+		component := got.Status.Components[i]
+		require.False(t, component.LastTransitionTime.IsZero())
+		component.LastTransitionTime = metav1.Time{}
+		require.Equal(t, expected, component)
+	}
+}
+
 func newClusterPolicyUpgradeTestReconciler(t *testing.T, cp *gpuv1.ClusterPolicy, nodes ...*corev1.Node) (*ClusterPolicyReconciler, client.Client, *OperatorMetrics) {
 	t.Helper()
 	scheme := runtime.NewScheme()
 	require.NoError(t, gpuv1.AddToScheme(scheme))
 	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
 	require.NoError(t, nvidiav1alpha1.AddToScheme(scheme))
 
 	objects := []client.Object{cp}
@@ -0,0 +1,132 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"context"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1"
+)
+
+// componentStatus returns the status entry of the state at idx, given the status it
+// reported in the last step. DaemonSet counts and image are read from the live DaemonSets.
+func (n ClusterPolicyController) componentStatus(idx int, state gpuv1.State, daemonSets []appsv1.DaemonSet) gpuv1.ComponentStatus {
+	component := gpuv1.ComponentStatus{
+		Name:    n.stateNames[idx],
+		Enabled: state != gpuv1.Disabled,
+		State:   state,
+	}
+	if !component.Enabled || idx >= len(n.resources) || n.resources[idx].DaemonSet.Name == "" {
+		return component
+	}
+
+	component.DaemonSet = n.resources[idx].DaemonSet.Name
+	for _, ds := range daemonSets {
+		if !isComponentDaemonSet(component.DaemonSet, ds.Name) {
+			continue
+		}
+		component.DesiredNumberScheduled += ds.Status.DesiredNumberScheduled
+		component.NumberReady += ds.Status.NumberReady
+		if component.Image == "" && len(ds.Spec.Template.Spec.Containers) > 0 {
+			component.Image = ds.Spec.Template.Spec.Containers[0].Image
+		}
+	}
+	return component
+}
+
+// isComponentDaemonSet returns true if the DaemonSet named dsName was deployed from
+// the DaemonSet template named name. The driver and vGPU manager states deploy one
+// DaemonSet per kernel or OS version, suffixing the template name.
+func isComponentDaemonSet(name, dsName string) bool {
+	if dsName == name {
+		return true
+	}
+	if name == commonDriverDaemonsetName || name == commonVGPUManagerDaemonsetName {
+		return strings.HasPrefix(dsName, name+"-")
+	}
+	return false
+}
+
+// mergeComponentStatuses merges the freshly computed component statuses into the
+// previous ones, ordered as stateNames. Entries of states that were not reached in
+// this reconciliation are kept, and the last transition time is only bumped for
+// entries whose enabled flag or state changed.
+func mergeComponentStatuses(previous, current []gpuv1.ComponentStatus, stateNames []string, now metav1.Time) []gpuv1.ComponentStatus {
+	previousByName := make(map[string]gpuv1.ComponentStatus, len(previous))
+	for _, component := range previous {
+		previousByName[component.Name] = component
+	}
+	currentByName := make(map[string]gpuv1.ComponentStatus, len(current))
+	for _, component := range current {
+		currentByName[component.Name] = component
+	}
+
+	merged := make([]gpuv1.ComponentStatus, 0, len(stateNames))
+	for _, name := range stateNames {
+		component, ok := currentByName[name]
+		prev, hasPrevious := previousByName[name]
+		switch {
+		case !ok && hasPrevious:
+			component = prev
+		case !ok:
+			continue
+		case hasPrevious && prev.State == component.State && prev.Enabled == component.Enabled:
+			component.LastTransitionTime = prev.LastTransitionTime
+		default:
+			component.LastTransitionTime = now
+		}
+		merged = append(merged, component)
+	}
+	return merged
+}
+
+// updateCRComponents updates the per-state breakdown of the ClusterPolicy status with
+// the states reported by the steps run so far, states[i] being the status of state i
+func updateCRComponents(ctx context.Context, r *ClusterPolicyReconciler, namespacedName types.NamespacedName, states []gpuv1.State) {
+	daemonSets := &appsv1.DaemonSetList{}
+	if err := r.List(ctx, daemonSets, client.InNamespace(clusterPolicyCtrl.operatorNamespace)); err != nil {
+		r.Log.Error(err, "Failed to list DaemonSets for ClusterPolicy status")
+		return
+	}
+
+	components := make([]gpuv1.ComponentStatus, 0, len(states))
+	for idx, state := range states {
+		components = append(components, clusterPolicyCtrl.componentStatus(idx, state, daemonSets.Items))
+	}
+
+	// Fetch latest instance and update components to avoid version mismatch
+	instance := &gpuv1.ClusterPolicy{}
+	if err := r.Get(ctx, namespacedName, instance); err != nil {
+		r.Log.Error(err, "Failed to get ClusterPolicy instance for status update")
+		return
+	}
+	components = mergeComponentStatuses(instance.Status.Components, components, clusterPolicyCtrl.stateNames, metav1.Now())
+	if apiequality.Semantic.DeepEqual(instance.Status.Components, components) {
+		return
+	}
+	instance.Status.Components = components
+	if err := r.Client.Status().Update(ctx, instance); err != nil {
+		r.Log.Error(err, "Failed to update ClusterPolicy status")
+	}
+}
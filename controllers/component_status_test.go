@@ -0,0 +1,116 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1"
+)
+
+func newComponentTestDaemonSet(name, image string, desired, ready int32) appsv1.DaemonSet {
+	return appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: appsv1.DaemonSetSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "main", Image: image}}},
+			},
+		},
+		Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: desired, NumberReady: ready},
+	}
+}
+
+func TestComponentStatus(t *testing.T) {
+	n := ClusterPolicyController{
+		stateNames: []string{"pre-requisites", "state-driver", "state-dcgm", "state-dcgm-exporter"},
+		resources: []Resources{
+			{},
+			{DaemonSet: appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: commonDriverDaemonsetName}}},
+			{DaemonSet: appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "nvidia-dcgm"}}},
+			{DaemonSet: appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "nvidia-dcgm-exporter"}}},
+		},
+	}
+	daemonSets := []appsv1.DaemonSet{
+		newComponentTestDaemonSet("nvidia-driver-daemonset-5.15.0-1-ubuntu22.04", "nvcr.io/nvidia/driver:580-5.15.0-1-ubuntu22.04", 2, 2),
+		newComponentTestDaemonSet("nvidia-driver-daemonset-6.8.0-1-ubuntu24.04", "nvcr.io/nvidia/driver:580-6.8.0-1-ubuntu24.04", 3, 1),
+		newComponentTestDaemonSet("nvidia-dcgm-exporter", "nvcr.io/nvidia/k8s/dcgm-exporter:4.2.3", 5, 4),
+	}
+
+	require.Equal(t, gpuv1.ComponentStatus{Name: "pre-requisites", Enabled: true, State: gpuv1.Ready},
+		n.componentStatus(0, gpuv1.Ready, daemonSets))
+
+	// Precompiled driver DaemonSets are aggregated under the common name
+	require.Equal(t, gpuv1.ComponentStatus{
+		Name:                   "state-driver",
+		Enabled:                true,
+		State:                  gpuv1.NotReady,
+		DaemonSet:              commonDriverDaemonsetName,
+		DesiredNumberScheduled: 5,
+		NumberReady:            3,
+		Image:                  "nvcr.io/nvidia/driver:580-5.15.0-1-ubuntu22.04",
+	}, n.componentStatus(1, gpuv1.NotReady, daemonSets))
+
+	// nvidia-dcgm must not pick up the nvidia-dcgm-exporter DaemonSet
+	require.Equal(t, gpuv1.ComponentStatus{Name: "state-dcgm", State: gpuv1.Disabled},
+		n.componentStatus(2, gpuv1.Disabled, daemonSets))
+	require.Equal(t, gpuv1.ComponentStatus{Name: "state-dcgm", Enabled: true, State: gpuv1.NotReady, DaemonSet: "nvidia-dcgm"},
+		n.componentStatus(2, gpuv1.NotReady, daemonSets))
+
+	require.Equal(t, gpuv1.ComponentStatus{
+		Name:                   "state-dcgm-exporter",
+		Enabled:                true,
+		State:                  gpuv1.NotReady,
+		DaemonSet:              "nvidia-dcgm-exporter",
+		DesiredNumberScheduled: 5,
+		NumberReady:            4,
+		Image:                  "nvcr.io/nvidia/k8s/dcgm-exporter:4.2.3",
+	}, n.componentStatus(3, gpuv1.NotReady, daemonSets))
+}
+
+func TestMergeComponentStatuses(t *testing.T) {
+	then := metav1.NewTime(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	now := metav1.NewTime(then.Add(time.Hour))
+	stateNames := []string{"state-driver", "state-container-toolkit", "state-device-plugin"}
+
+	previous := []gpuv1.ComponentStatus{
+		{Name: "state-driver", Enabled: true, State: gpuv1.Ready, LastTransitionTime: then},
+		{Name: "state-container-toolkit", Enabled: true, State: gpuv1.NotReady, LastTransitionTime: then},
+		{Name: "state-device-plugin", Enabled: true, State: gpuv1.NotReady, LastTransitionTime: then},
+	}
+	// the reconciliation failed on the toolkit state, the device plugin was not reached
+	current := []gpuv1.ComponentStatus{
+		{Name: "state-driver", Enabled: true, State: gpuv1.Ready, NumberReady: 2},
+		{Name: "state-container-toolkit", Enabled: false, State: gpuv1.Disabled},
+	}
+
+	require.Equal(t, []gpuv1.ComponentStatus{
+		{Name: "state-driver", Enabled: true, State: gpuv1.Ready, NumberReady: 2, LastTransitionTime: then},
+		{Name: "state-container-toolkit", Enabled: false, State: gpuv1.Disabled, LastTransitionTime: now},
+		{Name: "state-device-plugin", Enabled: true, State: gpuv1.NotReady, LastTransitionTime: then},
+	}, mergeComponentStatuses(previous, current, stateNames, now))
+
+	// new entries transition now
+	require.Equal(t, []gpuv1.ComponentStatus{
+		{Name: "state-driver", Enabled: true, State: gpuv1.Ready, LastTransitionTime: now},
+	}, mergeComponentStatuses(nil, []gpuv1.ComponentStatus{{Name: "state-driver", Enabled: true, State: gpuv1.Ready}}, stateNames, now))
+}
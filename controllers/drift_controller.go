@@ -0,0 +1,405 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1"
+	nvidiav1alpha1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1alpha1"
+	"github.com/NVIDIA/gpu-operator/controllers/clusterinfo"
+	"github.com/NVIDIA/gpu-operator/internal/conditions"
+	"github.com/NVIDIA/gpu-operator/internal/consts"
+	"github.com/NVIDIA/gpu-operator/internal/drift"
+	"github.com/NVIDIA/gpu-operator/internal/pause"
+	"github.com/NVIDIA/gpu-operator/internal/state"
+	"github.com/NVIDIA/gpu-operator/internal/utils"
+)
+
+const (
+	// driftControllerSingletonName is the request name every watch enqueues; a single
+	// reconciliation checks all the custom resources.
+	driftControllerSingletonName = "drift-detection"
+
+	// DefaultDriftDetectionInterval is the time between two drift checks of a DriftReconciler
+	// set up without an interval
+	DefaultDriftDetectionInterval = 5 * time.Minute
+)
+
+// DriftReconciler periodically compares the DaemonSets, ConfigMaps and RBAC objects
+// deployed for the ClusterPolicy, NVIDIADriver and GPUCluster instances against the
+// objects rendered from them. Objects edited out of band are reported through the
+// Drifted condition of their custom resource, an event and the
+// gpu_operator_drifted_objects metric, and restored to their desired state when the
+// policy is to correct them.
+type DriftReconciler struct {
+	client.Client
+	Scheme          *runtime.Scheme
+	Namespace       string
+	ClusterInfo     clusterinfo.Interface
+	OperatorMetrics *OperatorMetrics
+	Policy          drift.Policy
+	Interval        time.Duration
+
+	recorder events.EventRecorder
+
+	// checkedGenerations are the generations of the custom resources at the previous
+	// check, by UID. The objects of a custom resource are only compared once its
+	// generation is unchanged since the previous check: right after a spec change its
+	// controller may not have rolled the change out yet, and ConfigMaps and RBAC objects
+	// carry no hash annotation telling a pending update from drift.
+	checkedGenerations map[types.UID]int64
+
+	// assetsDir and manifestsDir are the directories the objects are rendered from,
+	// DefaultAssetsDir and state.DefaultManifestsDir if unset
+	assetsDir    string
+	manifestsDir string
+	// getKubernetesVersion returns the version of the cluster, KubernetesVersion if unset
+	getKubernetesVersion func() (string, error)
+}
+
+// desiredObject is an object rendered for a custom resource along with the state
+// deploying it
+type desiredObject struct {
+	obj   *unstructured.Unstructured
+	state string
+}
+
+// Reconcile checks every custom resource for drifted objects and requeues itself
+// after the drift detection interval.
+func (r *DriftReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	logger.V(consts.LogLevelInfo).Info("Checking operator managed objects for drift")
+
+	clusterPolicy, gpuCluster, err := resolveActiveConfig(ctx, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	nvidiaDrivers := &nvidiav1alpha1.NVIDIADriverList{}
+	if err := r.List(ctx, nvidiaDrivers); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list NVIDIADriver: %w", err)
+	}
+
+	var crs []client.Object
+	if clusterPolicy != nil {
+		crs = append(crs, clusterPolicy)
+	}
+	if gpuCluster != nil {
+		crs = append(crs, gpuCluster)
+	}
+	for i := range nvidiaDrivers.Items {
+		crs = append(crs, &nvidiaDrivers.Items[i])
+	}
+
+	r.OperatorMetrics.driftedObjects.Reset()
+	if len(crs) == 0 {
+		// a new custom resource triggers the next check
+		return ctrl.Result{}, nil
+	}
+
+	opts, err := r.renderOptions(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	generations := make(map[types.UID]int64, len(crs))
+	for _, cr := range crs {
+		if !cr.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+		generations[cr.GetUID()] = cr.GetGeneration()
+		if checked, ok := r.checkedGenerations[cr.GetUID()]; !ok || checked != cr.GetGeneration() {
+			logger.V(consts.LogLevelInfo).Info("Deferring the drift check until the spec is rolled out",
+				"kind", customResourceKind(cr), "name", cr.GetName(), "generation", cr.GetGeneration())
+			continue
+		}
+		crOpts := opts
+		// the NVIDIADriver controller mounts the host root of the ClusterPolicy
+		if _, ok := cr.(*nvidiav1alpha1.NVIDIADriver); ok && clusterPolicy != nil && gpuCluster == nil {
+			crOpts.HostRoot = clusterPolicy.Spec.HostPaths.RootFS
+		}
+		if err := r.checkDrift(ctx, cr, crOpts); err != nil {
+			logger.Error(err, "Failed to check drift", "kind", customResourceKind(cr), "name", cr.GetName())
+		}
+	}
+	r.checkedGenerations = generations
+
+	return ctrl.Result{RequeueAfter: r.Interval}, nil
+}
+
+// checkDrift compares the objects deployed for cr against the objects rendered from it
+// and reports, or corrects, the drifted ones
+func (r *DriftReconciler) checkDrift(ctx context.Context, cr client.Object, opts RenderOptions) error {
+	kind := customResourceKind(cr)
+	logger := log.FromContext(ctx).WithValues("kind", kind, "name", cr.GetName())
+
+	desired, err := renderDesiredObjects(ctx, cr, opts)
+	if err != nil {
+		return fmt.Errorf("failed to render objects: %w", err)
+	}
+
+	var drifted, corrected []string
+	for _, d := range desired {
+		if !drift.IsSupported(d.obj) {
+			continue
+		}
+		live := &unstructured.Unstructured{}
+		live.SetGroupVersionKind(d.obj.GroupVersionKind())
+		err := r.Get(ctx, client.ObjectKeyFromObject(d.obj), live)
+		if apierrors.IsNotFound(err) {
+			// not deployed yet, or no longer deployed, by the controller of cr
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get %s %s: %w", d.obj.GetKind(), d.obj.GetName(), err)
+		}
+
+		fields := drift.Detect(live, d.obj)
+		if len(fields) == 0 {
+			continue
+		}
+		name := driftedObjectName(d.obj)
+		logger.Info("Object drifted from its desired state", "object", name, "fields", fields)
+		r.recorder.Eventf(cr, live, corev1.EventTypeWarning, "Drifted", "DetectDrift",
+			"%s drifted from its desired state: %s", name, strings.Join(fields, ", "))
+
+		if r.Policy != drift.PolicyCorrect || pause.IsPaused(cr, d.state) {
+			drifted = append(drifted, name)
+			continue
+		}
+		if err := utils.ApplyObject(ctx, r.Client, d.obj); err != nil {
+			logger.Error(err, "Failed to correct drifted object", "object", name)
+			drifted = append(drifted, name)
+			continue
+		}
+		r.recorder.Eventf(cr, live, corev1.EventTypeNormal, "DriftCorrected", "CorrectDrift",
+			"%s was restored to its desired state", name)
+		corrected = append(corrected, name)
+	}
+
+	r.OperatorMetrics.driftedObjects.WithLabelValues(kind, cr.GetName()).Set(float64(len(drifted)))
+
+	updater := driftConditionUpdater(r.Client, cr)
+	switch {
+	case len(drifted) > 0:
+		return updater.SetConditionDrifted(ctx, cr, true, conditions.DriftDetected,
+			fmt.Sprintf("Objects drifted from their desired state: %s", strings.Join(drifted, ", ")))
+	case len(corrected) > 0:
+		return updater.SetConditionDrifted(ctx, cr, false, conditions.DriftCorrected,
+			fmt.Sprintf("Objects restored to their desired state: %s", strings.Join(corrected, ", ")))
+	}
+	return updater.SetConditionDrifted(ctx, cr, false, conditions.NoDriftDetected, "All objects match their desired state")
+}
+
+// renderOptions returns the options to render the custom resources for this cluster
+func (r *DriftReconciler) renderOptions(ctx context.Context) (RenderOptions, error) {
+	getKubernetesVersion := r.getKubernetesVersion
+	if getKubernetesVersion == nil {
+		getKubernetesVersion = KubernetesVersion
+	}
+	k8sVersion, err := getKubernetesVersion()
+	if err != nil {
+		return RenderOptions{}, err
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := r.List(ctx, nodes); err != nil {
+		return RenderOptions{}, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	opts := RenderOptions{
+		Namespace:         r.Namespace,
+		AssetsDir:         r.assetsDir,
+		ManifestsDir:      r.manifestsDir,
+		Nodes:             nodes.Items,
+		KubernetesVersion: k8sVersion,
+		Logger:            logr.Discard(),
+	}
+	if opts.AssetsDir == "" {
+		opts.AssetsDir = DefaultAssetsDir
+	}
+	if opts.ManifestsDir == "" {
+		opts.ManifestsDir = state.DefaultManifestsDir
+	}
+
+	if opts.ContainerRuntime, err = r.ClusterInfo.GetContainerRuntime(); err != nil {
+		return RenderOptions{}, err
+	}
+	if opts.OpenshiftVersion, err = r.ClusterInfo.GetOpenshiftVersion(); err != nil {
+		return RenderOptions{}, err
+	}
+	if opts.OpenshiftVersion != "" {
+		opts.OpenshiftDriverToolkitImages = r.ClusterInfo.GetOpenshiftDriverToolkitImages()
+		if opts.OpenshiftProxySpec, err = r.ClusterInfo.GetOpenshiftProxySpec(); err != nil {
+			return RenderOptions{}, err
+		}
+	}
+	gvr, supported, err := r.ClusterInfo.GetDRAResourceGVR()
+	if err != nil {
+		return RenderOptions{}, err
+	}
+	if supported {
+		opts.DRAResourceAPIVersion = gvr.GroupVersion().String()
+	}
+	return opts, nil
+}
+
+// renderDesiredObjects returns the objects the controller of cr deploys for it
+func renderDesiredObjects(ctx context.Context, cr client.Object, opts RenderOptions) ([]desiredObject, error) {
+	if cp, ok := cr.(*gpuv1.ClusterPolicy); ok {
+		recorder, err := renderClusterPolicy(ctx, cp, opts)
+		if err != nil {
+			return nil, err
+		}
+		var desired []desiredObject
+		for _, obj := range recorder.objects() {
+			desired = append(desired, desiredObject{obj: obj, state: recorder.stateOf(obj)})
+		}
+		return desired, nil
+	}
+
+	objs, err := Render(ctx, cr, opts)
+	if err != nil {
+		return nil, err
+	}
+	desired := make([]desiredObject, 0, len(objs))
+	for _, obj := range objs {
+		desired = append(desired, desiredObject{obj: obj, state: obj.GetLabels()[consts.StateLabel]})
+	}
+	return desired, nil
+}
+
+// driftConditionUpdater returns the condition updater for the kind of cr
+func driftConditionUpdater(c client.Client, cr client.Object) conditions.Updater {
+	switch cr.(type) {
+	case *gpuv1.ClusterPolicy:
+		return conditions.NewClusterPolicyUpdater(c)
+	case *nvidiav1alpha1.GPUCluster:
+		return conditions.NewGPUClusterUpdater(c)
+	}
+	return conditions.NewNvDriverUpdater(c)
+}
+
+func customResourceKind(cr client.Object) string {
+	switch cr.(type) {
+	case *gpuv1.ClusterPolicy:
+		return "ClusterPolicy"
+	case *nvidiav1alpha1.GPUCluster:
+		return "GPUCluster"
+	case *nvidiav1alpha1.NVIDIADriver:
+		return "NVIDIADriver"
+	}
+	return fmt.Sprintf("%T", cr)
+}
+
+func driftedObjectName(obj *unstructured.Unstructured) string {
+	if obj.GetNamespace() == "" {
+		return fmt.Sprintf("%s %s", obj.GetKind(), obj.GetName())
+	}
+	return fmt.Sprintf("%s %s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName())
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *DriftReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
+	r.recorder = mgr.GetEventRecorder("nvidia-gpu-operator")
+	if r.Interval == 0 {
+		r.Interval = DefaultDriftDetectionInterval
+	}
+
+	c, err := controller.New("drift-controller", mgr, controller.Options{
+		Reconciler:              r,
+		MaxConcurrentReconciles: 1,
+		RateLimiter:             workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](minDelayCR, maxDelayCR),
+	})
+	if err != nil {
+		return err
+	}
+
+	mapToSingleton := func(_ context.Context, _ client.Object) []reconcile.Request {
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: driftControllerSingletonName}}}
+	}
+
+	// Only the creation of a custom resource starts the periodic checks. Checking right
+	// after a spec change would race with its controller rolling the change out.
+	err = c.Watch(source.Kind(
+		mgr.GetCache(),
+		&gpuv1.ClusterPolicy{},
+		handler.TypedEnqueueRequestsFromMapFunc(func(ctx context.Context, cp *gpuv1.ClusterPolicy) []reconcile.Request {
+			return mapToSingleton(ctx, cp)
+		}),
+		createOnlyPredicate[*gpuv1.ClusterPolicy](),
+	))
+	if err != nil {
+		return err
+	}
+
+	err = c.Watch(source.Kind(
+		mgr.GetCache(),
+		&nvidiav1alpha1.NVIDIADriver{},
+		handler.TypedEnqueueRequestsFromMapFunc(func(ctx context.Context, nvd *nvidiav1alpha1.NVIDIADriver) []reconcile.Request {
+			return mapToSingleton(ctx, nvd)
+		}),
+		createOnlyPredicate[*nvidiav1alpha1.NVIDIADriver](),
+	))
+	if err != nil {
+		return err
+	}
+
+	err = c.Watch(source.Kind(
+		mgr.GetCache(),
+		&nvidiav1alpha1.GPUCluster{},
+		handler.TypedEnqueueRequestsFromMapFunc(func(ctx context.Context, gc *nvidiav1alpha1.GPUCluster) []reconcile.Request {
+			return mapToSingleton(ctx, gc)
+		}),
+		createOnlyPredicate[*nvidiav1alpha1.GPUCluster](),
+	))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func createOnlyPredicate[T client.Object]() predicate.TypedPredicate[T] {
+	return predicate.TypedFuncs[T]{
+		CreateFunc:  func(event.TypedCreateEvent[T]) bool { return true },
+		UpdateFunc:  func(event.TypedUpdateEvent[T]) bool { return false },
+		DeleteFunc:  func(event.TypedDeleteEvent[T]) bool { return false },
+		GenericFunc: func(event.TypedGenericEvent[T]) bool { return false },
+	}
+}
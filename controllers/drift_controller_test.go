@@ -0,0 +1,245 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1"
+	nvidiav1alpha1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1alpha1"
+	"github.com/NVIDIA/gpu-operator/internal/conditions"
+	"github.com/NVIDIA/gpu-operator/internal/consts"
+	"github.com/NVIDIA/gpu-operator/internal/drift"
+)
+
+type fakeClusterInfo struct{}
+
+func (fakeClusterInfo) GetContainerRuntime() (string, error)                { return "containerd", nil }
+func (fakeClusterInfo) GetOpenshiftVersion() (string, error)                { return "", nil }
+func (fakeClusterInfo) GetOpenshiftDriverToolkitImages() map[string]string  { return nil }
+func (fakeClusterInfo) GetOpenshiftProxySpec() (*configv1.ProxySpec, error) { return nil, nil }
+func (fakeClusterInfo) GetDRAResourceGVR() (schema.GroupVersionResource, bool, error) {
+	return schema.GroupVersionResource{Group: "resource.k8s.io", Version: "v1", Resource: "resourceclaims"}, true, nil
+}
+
+// newDriftTestReconciler builds a reconciler over a fake client seeded with driver and
+// the objects rendered for it, as deployed by the NVIDIADriver controller
+func newDriftTestReconciler(t *testing.T, driver *nvidiav1alpha1.NVIDIADriver) (*DriftReconciler, client.Client, *events.FakeRecorder) {
+	t.Helper()
+	opts := newRenderTestOptions(t)
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, gpuv1.AddToScheme(scheme))
+	require.NoError(t, nvidiav1alpha1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, rbacv1.AddToScheme(scheme))
+
+	objs := []client.Object{driver}
+	for i := range opts.Nodes {
+		objs = append(objs, &opts.Nodes[i])
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&nvidiav1alpha1.NVIDIADriver{}).
+		Build()
+
+	deployed, err := Render(t.Context(), driver, opts)
+	require.NoError(t, err)
+	for _, obj := range deployed {
+		require.NoError(t, c.Create(t.Context(), obj))
+	}
+
+	recorder := events.NewFakeRecorder(100)
+	r := &DriftReconciler{
+		Client:               c,
+		Scheme:               scheme,
+		Namespace:            opts.Namespace,
+		ClusterInfo:          fakeClusterInfo{},
+		OperatorMetrics:      newOperatorMetrics(),
+		Policy:               drift.PolicyReport,
+		Interval:             time.Minute,
+		recorder:             recorder,
+		assetsDir:            opts.AssetsDir,
+		manifestsDir:         opts.ManifestsDir,
+		getKubernetesVersion: func() (string, error) { return opts.KubernetesVersion, nil },
+	}
+	// the first check only records the generation of the custom resources
+	driftReconcile(t, r)
+	return r, c, recorder
+}
+
+func newDriftTestDriver() *nvidiav1alpha1.NVIDIADriver {
+	return &nvidiav1alpha1.NVIDIADriver{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", UID: "8f7b3c1e"},
+		Spec: nvidiav1alpha1.NVIDIADriverSpec{
+			DriverType: nvidiav1alpha1.GPU,
+			Repository: "nvcr.io/nvidia",
+			Image:      "driver",
+			Version:    "580.65.06",
+		},
+	}
+}
+
+// editDriverImage sets the image of the driver container of the deployed DaemonSet
+func editDriverImage(t *testing.T, c client.Client, image string) types.NamespacedName {
+	t.Helper()
+	list := &appsv1.DaemonSetList{}
+	require.NoError(t, c.List(t.Context(), list))
+	require.Len(t, list.Items, 1)
+	ds := &list.Items[0]
+	if image != "" {
+		ds.Spec.Template.Spec.Containers[0].Image = image
+		require.NoError(t, c.Update(t.Context(), ds))
+	}
+	return client.ObjectKeyFromObject(ds)
+}
+
+func driverImage(t *testing.T, c client.Client, key types.NamespacedName) string {
+	t.Helper()
+	ds := &appsv1.DaemonSet{}
+	require.NoError(t, c.Get(t.Context(), key, ds))
+	return ds.Spec.Template.Spec.Containers[0].Image
+}
+
+func driftReconcile(t *testing.T, r *DriftReconciler) {
+	t.Helper()
+	result, err := r.Reconcile(t.Context(), ctrl.Request{NamespacedName: types.NamespacedName{Name: driftControllerSingletonName}})
+	require.NoError(t, err)
+	require.Equal(t, r.Interval, result.RequeueAfter)
+}
+
+func driftedCondition(t *testing.T, c client.Client, name string) *metav1.Condition {
+	t.Helper()
+	driver := &nvidiav1alpha1.NVIDIADriver{}
+	require.NoError(t, c.Get(t.Context(), types.NamespacedName{Name: name}, driver))
+	cond := meta.FindStatusCondition(driver.Status.Conditions, conditions.Drifted)
+	require.NotNil(t, cond)
+	return cond
+}
+
+func driftedObjectsMetric(t *testing.T, r *DriftReconciler, kind, name string) float64 {
+	t.Helper()
+	m := &dto.Metric{}
+	require.NoError(t, r.OperatorMetrics.driftedObjects.WithLabelValues(kind, name).Write(m))
+	return m.GetGauge().GetValue()
+}
+
+func TestDriftReconcileNoDrift(t *testing.T) {
+	r, c, recorder := newDriftTestReconciler(t, newDriftTestDriver())
+
+	driftReconcile(t, r)
+
+	cond := driftedCondition(t, c, "default")
+	require.Equal(t, metav1.ConditionFalse, cond.Status)
+	require.Equal(t, conditions.NoDriftDetected, cond.Reason)
+	require.Zero(t, driftedObjectsMetric(t, r, "NVIDIADriver", "default"))
+	require.Empty(t, recorder.Events)
+}
+
+func TestDriftReconcileReport(t *testing.T) {
+	r, c, recorder := newDriftTestReconciler(t, newDriftTestDriver())
+	key := editDriverImage(t, c, "nvcr.io/nvidia/driver:hotfix")
+
+	driftReconcile(t, r)
+
+	cond := driftedCondition(t, c, "default")
+	require.Equal(t, metav1.ConditionTrue, cond.Status)
+	require.Equal(t, conditions.DriftDetected, cond.Reason)
+	require.Contains(t, cond.Message, key.Name)
+	require.EqualValues(t, 1, driftedObjectsMetric(t, r, "NVIDIADriver", "default"))
+	require.Len(t, recorder.Events, 1)
+	require.Contains(t, <-recorder.Events, "Drifted")
+
+	// the live object is left as is
+	require.Equal(t, "nvcr.io/nvidia/driver:hotfix", driverImage(t, c, key))
+}
+
+func TestDriftReconcileCorrect(t *testing.T) {
+	r, c, recorder := newDriftTestReconciler(t, newDriftTestDriver())
+	r.Policy = drift.PolicyCorrect
+	key := editDriverImage(t, c, "")
+	desiredImage := driverImage(t, c, key)
+	editDriverImage(t, c, "nvcr.io/nvidia/driver:hotfix")
+
+	driftReconcile(t, r)
+
+	require.Equal(t, desiredImage, driverImage(t, c, key))
+	cond := driftedCondition(t, c, "default")
+	require.Equal(t, metav1.ConditionFalse, cond.Status)
+	require.Equal(t, conditions.DriftCorrected, cond.Reason)
+	require.Zero(t, driftedObjectsMetric(t, r, "NVIDIADriver", "default"))
+	require.Len(t, recorder.Events, 2)
+	require.Contains(t, <-recorder.Events, "Drifted")
+	require.Contains(t, <-recorder.Events, "DriftCorrected")
+
+	// the next check finds nothing to correct
+	driftReconcile(t, r)
+	require.Equal(t, conditions.NoDriftDetected, driftedCondition(t, c, "default").Reason)
+}
+
+func TestDriftReconcileSpecChange(t *testing.T) {
+	r, c, recorder := newDriftTestReconciler(t, newDriftTestDriver())
+	key := editDriverImage(t, c, "nvcr.io/nvidia/driver:hotfix")
+	driver := &nvidiav1alpha1.NVIDIADriver{}
+	require.NoError(t, c.Get(t.Context(), types.NamespacedName{Name: "default"}, driver))
+
+	// the objects of a custom resource whose spec just changed are not compared yet
+	r.checkedGenerations[driver.UID] = driver.Generation + 1
+	driftReconcile(t, r)
+	require.Empty(t, recorder.Events)
+	require.Zero(t, driftedObjectsMetric(t, r, "NVIDIADriver", "default"))
+
+	// the next check compares them
+	driftReconcile(t, r)
+	require.Len(t, recorder.Events, 1)
+	require.Equal(t, metav1.ConditionTrue, driftedCondition(t, c, "default").Status)
+	require.Equal(t, "nvcr.io/nvidia/driver:hotfix", driverImage(t, c, key))
+}
+
+func TestDriftReconcileCorrectPaused(t *testing.T) {
+	driver := newDriftTestDriver()
+	driver.Annotations = map[string]string{consts.ReconcilePausedAnnotation: "state-driver"}
+	r, c, _ := newDriftTestReconciler(t, driver)
+	r.Policy = drift.PolicyCorrect
+	key := editDriverImage(t, c, "nvcr.io/nvidia/driver:hotfix")
+
+	driftReconcile(t, r)
+
+	// a paused component is hand-patched on purpose and only reported
+	require.Equal(t, "nvcr.io/nvidia/driver:hotfix", driverImage(t, c, key))
+	require.Equal(t, metav1.ConditionTrue, driftedCondition(t, c, "default").Status)
+	require.EqualValues(t, 1, driftedObjectsMetric(t, r, "NVIDIADriver", "default"))
+}
@@ -45,6 +45,7 @@ import (
 	"github.com/NVIDIA/gpu-operator/controllers/clusterinfo"
 	"github.com/NVIDIA/gpu-operator/internal/conditions"
 	"github.com/NVIDIA/gpu-operator/internal/consts"
+	"github.com/NVIDIA/gpu-operator/internal/pause"
 	"github.com/NVIDIA/gpu-operator/internal/state"
 	"github.com/NVIDIA/gpu-operator/internal/utils"
 )
@@ -74,7 +75,9 @@ type GPUClusterReconciler struct {
 //+kubebuilder:rbac:groups=nvidia.com,resources=clusterpolicies,verbs=get;list;watch
 //+kubebuilder:rbac:groups="",resources=namespaces,verbs=get;update;patch
 //+kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch
-//+kubebuilder:rbac:groups=resource.k8s.io,resources=resourceclaimtemplates,verbs=get;list;watch;create;update;delete
+//+kubebuilder:rbac:groups=resource.k8s.io,resources=resourceclaimtemplates;deviceclasses;resourceslices,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=validatingadmissionpolicies;validatingadmissionpolicybindings,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=resource.k8s.io,resources=resourceclaims,verbs=create
 
 func (r *GPUClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
@@ -100,7 +103,7 @@ func (r *GPUClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, fmt.Errorf("error adding finalizer to GPUCluster %s: %w", req.NamespacedName, err)
 	}
 
-	if msg, err := r.validatePrerequisites(ctx); err != nil {
+	if msg, err := validateGPUClusterPrerequisites(ctx, r.Client); err != nil {
 		return ctrl.Result{}, err
 	} else if msg != "" {
 		logger.V(consts.LogLevelWarning).Info("GPUCluster prerequisite not met", "reason", msg)
@@ -122,7 +125,11 @@ func (r *GPUClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	infoCatalog := state.NewInfoCatalog()
 	infoCatalog.Add(state.InfoTypeClusterInfo, r.ClusterInfo)
 
-	managerStatus := r.stateManager.SyncState(ctx, instance, infoCatalog)
+	// Render from a defaulted copy so objects admitted without the defaulting webhook
+	// behave the same as defaulted ones; the copy is never written back.
+	desired := instance.DeepCopy()
+	desired.Spec.SetDefaults()
+	managerStatus := r.stateManager.SyncState(ctx, desired, infoCatalog)
 
 	if err := r.updateCRStatus(ctx, instance, nvidiav1alpha1.State(managerStatus.Status)); err != nil {
 		return ctrl.Result{}, err
@@ -155,11 +162,12 @@ func (r *GPUClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	return ctrl.Result{RequeueAfter: time.Minute}, nil
 }
 
-// validatePrerequisites checks the cross-CR rules that gate DRA enablement, returning
+// validateGPUClusterPrerequisites checks the cross-CR rules that gate DRA enablement, returning
 // a message describing the first unmet prerequisite or an empty string when all are met.
-func (r *GPUClusterReconciler) validatePrerequisites(ctx context.Context) (string, error) {
+// It is shared by the reconciler and the GPUCluster validating webhook.
+func validateGPUClusterPrerequisites(ctx context.Context, c client.Reader) (string, error) {
 	clusterPolicies := &gpuv1.ClusterPolicyList{}
-	if err := r.List(ctx, clusterPolicies); err != nil {
+	if err := c.List(ctx, clusterPolicies); err != nil {
 		return "", fmt.Errorf("error listing ClusterPolicy objects: %w", err)
 	}
 	// TODO: relax this prerequisite once ClusterPolicy and GPUCluster can co-exist
@@ -313,7 +321,10 @@ func (r *GPUClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Ma
 		mgr.GetCache(),
 		&nvidiav1alpha1.GPUCluster{},
 		handler.TypedEnqueueRequestsFromMapFunc(r.enqueueAllGPUClusters),
-		predicate.TypedGenerationChangedPredicate[*nvidiav1alpha1.GPUCluster]{},
+		predicate.Or[*nvidiav1alpha1.GPUCluster](
+			predicate.TypedGenerationChangedPredicate[*nvidiav1alpha1.GPUCluster]{},
+			pause.AnnotationChangedPredicate[*nvidiav1alpha1.GPUCluster](),
+		),
 	),
 	)
 	if err != nil {
@@ -0,0 +1,773 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/prometheus/common/model"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1"
+	nvidiav1alpha1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1alpha1"
+	"github.com/NVIDIA/gpu-operator/internal/consts"
+	"github.com/NVIDIA/gpu-operator/internal/validation"
+)
+
+const (
+	// gpuHealthControllerSingletonName is the request name every watch enqueues; a single
+	// reconciliation checks all the GPU nodes.
+	gpuHealthControllerSingletonName = "gpu-health"
+
+	// gpuUnhealthySinceAnnotationKey is the node annotation keeping the time a GPU node was
+	// found unhealthy at, in RFC 3339 format
+	gpuUnhealthySinceAnnotationKey = "nvidia.com/gpu.unhealthy-since"
+	// gpuHealthRemediationAnnotationKey is the node annotation recording the remediation of an
+	// unhealthy GPU node, so that it is only remediated once
+	gpuHealthRemediationAnnotationKey = "nvidia.com/gpu.health-remediation"
+	// gpuHealthRemediationCordoned is the remediation of a node cordoned by the controller
+	gpuHealthRemediationCordoned = "cordoned"
+	// gpuHealthRemediationRestarted is the remediation of a node already cordoned, which the
+	// controller leaves cordoned once healthy again
+	gpuHealthRemediationRestarted = "restarted"
+
+	// revalidationFailedKey is the key of the label or taint the controller sets on the nodes
+	// whose operator validator reports failed revalidations, according to the failure action
+	revalidationFailedKey = "nvidia.com/gpu.revalidation.failed"
+
+	// dcgmExporterMetricsPortName is the name of the port dcgm-exporter serves its metrics on
+	dcgmExporterMetricsPortName = "metrics"
+	// dcgmExporterDefaultPort is the port dcgm-exporter serves its metrics on by default
+	dcgmExporterDefaultPort = 9400
+	// dcgmExporterScrapeTimeout is the timeout of a scrape of the metrics of dcgm-exporter
+	dcgmExporterScrapeTimeout = 10 * time.Second
+)
+
+// dcgmExporterAppLabelValues are the app labels of the dcgm-exporter pods of the
+// ClusterPolicy and GPUCluster stacks
+var dcgmExporterAppLabelValues = []string{"nvidia-dcgm-exporter", "nvidia-dcgm-exporter-dra"}
+
+// GPUHealthReconciler periodically checks the health of the GPU nodes against the results of
+// the operator validator, the DCGM health fields exported by dcgm-exporter and the GPUs the
+// device plugin withdrew from the allocatable resources. Unhealthy nodes are tainted with
+// nvidia.com/gpu.unhealthy:NoSchedule until they are healthy again. Nodes which stay
+// unhealthy are cordoned and their driver pod restarted when escalation is enabled.
+// The nodes whose operator validator reports failed revalidations are labeled or tainted
+// according to the revalidation failure action, whether the health checks are enabled or not.
+type GPUHealthReconciler struct {
+	client.Client
+	Scheme    *runtime.Scheme
+	Namespace string
+	Log       logr.Logger
+
+	recorder events.EventRecorder
+
+	// scrapeDCGMExporter returns the metrics exported by a dcgm-exporter pod,
+	// scrapeDCGMExporterPod if unset
+	scrapeDCGMExporter func(ctx context.Context, pod *corev1.Pod) (map[string]*dto.MetricFamily, error)
+	// now returns the current time, time.Now if unset
+	now func() time.Time
+}
+
+// gpuHealthCheck holds the configuration of a reconciliation
+type gpuHealthCheck struct {
+	spec *gpuv1.GPUHealthSpec
+	// related is the custom resource the configuration comes from
+	related runtime.Object
+	// signals are the signals checked, those not applying to the deployed stack excluded
+	signals map[gpuv1.GPUHealthSignal]bool
+	// dcgmExporters are the dcgm-exporter pods, by node
+	dcgmExporters map[string]*corev1.Pod
+	// revalidationFailureAction is the action taken on the nodes failing a revalidation
+	revalidationFailureAction gpuv1.RevalidationFailureAction
+	// revalidationFailures are the components failing their revalidation, by node
+	revalidationFailures map[string][]string
+	// validationSummaries are the validation summaries published by the node status
+	// exporter pods, by node
+	validationSummaries map[string]string
+}
+
+// gpuHealth is the health of the GPUs of a node
+type gpuHealth struct {
+	// problems are the reasons the GPUs are unhealthy
+	problems []string
+	// unknown is true if a signal could not be checked
+	unknown bool
+}
+
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;delete
+
+// Reconcile checks the health of all the GPU nodes and requeues itself after the check interval.
+func (r *GPUHealthReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	clusterPolicy, gpuCluster, err := resolveActiveConfig(ctx, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	check := &gpuHealthCheck{
+		signals:                   map[gpuv1.GPUHealthSignal]bool{},
+		revalidationFailureAction: gpuv1.RevalidationFailureActionNone,
+	}
+	switch {
+	case clusterPolicy != nil && gpuCluster != nil:
+		return ctrl.Result{}, fmt.Errorf("both ClusterPolicy and GPUCluster CRs exist; only one may be present at a time")
+	case clusterPolicy != nil:
+		check.spec = clusterPolicy.Spec.GPUHealth
+		check.related = clusterPolicy
+		if revalidation := &clusterPolicy.Spec.Validator.Revalidation; revalidation.IsEnabled() {
+			check.revalidationFailureAction = revalidation.GetFailureAction()
+			check.revalidationFailures, err = r.getRevalidationFailures(ctx)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	case gpuCluster != nil:
+		check.spec = gpuCluster.Spec.GPUHealth
+		check.related = gpuCluster
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := r.List(ctx, nodes, client.MatchingLabels{commonGPULabelKey: "true"}); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list GPU nodes: %w", err)
+	}
+	for i := range nodes.Items {
+		// the nodes are also marked when the checks are disabled, or unmarked when the
+		// revalidation is disabled
+		if err := r.markRevalidationFailures(ctx, check, &nodes.Items[i]); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if !check.spec.IsEnabled() {
+		// release the nodes tainted before the checks were disabled
+		return ctrl.Result{}, r.releaseNodes(ctx, check, true)
+	}
+	// release the nodes tainted before they lost their GPUs, they are no longer checked
+	if err := r.releaseNodes(ctx, check, false); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	for _, signal := range check.spec.GetSignals() {
+		check.signals[signal] = true
+	}
+	if gpuCluster != nil {
+		// the DRA driver does not withdraw unhealthy GPUs from the allocatable resources
+		delete(check.signals, gpuv1.GPUHealthSignalDevicePlugin)
+	}
+	dcgmExporterEnabled := clusterPolicy != nil && clusterPolicy.Spec.DCGMExporter.IsEnabled() ||
+		gpuCluster != nil && gpuCluster.Spec.DCGMExporter != nil && gpuCluster.Spec.DCGMExporter.IsEnabled()
+	if !dcgmExporterEnabled {
+		delete(check.signals, gpuv1.GPUHealthSignalDCGM)
+	}
+	if check.signals[gpuv1.GPUHealthSignalDCGM] {
+		check.dcgmExporters, err = r.getDCGMExporterPods(ctx)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	if check.signals[gpuv1.GPUHealthSignalValidation] {
+		check.validationSummaries, err = getValidationSummaries(ctx, r.Client, r.Namespace)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		health := r.checkNode(ctx, check, node)
+		if err := r.updateNode(ctx, check, node, health); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{RequeueAfter: check.spec.GetInterval()}, nil
+}
+
+// checkNode checks the health of the GPUs of node against the signals
+func (r *GPUHealthReconciler) checkNode(ctx context.Context, check *gpuHealthCheck, node *corev1.Node) gpuHealth {
+	health := gpuHealth{}
+	if check.signals[gpuv1.GPUHealthSignalValidation] {
+		health.problems = append(health.problems, validationHealthProblems(node.Name, check.validationSummaries, check.revalidationFailures[node.Name], r.Log)...)
+	}
+	if check.signals[gpuv1.GPUHealthSignalDevicePlugin] {
+		health.problems = append(health.problems, devicePluginHealthProblems(node)...)
+	}
+	if check.signals[gpuv1.GPUHealthSignalDCGM] {
+		pod, ok := check.dcgmExporters[node.Name]
+		if !ok {
+			// dcgm-exporter may not be running yet, or be restarted by a remediation
+			health.unknown = true
+			return health
+		}
+		families, err := r.scrapeDCGMExporter(ctx, pod)
+		if err != nil {
+			r.Log.V(consts.LogLevelDebug).Info("Failed to scrape dcgm-exporter", "node", node.Name, "pod", pod.Name, "error", err.Error())
+			health.unknown = true
+			return health
+		}
+		health.problems = append(health.problems, dcgmHealthProblems(families, check.spec.GetDCGMFields())...)
+	}
+	return health
+}
+
+// updateNode taints node if its GPUs are unhealthy and remediates it once it stayed unhealthy
+// long enough, or releases it if they are healthy again
+func (r *GPUHealthReconciler) updateNode(ctx context.Context, check *gpuHealthCheck, node *corev1.Node, health gpuHealth) error {
+	tainted := hasGPUUnhealthyTaint(node)
+	if len(health.problems) == 0 {
+		if health.unknown || !tainted && node.Annotations[gpuUnhealthySinceAnnotationKey] == "" {
+			// the health of a tainted node is only known once all the signals are checked
+			return nil
+		}
+		if err := r.releaseNode(ctx, node); err != nil {
+			return err
+		}
+		r.recorder.Eventf(node, check.related, corev1.EventTypeNormal, "GPUHealthy", "Untaint",
+			"GPUs of node %s are healthy again", node.Name)
+		return nil
+	}
+
+	message := strings.Join(health.problems, "; ")
+	if !tainted {
+		original := node.DeepCopy()
+		setGPUUnhealthyTaint(node, true)
+		if node.Annotations == nil {
+			node.Annotations = map[string]string{}
+		}
+		node.Annotations[gpuUnhealthySinceAnnotationKey] = r.now().UTC().Format(time.RFC3339)
+		if err := r.patchNode(ctx, node, original); err != nil {
+			return err
+		}
+		r.Log.Info("Tainted node with unhealthy GPUs", "node", node.Name, "problems", message)
+		r.recorder.Eventf(node, check.related, corev1.EventTypeWarning, "GPUUnhealthy", "Taint",
+			"GPUs of node %s are unhealthy: %s", node.Name, message)
+	}
+
+	if !check.spec.IsEscalationEnabled() || !gpuHealthRemediationDue(node, check.spec.GetEscalationAfter(), r.now()) {
+		return nil
+	}
+	return r.remediateNode(ctx, check, node, message)
+}
+
+// remediateNode cordons node and restarts its driver pod
+func (r *GPUHealthReconciler) remediateNode(ctx context.Context, check *gpuHealthCheck, node *corev1.Node, message string) error {
+	original := node.DeepCopy()
+	remediation := gpuHealthRemediationRestarted
+	if !node.Spec.Unschedulable {
+		node.Spec.Unschedulable = true
+		remediation = gpuHealthRemediationCordoned
+	}
+	node.Annotations[gpuHealthRemediationAnnotationKey] = remediation
+	if err := r.patchNode(ctx, node, original); err != nil {
+		return err
+	}
+
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(r.Namespace),
+		client.MatchingLabels{AppComponentLabelKey: DriverAppComponentLabelValue}); err != nil {
+		return fmt.Errorf("failed to list driver pods: %w", err)
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Spec.NodeName != node.Name {
+			continue
+		}
+		if err := r.Delete(ctx, pod); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to restart driver pod %s: %w", pod.Name, err)
+		}
+		r.Log.Info("Restarted driver pod of node with unhealthy GPUs", "node", node.Name, "pod", pod.Name)
+	}
+
+	r.recorder.Eventf(node, check.related, corev1.EventTypeWarning, "GPUHealthRemediation", "Cordon",
+		"GPUs of node %s stayed unhealthy for %s, cordoned the node and restarted its driver: %s",
+		node.Name, check.spec.GetEscalationAfter(), message)
+	return nil
+}
+
+// releaseNode removes the taint and annotations of unhealthy GPU nodes from node, and
+// uncordons it if the controller cordoned it
+func (r *GPUHealthReconciler) releaseNode(ctx context.Context, node *corev1.Node) error {
+	original := node.DeepCopy()
+	setGPUUnhealthyTaint(node, false)
+	if node.Annotations[gpuHealthRemediationAnnotationKey] == gpuHealthRemediationCordoned {
+		node.Spec.Unschedulable = false
+	}
+	delete(node.Annotations, gpuUnhealthySinceAnnotationKey)
+	delete(node.Annotations, gpuHealthRemediationAnnotationKey)
+	if err := r.patchNode(ctx, node, original); err != nil {
+		return err
+	}
+	r.Log.Info("Released node with healthy GPUs", "node", node.Name)
+	return nil
+}
+
+// releaseNodes releases the nodes tainted as unhealthy: all of them when the checks are
+// disabled, or only those no longer labeled as GPU nodes otherwise
+func (r *GPUHealthReconciler) releaseNodes(ctx context.Context, check *gpuHealthCheck, disabled bool) error {
+	nodes := &corev1.NodeList{}
+	if err := r.List(ctx, nodes); err != nil {
+		return fmt.Errorf("failed to list nodes: %w", err)
+	}
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if !hasGPUUnhealthyTaint(node) && node.Annotations[gpuUnhealthySinceAnnotationKey] == "" {
+			continue
+		}
+		if !disabled && node.Labels[commonGPULabelKey] == "true" {
+			continue
+		}
+		if err := r.releaseNode(ctx, node); err != nil {
+			return err
+		}
+		if check.related == nil {
+			continue
+		}
+		if disabled {
+			r.recorder.Eventf(node, check.related, corev1.EventTypeNormal, "GPUHealthDisabled", "Untaint",
+				"GPU health checks are disabled, released node %s", node.Name)
+		} else {
+			r.recorder.Eventf(node, check.related, corev1.EventTypeNormal, "GPUNodeRemoved", "Untaint",
+				"Node %s is no longer a GPU node, released it", node.Name)
+		}
+	}
+	return nil
+}
+
+// markRevalidationFailures labels or taints node, according to the revalidation failure
+// action, if its operator validator reports failed revalidations. Otherwise it removes the
+// label or taint.
+func (r *GPUHealthReconciler) markRevalidationFailures(ctx context.Context, check *gpuHealthCheck, node *corev1.Node) error {
+	failures := check.revalidationFailures[node.Name]
+	original := node.DeepCopy()
+	if !setRevalidationFailed(node, check.revalidationFailureAction, len(failures) > 0) {
+		return nil
+	}
+	if err := r.patchNode(ctx, node, original); err != nil {
+		return err
+	}
+	if len(failures) == 0 || check.revalidationFailureAction == gpuv1.RevalidationFailureActionNone {
+		r.Log.Info("Unmarked node previously failing revalidations", "node", node.Name)
+		if check.related != nil {
+			r.recorder.Eventf(node, check.related, corev1.EventTypeNormal, "GPURevalidationUnmarked", "Unmark",
+				"Node %s is no longer marked as failing revalidations", node.Name)
+		}
+		return nil
+	}
+	message := strings.Join(failures, ", ")
+	r.Log.Info("Marked node failing revalidations", "node", node.Name, "components", message,
+		"action", check.revalidationFailureAction)
+	r.recorder.Eventf(node, check.related, corev1.EventTypeWarning, "GPURevalidationFailed", "Mark",
+		"Revalidation of %s failed on node %s", message, node.Name)
+	return nil
+}
+
+// patchNode patches node from original. The patch fails if node changed in between, to not
+// drop the taints added meanwhile.
+func (r *GPUHealthReconciler) patchNode(ctx context.Context, node, original *corev1.Node) error {
+	if err := r.Patch(ctx, node, client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{})); err != nil {
+		return fmt.Errorf("failed to patch node %s: %w", node.Name, err)
+	}
+	return nil
+}
+
+// getDCGMExporterPods returns the running dcgm-exporter pods, by node
+func (r *GPUHealthReconciler) getDCGMExporterPods(ctx context.Context) (map[string]*corev1.Pod, error) {
+	requirement, err := labels.NewRequirement("app", selection.In, dcgmExporterAppLabelValues)
+	if err != nil {
+		return nil, err
+	}
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(r.Namespace),
+		client.MatchingLabelsSelector{Selector: labels.NewSelector().Add(*requirement)}); err != nil {
+		return nil, fmt.Errorf("failed to list dcgm-exporter pods: %w", err)
+	}
+	exporters := map[string]*corev1.Pod{}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Spec.NodeName == "" || pod.Status.PodIP == "" || pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		exporters[pod.Spec.NodeName] = pod
+	}
+	return exporters, nil
+}
+
+// getRevalidationFailures returns the components the operator validator pods report failing
+// their revalidation, by node
+func (r *GPUHealthReconciler) getRevalidationFailures(ctx context.Context) (map[string][]string, error) {
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(r.Namespace),
+		client.MatchingLabels{DriverLabelKey: ValidatorAppLabelValue}); err != nil {
+		return nil, fmt.Errorf("failed to list operator validator pods: %w", err)
+	}
+	failures := map[string][]string{}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		value := pod.Annotations[validation.RevalidationFailedAnnotation]
+		if pod.Spec.NodeName == "" || value == "" {
+			continue
+		}
+		failures[pod.Spec.NodeName] = strings.Split(value, ",")
+	}
+	return failures, nil
+}
+
+// scrapeDCGMExporterPod returns the metrics exported by the dcgm-exporter pod
+func scrapeDCGMExporterPod(ctx context.Context, pod *corev1.Pod) (map[string]*dto.MetricFamily, error) {
+	ctx, cancel := context.WithTimeout(ctx, dcgmExporterScrapeTimeout)
+	defer cancel()
+
+	url := fmt.Sprintf("http://%s/metrics", net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(int(dcgmExporterPort(pod)))))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s scraping %s", resp.Status, url)
+	}
+	parser := expfmt.NewTextParser(model.UTF8Validation)
+	return parser.TextToMetricFamilies(resp.Body)
+}
+
+// dcgmExporterPort returns the port the dcgm-exporter pod serves its metrics on
+func dcgmExporterPort(pod *corev1.Pod) int32 {
+	for _, container := range pod.Spec.Containers {
+		for _, port := range container.Ports {
+			if port.Name == dcgmExporterMetricsPortName {
+				return port.ContainerPort
+			}
+		}
+	}
+	return dcgmExporterDefaultPort
+}
+
+// validationHealthProblems returns the components of the operator validator which failed on
+// node according to its validation summary, and those failing their revalidation
+func validationHealthProblems(node string, summaries map[string]string, revalidationFailures []string, logger logr.Logger) []string {
+	var problems []string
+	if value, ok := summaries[node]; ok {
+		summary, err := validation.ParseNodeSummary(value)
+		if err != nil {
+			logger.Error(err, "Ignoring invalid validation summary of node", "node", node)
+		}
+		var failed []string
+		for component, report := range summary {
+			if report.Result == validation.ResultFailed {
+				failed = append(failed, component)
+			}
+		}
+		sort.Strings(failed)
+		for _, component := range failed {
+			problems = append(problems, fmt.Sprintf("%s validation failed", component))
+		}
+	}
+
+	for _, component := range revalidationFailures {
+		problems = append(problems, fmt.Sprintf("%s revalidation failed", component))
+	}
+	return problems
+}
+
+// devicePluginHealthProblems returns the GPU resources the device plugin withdrew from the
+// allocatable resources of node as unhealthy
+func devicePluginHealthProblems(node *corev1.Node) []string {
+	var problems []string
+	for name, capacity := range node.Status.Capacity {
+		if !strings.HasPrefix(string(name), "nvidia.com/gpu") && !strings.HasPrefix(string(name), "nvidia.com/mig-") {
+			continue
+		}
+		allocatable := node.Status.Allocatable[name]
+		if unhealthy := capacity.Value() - allocatable.Value(); unhealthy > 0 {
+			problems = append(problems, fmt.Sprintf("%d of %d %s unhealthy", unhealthy, capacity.Value(), name))
+		}
+	}
+	sort.Strings(problems)
+	return problems
+}
+
+// dcgmHealthProblems returns the GPUs with a non zero value of any of fields in the metrics
+// families exported by dcgm-exporter
+func dcgmHealthProblems(families map[string]*dto.MetricFamily, fields []string) []string {
+	var problems []string
+	for _, field := range fields {
+		family, ok := families[field]
+		if !ok {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			var value float64
+			switch {
+			case metric.GetGauge() != nil:
+				value = metric.GetGauge().GetValue()
+			case metric.GetCounter() != nil:
+				value = metric.GetCounter().GetValue()
+			case metric.GetUntyped() != nil:
+				value = metric.GetUntyped().GetValue()
+			}
+			if value == 0 {
+				continue
+			}
+			gpu := "unknown"
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "gpu" {
+					gpu = label.GetValue()
+				}
+			}
+			problems = append(problems, fmt.Sprintf("GPU %s reports %s=%v", gpu, field, value))
+		}
+	}
+	return problems
+}
+
+// hasGPUUnhealthyTaint returns true if node is tainted as unhealthy
+func hasGPUUnhealthyTaint(node *corev1.Node) bool {
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == consts.GPUUnhealthyTaintKey {
+			return true
+		}
+	}
+	return false
+}
+
+// setGPUUnhealthyTaint taints node as unhealthy if unhealthy, or removes the taint. It returns
+// true if node was changed.
+func setGPUUnhealthyTaint(node *corev1.Node, unhealthy bool) bool {
+	tainted := hasGPUUnhealthyTaint(node)
+	if unhealthy == tainted {
+		return false
+	}
+	if unhealthy {
+		node.Spec.Taints = append(node.Spec.Taints, corev1.Taint{
+			Key:    consts.GPUUnhealthyTaintKey,
+			Value:  "true",
+			Effect: corev1.TaintEffectNoSchedule,
+		})
+		return true
+	}
+	var taints []corev1.Taint
+	for _, taint := range node.Spec.Taints {
+		if taint.Key != consts.GPUUnhealthyTaintKey {
+			taints = append(taints, taint)
+		}
+	}
+	node.Spec.Taints = taints
+	return true
+}
+
+// setRevalidationFailed labels or taints node, according to action, if failed. Otherwise it
+// removes any label or taint. It returns true if node was changed.
+func setRevalidationFailed(node *corev1.Node, action gpuv1.RevalidationFailureAction, failed bool) bool {
+	changed := false
+	if failed && action == gpuv1.RevalidationFailureActionLabel {
+		if node.Labels[revalidationFailedKey] != "true" {
+			if node.Labels == nil {
+				node.Labels = map[string]string{}
+			}
+			node.Labels[revalidationFailedKey] = "true"
+			changed = true
+		}
+	} else if _, ok := node.Labels[revalidationFailedKey]; ok {
+		delete(node.Labels, revalidationFailedKey)
+		changed = true
+	}
+
+	var taints []corev1.Taint
+	tainted := false
+	for _, taint := range node.Spec.Taints {
+		if taint.Key != revalidationFailedKey {
+			taints = append(taints, taint)
+			continue
+		}
+		tainted = true
+	}
+	if failed && action == gpuv1.RevalidationFailureActionTaint {
+		if !tainted {
+			node.Spec.Taints = append(node.Spec.Taints, corev1.Taint{
+				Key:    revalidationFailedKey,
+				Value:  "true",
+				Effect: corev1.TaintEffectNoSchedule,
+			})
+			changed = true
+		}
+	} else if tainted {
+		node.Spec.Taints = taints
+		changed = true
+	}
+	return changed
+}
+
+// gpuHealthRemediationDue returns true if the unhealthy node was not remediated yet and stayed
+// unhealthy for after
+func gpuHealthRemediationDue(node *corev1.Node, after time.Duration, now time.Time) bool {
+	if node.Annotations[gpuHealthRemediationAnnotationKey] != "" {
+		return false
+	}
+	since, err := time.Parse(time.RFC3339, node.Annotations[gpuUnhealthySinceAnnotationKey])
+	if err != nil {
+		return false
+	}
+	return !now.Before(since.Add(after))
+}
+
+// gpuHealthInputsChanged returns true if the node changed in a way which may change the health
+// of its GPUs
+func gpuHealthInputsChanged(oldNode, newNode *corev1.Node) bool {
+	return len(devicePluginHealthProblems(oldNode)) != len(devicePluginHealthProblems(newNode))
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GPUHealthReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
+	r.recorder = mgr.GetEventRecorder("nvidia-gpu-operator")
+	if r.scrapeDCGMExporter == nil {
+		r.scrapeDCGMExporter = scrapeDCGMExporterPod
+	}
+	if r.now == nil {
+		r.now = time.Now
+	}
+
+	c, err := controller.New("gpu-health-controller", mgr, controller.Options{
+		Reconciler:              r,
+		MaxConcurrentReconciles: 1,
+		RateLimiter:             workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](minDelayCR, maxDelayCR),
+	})
+	if err != nil {
+		return fmt.Errorf("error creating gpu-health controller: %w", err)
+	}
+
+	mapToSingleton := func(_ context.Context, _ client.Object) []reconcile.Request {
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: gpuHealthControllerSingletonName}}}
+	}
+
+	if err := c.Watch(source.Kind(
+		mgr.GetCache(),
+		&gpuv1.ClusterPolicy{},
+		handler.TypedEnqueueRequestsFromMapFunc(func(ctx context.Context, cp *gpuv1.ClusterPolicy) []reconcile.Request {
+			return mapToSingleton(ctx, cp)
+		}),
+		singletonCRPredicate[*gpuv1.ClusterPolicy](),
+	)); err != nil {
+		return fmt.Errorf("error watching ClusterPolicy: %w", err)
+	}
+
+	if err := c.Watch(source.Kind(
+		mgr.GetCache(),
+		&nvidiav1alpha1.GPUCluster{},
+		handler.TypedEnqueueRequestsFromMapFunc(func(ctx context.Context, gc *nvidiav1alpha1.GPUCluster) []reconcile.Request {
+			return mapToSingleton(ctx, gc)
+		}),
+		singletonCRPredicate[*nvidiav1alpha1.GPUCluster](),
+	)); err != nil {
+		return fmt.Errorf("error watching GPUCluster: %w", err)
+	}
+
+	// The periodic checks catch every change eventually, the watch of the nodes only reacts
+	// sooner to the signals published on the nodes.
+	nodePredicate := predicate.TypedFuncs[*corev1.Node]{
+		CreateFunc: func(e event.TypedCreateEvent[*corev1.Node]) bool {
+			return false
+		},
+		UpdateFunc: func(e event.TypedUpdateEvent[*corev1.Node]) bool {
+			if e.ObjectNew.GetLabels()[commonGPULabelKey] != "true" {
+				// a node losing its GPUs is released
+				return e.ObjectOld.GetLabels()[commonGPULabelKey] == "true"
+			}
+			return gpuHealthInputsChanged(e.ObjectOld, e.ObjectNew)
+		},
+		DeleteFunc: func(e event.TypedDeleteEvent[*corev1.Node]) bool {
+			return false
+		},
+	}
+	if err := c.Watch(source.Kind(
+		mgr.GetCache(),
+		&corev1.Node{},
+		handler.TypedEnqueueRequestsFromMapFunc(func(ctx context.Context, n *corev1.Node) []reconcile.Request {
+			return mapToSingleton(ctx, n)
+		}),
+		nodePredicate,
+	)); err != nil {
+		return fmt.Errorf("error watching Nodes: %w", err)
+	}
+
+	// The operator validator pods report the components failing their revalidation, and the
+	// node status exporter pods the validation summaries. A deleted pod no longer reports them.
+	validatorPodPredicate := predicate.TypedFuncs[*corev1.Pod]{
+		CreateFunc: func(e event.TypedCreateEvent[*corev1.Pod]) bool {
+			return false
+		},
+		UpdateFunc: func(e event.TypedUpdateEvent[*corev1.Pod]) bool {
+			annotation, ok := gpuHealthPodAnnotation(e.ObjectNew)
+			return ok && e.ObjectOld.GetAnnotations()[annotation] != e.ObjectNew.GetAnnotations()[annotation]
+		},
+		DeleteFunc: func(e event.TypedDeleteEvent[*corev1.Pod]) bool {
+			annotation, ok := gpuHealthPodAnnotation(e.Object)
+			return ok && e.Object.GetAnnotations()[annotation] != ""
+		},
+	}
+	if err := c.Watch(source.Kind(
+		mgr.GetCache(),
+		&corev1.Pod{},
+		handler.TypedEnqueueRequestsFromMapFunc(func(ctx context.Context, p *corev1.Pod) []reconcile.Request {
+			return mapToSingleton(ctx, p)
+		}),
+		validatorPodPredicate,
+	)); err != nil {
+		return fmt.Errorf("error watching Pods: %w", err)
+	}
+	return nil
+}
+
+// gpuHealthPodAnnotation returns the annotation a pod reports a GPU health signal with, and
+// true if the pod reports one
+func gpuHealthPodAnnotation(pod *corev1.Pod) (string, bool) {
+	switch pod.GetLabels()[DriverLabelKey] {
+	case ValidatorAppLabelValue:
+		return validation.RevalidationFailedAnnotation, true
+	case nodeStatusExporterAppLabelValue:
+		return validation.SummaryAnnotation, true
+	}
+	return "", false
+}
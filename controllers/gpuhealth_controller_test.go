@@ -0,0 +1,471 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1"
+	nvidiav1alpha1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1alpha1"
+	"github.com/NVIDIA/gpu-operator/internal/consts"
+	"github.com/NVIDIA/gpu-operator/internal/validation"
+)
+
+const gpuHealthTestNamespace = "gpu-operator"
+
+func parseDCGMMetrics(t *testing.T, text string) map[string]*dto.MetricFamily {
+	t.Helper()
+	parser := expfmt.NewTextParser(model.UTF8Validation)
+	families, err := parser.TextToMetricFamilies(strings.NewReader(text))
+	require.NoError(t, err)
+	return families
+}
+
+func newGPUHealthTestNode(gpus, allocatable int64) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "gpu-node",
+			Labels: map[string]string{commonGPULabelKey: "true"},
+		},
+		Status: corev1.NodeStatus{
+			Capacity:    corev1.ResourceList{"nvidia.com/gpu": *resource.NewQuantity(gpus, resource.DecimalSI)},
+			Allocatable: corev1.ResourceList{"nvidia.com/gpu": *resource.NewQuantity(allocatable, resource.DecimalSI)},
+		},
+	}
+}
+
+// newGPUHealthTestReconciler builds a reconciler over a fake client seeded with objs. The
+// dcgm-exporter of the nodes exports metrics.
+func newGPUHealthTestReconciler(t *testing.T, metrics *string, objs ...client.Object) (*GPUHealthReconciler, client.Client, *events.FakeRecorder, *time.Time) {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, gpuv1.AddToScheme(scheme))
+	require.NoError(t, nvidiav1alpha1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	recorder := events.NewFakeRecorder(100)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	return &GPUHealthReconciler{
+		Client:    c,
+		Scheme:    scheme,
+		Namespace: gpuHealthTestNamespace,
+		Log:       logr.Discard(),
+		recorder:  recorder,
+		scrapeDCGMExporter: func(_ context.Context, _ *corev1.Pod) (map[string]*dto.MetricFamily, error) {
+			return parseDCGMMetrics(t, *metrics), nil
+		},
+		now: func() time.Time { return now },
+	}, c, recorder, &now
+}
+
+func gpuHealthReconcile(t *testing.T, r *GPUHealthReconciler) ctrl.Result {
+	t.Helper()
+	result, err := r.Reconcile(t.Context(), ctrl.Request{NamespacedName: types.NamespacedName{Name: gpuHealthControllerSingletonName}})
+	require.NoError(t, err)
+	return result
+}
+
+func getGPUHealthTestNode(t *testing.T, c client.Client) *corev1.Node {
+	t.Helper()
+	node := &corev1.Node{}
+	require.NoError(t, c.Get(t.Context(), types.NamespacedName{Name: "gpu-node"}, node))
+	return node
+}
+
+func TestValidationHealthProblems(t *testing.T) {
+	summary := func(t *testing.T, results map[string]validation.Result) string {
+		t.Helper()
+		summary := validation.NodeSummary{}
+		for component, result := range results {
+			summary[component] = validation.ComponentSummary{Result: result}
+		}
+		data, err := json.Marshal(summary)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	testCases := []struct {
+		description          string
+		summaries            func(t *testing.T) map[string]string
+		revalidationFailures []string
+		expected             []string
+	}{
+		{
+			description: "no validation summary",
+			summaries: func(t *testing.T) map[string]string {
+				return nil
+			},
+		},
+		{
+			description: "all components passed or running",
+			summaries: func(t *testing.T) map[string]string {
+				return map[string]string{"gpu-node": summary(t, map[string]validation.Result{
+					"driver": validation.ResultPassed,
+					"cuda":   validation.ResultRunning,
+				})}
+			},
+		},
+		{
+			description: "failed components",
+			summaries: func(t *testing.T) map[string]string {
+				return map[string]string{"gpu-node": summary(t, map[string]validation.Result{
+					"driver": validation.ResultPassed,
+					"plugin": validation.ResultFailed,
+					"cuda":   validation.ResultFailed,
+				})}
+			},
+			expected: []string{"cuda validation failed", "plugin validation failed"},
+		},
+		{
+			description: "failed revalidations",
+			summaries: func(t *testing.T) map[string]string {
+				return map[string]string{"gpu-node": summary(t, map[string]validation.Result{
+					"driver": validation.ResultFailed,
+				})}
+			},
+			revalidationFailures: []string{"driver", "cuda"},
+			expected:             []string{"driver validation failed", "driver revalidation failed", "cuda revalidation failed"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			require.Equal(t, tc.expected, validationHealthProblems("gpu-node", tc.summaries(t), tc.revalidationFailures, logr.Discard()))
+		})
+	}
+}
+
+func TestDevicePluginHealthProblems(t *testing.T) {
+	require.Empty(t, devicePluginHealthProblems(newGPUHealthTestNode(8, 8)))
+	require.Equal(t, []string{"2 of 8 nvidia.com/gpu unhealthy"}, devicePluginHealthProblems(newGPUHealthTestNode(8, 6)))
+
+	node := newGPUHealthTestNode(0, 0)
+	node.Status.Capacity = corev1.ResourceList{
+		"nvidia.com/mig-1g.10gb": resource.MustParse("7"),
+		corev1.ResourceCPU:       resource.MustParse("64"),
+	}
+	node.Status.Allocatable = corev1.ResourceList{
+		"nvidia.com/mig-1g.10gb": resource.MustParse("6"),
+		corev1.ResourceCPU:       resource.MustParse("63"),
+	}
+	require.Equal(t, []string{"1 of 7 nvidia.com/mig-1g.10gb unhealthy"}, devicePluginHealthProblems(node))
+}
+
+func TestDCGMHealthProblems(t *testing.T) {
+	families := parseDCGMMetrics(t, `# TYPE DCGM_FI_DEV_ROW_REMAP_FAILURE gauge
+DCGM_FI_DEV_ROW_REMAP_FAILURE{gpu="0",UUID="GPU-0"} 0
+DCGM_FI_DEV_ROW_REMAP_FAILURE{gpu="1",UUID="GPU-1"} 1
+# TYPE DCGM_FI_DEV_XID_ERRORS gauge
+DCGM_FI_DEV_XID_ERRORS{gpu="0",UUID="GPU-0"} 79
+`)
+
+	require.Equal(t, []string{"GPU 1 reports DCGM_FI_DEV_ROW_REMAP_FAILURE=1"},
+		dcgmHealthProblems(families, []string{"DCGM_FI_DEV_ROW_REMAP_FAILURE"}))
+	require.Equal(t, []string{"GPU 1 reports DCGM_FI_DEV_ROW_REMAP_FAILURE=1", "GPU 0 reports DCGM_FI_DEV_XID_ERRORS=79"},
+		dcgmHealthProblems(families, []string{"DCGM_FI_DEV_ROW_REMAP_FAILURE", "DCGM_FI_DEV_XID_ERRORS"}))
+	require.Empty(t, dcgmHealthProblems(families, []string{"DCGM_FI_DEV_NVLINK_CRC_FLIT_ERROR_COUNT_TOTAL"}))
+}
+
+func TestSetGPUUnhealthyTaint(t *testing.T) {
+	node := &corev1.Node{Spec: corev1.NodeSpec{Taints: []corev1.Taint{
+		{Key: "nvidia.com/gpu", Effect: corev1.TaintEffectNoSchedule},
+	}}}
+
+	require.True(t, setGPUUnhealthyTaint(node, true))
+	require.True(t, hasGPUUnhealthyTaint(node))
+	require.Len(t, node.Spec.Taints, 2)
+	require.False(t, setGPUUnhealthyTaint(node, true))
+
+	require.True(t, setGPUUnhealthyTaint(node, false))
+	require.Equal(t, []corev1.Taint{{Key: "nvidia.com/gpu", Effect: corev1.TaintEffectNoSchedule}}, node.Spec.Taints)
+	require.False(t, setGPUUnhealthyTaint(node, false))
+}
+
+func TestSetRevalidationFailed(t *testing.T) {
+	otherTaint := corev1.Taint{Key: "nvidia.com/gpu", Effect: corev1.TaintEffectNoSchedule}
+	failedTaint := corev1.Taint{Key: revalidationFailedKey, Value: "true", Effect: corev1.TaintEffectNoSchedule}
+
+	node := &corev1.Node{Spec: corev1.NodeSpec{Taints: []corev1.Taint{otherTaint}}}
+	require.False(t, setRevalidationFailed(node, gpuv1.RevalidationFailureActionNone, true))
+
+	require.True(t, setRevalidationFailed(node, gpuv1.RevalidationFailureActionLabel, true))
+	require.Equal(t, map[string]string{revalidationFailedKey: "true"}, node.Labels)
+	require.False(t, setRevalidationFailed(node, gpuv1.RevalidationFailureActionLabel, true))
+
+	// changing the failure action replaces the label with the taint
+	require.True(t, setRevalidationFailed(node, gpuv1.RevalidationFailureActionTaint, true))
+	require.Empty(t, node.Labels)
+	require.Equal(t, []corev1.Taint{otherTaint, failedTaint}, node.Spec.Taints)
+	require.False(t, setRevalidationFailed(node, gpuv1.RevalidationFailureActionTaint, true))
+
+	require.True(t, setRevalidationFailed(node, gpuv1.RevalidationFailureActionTaint, false))
+	require.Equal(t, []corev1.Taint{otherTaint}, node.Spec.Taints)
+}
+
+func TestGPUHealthRemediationDue(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 10, 0, 0, time.UTC)
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+		gpuUnhealthySinceAnnotationKey: "2026-01-01T00:00:00Z",
+	}}}
+
+	require.True(t, gpuHealthRemediationDue(node, 10*time.Minute, now))
+	require.False(t, gpuHealthRemediationDue(node, 11*time.Minute, now))
+
+	node.Annotations[gpuHealthRemediationAnnotationKey] = gpuHealthRemediationCordoned
+	require.False(t, gpuHealthRemediationDue(node, 10*time.Minute, now))
+
+	require.False(t, gpuHealthRemediationDue(&corev1.Node{}, 0, now))
+}
+
+func TestGPUHealthReconcile(t *testing.T) {
+	clusterPolicy := &gpuv1.ClusterPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-policy"},
+		Spec: gpuv1.ClusterPolicySpec{
+			GPUHealth: &gpuv1.GPUHealthSpec{
+				Enabled:    newBoolPtr(true),
+				Interval:   &metav1.Duration{Duration: 30 * time.Second},
+				Escalation: &gpuv1.GPUHealthEscalationSpec{Enabled: newBoolPtr(true)},
+			},
+		},
+	}
+	dcgmExporter := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "nvidia-dcgm-exporter-abcde",
+			Namespace: gpuHealthTestNamespace,
+			Labels:    map[string]string{"app": "nvidia-dcgm-exporter"},
+		},
+		Spec:   corev1.PodSpec{NodeName: "gpu-node"},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.0.0.1"},
+	}
+	driver := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "nvidia-driver-daemonset-abcde",
+			Namespace: gpuHealthTestNamespace,
+			Labels:    map[string]string{AppComponentLabelKey: DriverAppComponentLabelValue},
+		},
+		Spec: corev1.PodSpec{NodeName: "gpu-node"},
+	}
+	metrics := `DCGM_FI_DEV_ROW_REMAP_FAILURE{gpu="0"} 0` + "\n"
+	r, c, recorder, now := newGPUHealthTestReconciler(t, &metrics,
+		clusterPolicy, dcgmExporter, driver, newGPUHealthTestNode(8, 8))
+
+	// a healthy node is left as is
+	result := gpuHealthReconcile(t, r)
+	require.Equal(t, 30*time.Second, result.RequeueAfter)
+	node := getGPUHealthTestNode(t, c)
+	require.False(t, hasGPUUnhealthyTaint(node))
+	require.Empty(t, recorder.Events)
+
+	// dcgm-exporter reports a row remapping failure
+	metrics = `DCGM_FI_DEV_ROW_REMAP_FAILURE{gpu="0"} 1` + "\n"
+	gpuHealthReconcile(t, r)
+	node = getGPUHealthTestNode(t, c)
+	require.True(t, hasGPUUnhealthyTaint(node))
+	require.Equal(t, "2026-01-01T00:00:00Z", node.Annotations[gpuUnhealthySinceAnnotationKey])
+	require.False(t, node.Spec.Unschedulable)
+	require.Len(t, recorder.Events, 1)
+	require.Contains(t, <-recorder.Events, "GPUUnhealthy")
+
+	// the node is remediated once it stayed unhealthy long enough
+	*now = now.Add(gpuv1.DefaultGPUHealthEscalationAfter)
+	gpuHealthReconcile(t, r)
+	node = getGPUHealthTestNode(t, c)
+	require.True(t, node.Spec.Unschedulable)
+	require.Equal(t, gpuHealthRemediationCordoned, node.Annotations[gpuHealthRemediationAnnotationKey])
+	err := c.Get(t.Context(), client.ObjectKeyFromObject(driver), &corev1.Pod{})
+	require.True(t, apierrors.IsNotFound(err))
+	require.Len(t, recorder.Events, 1)
+	require.Contains(t, <-recorder.Events, "GPUHealthRemediation")
+
+	// and only once
+	gpuHealthReconcile(t, r)
+	require.Empty(t, recorder.Events)
+
+	// the node is released once healthy again
+	metrics = `DCGM_FI_DEV_ROW_REMAP_FAILURE{gpu="0"} 0` + "\n"
+	gpuHealthReconcile(t, r)
+	node = getGPUHealthTestNode(t, c)
+	require.False(t, hasGPUUnhealthyTaint(node))
+	require.False(t, node.Spec.Unschedulable)
+	require.NotContains(t, node.Annotations, gpuUnhealthySinceAnnotationKey)
+	require.NotContains(t, node.Annotations, gpuHealthRemediationAnnotationKey)
+	require.Len(t, recorder.Events, 1)
+	require.Contains(t, <-recorder.Events, "GPUHealthy")
+}
+
+func TestGPUHealthReconcileDevicePlugin(t *testing.T) {
+	clusterPolicy := &gpuv1.ClusterPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-policy"},
+		Spec: gpuv1.ClusterPolicySpec{
+			GPUHealth: &gpuv1.GPUHealthSpec{
+				Enabled: newBoolPtr(true),
+				Signals: []gpuv1.GPUHealthSignal{gpuv1.GPUHealthSignalDevicePlugin},
+			},
+		},
+	}
+	node := newGPUHealthTestNode(8, 7)
+	node.Spec.Unschedulable = true
+	r, c, recorder, now := newGPUHealthTestReconciler(t, nil, clusterPolicy, node)
+	r.scrapeDCGMExporter = nil
+
+	gpuHealthReconcile(t, r)
+	node = getGPUHealthTestNode(t, c)
+	require.True(t, hasGPUUnhealthyTaint(node))
+	require.Contains(t, <-recorder.Events, "1 of 8 nvidia.com/gpu unhealthy")
+
+	// escalation is disabled
+	*now = now.Add(time.Hour)
+	gpuHealthReconcile(t, r)
+	require.Empty(t, getGPUHealthTestNode(t, c).Annotations[gpuHealthRemediationAnnotationKey])
+	require.Empty(t, recorder.Events)
+}
+
+func TestGPUHealthReconcileUnknownHealth(t *testing.T) {
+	clusterPolicy := &gpuv1.ClusterPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-policy"},
+		Spec:       gpuv1.ClusterPolicySpec{GPUHealth: &gpuv1.GPUHealthSpec{Enabled: newBoolPtr(true)}},
+	}
+	node := newGPUHealthTestNode(8, 8)
+	node.Annotations = map[string]string{gpuUnhealthySinceAnnotationKey: "2026-01-01T00:00:00Z"}
+	node.Spec.Taints = []corev1.Taint{{Key: consts.GPUUnhealthyTaintKey, Value: "true", Effect: corev1.TaintEffectNoSchedule}}
+	// no dcgm-exporter pod runs on the node
+	r, c, recorder, _ := newGPUHealthTestReconciler(t, nil, clusterPolicy, node)
+
+	gpuHealthReconcile(t, r)
+	require.True(t, hasGPUUnhealthyTaint(getGPUHealthTestNode(t, c)))
+	require.Empty(t, recorder.Events)
+}
+
+func TestGPUHealthReconcileDisabled(t *testing.T) {
+	clusterPolicy := &gpuv1.ClusterPolicy{ObjectMeta: metav1.ObjectMeta{Name: "cluster-policy"}}
+	node := newGPUHealthTestNode(8, 8)
+	node.Annotations = map[string]string{
+		gpuUnhealthySinceAnnotationKey:    "2026-01-01T00:00:00Z",
+		gpuHealthRemediationAnnotationKey: gpuHealthRemediationRestarted,
+	}
+	node.Spec.Unschedulable = true
+	node.Spec.Taints = []corev1.Taint{{Key: consts.GPUUnhealthyTaintKey, Value: "true", Effect: corev1.TaintEffectNoSchedule}}
+	r, c, recorder, _ := newGPUHealthTestReconciler(t, nil, clusterPolicy, node)
+
+	result := gpuHealthReconcile(t, r)
+	require.Zero(t, result.RequeueAfter)
+	node = getGPUHealthTestNode(t, c)
+	require.False(t, hasGPUUnhealthyTaint(node))
+	require.Empty(t, node.Annotations)
+	// the node was cordoned before its remediation
+	require.True(t, node.Spec.Unschedulable)
+	require.Contains(t, <-recorder.Events, "GPUHealthDisabled")
+}
+
+func TestGPUHealthReconcileNodeWithoutGPUs(t *testing.T) {
+	clusterPolicy := &gpuv1.ClusterPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-policy"},
+		Spec: gpuv1.ClusterPolicySpec{
+			GPUHealth: &gpuv1.GPUHealthSpec{
+				Enabled: newBoolPtr(true),
+				Signals: []gpuv1.GPUHealthSignal{gpuv1.GPUHealthSignalDevicePlugin},
+			},
+		},
+	}
+	// the node lost its GPUs while tainted as unhealthy
+	node := newGPUHealthTestNode(8, 7)
+	node.Labels = nil
+	node.Annotations = map[string]string{
+		gpuUnhealthySinceAnnotationKey:    "2026-01-01T00:00:00Z",
+		gpuHealthRemediationAnnotationKey: gpuHealthRemediationCordoned,
+	}
+	node.Spec.Unschedulable = true
+	node.Spec.Taints = []corev1.Taint{{Key: consts.GPUUnhealthyTaintKey, Value: "true", Effect: corev1.TaintEffectNoSchedule}}
+	r, c, recorder, _ := newGPUHealthTestReconciler(t, nil, clusterPolicy, node)
+	r.scrapeDCGMExporter = nil
+
+	gpuHealthReconcile(t, r)
+	node = getGPUHealthTestNode(t, c)
+	require.False(t, hasGPUUnhealthyTaint(node))
+	require.Empty(t, node.Annotations)
+	require.False(t, node.Spec.Unschedulable)
+	require.Contains(t, <-recorder.Events, "GPUNodeRemoved")
+
+	// the released node is no longer checked
+	gpuHealthReconcile(t, r)
+	require.Empty(t, recorder.Events)
+}
+
+func TestGPUHealthReconcileRevalidation(t *testing.T) {
+	clusterPolicy := &gpuv1.ClusterPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-policy"},
+		Spec: gpuv1.ClusterPolicySpec{
+			Validator: gpuv1.ValidatorSpec{
+				Revalidation: gpuv1.RevalidationSpec{
+					Enabled:       newBoolPtr(true),
+					FailureAction: gpuv1.RevalidationFailureActionTaint,
+				},
+			},
+		},
+	}
+	validator := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "nvidia-operator-validator-abcde",
+			Namespace:   gpuHealthTestNamespace,
+			Labels:      map[string]string{DriverLabelKey: ValidatorAppLabelValue},
+			Annotations: map[string]string{validation.RevalidationFailedAnnotation: "driver,cuda"},
+		},
+		Spec: corev1.PodSpec{NodeName: "gpu-node"},
+	}
+	// the GPU health checks are disabled
+	r, c, recorder, _ := newGPUHealthTestReconciler(t, nil, clusterPolicy, validator, newGPUHealthTestNode(8, 8))
+
+	gpuHealthReconcile(t, r)
+	node := getGPUHealthTestNode(t, c)
+	require.Equal(t, []corev1.Taint{
+		{Key: revalidationFailedKey, Value: "true", Effect: corev1.TaintEffectNoSchedule},
+	}, node.Spec.Taints)
+	require.False(t, hasGPUUnhealthyTaint(node))
+	require.Contains(t, <-recorder.Events, "Revalidation of driver, cuda failed on node gpu-node")
+
+	// the node is marked once
+	gpuHealthReconcile(t, r)
+	require.Empty(t, recorder.Events)
+
+	// the node is unmarked once the revalidations pass again
+	delete(validator.Annotations, validation.RevalidationFailedAnnotation)
+	require.NoError(t, c.Update(t.Context(), validator))
+	gpuHealthReconcile(t, r)
+	require.Empty(t, getGPUHealthTestNode(t, c).Spec.Taints)
+	require.Contains(t, <-recorder.Events, "GPURevalidationUnmarked")
+}
@@ -612,6 +612,15 @@ func singletonCRPredicate[T interface {
 	}
 }
 
+// indexPodByNodeName indexes pods by the name of the node they are scheduled on
+func indexPodByNodeName(rawObj client.Object) []string {
+	pod := rawObj.(*corev1.Pod)
+	if pod.Spec.NodeName == "" {
+		return nil
+	}
+	return []string{pod.Spec.NodeName}
+}
+
 // SetupWithManager registers the NodeLabelingReconciler with the controller-runtime manager.
 func (r *NodeLabelingReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
 	mapToSingleton := func(_ context.Context, _ client.Object) []reconcile.Request {
@@ -619,13 +628,7 @@ func (r *NodeLabelingReconciler) SetupWithManager(ctx context.Context, mgr ctrl.
 	}
 
 	// Index pods by node name so nodeHasDRAClaimPods lists only the node's pods.
-	if err := mgr.GetFieldIndexer().IndexField(ctx, &corev1.Pod{}, podNodeNameIndexKey, func(rawObj client.Object) []string {
-		pod := rawObj.(*corev1.Pod)
-		if pod.Spec.NodeName == "" {
-			return nil
-		}
-		return []string{pod.Spec.NodeName}
-	}); err != nil {
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &corev1.Pod{}, podNodeNameIndexKey, indexPodByNodeName); err != nil {
 		return fmt.Errorf("failed to add pod node-name index: %w", err)
 	}
 
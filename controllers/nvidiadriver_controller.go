@@ -25,6 +25,7 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -45,6 +46,7 @@ import (
 	"github.com/NVIDIA/gpu-operator/controllers/clusterinfo"
 	"github.com/NVIDIA/gpu-operator/internal/conditions"
 	"github.com/NVIDIA/gpu-operator/internal/consts"
+	"github.com/NVIDIA/gpu-operator/internal/pause"
 	"github.com/NVIDIA/gpu-operator/internal/state"
 	"github.com/NVIDIA/gpu-operator/internal/validator"
 )
@@ -144,6 +146,11 @@ func (r *NVIDIADriverReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	// Add the host root, which is needed to deploy the driver daemonset
 	infoCatalog.Add(state.InfoTypeHostRoot, hostRoot)
 
+	// Add whether the GPU health checks are enabled, the driver daemonset only tolerates the
+	// taint of unhealthy GPU nodes then
+	infoCatalog.Add(state.InfoTypeGPUHealth, clusterPolicy != nil && clusterPolicy.Spec.GPUHealth.IsEnabled() ||
+		gpuCluster != nil && gpuCluster.Spec.GPUHealth.IsEnabled())
+
 	// Verify the nodeSelector configured for this NVIDIADriver instance does
 	// not conflict with any other instances. This ensures only one driver
 	// is deployed per GPU node.
@@ -249,11 +256,12 @@ func (r *NVIDIADriverReconciler) updateCrStatus(
 		return err
 	}
 
-	// Update global State
-	if instance.Status.State == desiredState {
+	// Update global State and the node pool status reported by the driver state
+	if instance.Status.State == desiredState && apiequality.Semantic.DeepEqual(instance.Status.NodePools, cr.Status.NodePools) {
 		return nil
 	}
 	instance.Status.State = desiredState
+	instance.Status.NodePools = cr.Status.NodePools
 
 	// send status update request to k8s API
 	reqLogger.V(consts.LogLevelInfo).Info("Updating CR Status", "Status", instance.Status)
@@ -362,7 +370,10 @@ func (r *NVIDIADriverReconciler) SetupWithManager(ctx context.Context, mgr ctrl.
 		mgr.GetCache(),
 		&nvidiav1alpha1.NVIDIADriver{},
 		handler.TypedEnqueueRequestsFromMapFunc(nvidiaDriverMapFn),
-		predicate.TypedGenerationChangedPredicate[*nvidiav1alpha1.NVIDIADriver]{},
+		predicate.Or[*nvidiav1alpha1.NVIDIADriver](
+			predicate.TypedGenerationChangedPredicate[*nvidiav1alpha1.NVIDIADriver]{},
+			pause.AnnotationChangedPredicate[*nvidiav1alpha1.NVIDIADriver](),
+		),
 	),
 	)
 	if err != nil {
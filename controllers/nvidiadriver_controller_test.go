@@ -68,6 +68,11 @@ func (f *FakeConditionUpdater) SetConditionsReady(ctx context.Context, obj any,
 	return f.CustomError
 }
 
+// SetConditionDrifted always returns CustomError if set
+func (f *FakeConditionUpdater) SetConditionDrifted(ctx context.Context, obj any, drifted bool, condType, msg string) error {
+	return f.CustomError
+}
+
 // FakeNodeSelectorValidator always returns CustomError if set
 type FakeNodeSelectorValidator struct {
 	CustomError error
@@ -455,6 +460,35 @@ func TestUpdateCrStatusPreservesNotReadyStateWhenSettingErrorCondition(t *testin
 	}, "expected an Error=True condition")
 }
 
+func TestUpdateCrStatusNodePools(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, nvidiav1alpha1.AddToScheme(scheme))
+
+	driver := &nvidiav1alpha1.NVIDIADriver{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-driver"},
+		Status:     nvidiav1alpha1.NVIDIADriverStatus{State: nvidiav1alpha1.Ready},
+	}
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(driver).
+		WithStatusSubresource(driver).
+		Build()
+	reconciler := &NVIDIADriverReconciler{Client: k8sClient}
+
+	// The node pools are persisted even when the overall state is unchanged
+	driver.Status.NodePools = []nvidiav1alpha1.NodePoolStatus{
+		{Name: "ubuntu22.04", OSTag: "ubuntu22.04", DaemonSet: "nvidia-gpu-driver-ubuntu22.04-7c6d7bd86b", Image: "nvcr.io/nvidia/driver:580.65.06-ubuntu22.04", DesiredNumberScheduled: 2, NumberReady: 2},
+	}
+	require.NoError(t, reconciler.updateCrStatus(context.Background(), driver, state.Results{
+		Status: state.SyncStateReady,
+	}))
+
+	updated := &nvidiav1alpha1.NVIDIADriver{}
+	require.NoError(t, k8sClient.Get(context.Background(), types.NamespacedName{Name: driver.Name}, updated))
+	require.Equal(t, nvidiav1alpha1.Ready, updated.Status.State)
+	require.Equal(t, driver.Status.NodePools, updated.Status.NodePools)
+}
+
 func TestEnqueueAllNVIDIADrivers(t *testing.T) {
 	scheme := runtime.NewScheme()
 	require.NoError(t, nvidiav1alpha1.AddToScheme(scheme))
@@ -23,13 +23,14 @@ import (
 	"path"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 
+	"github.com/go-logr/logr"
 	apiconfigv1 "github.com/openshift/api/config/v1"
 	apiimagev1 "github.com/openshift/api/image/v1"
-	secv1 "github.com/openshift/api/security/v1"
 	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	"golang.org/x/mod/semver"
 	appsv1 "k8s.io/api/apps/v1"
@@ -108,6 +109,14 @@ const (
 	ValidatorRuntimeClassEnvName = "VALIDATOR_RUNTIME_CLASS"
 	// MigStrategyEnvName indicates env name for passing MIG strategy
 	MigStrategyEnvName = "MIG_STRATEGY"
+	// P2PMinBandwidthEnvName indicates env name for the minimum peer-to-peer bandwidth between GPUs in GB/s
+	P2PMinBandwidthEnvName = "P2P_MIN_BANDWIDTH_GBPS"
+	// P2PMaxLatencyEnvName indicates env name for the maximum peer-to-peer latency between GPUs in microseconds
+	P2PMaxLatencyEnvName = "P2P_MAX_LATENCY_US"
+	// RevalidationIntervalEnvName indicates env name for the interval between two revalidations in seconds
+	RevalidationIntervalEnvName = "REVALIDATION_INTERVAL_SECONDS"
+	// RevalidationComponentsEnvName indicates env name for the comma separated list of components to revalidate
+	RevalidationComponentsEnvName = "REVALIDATION_COMPONENTS"
 	// MigPartedDefaultConfigMapName indicates name of ConfigMap containing default mig-parted config
 	MigPartedDefaultConfigMapName = "default-mig-parted-config"
 	// MigDefaultGPUClientsConfigMapName indicates name of ConfigMap containing default gpu-clients
@@ -188,7 +197,7 @@ const (
 	// DefaultDriverInstallDir represents the default path of a driver container installation
 	DefaultDriverInstallDir = "/run/nvidia/driver"
 	// DefaultKubeletRootDir represents the default path of a kubelet root directory
-	DefaultKubeletRootDir = "/var/lib/kubelet"
+	DefaultKubeletRootDir = gpuv1.DefaultKubeletRootDir
 	// DriverInstallDirEnvName is the name of the envvar used by the driver-validator to represent the driver install dir
 	DriverInstallDirEnvName = "DRIVER_INSTALL_DIR"
 	// DriverInstallDirCtrPathEnvName is the name of the envvar used by the driver-validator to represent the path
@@ -351,13 +360,8 @@ func ServiceAccount(n ClusterPolicyController) (gpuv1.State, error) {
 		return gpuv1.NotReady, err
 	}
 
-	if err := n.client.Create(ctx, obj); err != nil {
-		if apierrors.IsAlreadyExists(err) {
-			logger.Info("Found Resource, skipping update")
-			return gpuv1.Ready, nil
-		}
-
-		logger.Info("Couldn't create", "Error", err)
+	if err := utils.ApplyObject(ctx, n.client, obj); err != nil {
+		logger.Info("Couldn't apply", "Error", err)
 		return gpuv1.NotReady, err
 	}
 	return gpuv1.Ready, nil
@@ -386,21 +390,10 @@ func Role(n ClusterPolicyController) (gpuv1.State, error) {
 		return gpuv1.NotReady, err
 	}
 
-	if err := n.client.Create(ctx, obj); err != nil {
-		if apierrors.IsAlreadyExists(err) {
-			logger.Info("Found Resource, updating...")
-			err = n.client.Update(ctx, obj)
-			if err != nil {
-				logger.Info("Couldn't update", "Error", err)
-				return gpuv1.NotReady, err
-			}
-			return gpuv1.Ready, nil
-		}
-
-		logger.Info("Couldn't create", "Error", err)
+	if err := utils.ApplyObject(ctx, n.client, obj); err != nil {
+		logger.Info("Couldn't apply", "Error", err)
 		return gpuv1.NotReady, err
 	}
-
 	return gpuv1.Ready, nil
 }
 
@@ -437,21 +430,10 @@ func RoleBinding(n ClusterPolicyController) (gpuv1.State, error) {
 		return gpuv1.NotReady, err
 	}
 
-	if err := n.client.Create(ctx, obj); err != nil {
-		if apierrors.IsAlreadyExists(err) {
-			logger.Info("Found Resource, updating...")
-			err = n.client.Update(ctx, obj)
-			if err != nil {
-				logger.Info("Couldn't update", "Error", err)
-				return gpuv1.NotReady, err
-			}
-			return gpuv1.Ready, nil
-		}
-
-		logger.Info("Couldn't create", "Error", err)
+	if err := utils.ApplyObject(ctx, n.client, obj); err != nil {
+		logger.Info("Couldn't apply", "Error", err)
 		return gpuv1.NotReady, err
 	}
-
 	return gpuv1.Ready, nil
 }
 
@@ -502,21 +484,10 @@ func ClusterRole(n ClusterPolicyController) (gpuv1.State, error) {
 		return gpuv1.NotReady, err
 	}
 
-	if err := n.client.Create(ctx, obj); err != nil {
-		if apierrors.IsAlreadyExists(err) {
-			logger.Info("Found Resource, updating...")
-			err = n.client.Update(ctx, obj)
-			if err != nil {
-				logger.Info("Couldn't update", "Error", err)
-				return gpuv1.NotReady, err
-			}
-			return gpuv1.Ready, nil
-		}
-
-		logger.Info("Couldn't create", "Error", err)
+	if err := utils.ApplyObject(ctx, n.client, obj); err != nil {
+		logger.Info("Couldn't apply", "Error", err)
 		return gpuv1.NotReady, err
 	}
-
 	return gpuv1.Ready, nil
 }
 
@@ -556,21 +527,10 @@ func ClusterRoleBinding(n ClusterPolicyController) (gpuv1.State, error) {
 		return gpuv1.NotReady, err
 	}
 
-	if err := n.client.Create(ctx, obj); err != nil {
-		if apierrors.IsAlreadyExists(err) {
-			logger.Info("Found Resource, updating...")
-			err = n.client.Update(ctx, obj)
-			if err != nil {
-				logger.Info("Couldn't update", "Error", err)
-				return gpuv1.NotReady, err
-			}
-			return gpuv1.Ready, nil
-		}
-
-		logger.Info("Couldn't create", "Error", err)
+	if err := utils.ApplyObject(ctx, n.client, obj); err != nil {
+		logger.Info("Couldn't apply", "Error", err)
 		return gpuv1.NotReady, err
 	}
-
 	return gpuv1.Ready, nil
 }
 
@@ -622,20 +582,10 @@ func createConfigMap(n ClusterPolicyController, configMapIdx int) (gpuv1.State,
 		return gpuv1.NotReady, err
 	}
 
-	if err := n.client.Create(ctx, obj); err != nil {
-		if !apierrors.IsAlreadyExists(err) {
-			logger.Info("Couldn't create", "Error", err)
-			return gpuv1.NotReady, err
-		}
-
-		logger.Info("Found Resource, updating...")
-		err = n.client.Update(ctx, obj)
-		if err != nil {
-			logger.Info("Couldn't update", "Error", err)
-			return gpuv1.NotReady, err
-		}
+	if err := utils.ApplyObject(ctx, n.client, obj); err != nil {
+		logger.Info("Couldn't apply", "Error", err)
+		return gpuv1.NotReady, err
 	}
-
 	return gpuv1.Ready, nil
 }
 
@@ -827,6 +777,11 @@ func applyCommonDaemonsetConfig(obj *appsv1.DaemonSet, config *gpuv1.ClusterPoli
 		obj.Spec.Template.Spec.Tolerations = config.Daemonsets.Tolerations
 	}
 
+	// tolerate the taint of unhealthy GPU nodes, the remediation of the nodes restarts the operands
+	if config.GPUHealth.IsEnabled() {
+		addNoScheduleToleration(&obj.Spec.Template.Spec, consts.GPUUnhealthyTaintKey)
+	}
+
 	// set pod-level security context if specified (applies as defaults to all containers in the pod)
 	if config.Daemonsets.PodSecurityContext != nil {
 		obj.Spec.Template.Spec.SecurityContext = config.Daemonsets.PodSecurityContext
@@ -834,6 +789,22 @@ func applyCommonDaemonsetConfig(obj *appsv1.DaemonSet, config *gpuv1.ClusterPoli
 	return nil
 }
 
+// addNoScheduleToleration adds a toleration of the NoSchedule taint key to podSpec, unless
+// it already tolerates it
+func addNoScheduleToleration(podSpec *corev1.PodSpec, key string) {
+	for _, toleration := range podSpec.Tolerations {
+		if toleration.Key == key {
+			return
+		}
+	}
+	// the tolerations may be shared with the ClusterPolicy spec
+	podSpec.Tolerations = append(slices.Clone(podSpec.Tolerations), corev1.Toleration{
+		Key:      key,
+		Operator: corev1.TolerationOpExists,
+		Effect:   corev1.TaintEffectNoSchedule,
+	})
+}
+
 // apply necessary transforms if a custom host root path is configured
 func transformForHostRoot(obj *appsv1.DaemonSet, hostRoot string) {
 	if hostRoot == "" || hostRoot == "/" {
@@ -1057,7 +1028,7 @@ func TransformDriver(obj *appsv1.DaemonSet, config *gpuv1.ClusterPolicySpec, n C
 	// hasn't changed, avoiding unnecessary driver reinstalls and pod evictions.
 	// Used by k8s-driver-manager to decide if driver cleanup is needed and by
 	// nvidia-driver container to skip full reinstall for matching configurations.
-	driverConfig := extractDriverInstallConfig(&obj.Spec.Template.Spec)
+	driverConfig := driverconfig.DriverInstallStateFromPodSpec(&obj.Spec.Template.Spec)
 	configDigest := utils.GetObjectHashIgnoreEmptyKeys(driverConfig)
 
 	// Set the computed digest in driver-manager initContainer
@@ -1113,7 +1084,11 @@ func TransformVGPUManager(obj *appsv1.DaemonSet, config *gpuv1.ClusterPolicySpec
 // applyOCPProxySpec applies proxy settings to podSpec
 func applyOCPProxySpec(n ClusterPolicyController, podSpec *corev1.PodSpec) error {
 	// Pass HTTPS_PROXY, HTTP_PROXY and NO_PROXY env if set in clusterwide proxy for OCP
-	proxy, err := GetClusterWideProxy(n.ctx)
+	getClusterWideProxy := n.getClusterWideProxy
+	if getClusterWideProxy == nil {
+		getClusterWideProxy = GetClusterWideProxy
+	}
+	proxy, err := getClusterWideProxy(n.ctx)
 	if err != nil {
 		return fmt.Errorf("ERROR: failed to get clusterwide proxy object: %s", err)
 	}
@@ -2333,6 +2308,7 @@ func TransformValidator(obj *appsv1.DaemonSet, config *gpuv1.ClusterPolicySpec,
 		"gdrcopy",
 		"toolkit",
 		"cuda",
+		"p2p",
 		"plugin",
 	}
 
@@ -2346,6 +2322,10 @@ func TransformValidator(obj *appsv1.DaemonSet, config *gpuv1.ClusterPolicySpec,
 		n.logger.Info("WARN: errors transforming the validator containers: %v", validatorErr)
 	}
 
+	if err := transformValidatorRevalidation(obj, config); err != nil {
+		return err
+	}
+
 	// set hostNetwork for validator if specified
 	applyHostNetworkConfig(&obj.Spec.Template.Spec, config.Validator.HostNetwork)
 
@@ -2421,6 +2401,45 @@ func TransformValidatorShared(obj *appsv1.DaemonSet, config *gpuv1.ClusterPolicy
 	return nil
 }
 
+// transformValidatorRevalidation transforms the revalidation sidecar of the validator daemonset,
+// and removes it when the revalidation is not enabled
+func transformValidatorRevalidation(obj *appsv1.DaemonSet, config *gpuv1.ClusterPolicySpec) error {
+	for i, container := range obj.Spec.Template.Spec.Containers {
+		if container.Name != "revalidation" {
+			continue
+		}
+		revalidation := &config.Validator.Revalidation
+		if !revalidation.IsEnabled() {
+			// remove revalidation sidecar container from validator Daemonset if it is not enabled
+			obj.Spec.Template.Spec.Containers = append(obj.Spec.Template.Spec.Containers[:i], obj.Spec.Template.Spec.Containers[i+1:]...)
+			return nil
+		}
+		image, err := gpuv1.ImagePath(&config.Validator)
+		if err != nil {
+			return err
+		}
+		obj.Spec.Template.Spec.Containers[i].Image = image
+		obj.Spec.Template.Spec.Containers[i].ImagePullPolicy = gpuv1.ImagePullPolicy(config.Validator.ImagePullPolicy)
+		transformValidatorSecurityContext(&obj.Spec.Template.Spec.Containers[i])
+		// the validator keeps revalidating the nodes tainted for failing a revalidation, the
+		// operator clears the taint once the node passes its revalidations again
+		addNoScheduleToleration(&obj.Spec.Template.Spec, revalidationFailedKey)
+
+		var components []string
+		for _, component := range revalidation.GetComponents() {
+			// the device plugin is not revalidated when it is not deployed
+			if component == "plugin" && !config.DevicePlugin.IsEnabled() {
+				continue
+			}
+			components = append(components, component)
+		}
+		setContainerEnv(&obj.Spec.Template.Spec.Containers[i], RevalidationIntervalEnvName, strconv.Itoa(int(revalidation.GetIntervalSeconds())))
+		setContainerEnv(&obj.Spec.Template.Spec.Containers[i], RevalidationComponentsEnvName, strings.Join(components, ","))
+		return nil
+	}
+	return nil
+}
+
 // TransformValidatorComponent applies changes to given validator component
 func TransformValidatorComponent(config *gpuv1.ClusterPolicySpec, podSpec *corev1.PodSpec, component string) error {
 	for i, initContainer := range podSpec.InitContainers {
@@ -2461,6 +2480,34 @@ func TransformValidatorComponent(config *gpuv1.ClusterPolicySpec, podSpec *corev
 					setContainerEnv(&(podSpec.InitContainers[i]), env.Name, env.Value)
 				}
 			}
+		case "p2p":
+			// remove p2p init container from validator Daemonset if it is not enabled
+			if !config.Validator.P2P.IsEnabled() {
+				podSpec.InitContainers = append(podSpec.InitContainers[:i], podSpec.InitContainers[i+1:]...)
+				return nil
+			}
+			// set additional env to indicate image, pullSecrets to spin-off p2p validation workload pod.
+			setContainerEnv(&(podSpec.InitContainers[i]), ValidatorImageEnvName, image)
+			setContainerEnv(&(podSpec.InitContainers[i]), ValidatorImagePullPolicyEnvName, config.Validator.ImagePullPolicy)
+			if len(config.Validator.ImagePullSecrets) > 0 {
+				setContainerEnv(&(podSpec.InitContainers[i]), ValidatorImagePullSecretsEnvName, strings.Join(config.Validator.ImagePullSecrets, ","))
+			}
+			if podSpec.RuntimeClassName != nil {
+				setContainerEnv(&(podSpec.InitContainers[i]), ValidatorRuntimeClassEnvName, *podSpec.RuntimeClassName)
+			}
+			// apply thresholds the p2p validation workload pod checks the GPUs against
+			if config.Validator.P2P.MinBandwidthGBps > 0 {
+				setContainerEnv(&(podSpec.InitContainers[i]), P2PMinBandwidthEnvName, strconv.Itoa(int(config.Validator.P2P.MinBandwidthGBps)))
+			}
+			if config.Validator.P2P.MaxLatencyMicroseconds > 0 {
+				setContainerEnv(&(podSpec.InitContainers[i]), P2PMaxLatencyEnvName, strconv.Itoa(int(config.Validator.P2P.MaxLatencyMicroseconds)))
+			}
+			// set/append environment variables for p2p-validation container
+			if len(config.Validator.P2P.Env) > 0 {
+				for _, env := range config.Validator.P2P.Env {
+					setContainerEnv(&(podSpec.InitContainers[i]), env.Name, env.Value)
+				}
+			}
 		case "plugin":
 			// remove plugin init container from validator Daemonset if it is not enabled
 			if !config.DevicePlugin.IsEnabled() {
@@ -2681,62 +2728,6 @@ func findContainerByName(containers []corev1.Container, name string) *corev1.Con
 	return nil
 }
 
-// extractDriverInstallConfig extracts driver-relevant fields from a
-// post-transformation PodSpec (ClusterPolicy path). Fields like
-// KernelModuleType and proxy settings are captured implicitly via the
-// per-container env var maps rather than as top-level struct fields.
-func extractDriverInstallConfig(podSpec *corev1.PodSpec) *driverconfig.DriverInstallState {
-	config := &driverconfig.DriverInstallState{}
-
-	for i := range podSpec.InitContainers {
-		c := &podSpec.InitContainers[i]
-		if c.Name == "k8s-driver-manager" {
-			config.DriverManagerImage = c.Image
-			config.ManagerEnv = driverconfig.ExtractEnvVars(c.Env)
-		}
-	}
-
-	for i := range podSpec.Containers {
-		c := &podSpec.Containers[i]
-		switch c.Name {
-		case "nvidia-driver-ctr":
-			config.DriverImage = c.Image
-			config.DriverCommand = c.Command
-			config.DriverArgs = c.Args
-			config.DriverEnv = driverconfig.ExtractEnvVars(c.Env)
-			for _, ef := range c.EnvFrom {
-				if ef.SecretRef != nil {
-					config.SecretEnvSource = ef.SecretRef.Name
-				}
-			}
-			config.AdditionalVolumeMounts = driverconfig.ExtractVolumeMounts(c.VolumeMounts)
-		case "nvidia-peermem-ctr":
-			config.PeermemImage = c.Image
-			config.GPUDirectRDMAEnabled = true
-		case "nvidia-fs-ctr":
-			config.GDSImage = c.Image
-			config.GDSEnabled = true
-			config.GDSEnv = driverconfig.ExtractEnvVars(c.Env)
-		case "nvidia-gdrcopy-ctr":
-			config.GDRCopyImage = c.Image
-			config.GDRCopyEnabled = true
-			config.GDRCopyEnv = driverconfig.ExtractEnvVars(c.Env)
-		case "openshift-driver-toolkit-ctr":
-			config.DTKImage = c.Image
-			config.DTKEnabled = true
-		}
-	}
-
-	config.AdditionalVolumes = driverconfig.ExtractVolumes(podSpec.Volumes)
-	for _, v := range podSpec.Volumes {
-		if v.Name == "host-root" && v.HostPath != nil {
-			config.HostRoot = v.HostPath.Path
-		}
-	}
-
-	return config
-}
-
 func getRuntimeClassName(config *gpuv1.ClusterPolicySpec) string {
 	if config.Operator.RuntimeClass != "" {
 		return config.Operator.RuntimeClass
@@ -3874,6 +3865,11 @@ func transformVGPUManagerContainer(obj *appsv1.DaemonSet, config *gpuv1.ClusterP
 }
 
 func applyUpdateStrategyConfig(obj *appsv1.DaemonSet, config *gpuv1.ClusterPolicySpec) error {
+	if config.Daemonsets.IsManagedRolloutEnabled() && managedRolloutDaemonSets[obj.Name] {
+		// pods of managed rollouts are replaced by the operator, see rolloutDaemonSet
+		obj.Spec.UpdateStrategy = appsv1.DaemonSetUpdateStrategy{Type: appsv1.OnDeleteDaemonSetStrategyType}
+		return nil
+	}
 	switch config.Daemonsets.UpdateStrategy {
 	case "OnDelete":
 		obj.Spec.UpdateStrategy = appsv1.DaemonSetUpdateStrategy{Type: appsv1.OnDeleteDaemonSetStrategyType}
@@ -4037,7 +4033,7 @@ func isDaemonSetReady(name string, n ClusterPolicyController) gpuv1.State {
 	}
 
 	dsPods := getPodsOwnedbyDaemonset(ds, list.Items, n)
-	daemonsetRevisionHash, err := getDaemonsetControllerRevisionHash(ctx, ds, n)
+	daemonsetRevisionHash, err := getDaemonsetControllerRevisionHash(ctx, n.client, n.logger, n.operatorNamespace, ds)
 	if err != nil {
 		n.logger.Error(
 			err, "Failed to get daemonset template revision hash", "daemonset", ds)
@@ -4110,20 +4106,20 @@ func getPodControllerRevisionHash(ctx context.Context, pod *corev1.Pod) (string,
 	return "", fmt.Errorf("controller-revision-hash label not present for pod %s", pod.Name)
 }
 
-func getDaemonsetControllerRevisionHash(ctx context.Context, daemonset *appsv1.DaemonSet, n ClusterPolicyController) (string, error) {
+func getDaemonsetControllerRevisionHash(ctx context.Context, c client.Client, logger logr.Logger, namespace string, daemonset *appsv1.DaemonSet) (string, error) {
 
 	// get all revisions for the daemonset
 	opts := []client.ListOption{
 		client.MatchingLabels(daemonset.Spec.Selector.MatchLabels),
-		client.InNamespace(n.operatorNamespace),
+		client.InNamespace(namespace),
 	}
 	list := &appsv1.ControllerRevisionList{}
-	err := n.client.List(ctx, list, opts...)
+	err := c.List(ctx, list, opts...)
 	if err != nil {
 		return "", fmt.Errorf("error getting controller revision list for daemonset %s: %v", daemonset.Name, err)
 	}
 
-	n.logger.V(2).Info("obtained controller revisions", "Daemonset", daemonset.Name, "len", len(list.Items))
+	logger.V(2).Info("obtained controller revisions", "Daemonset", daemonset.Name, "len", len(list.Items))
 
 	var revisions []appsv1.ControllerRevision
 	for _, controllerRevision := range list.Items {
@@ -4168,18 +4164,8 @@ func Deployment(n ClusterPolicyController) (gpuv1.State, error) {
 		return gpuv1.NotReady, err
 	}
 
-	if err := n.client.Create(ctx, obj); err != nil {
-		if apierrors.IsAlreadyExists(err) {
-			logger.Info("Found Resource, updating...")
-			err = n.client.Update(ctx, obj)
-			if err != nil {
-				logger.Info("Couldn't update", "Error", err)
-				return gpuv1.NotReady, err
-			}
-			return isDeploymentReady(obj.Name, n), nil
-		}
-
-		logger.Info("Couldn't create", "Error", err)
+	if err := utils.ApplyObject(ctx, n.client, obj); err != nil {
+		logger.Info("Couldn't apply", "Error", err)
 		return gpuv1.NotReady, err
 	}
 
@@ -4746,57 +4732,65 @@ func DaemonSet(n ClusterPolicyController) (gpuv1.State, error) {
 		obj.Annotations[annoKey] = annoValue
 	}
 
+	// The hash of the desired DaemonSet tells whether applying it rolls out a change
 	found := &appsv1.DaemonSet{}
 	err = n.client.Get(ctx, types.NamespacedName{Namespace: obj.Namespace, Name: obj.Name}, found)
-	if err != nil && apierrors.IsNotFound(err) {
-		logger.Info("DaemonSet not found, creating",
-			"Name", obj.Name,
-		)
-		// generate hash for the spec to create
-		hashStr := utils.GetObjectHash(obj)
-		// add annotation to the Daemonset with hash value during creation
-		obj.Annotations[NvidiaAnnotationHashKey] = hashStr
-		err = n.client.Create(ctx, obj)
-		if err != nil {
-			logger.Info("Couldn't create DaemonSet",
-				"Name", obj.Name,
-				"Error", err,
-			)
-			return gpuv1.NotReady, err
-		}
-		return isDaemonSetReady(obj.Name, n), nil
-	} else if err != nil {
+	if err != nil && !apierrors.IsNotFound(err) {
 		logger.Info("Failed to get DaemonSet from client",
 			"Name", obj.Name,
 			"Error", err.Error())
 		return gpuv1.NotReady, err
 	}
+	created := apierrors.IsNotFound(err)
+	if created {
+		found = nil
+	}
 
-	changed := isDaemonsetSpecChanged(found, obj)
-	if changed {
-		logger.Info("DaemonSet is different, updating", "name", obj.Name)
-		err = n.client.Update(ctx, obj)
-		if err != nil {
-			return gpuv1.NotReady, err
+	if !isDaemonsetSpecChanged(found, obj) {
+		logger.Info("DaemonSet identical, skipping apply", "name", obj.Name)
+		// A changed DaemonSet is applied and reported NotReady, its managed rollout starts
+		// with the next reconciliation, once the DaemonSet is identical. The rollout moves on
+		// with each reconciliation, requeued while it is reported NotReady.
+		if n.singleton.Spec.Daemonsets.IsManagedRolloutEnabled() && managedRolloutDaemonSets[obj.Name] {
+			rolling, err := n.rolloutDaemonSet(ctx, found)
+			if err != nil {
+				logger.Info("Managed rollout failed", "name", obj.Name, "Error", err)
+				return gpuv1.NotReady, err
+			}
+			if rolling {
+				logger.Info("Managed rollout in progress", "name", obj.Name)
+				return gpuv1.NotReady, nil
+			}
 		}
-		return gpuv1.NotReady, nil
-	} else {
-		logger.Info("DaemonSet identical, skipping update", "name", obj.Name)
+		return isDaemonSetReady(obj.Name, n), nil
+	}
+
+	logger.Info("DaemonSet is different, applying", "name", obj.Name)
+	if err := utils.ApplyObject(ctx, n.client, obj); err != nil {
+		logger.Info("Couldn't apply DaemonSet",
+			"Name", obj.Name,
+			"Error", err,
+		)
+		return gpuv1.NotReady, err
+	}
+	if created {
+		return isDaemonSetReady(obj.Name, n), nil
 	}
-	return isDaemonSetReady(obj.Name, n), nil
+	return gpuv1.NotReady, nil
 }
 
 // isDaemonsetSpecChanged returns true if the spec has changed between existing one
 // and new Daemonset spec compared by hash.
 func isDaemonsetSpecChanged(current *appsv1.DaemonSet, new *appsv1.DaemonSet) bool {
-	if current == nil && new != nil {
-		return true
-	}
-	if current.Annotations == nil || new.Annotations == nil {
+	if new.Annotations == nil {
 		panic("appsv1.DaemonSet.Annotations must be allocated prior to calling isDaemonsetSpecChanged()")
 	}
 
 	hashStr := utils.GetObjectHash(new)
+	if current == nil {
+		new.Annotations[NvidiaAnnotationHashKey] = hashStr
+		return true
+	}
 	foundHashAnnotation := false
 
 	for annotation, value := range current.Annotations {
@@ -4878,26 +4872,8 @@ func SecurityContextConstraints(n ClusterPolicyController) (gpuv1.State, error)
 		return gpuv1.NotReady, err
 	}
 
-	found := &secv1.SecurityContextConstraints{}
-	err := n.client.Get(ctx, types.NamespacedName{Namespace: "", Name: obj.Name}, found)
-	if err != nil && apierrors.IsNotFound(err) {
-		logger.Info("Not found, creating...")
-		err = n.client.Create(ctx, obj)
-		if err != nil {
-			logger.Info("Couldn't create", "Error", err)
-			return gpuv1.NotReady, err
-		}
-		return gpuv1.Ready, nil
-	} else if err != nil {
-		return gpuv1.NotReady, err
-	}
-
-	logger.Info("Found Resource, updating...")
-	obj.ResourceVersion = found.ResourceVersion
-
-	err = n.client.Update(ctx, obj)
-	if err != nil {
-		logger.Info("Couldn't update", "Error", err)
+	if err := utils.ApplyObject(ctx, n.client, obj); err != nil {
+		logger.Info("Couldn't apply", "Error", err)
 		return gpuv1.NotReady, err
 	}
 	return gpuv1.Ready, nil
@@ -4933,27 +4909,8 @@ func Service(n ClusterPolicyController) (gpuv1.State, error) {
 		return gpuv1.NotReady, err
 	}
 
-	found := &corev1.Service{}
-	err = n.client.Get(ctx, types.NamespacedName{Namespace: obj.Namespace, Name: obj.Name}, found)
-	if err != nil && apierrors.IsNotFound(err) {
-		logger.Info("Not found, creating...")
-		err = n.client.Create(ctx, obj)
-		if err != nil {
-			logger.Info("Couldn't create", "Error", err)
-			return gpuv1.NotReady, err
-		}
-		return gpuv1.Ready, nil
-	} else if err != nil {
-		return gpuv1.NotReady, err
-	}
-
-	logger.Info("Found Resource, updating...")
-	obj.ResourceVersion = found.ResourceVersion
-	obj.Spec.ClusterIP = found.Spec.ClusterIP
-
-	err = n.client.Update(ctx, obj)
-	if err != nil {
-		logger.Info("Couldn't update", "Error", err)
+	if err := utils.ApplyObject(ctx, n.client, obj); err != nil {
+		logger.Info("Couldn't apply", "Error", err)
 		return gpuv1.NotReady, err
 	}
 	return gpuv1.Ready, nil
@@ -5103,26 +5060,8 @@ func ServiceMonitor(n ClusterPolicyController) (gpuv1.State, error) {
 		return gpuv1.NotReady, err
 	}
 
-	found := &promv1.ServiceMonitor{}
-	err = n.client.Get(ctx, types.NamespacedName{Namespace: obj.Namespace, Name: obj.Name}, found)
-	if err != nil && apierrors.IsNotFound(err) {
-		logger.Info("Not found, creating...")
-		err = n.client.Create(ctx, obj)
-		if err != nil {
-			logger.Info("Couldn't create", "Error", err)
-			return gpuv1.NotReady, err
-		}
-		return gpuv1.Ready, nil
-	} else if err != nil {
-		return gpuv1.NotReady, err
-	}
-
-	logger.Info("Found Resource, updating...")
-	obj.ResourceVersion = found.ResourceVersion
-
-	err = n.client.Update(ctx, obj)
-	if err != nil {
-		logger.Info("Couldn't update", "Error", err)
+	if err := utils.ApplyObject(ctx, n.client, obj); err != nil {
+		logger.Info("Couldn't apply", "Error", err)
 		return gpuv1.NotReady, err
 	}
 	return gpuv1.Ready, nil
@@ -5150,26 +5089,8 @@ func transformRuntimeClassLegacy(n ClusterPolicyController, spec nodev1.RuntimeC
 		return gpuv1.NotReady, err
 	}
 
-	found := &nodev1beta1.RuntimeClass{}
-	err := n.client.Get(ctx, types.NamespacedName{Namespace: "", Name: obj.Name}, found)
-	if err != nil && apierrors.IsNotFound(err) {
-		logger.Info("Not found, creating...")
-		err = n.client.Create(ctx, obj)
-		if err != nil {
-			logger.Info("Couldn't create", "Error", err)
-			return gpuv1.NotReady, err
-		}
-		return gpuv1.Ready, nil
-	} else if err != nil {
-		return gpuv1.NotReady, err
-	}
-
-	logger.Info("Found Resource, updating...")
-	obj.ResourceVersion = found.ResourceVersion
-
-	err = n.client.Update(ctx, obj)
-	if err != nil {
-		logger.Info("Couldn't update", "Error", err)
+	if err := utils.ApplyObject(ctx, n.client, obj); err != nil {
+		logger.Info("Couldn't apply", "Error", err)
 		return gpuv1.NotReady, err
 	}
 	return gpuv1.Ready, nil
@@ -5197,26 +5118,8 @@ func transformRuntimeClass(n ClusterPolicyController, spec nodev1.RuntimeClass)
 		return gpuv1.NotReady, err
 	}
 
-	found := &nodev1.RuntimeClass{}
-	err := n.client.Get(ctx, types.NamespacedName{Namespace: "", Name: obj.Name}, found)
-	if err != nil && apierrors.IsNotFound(err) {
-		logger.Info("Not found, creating...")
-		err = n.client.Create(ctx, obj)
-		if err != nil {
-			logger.Info("Couldn't create", "Error", err)
-			return gpuv1.NotReady, err
-		}
-		return gpuv1.Ready, nil
-	} else if err != nil {
-		return gpuv1.NotReady, err
-	}
-
-	logger.Info("Found Resource, updating...")
-	obj.ResourceVersion = found.ResourceVersion
-
-	err = n.client.Update(ctx, obj)
-	if err != nil {
-		logger.Info("Couldn't update", "Error", err)
+	if err := utils.ApplyObject(ctx, n.client, obj); err != nil {
+		logger.Info("Couldn't apply", "Error", err)
 		return gpuv1.NotReady, err
 	}
 	return gpuv1.Ready, nil
@@ -5309,26 +5212,8 @@ func PrometheusRule(n ClusterPolicyController) (gpuv1.State, error) {
 		return gpuv1.NotReady, err
 	}
 
-	found := &promv1.PrometheusRule{}
-	err := n.client.Get(ctx, types.NamespacedName{Namespace: obj.Namespace, Name: obj.Name}, found)
-	if err != nil && apierrors.IsNotFound(err) {
-		logger.Info("Not found, creating...")
-		err = n.client.Create(ctx, obj)
-		if err != nil {
-			logger.Info("Couldn't create", "Error", err)
-			return gpuv1.NotReady, err
-		}
-		return gpuv1.Ready, nil
-	} else if err != nil {
-		return gpuv1.NotReady, err
-	}
-
-	logger.Info("Found Resource, updating...")
-	obj.ResourceVersion = found.ResourceVersion
-
-	err = n.client.Update(ctx, obj)
-	if err != nil {
-		logger.Info("Couldn't update", "Error", err)
+	if err := utils.ApplyObject(ctx, n.client, obj); err != nil {
+		logger.Info("Couldn't apply", "Error", err)
 		return gpuv1.NotReady, err
 	}
 	return gpuv1.Ready, nil
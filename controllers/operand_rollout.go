@@ -0,0 +1,251 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// managedRolloutStartedAnnotationKeyFmt is the format of the node annotation key recording
+// when the managed rollout of a DaemonSet started on the node
+const managedRolloutStartedAnnotationKeyFmt = "nvidia.com/%s.rollout-started-at"
+
+// managedRolloutDaemonSets are the DaemonSets rolled out node by node when managed
+// rollouts are enabled
+var managedRolloutDaemonSets = map[string]bool{
+	"nvidia-container-toolkit-daemonset": true,
+	"nvidia-device-plugin-daemonset":     true,
+	"nvidia-mig-manager":                 true,
+	"nvidia-dcgm":                        true,
+	"nvidia-dcgm-exporter":               true,
+}
+
+func managedRolloutStartedAnnotationKey(dsName string) string {
+	return fmt.Sprintf(managedRolloutStartedAnnotationKeyFmt, dsName)
+}
+
+// rolloutDaemonSet replaces the outdated pods of the managed DaemonSet ds node by node. The
+// new pod of a node is rolled out once the operator validator, restarted after the pod got
+// ready, passed on the node. At most maxUnavailable nodes are rolled out at a time, and the
+// rollout is halted when the validation of a node times out. Pods whose node is gone are
+// skipped.
+//
+// Each call moves the rollout one step forward. It returns true until the rollout completes
+// for the DaemonSet state to be reported NotReady, which requeues the ClusterPolicy
+// reconciliation; a halted rollout stays NotReady until the node passes its validation.
+func (n ClusterPolicyController) rolloutDaemonSet(ctx context.Context, ds *appsv1.DaemonSet) (bool, error) {
+	spec := n.singleton.Spec.Daemonsets.ManagedRollout
+	key := managedRolloutStartedAnnotationKey(ds.Name)
+	logger := n.logger.WithValues("DaemonSet", ds.Name)
+
+	list := &corev1.PodList{}
+	if err := n.client.List(ctx, list, client.InNamespace(n.operatorNamespace), client.MatchingLabels(ds.Spec.Selector.MatchLabels)); err != nil {
+		return false, fmt.Errorf("failed to list the pods of DaemonSet %s: %w", ds.Name, err)
+	}
+	pods := getPodsOwnedbyDaemonset(ds, list.Items, n)
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].Spec.NodeName < pods[j].Spec.NodeName
+	})
+	revisionHash, err := getDaemonsetControllerRevisionHash(ctx, n.client, n.logger, n.operatorNamespace, ds)
+	if err != nil {
+		return false, err
+	}
+	validators, err := n.getValidatorPods(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	maxUnavailable, err := intstr.GetScaledValueFromIntOrPercent(spec.GetMaxUnavailable(), int(ds.Status.DesiredNumberScheduled), true)
+	if err != nil {
+		return false, fmt.Errorf("invalid managed rollout maxUnavailable: %w", err)
+	}
+	maxUnavailable = max(maxUnavailable, 1)
+
+	now := time.Now()
+	var outdated []*corev1.Pod
+	nodes := map[string]*corev1.Node{}
+	inProgress := 0
+	halted := false
+	for i := range pods {
+		pod := &pods[i]
+		node := &corev1.Node{}
+		if err := n.client.Get(ctx, types.NamespacedName{Name: pod.Spec.NodeName}, node); err != nil {
+			if apierrors.IsNotFound(err) {
+				// the pod of a deleted node is garbage collected
+				logger.Info("Skipping the managed rollout of a pod whose node is gone", "pod", pod.Name, "node", pod.Spec.NodeName)
+				continue
+			}
+			return false, err
+		}
+		nodes[node.Name] = node
+		startedAt, rolling := managedRolloutStartedAt(node, key)
+
+		podRevisionHash, err := getPodControllerRevisionHash(ctx, pod)
+		if err != nil {
+			return false, err
+		}
+		if podRevisionHash != revisionHash {
+			if !rolling {
+				outdated = append(outdated, pod)
+				continue
+			}
+			inProgress++
+			// A new revision was applied while the node was rolled out
+			if pod.DeletionTimestamp == nil {
+				if err := n.replaceRolloutPod(ctx, node, key, pod, now); err != nil {
+					return false, err
+				}
+			}
+			continue
+		}
+		if !rolling {
+			continue
+		}
+
+		validated, err := n.isRolloutValidated(ctx, pod, validators[node.Name])
+		if err != nil {
+			return false, err
+		}
+		switch {
+		case validated:
+			logger.Info("Managed rollout validated on node", "node", node.Name)
+			if err := n.setManagedRolloutStartedAnnotation(ctx, node, key, nil); err != nil {
+				return false, err
+			}
+		case now.Sub(startedAt) > spec.GetValidationTimeout():
+			logger.Error(fmt.Errorf("operator validator did not pass within %s", spec.GetValidationTimeout()),
+				"Managed rollout failed on node, halting the rollout", "node", node.Name)
+			halted = true
+		default:
+			inProgress++
+		}
+	}
+	if halted || len(outdated) == 0 {
+		return halted || inProgress > 0, nil
+	}
+
+	available := max(maxUnavailable-inProgress, 0)
+	for _, pod := range outdated[:min(available, len(outdated))] {
+		node := nodes[pod.Spec.NodeName]
+		logger.Info("Rolling out DaemonSet on node", "node", node.Name)
+		if err := n.replaceRolloutPod(ctx, node, key, pod, now); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// replaceRolloutPod starts the rollout of the node of pod at now, and deletes pod for the
+// DaemonSet to replace it
+func (n ClusterPolicyController) replaceRolloutPod(ctx context.Context, node *corev1.Node, key string, pod *corev1.Pod, now time.Time) error {
+	if err := n.setManagedRolloutStartedAnnotation(ctx, node, key, &now); err != nil {
+		return err
+	}
+	if err := n.client.Delete(ctx, pod); client.IgnoreNotFound(err) != nil {
+		n.logger.Error(err, "Failed to delete pod for the managed rollout", "pod", pod.Name)
+		return err
+	}
+	return nil
+}
+
+// isRolloutValidated returns whether the operator validator passed on the node of pod
+// since pod got ready. The validator pod is restarted if it started before.
+func (n ClusterPolicyController) isRolloutValidated(ctx context.Context, pod *corev1.Pod, validator *corev1.Pod) (bool, error) {
+	readyCondition := findPodCondition(pod, corev1.PodReady)
+	if readyCondition == nil || readyCondition.Status != corev1.ConditionTrue || validator == nil {
+		return false, nil
+	}
+	if validator.CreationTimestamp.Before(&readyCondition.LastTransitionTime) {
+		if validator.DeletionTimestamp == nil {
+			n.logger.Info("Restarting the operator validator to validate the rollout", "node", pod.Spec.NodeName, "pod", pod.Name)
+			if err := n.client.Delete(ctx, validator); client.IgnoreNotFound(err) != nil {
+				return false, err
+			}
+		}
+		return false, nil
+	}
+	condition := findPodCondition(validator, corev1.PodReady)
+	return condition != nil && condition.Status == corev1.ConditionTrue, nil
+}
+
+// getValidatorPods returns the operator validator pods by node name
+func (n ClusterPolicyController) getValidatorPods(ctx context.Context) (map[string]*corev1.Pod, error) {
+	list := &corev1.PodList{}
+	err := n.client.List(ctx, list, client.InNamespace(n.operatorNamespace), client.MatchingLabels{DriverLabelKey: ValidatorAppLabelValue})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list validator pods: %w", err)
+	}
+	validators := make(map[string]*corev1.Pod, len(list.Items))
+	for i := range list.Items {
+		validators[list.Items[i].Spec.NodeName] = &list.Items[i]
+	}
+	return validators, nil
+}
+
+// managedRolloutStartedAt returns when the managed rollout recorded under key started on
+// node, and whether it is in progress
+func managedRolloutStartedAt(node *corev1.Node, key string) (time.Time, bool) {
+	value, ok := node.Annotations[key]
+	if !ok {
+		return time.Time{}, false
+	}
+	startedAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		// The validation of the node is given the full timeout
+		return time.Now(), true
+	}
+	return startedAt, true
+}
+
+// setManagedRolloutStartedAnnotation sets the managed rollout annotation key of node to
+// startedAt, or removes it if startedAt is nil
+func (n ClusterPolicyController) setManagedRolloutStartedAnnotation(ctx context.Context, node *corev1.Node, key string, startedAt *time.Time) error {
+	patch := client.MergeFrom(node.DeepCopy())
+	if startedAt == nil {
+		delete(node.Annotations, key)
+	} else {
+		if node.Annotations == nil {
+			node.Annotations = map[string]string{}
+		}
+		node.Annotations[key] = startedAt.UTC().Format(time.RFC3339)
+	}
+	if err := n.client.Patch(ctx, node, patch); err != nil {
+		n.logger.Error(err, "Failed to update managed rollout annotation of node", "node", node.Name)
+		return err
+	}
+	return nil
+}
+
+// findPodCondition returns the condition of pod of type conditionType, or nil if there is none
+func findPodCondition(pod *corev1.Pod, conditionType corev1.PodConditionType) *corev1.PodCondition {
+	for i := range pod.Status.Conditions {
+		if pod.Status.Conditions[i].Type == conditionType {
+			return &pod.Status.Conditions[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,274 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1"
+)
+
+const (
+	rolloutTestNamespace = "gpu-operator"
+	rolloutTestDaemonSet = "nvidia-device-plugin-daemonset"
+	rolloutTestOldHash   = "5b7c9d8f6"
+	rolloutTestNewHash   = "69b97fbcbf"
+)
+
+type rolloutTest struct {
+	controller ClusterPolicyController
+	ds         *appsv1.DaemonSet
+}
+
+// newRolloutTest returns a managed rollout of the device plugin DaemonSet over nodes, whose
+// pods run the revision hashes of podHashes
+func newRolloutTest(t *testing.T, rollout *gpuv1.ManagedRolloutSpec, podHashes map[string]string, objs ...client.Object) *rolloutTest {
+	t.Helper()
+	selector := map[string]string{"app": rolloutTestDaemonSet}
+	ds := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: rolloutTestDaemonSet, Namespace: rolloutTestNamespace, UID: "device-plugin-uid"},
+		Spec: appsv1.DaemonSetSpec{
+			Selector:       &metav1.LabelSelector{MatchLabels: selector},
+			UpdateStrategy: appsv1.DaemonSetUpdateStrategy{Type: appsv1.OnDeleteDaemonSetStrategyType},
+		},
+		Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: int32(len(podHashes))},
+	}
+	objs = append(objs, ds)
+	for revision, hash := range []string{rolloutTestOldHash, rolloutTestNewHash} {
+		objs = append(objs, &appsv1.ControllerRevision{
+			ObjectMeta: metav1.ObjectMeta{Name: rolloutTestDaemonSet + "-" + hash, Namespace: rolloutTestNamespace, Labels: selector},
+			Revision:   int64(revision + 1),
+		})
+	}
+	for node, hash := range podHashes {
+		objs = append(objs,
+			&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: node}},
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      rolloutTestDaemonSet + "-" + node,
+					Namespace: rolloutTestNamespace,
+					Labels:    map[string]string{"app": rolloutTestDaemonSet, PodControllerRevisionHashLabelKey: hash},
+					OwnerReferences: []metav1.OwnerReference{{
+						APIVersion: appsv1.SchemeGroupVersion.String(),
+						Kind:       "DaemonSet",
+						Name:       rolloutTestDaemonSet,
+						UID:        ds.UID,
+					}},
+				},
+				Spec: corev1.PodSpec{NodeName: node},
+			})
+	}
+
+	cp := &gpuv1.ClusterPolicy{}
+	cp.Spec.Daemonsets.ManagedRollout = rollout
+	return &rolloutTest{
+		controller: ClusterPolicyController{
+			ctx:               t.Context(),
+			client:            fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objs...).Build(),
+			logger:            ctrl.Log.WithName("test"),
+			operatorNamespace: rolloutTestNamespace,
+			singleton:         cp,
+		},
+		ds: ds,
+	}
+}
+
+// rollingNode returns a node whose managed rollout of the device plugin started at startedAt
+func rollingNode(name string, startedAt time.Time) *corev1.Node {
+	return &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:        name,
+		Annotations: map[string]string{managedRolloutStartedAnnotationKey(rolloutTestDaemonSet): startedAt.UTC().Format(time.RFC3339)},
+	}}
+}
+
+// readyRolloutPod returns the new pod of the device plugin on node, ready since readySince
+func readyRolloutPod(node string, readySince time.Time) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      rolloutTestDaemonSet + "-new-" + node,
+			Namespace: rolloutTestNamespace,
+			Labels:    map[string]string{"app": rolloutTestDaemonSet, PodControllerRevisionHashLabelKey: rolloutTestNewHash},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: appsv1.SchemeGroupVersion.String(),
+				Kind:       "DaemonSet",
+				Name:       rolloutTestDaemonSet,
+				UID:        "device-plugin-uid",
+			}},
+		},
+		Spec: corev1.PodSpec{NodeName: node},
+		Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{
+			Type: corev1.PodReady, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(readySince),
+		}}},
+	}
+}
+
+// rolloutValidatorPod returns the operator validator pod of node created at createdAt
+func rolloutValidatorPod(node string, createdAt time.Time, ready bool) *corev1.Pod {
+	pod := validatorPod(node, ready)
+	pod.Namespace = rolloutTestNamespace
+	pod.CreationTimestamp = metav1.NewTime(createdAt)
+	return pod
+}
+
+func (r *rolloutTest) exists(t *testing.T, obj client.Object) bool {
+	t.Helper()
+	err := r.controller.client.Get(t.Context(), client.ObjectKeyFromObject(obj), obj)
+	if apierrors.IsNotFound(err) {
+		return false
+	}
+	require.NoError(t, err)
+	return true
+}
+
+// rollingNodes returns the names of the nodes the managed rollout is in progress on
+func (r *rolloutTest) rollingNodes(t *testing.T) []string {
+	t.Helper()
+	list := &corev1.NodeList{}
+	require.NoError(t, r.controller.client.List(t.Context(), list))
+	var names []string
+	for _, node := range list.Items {
+		if _, ok := node.Annotations[managedRolloutStartedAnnotationKey(rolloutTestDaemonSet)]; ok {
+			names = append(names, node.Name)
+		}
+	}
+	return names
+}
+
+func (r *rolloutTest) oldPodExists(t *testing.T, node string) bool {
+	t.Helper()
+	return r.exists(t, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: rolloutTestDaemonSet + "-" + node, Namespace: rolloutTestNamespace}})
+}
+
+func TestRolloutDaemonSet(t *testing.T) {
+	enabled := &gpuv1.ManagedRolloutSpec{Enabled: ptr.To(true)}
+	allOutdated := map[string]string{"node-a": rolloutTestOldHash, "node-b": rolloutTestOldHash, "node-c": rolloutTestOldHash}
+
+	t.Run("rollout starts on one node", func(t *testing.T) {
+		r := newRolloutTest(t, enabled, allOutdated)
+		rolling, err := r.controller.rolloutDaemonSet(t.Context(), r.ds)
+		require.NoError(t, err)
+		assert.True(t, rolling)
+		assert.Equal(t, []string{"node-a"}, r.rollingNodes(t))
+		assert.False(t, r.oldPodExists(t, "node-a"))
+		assert.True(t, r.oldPodExists(t, "node-b"))
+		assert.True(t, r.oldPodExists(t, "node-c"))
+	})
+
+	t.Run("percentage maxUnavailable", func(t *testing.T) {
+		r := newRolloutTest(t, &gpuv1.ManagedRolloutSpec{Enabled: ptr.To(true), MaxUnavailable: ptr.To(intstr.FromString("50%"))}, allOutdated)
+		rolling, err := r.controller.rolloutDaemonSet(t.Context(), r.ds)
+		require.NoError(t, err)
+		assert.True(t, rolling)
+		assert.Equal(t, []string{"node-a", "node-b"}, r.rollingNodes(t))
+		assert.True(t, r.oldPodExists(t, "node-c"))
+	})
+
+	t.Run("validator started before the new pod is restarted", func(t *testing.T) {
+		now := time.Now()
+		validator := rolloutValidatorPod("node-a", now.Add(-time.Hour), true)
+		r := newRolloutTest(t, enabled, map[string]string{"node-b": rolloutTestOldHash},
+			rollingNode("node-a", now.Add(-time.Minute)), readyRolloutPod("node-a", now.Add(-30*time.Second)), validator)
+		rolling, err := r.controller.rolloutDaemonSet(t.Context(), r.ds)
+		require.NoError(t, err)
+		assert.True(t, rolling)
+		assert.False(t, r.exists(t, validator), "validator is restarted")
+		assert.Equal(t, []string{"node-a"}, r.rollingNodes(t))
+		assert.True(t, r.oldPodExists(t, "node-b"), "rollout waits for the validation of node-a")
+	})
+
+	t.Run("rollout moves on once the node is validated", func(t *testing.T) {
+		now := time.Now()
+		r := newRolloutTest(t, enabled, map[string]string{"node-b": rolloutTestOldHash},
+			rollingNode("node-a", now.Add(-time.Minute)), readyRolloutPod("node-a", now.Add(-30*time.Second)),
+			rolloutValidatorPod("node-a", now.Add(-10*time.Second), true))
+		rolling, err := r.controller.rolloutDaemonSet(t.Context(), r.ds)
+		require.NoError(t, err)
+		assert.True(t, rolling)
+		assert.Equal(t, []string{"node-b"}, r.rollingNodes(t))
+		assert.False(t, r.oldPodExists(t, "node-b"))
+	})
+
+	t.Run("rollout halts when the validation times out", func(t *testing.T) {
+		now := time.Now()
+		r := newRolloutTest(t, enabled, map[string]string{"node-b": rolloutTestOldHash},
+			rollingNode("node-a", now.Add(-time.Hour)), readyRolloutPod("node-a", now.Add(-50*time.Minute)),
+			rolloutValidatorPod("node-a", now.Add(-40*time.Minute), false))
+		rolling, err := r.controller.rolloutDaemonSet(t.Context(), r.ds)
+		require.NoError(t, err)
+		assert.True(t, rolling)
+		assert.Equal(t, []string{"node-a"}, r.rollingNodes(t))
+		assert.True(t, r.oldPodExists(t, "node-b"))
+	})
+
+	t.Run("outdated pod of a node being rolled out is replaced", func(t *testing.T) {
+		r := newRolloutTest(t, enabled, map[string]string{"node-b": rolloutTestOldHash},
+			rollingNode("node-a", time.Now().Add(-time.Minute)))
+		pod := readyRolloutPod("node-a", time.Now())
+		pod.Labels[PodControllerRevisionHashLabelKey] = rolloutTestOldHash
+		require.NoError(t, r.controller.client.Create(t.Context(), pod))
+
+		rolling, err := r.controller.rolloutDaemonSet(t.Context(), r.ds)
+		require.NoError(t, err)
+		assert.True(t, rolling)
+		assert.False(t, r.exists(t, pod))
+		assert.Equal(t, []string{"node-a"}, r.rollingNodes(t))
+		assert.True(t, r.oldPodExists(t, "node-b"))
+
+		node := &corev1.Node{}
+		require.NoError(t, r.controller.client.Get(t.Context(), types.NamespacedName{Name: "node-a"}, node))
+		startedAt, rolling := managedRolloutStartedAt(node, managedRolloutStartedAnnotationKey(rolloutTestDaemonSet))
+		assert.True(t, rolling)
+		assert.WithinDuration(t, time.Now(), startedAt, 5*time.Second, "validation timeout starts over")
+	})
+
+	t.Run("rollout completes once the last node is validated", func(t *testing.T) {
+		now := time.Now()
+		r := newRolloutTest(t, enabled, nil,
+			rollingNode("node-a", now.Add(-time.Minute)), readyRolloutPod("node-a", now.Add(-30*time.Second)),
+			rolloutValidatorPod("node-a", now.Add(-10*time.Second), true))
+		rolling, err := r.controller.rolloutDaemonSet(t.Context(), r.ds)
+		require.NoError(t, err)
+		assert.False(t, rolling)
+		assert.Empty(t, r.rollingNodes(t))
+	})
+
+	t.Run("pods of deleted nodes are skipped", func(t *testing.T) {
+		r := newRolloutTest(t, enabled, allOutdated)
+		require.NoError(t, r.controller.client.Delete(t.Context(), &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}}))
+		rolling, err := r.controller.rolloutDaemonSet(t.Context(), r.ds)
+		require.NoError(t, err)
+		assert.True(t, rolling)
+		assert.Equal(t, []string{"node-b"}, r.rollingNodes(t))
+		assert.True(t, r.oldPodExists(t, "node-a"))
+		assert.False(t, r.oldPodExists(t, "node-b"))
+	})
+}
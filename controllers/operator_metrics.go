@@ -47,6 +47,12 @@ type OperatorMetrics struct {
 	upgradesFailed           promcli.Gauge
 	upgradesAvailable        promcli.Gauge
 	upgradesPending          promcli.Gauge
+	upgradeCanaryHalted      promcli.Gauge
+
+	upgradeMaintenanceWindowStart *promcli.GaugeVec
+	upgradeMaintenanceWindowEnd   *promcli.GaugeVec
+
+	driftedObjects *promcli.GaugeVec
 }
 
 const (
@@ -62,6 +68,9 @@ const (
 	driverAutoUpgradeEnabled  = 1
 	driverAutoUpgradeDisabled = 0
 
+	upgradeCanaryHalted    = 1
+	upgradeCanaryNotHalted = 0
+
 	// operatorMetricsNamespace is the name of the namespace used for the GPU Operator metrics.
 	operatorMetricsNamespace = "gpu_operator"
 )
@@ -69,7 +78,42 @@ const (
 // InitOperatorMetrics registers all GPU operator Prometheus metrics with the
 // controller-runtime registry and returns the initialised OperatorMetrics.
 func InitOperatorMetrics() *OperatorMetrics {
-	m := &OperatorMetrics{
+	m := newOperatorMetrics()
+
+	metrics.Registry.MustRegister(
+		m.gpuNodesTotal,
+
+		m.reconciliationLastSuccess,
+		m.reconciliationStatus,
+		m.reconciliationTotal,
+		m.reconciliationFailed,
+		m.reconciliationHasNFDLabels,
+
+		m.openshiftDriverToolkitEnabled,
+		m.openshiftDriverToolkitNfdTooOld,
+		m.openshiftDriverToolkitIsMissing,
+		m.openshiftDriverToolkitRhcosTagsMissing,
+		m.openshiftDriverToolkitIsBroken,
+
+		m.driverAutoUpgradeEnabled,
+		m.upgradesInProgress,
+		m.upgradesDone,
+		m.upgradesAvailable,
+		m.upgradesFailed,
+		m.upgradesPending,
+		m.upgradeCanaryHalted,
+		m.upgradeMaintenanceWindowStart,
+		m.upgradeMaintenanceWindowEnd,
+
+		m.driftedObjects,
+	)
+
+	return m
+}
+
+// newOperatorMetrics returns the GPU operator metrics without registering them
+func newOperatorMetrics() *OperatorMetrics {
+	return &OperatorMetrics{
 		gpuNodesTotal: promcli.NewGauge(
 			promcli.GaugeOpts{
 				Namespace: operatorMetricsNamespace,
@@ -194,30 +238,36 @@ func InitOperatorMetrics() *OperatorMetrics {
 				Help:      "Total number of nodes on which the gpu operator pod upgrades are pending",
 			},
 		),
+		upgradeCanaryHalted: promcli.NewGauge(
+			promcli.GaugeOpts{
+				Namespace: operatorMetricsNamespace,
+				Name:      "driver_upgrade_canary_halted",
+				Help:      "1 if the driver upgrade is halted because it failed on a canary node, 0 otherwise",
+			},
+		),
+		upgradeMaintenanceWindowStart: promcli.NewGaugeVec(
+			promcli.GaugeOpts{
+				Namespace: operatorMetricsNamespace,
+				Name:      "driver_upgrade_maintenance_window_start_ts_seconds",
+				Help:      "Timestamp (in seconds) of the start of the driver upgrade maintenance window open now, or else of the next one",
+			},
+			[]string{"kind", "name"},
+		),
+		upgradeMaintenanceWindowEnd: promcli.NewGaugeVec(
+			promcli.GaugeOpts{
+				Namespace: operatorMetricsNamespace,
+				Name:      "driver_upgrade_maintenance_window_end_ts_seconds",
+				Help:      "Timestamp (in seconds) of the end of the driver upgrade maintenance window open now, or else of the next one",
+			},
+			[]string{"kind", "name"},
+		),
+		driftedObjects: promcli.NewGaugeVec(
+			promcli.GaugeOpts{
+				Namespace: operatorMetricsNamespace,
+				Name:      "drifted_objects",
+				Help:      "Number of objects deployed for a custom resource that were edited out of band and no longer match their desired state",
+			},
+			[]string{"kind", "name"},
+		),
 	}
-
-	metrics.Registry.MustRegister(
-		m.gpuNodesTotal,
-
-		m.reconciliationLastSuccess,
-		m.reconciliationStatus,
-		m.reconciliationTotal,
-		m.reconciliationFailed,
-		m.reconciliationHasNFDLabels,
-
-		m.openshiftDriverToolkitEnabled,
-		m.openshiftDriverToolkitNfdTooOld,
-		m.openshiftDriverToolkitIsMissing,
-		m.openshiftDriverToolkitRhcosTagsMissing,
-		m.openshiftDriverToolkitIsBroken,
-
-		m.driverAutoUpgradeEnabled,
-		m.upgradesInProgress,
-		m.upgradesDone,
-		m.upgradesAvailable,
-		m.upgradesFailed,
-		m.upgradesPending,
-	)
-
-	return m
 }
@@ -0,0 +1,410 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-logr/logr"
+	apiconfigv1 "github.com/openshift/api/config/v1"
+	apiimagev1 "github.com/openshift/api/image/v1"
+	secv1 "github.com/openshift/api/security/v1"
+	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"golang.org/x/mod/semver"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1"
+	nvidiav1alpha1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1alpha1"
+	"github.com/NVIDIA/gpu-operator/internal/consts"
+	"github.com/NVIDIA/gpu-operator/internal/nvidiadriver"
+	"github.com/NVIDIA/gpu-operator/internal/state"
+	"github.com/NVIDIA/gpu-operator/internal/utils"
+)
+
+// RenderOptions describes the cluster the objects are rendered for. The rendering
+// never talks to an API server, every cluster property the controllers would
+// discover is taken from here instead.
+type RenderOptions struct {
+	// Namespace is the namespace the operator is installed in
+	Namespace string
+	// AssetsDir is the directory holding the ClusterPolicy state assets
+	AssetsDir string
+	// ManifestsDir is the directory holding the NVIDIADriver and GPUCluster state manifests
+	ManifestsDir string
+
+	// Nodes are the nodes of the cluster, as labeled by NFD
+	Nodes []corev1.Node
+
+	// KubernetesVersion is the version of the cluster, e.g. v1.33.0
+	KubernetesVersion string
+	// ContainerRuntime is the container runtime of the GPU nodes, detected from the
+	// nodes if empty
+	ContainerRuntime string
+	// OpenshiftVersion is the OpenShift version, empty on other Kubernetes distributions
+	OpenshiftVersion string
+	// OpenshiftDriverToolkitImages maps RHCOS versions to their driver toolkit image
+	OpenshiftDriverToolkitImages map[string]string
+	// OpenshiftProxySpec is the OpenShift cluster wide proxy, if any
+	OpenshiftProxySpec *apiconfigv1.ProxySpec
+	// DRAResourceAPIVersion is the served resource.k8s.io version, e.g.
+	// resource.k8s.io/v1, empty when DRA is not supported
+	DRAResourceAPIVersion string
+
+	// HostRoot is the root of the host filesystem used by the NVIDIADriver states
+	HostRoot string
+
+	Logger logr.Logger
+}
+
+// NewRenderScheme returns a scheme with all the types the operator deploys
+func NewRenderScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(gpuv1.AddToScheme(scheme))
+	utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
+	utilruntime.Must(nvidiav1alpha1.AddToScheme(scheme))
+	utilruntime.Must(promv1.AddToScheme(scheme))
+	utilruntime.Must(secv1.Install(scheme))
+	utilruntime.Must(apiconfigv1.Install(scheme))
+	utilruntime.Must(apiimagev1.Install(scheme))
+	return scheme
+}
+
+// Render returns the objects the controller reconciling cr deploys for it. cr must be
+// a ClusterPolicy, a v1alpha1 NVIDIADriver or a GPUCluster.
+func Render(ctx context.Context, cr client.Object, opts RenderOptions) ([]*unstructured.Unstructured, error) {
+	switch cr := cr.(type) {
+	case *gpuv1.ClusterPolicy:
+		return RenderClusterPolicy(ctx, cr, opts)
+	case *nvidiav1alpha1.NVIDIADriver:
+		return RenderNVIDIADriver(ctx, cr, opts)
+	case *nvidiav1alpha1.GPUCluster:
+		return RenderGPUCluster(ctx, cr, opts)
+	}
+	return nil, fmt.Errorf("unsupported custom resource %T", cr)
+}
+
+// RenderClusterPolicy returns the objects the ClusterPolicy controller deploys for cp,
+// in the order they are deployed. The states are run against an in-memory client
+// seeded with the nodes of opts.
+func RenderClusterPolicy(ctx context.Context, cp *gpuv1.ClusterPolicy, opts RenderOptions) ([]*unstructured.Unstructured, error) {
+	recorder, err := renderClusterPolicy(ctx, cp, opts)
+	if err != nil {
+		return nil, err
+	}
+	return recorder.objects(), nil
+}
+
+// renderClusterPolicy runs the states of cp and returns the recorder holding the
+// objects they deploy along with the state of each
+func renderClusterPolicy(ctx context.Context, cp *gpuv1.ClusterPolicy, opts RenderOptions) (*objectRecorder, error) {
+	if !semver.IsValid(opts.KubernetesVersion) {
+		return nil, fmt.Errorf("k8s version '%s' is not a valid semantic version", opts.KubernetesVersion)
+	}
+	if err := validateClusterPolicySpec(&cp.Spec); err != nil {
+		return nil, fmt.Errorf("error validating clusterpolicy: %w", err)
+	}
+	// asset loading panics on a missing directory, fail gracefully instead
+	if _, err := os.Stat(opts.AssetsDir); err != nil {
+		return nil, fmt.Errorf("failed to read the assets directory: %w", err)
+	}
+
+	scheme := NewRenderScheme()
+	recorder := newObjectRecorder(scheme)
+	c := newRenderClient(scheme, recorder, cp, opts)
+
+	nlc := &nodeLabelingController{client: c, namespace: opts.Namespace, clusterPolicy: cp, logger: opts.Logger}
+	if _, err := nlc.labelGPUNodes(ctx); err != nil {
+		return nil, err
+	}
+
+	n := &ClusterPolicyController{
+		client:            c,
+		ctx:               ctx,
+		singleton:         cp,
+		logger:            opts.Logger,
+		scheme:            scheme,
+		operatorNamespace: opts.Namespace,
+		operatorMetrics:   newOperatorMetrics(),
+		k8sVersion:        opts.KubernetesVersion,
+		openshift:         opts.OpenshiftVersion,
+		getClusterWideProxy: func(context.Context) (*apiconfigv1.Proxy, error) {
+			if opts.OpenshiftProxySpec == nil {
+				return nil, nil
+			}
+			return &apiconfigv1.Proxy{Spec: *opts.OpenshiftProxySpec}, nil
+		},
+	}
+	n.addStates(opts.AssetsDir)
+	if err := n.configure(); err != nil {
+		return nil, err
+	}
+	if opts.ContainerRuntime != "" {
+		n.runtime = gpuv1.Runtime(opts.ContainerRuntime)
+	}
+
+	for !n.last() {
+		stateName := n.stateNames[n.idx]
+		recorder.state = stateName
+		if _, err := n.step(); err != nil {
+			return nil, fmt.Errorf("failed to render state %s: %w", stateName, err)
+		}
+	}
+	return recorder, nil
+}
+
+// RenderNVIDIADriver returns the objects the NVIDIADriver controller deploys for
+// driver, in the order they are deployed.
+func RenderNVIDIADriver(ctx context.Context, driver *nvidiav1alpha1.NVIDIADriver, opts RenderOptions) ([]*unstructured.Unstructured, error) {
+	scheme := NewRenderScheme()
+	c := newRenderClient(scheme, nil, driver, opts)
+
+	nlc := &nodeLabelingController{client: c, namespace: opts.Namespace, logger: opts.Logger}
+	if _, err := nlc.labelGPUNodes(ctx); err != nil {
+		return nil, err
+	}
+	if _, err := nvidiadriver.AssignOwners(ctx, c); err != nil {
+		return nil, err
+	}
+
+	hostRoot := opts.HostRoot
+	if hostRoot == "" {
+		hostRoot = defaultHostRoot
+	}
+	infoCatalog := state.NewInfoCatalog()
+	infoCatalog.Add(state.InfoTypeClusterInfo, newRenderClusterInfo(opts))
+	infoCatalog.Add(state.InfoTypeHostRoot, hostRoot)
+
+	objs, err := state.RenderObjects(ctx, nvidiav1alpha1.NVIDIADriverCRDName, opts.Namespace, c, scheme,
+		opts.ManifestsDir, driver, infoCatalog)
+	if err != nil {
+		return nil, err
+	}
+	return renderedObjects(objs), nil
+}
+
+// RenderGPUCluster returns the objects the GPUCluster controller deploys for gc, in
+// the order they are deployed.
+func RenderGPUCluster(ctx context.Context, gc *nvidiav1alpha1.GPUCluster, opts RenderOptions) ([]*unstructured.Unstructured, error) {
+	scheme := NewRenderScheme()
+	c := newRenderClient(scheme, nil, gc, opts)
+
+	nlc := &nodeLabelingController{client: c, namespace: opts.Namespace, gpuCluster: gc, logger: opts.Logger}
+	if _, err := nlc.labelGPUNodes(ctx); err != nil {
+		return nil, err
+	}
+
+	infoCatalog := state.NewInfoCatalog()
+	infoCatalog.Add(state.InfoTypeClusterInfo, newRenderClusterInfo(opts))
+
+	// Render from a defaulted copy, as the GPUCluster controller does
+	desired := gc.DeepCopy()
+	desired.Spec.SetDefaults()
+	objs, err := state.RenderObjects(ctx, nvidiav1alpha1.GPUClusterCRDName, opts.Namespace, c, scheme,
+		opts.ManifestsDir, desired, infoCatalog)
+	if err != nil {
+		return nil, err
+	}
+	return renderedObjects(objs), nil
+}
+
+// newRenderClient returns an in-memory client seeded with the custom resource, the
+// nodes and the operator namespace. Objects created or updated through it are
+// recorded by recorder, if set.
+func newRenderClient(scheme *runtime.Scheme, recorder *objectRecorder, cr client.Object, opts RenderOptions) client.Client {
+	objs := []client.Object{
+		cr,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: opts.Namespace}},
+	}
+	for i := range opts.Nodes {
+		objs = append(objs, opts.Nodes[i].DeepCopy())
+	}
+	if opts.OpenshiftVersion != "" && len(opts.OpenshiftDriverToolkitImages) > 0 {
+		imageStream := &apiimagev1.ImageStream{
+			ObjectMeta: metav1.ObjectMeta{Name: "driver-toolkit", Namespace: consts.OpenshiftNamespace},
+		}
+		for rhcosVersion, image := range opts.OpenshiftDriverToolkitImages {
+			imageStream.Spec.Tags = append(imageStream.Spec.Tags, apiimagev1.TagReference{
+				Name: rhcosVersion,
+				From: &corev1.ObjectReference{Kind: "DockerImage", Name: image},
+			})
+		}
+		objs = append(objs, imageStream)
+	}
+
+	builder := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithIndex(&appsv1.DaemonSet{}, clusterPolicyControllerIndexKey, clusterPolicyDaemonSetIndexer).
+		WithIndex(&corev1.Pod{}, podNodeNameIndexKey, indexPodByNodeName)
+	if recorder != nil {
+		builder = builder.WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				if err := c.Create(ctx, obj, opts...); err != nil {
+					return err
+				}
+				return recorder.record(obj)
+			},
+			Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				if err := c.Update(ctx, obj, opts...); err != nil {
+					return err
+				}
+				return recorder.record(obj)
+			},
+			Apply: func(ctx context.Context, c client.WithWatch, obj runtime.ApplyConfiguration, opts ...client.ApplyOption) error {
+				if err := c.Apply(ctx, obj, opts...); err != nil {
+					return err
+				}
+				// record the applied object as stored, not the applied configuration
+				data, err := json.Marshal(obj)
+				if err != nil {
+					return err
+				}
+				applied := &unstructured.Unstructured{}
+				if err := applied.UnmarshalJSON(data); err != nil {
+					return err
+				}
+				if err := c.Get(ctx, client.ObjectKeyFromObject(applied), applied); err != nil {
+					return err
+				}
+				return recorder.record(applied)
+			},
+		})
+	}
+	return builder.Build()
+}
+
+// objectRecorder keeps the latest content of every object written, in the order the
+// objects were first written, along with the state that first wrote it
+type objectRecorder struct {
+	scheme *runtime.Scheme
+	keys   []string
+	objs   map[string]*unstructured.Unstructured
+	// state is the state currently writing objects
+	state  string
+	states map[string]string
+}
+
+func newObjectRecorder(scheme *runtime.Scheme) *objectRecorder {
+	return &objectRecorder{scheme: scheme, objs: map[string]*unstructured.Unstructured{}, states: map[string]string{}}
+}
+
+func (r *objectRecorder) record(obj client.Object) error {
+	gvk, err := apiutil.GVKForObject(obj, r.scheme)
+	if err != nil {
+		return err
+	}
+	u, err := utils.ToUnstructured(obj)
+	if err != nil {
+		return fmt.Errorf("failed to convert %s %s: %w", gvk.Kind, obj.GetName(), err)
+	}
+	u.SetGroupVersionKind(gvk)
+
+	key := recorderKey(gvk, obj)
+	if _, ok := r.objs[key]; !ok {
+		r.keys = append(r.keys, key)
+		r.states[key] = r.state
+	}
+	r.objs[key] = u
+	return nil
+}
+
+// stateOf returns the state that wrote obj
+func (r *objectRecorder) stateOf(obj *unstructured.Unstructured) string {
+	return r.states[recorderKey(obj.GroupVersionKind(), obj)]
+}
+
+func recorderKey(gvk schema.GroupVersionKind, obj metav1.Object) string {
+	return strings.Join([]string{gvk.String(), obj.GetNamespace(), obj.GetName()}, "/")
+}
+
+func (r *objectRecorder) objects() []*unstructured.Unstructured {
+	objs := make([]*unstructured.Unstructured, 0, len(r.keys))
+	for _, key := range r.keys {
+		objs = append(objs, r.objs[key])
+	}
+	return renderedObjects(objs)
+}
+
+// renderedObjects drops the fields set by the API server from objs
+func renderedObjects(objs []*unstructured.Unstructured) []*unstructured.Unstructured {
+	for _, obj := range objs {
+		obj.SetResourceVersion("")
+		obj.SetManagedFields(nil)
+		unstructured.RemoveNestedField(obj.Object, "metadata", "creationTimestamp")
+		unstructured.RemoveNestedField(obj.Object, "status")
+	}
+	return objs
+}
+
+// renderClusterInfo is a clusterinfo.Interface answering from RenderOptions
+type renderClusterInfo struct {
+	opts RenderOptions
+}
+
+func newRenderClusterInfo(opts RenderOptions) *renderClusterInfo {
+	return &renderClusterInfo{opts: opts}
+}
+
+func (i *renderClusterInfo) GetContainerRuntime() (string, error) {
+	if i.opts.ContainerRuntime != "" {
+		return i.opts.ContainerRuntime, nil
+	}
+	if i.opts.OpenshiftVersion != "" {
+		return consts.CRIO, nil
+	}
+	return consts.Containerd, nil
+}
+
+func (i *renderClusterInfo) GetOpenshiftVersion() (string, error) {
+	return i.opts.OpenshiftVersion, nil
+}
+
+func (i *renderClusterInfo) GetOpenshiftDriverToolkitImages() map[string]string {
+	return i.opts.OpenshiftDriverToolkitImages
+}
+
+func (i *renderClusterInfo) GetOpenshiftProxySpec() (*apiconfigv1.ProxySpec, error) {
+	return i.opts.OpenshiftProxySpec, nil
+}
+
+func (i *renderClusterInfo) GetDRAResourceGVR() (schema.GroupVersionResource, bool, error) {
+	if i.opts.DRAResourceAPIVersion == "" {
+		return schema.GroupVersionResource{}, false, nil
+	}
+	gv, err := schema.ParseGroupVersion(i.opts.DRAResourceAPIVersion)
+	if err != nil {
+		return schema.GroupVersionResource{}, false, fmt.Errorf("invalid DRA resource apiVersion: %w", err)
+	}
+	return gv.WithResource("deviceclasses"), true, nil
+}
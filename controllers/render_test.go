@@ -0,0 +1,150 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1"
+	nvidiav1alpha1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1alpha1"
+	"github.com/NVIDIA/gpu-operator/internal/consts"
+)
+
+func newRenderTestOptions(t *testing.T) RenderOptions {
+	t.Helper()
+	for _, env := range []string{"DRIVER_IMAGE", "DRIVER_MANAGER_IMAGE", "CONTAINER_TOOLKIT_IMAGE", "DEVICE_PLUGIN_IMAGE",
+		"DCGM_IMAGE", "DCGM_EXPORTER_IMAGE", "GFD_IMAGE", "VALIDATOR_IMAGE", "MIG_MANAGER_IMAGE", "DRA_DRIVER_IMAGE"} {
+		t.Setenv(env, "nvcr.io/nvidia/"+env+":v1")
+	}
+
+	return RenderOptions{
+		Namespace:             "gpu-operator",
+		AssetsDir:             filepath.Join(cfg.root, "assets"),
+		ManifestsDir:          filepath.Join(cfg.root, "manifests"),
+		KubernetesVersion:     "v1.33.0",
+		DRAResourceAPIVersion: "resource.k8s.io/v1",
+		Nodes: []corev1.Node{
+			{ObjectMeta: metav1.ObjectMeta{Name: "gpu-node", Labels: nfdLabels}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "cpu-node"}},
+		},
+	}
+}
+
+func renderedKinds(objs []*unstructured.Unstructured) map[string][]string {
+	kinds := map[string][]string{}
+	for _, obj := range objs {
+		kinds[obj.GetKind()] = append(kinds[obj.GetKind()], obj.GetName())
+	}
+	return kinds
+}
+
+func TestRenderClusterPolicy(t *testing.T) {
+	opts := newRenderTestOptions(t)
+
+	data, err := os.ReadFile(filepath.Join(cfg.root, clusterPolicyPath))
+	require.NoError(t, err)
+	cp := &gpuv1.ClusterPolicy{}
+	require.NoError(t, yaml.Unmarshal(data, cp))
+
+	objs, err := RenderClusterPolicy(t.Context(), cp, opts)
+	require.NoError(t, err)
+
+	kinds := renderedKinds(objs)
+	require.Contains(t, kinds["DaemonSet"], "nvidia-driver-daemonset")
+	require.Contains(t, kinds["DaemonSet"], "nvidia-device-plugin-daemonset")
+	require.NotEmpty(t, kinds["ServiceAccount"])
+	require.NotEmpty(t, kinds["ClusterRole"])
+	require.Empty(t, kinds["Node"])
+
+	for _, obj := range objs {
+		require.Empty(t, obj.GetResourceVersion())
+		_, found := obj.Object["status"]
+		require.False(t, found, "%s %s has a status", obj.GetKind(), obj.GetName())
+		if obj.GetKind() == "DaemonSet" {
+			require.Equal(t, "gpu-operator", obj.GetNamespace())
+		}
+	}
+
+	// An invalid spec is rejected like the controller does
+	invalid := cp.DeepCopy()
+	invalid.Spec.CDI.Enabled = ptr.To(false)
+	invalid.Spec.CDI.NRIPluginEnabled = ptr.To(true)
+	_, err = RenderClusterPolicy(t.Context(), invalid, opts)
+	require.ErrorContains(t, err, "error validating clusterpolicy")
+
+	opts.AssetsDir = filepath.Join(t.TempDir(), "missing")
+	_, err = RenderClusterPolicy(t.Context(), cp, opts)
+	require.ErrorContains(t, err, "failed to read the assets directory")
+}
+
+func TestRenderNVIDIADriver(t *testing.T) {
+	opts := newRenderTestOptions(t)
+
+	driver := &nvidiav1alpha1.NVIDIADriver{
+		ObjectMeta: metav1.ObjectMeta{Name: "default"},
+		Spec: nvidiav1alpha1.NVIDIADriverSpec{
+			DriverType: nvidiav1alpha1.GPU,
+			Repository: "nvcr.io/nvidia",
+			Image:      "driver",
+			Version:    "580.65.06",
+		},
+	}
+
+	objs, err := RenderNVIDIADriver(t.Context(), driver, opts)
+	require.NoError(t, err)
+
+	kinds := renderedKinds(objs)
+	require.Len(t, kinds["DaemonSet"], 1)
+	for _, obj := range objs {
+		require.Equal(t, "state-driver", obj.GetLabels()[consts.StateLabel])
+		require.Equal(t, "default", obj.GetOwnerReferences()[0].Name)
+	}
+}
+
+func TestRenderGPUCluster(t *testing.T) {
+	opts := newRenderTestOptions(t)
+
+	gc := &nvidiav1alpha1.GPUCluster{ObjectMeta: metav1.ObjectMeta{Name: "gpu-cluster"}}
+	objs, err := RenderGPUCluster(t.Context(), gc, opts)
+	require.NoError(t, err)
+	require.NotEmpty(t, renderedKinds(objs)["DaemonSet"])
+
+	// Rendering fails like the controller when DRA is not served
+	opts.DRAResourceAPIVersion = ""
+	_, err = RenderGPUCluster(t.Context(), gc, opts)
+	require.ErrorContains(t, err, "DeviceClass API is not served")
+}
+
+func TestRender(t *testing.T) {
+	opts := newRenderTestOptions(t)
+
+	objs, err := Render(t.Context(), &nvidiav1alpha1.GPUCluster{ObjectMeta: metav1.ObjectMeta{Name: "gpu-cluster"}}, opts)
+	require.NoError(t, err)
+	require.NotEmpty(t, objs)
+
+	_, err = Render(t.Context(), &corev1.Node{}, opts)
+	require.ErrorContains(t, err, "unsupported custom resource *v1.Node")
+}
@@ -35,6 +35,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 
 	gpuv1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1"
+	"github.com/NVIDIA/gpu-operator/internal/pause"
 )
 
 const (
@@ -197,6 +198,10 @@ type OpenShiftDriverToolkit struct {
 	rhcosDriverToolkitImages map[string]string
 }
 
+// DefaultAssetsDir is the directory the ClusterPolicy state assets are installed to
+// in the operator image
+const DefaultAssetsDir = "/opt/gpu-operator"
+
 // ClusterPolicyController represents clusterpolicy controller spec for GPU operator
 type ClusterPolicyController struct {
 	client client.Client
@@ -225,6 +230,9 @@ type ClusterPolicyController struct {
 	hasGPUNodes      bool
 	hasNFDLabels     bool
 	sandboxEnabled   bool
+
+	// getClusterWideProxy returns the OpenShift cluster wide proxy, GetClusterWideProxy if unset
+	getClusterWideProxy func(ctx context.Context) (*apiconfigv1.Proxy, error)
 }
 
 func addState(n *ClusterPolicyController, path string) {
@@ -612,7 +620,7 @@ func (n *ClusterPolicyController) getGPUNodeOSInfo() (string, string, error) {
 
 func (n *ClusterPolicyController) setPodSecurityLabelsForNamespace() error {
 	ctx := n.ctx
-	namespaceName := clusterPolicyCtrl.operatorNamespace
+	namespaceName := n.operatorNamespace
 
 	if n.openshift != "" && namespaceName != ocpSuggestedNamespace {
 		// The GPU Operator is not installed in the suggested
@@ -662,7 +670,7 @@ func (n *ClusterPolicyController) setPodSecurityLabelsForNamespace() error {
 
 func (n *ClusterPolicyController) ocpEnsureNamespaceMonitoring() error {
 	ctx := n.ctx
-	namespaceName := clusterPolicyCtrl.operatorNamespace
+	namespaceName := n.operatorNamespace
 
 	if namespaceName != ocpSuggestedNamespace {
 		// The GPU Operator is not installed in the suggested
@@ -772,7 +780,7 @@ func (n *ClusterPolicyController) init(ctx context.Context, reconciler *ClusterP
 	n.scheme = reconciler.Scheme
 
 	if len(n.controls) == 0 {
-		clusterPolicyCtrl.operatorNamespace = reconciler.Namespace
+		n.operatorNamespace = reconciler.Namespace
 
 		version, err := OpenshiftVersion(ctx)
 		if err != nil && !apierrors.IsNotFound(err) {
@@ -795,29 +803,42 @@ func (n *ClusterPolicyController) init(ctx context.Context, reconciler *ClusterP
 			return fmt.Errorf("error validating clusterpolicy: %w", err)
 		}
 
-		addState(n, "/opt/gpu-operator/pre-requisites")
-		addState(n, "/opt/gpu-operator/state-operator-metrics")
-		addState(n, "/opt/gpu-operator/state-driver")
-		addState(n, "/opt/gpu-operator/state-container-toolkit")
-		addState(n, "/opt/gpu-operator/state-operator-validation")
-		addState(n, "/opt/gpu-operator/state-device-plugin")
-		addState(n, "/opt/gpu-operator/state-mps-control-daemon")
-		addState(n, "/opt/gpu-operator/state-dcgm")
-		addState(n, "/opt/gpu-operator/state-dcgm-exporter")
-		addState(n, "/opt/gpu-operator/gpu-feature-discovery")
-		addState(n, "/opt/gpu-operator/state-mig-manager")
-		addState(n, "/opt/gpu-operator/state-node-status-exporter")
-		// add sandbox workload states
-		addState(n, "/opt/gpu-operator/state-vgpu-manager")
-		addState(n, "/opt/gpu-operator/state-vgpu-device-manager")
-		addState(n, "/opt/gpu-operator/state-sandbox-validation")
-		addState(n, "/opt/gpu-operator/state-vfio-manager")
-		addState(n, "/opt/gpu-operator/state-sandbox-device-plugin")
-		addState(n, "/opt/gpu-operator/state-kata-device-plugin")
-		addState(n, "/opt/gpu-operator/state-kata-manager")
-		addState(n, "/opt/gpu-operator/state-cc-manager")
+		n.addStates(DefaultAssetsDir)
 	}
 
+	return n.configure()
+}
+
+// addStates loads the assets of every state from assetsDir, in the order the
+// states are deployed
+func (n *ClusterPolicyController) addStates(assetsDir string) {
+	addState(n, filepath.Join(assetsDir, "pre-requisites"))
+	addState(n, filepath.Join(assetsDir, "state-operator-metrics"))
+	addState(n, filepath.Join(assetsDir, "state-driver"))
+	addState(n, filepath.Join(assetsDir, "state-container-toolkit"))
+	addState(n, filepath.Join(assetsDir, "state-operator-validation"))
+	addState(n, filepath.Join(assetsDir, "state-device-plugin"))
+	addState(n, filepath.Join(assetsDir, "state-mps-control-daemon"))
+	addState(n, filepath.Join(assetsDir, "state-dcgm"))
+	addState(n, filepath.Join(assetsDir, "state-dcgm-exporter"))
+	addState(n, filepath.Join(assetsDir, "gpu-feature-discovery"))
+	addState(n, filepath.Join(assetsDir, "state-mig-manager"))
+	addState(n, filepath.Join(assetsDir, "state-node-status-exporter"))
+	// add sandbox workload states
+	addState(n, filepath.Join(assetsDir, "state-vgpu-manager"))
+	addState(n, filepath.Join(assetsDir, "state-vgpu-device-manager"))
+	addState(n, filepath.Join(assetsDir, "state-sandbox-validation"))
+	addState(n, filepath.Join(assetsDir, "state-vfio-manager"))
+	addState(n, filepath.Join(assetsDir, "state-sandbox-device-plugin"))
+	addState(n, filepath.Join(assetsDir, "state-kata-device-plugin"))
+	addState(n, filepath.Join(assetsDir, "state-kata-manager"))
+	addState(n, filepath.Join(assetsDir, "state-cc-manager"))
+}
+
+// configure derives the per-reconciliation settings from the ClusterPolicy and the
+// GPU nodes of the cluster, before the states are stepped through
+func (n *ClusterPolicyController) configure() error {
+	clusterPolicy := n.singleton
 	if clusterPolicy.Spec.SandboxWorkloads.IsEnabled() {
 		n.sandboxEnabled = true
 		// defaultGPUWorkloadConfig is container, unless
@@ -955,6 +976,15 @@ func (n *ClusterPolicyController) initOCPParams() error {
 func (n *ClusterPolicyController) step() (gpuv1.State, error) {
 	result := gpuv1.Ready
 
+	// While the state is paused by the reconcile-paused annotation, the controls run
+	// against a dry-run client: they still compute the status of the state, but none
+	// of their writes are persisted.
+	ctrl := *n
+	if pause.IsPaused(n.singleton, n.stateNames[n.idx]) {
+		n.logger.Info("Reconciliation is paused, skipping writes", "state", n.stateNames[n.idx])
+		ctrl.client = client.NewDryRunClient(n.client)
+	}
+
 	// Skip driver daemonset states if NVIDIADriver CRD is enabled
 	// TODO:
 	//   - Properly clean up any k8s object associated with 'state-driver'
@@ -968,7 +998,7 @@ func (n *ClusterPolicyController) step() (gpuv1.State, error) {
 		n.idx++
 		// Cleanup all driver daemonsets owned by ClusterPolicy while keeping the
 		// running driver pods available until NVIDIADriver rolls replacements.
-		err := n.cleanupAllDriverDaemonSets(n.ctx, metav1.DeletePropagationOrphan)
+		err := ctrl.cleanupAllDriverDaemonSets(n.ctx, metav1.DeletePropagationOrphan)
 		if err != nil {
 			return gpuv1.NotReady, fmt.Errorf("failed to cleanup all NVIDIA driver daemonsets owned by ClusterPolicy: %w", err)
 		}
@@ -976,7 +1006,7 @@ func (n *ClusterPolicyController) step() (gpuv1.State, error) {
 	}
 
 	for _, fs := range n.controls[n.idx] {
-		stat, err := fs(*n)
+		stat, err := fs(ctrl)
 		if err != nil {
 			return stat, err
 		}
@@ -23,6 +23,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/utils/ptr"
@@ -604,3 +605,52 @@ func TestIsStateEnabled_SandboxAndKataDevicePlugin(t *testing.T) {
 		})
 	}
 }
+
+func TestStepPaused(t *testing.T) {
+	testCases := []struct {
+		name          string
+		annotation    string
+		expectCreated bool
+	}{
+		{name: "not paused", annotation: "", expectCreated: true},
+		{name: "all states paused", annotation: "true", expectCreated: false},
+		{name: "state paused", annotation: "state-dcgm-exporter", expectCreated: false},
+		{name: "other state paused", annotation: "state-driver", expectCreated: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := fake.NewClientBuilder().Build()
+			configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test-config", Namespace: "test-operator"}}
+			n := &ClusterPolicyController{
+				ctx:    t.Context(),
+				client: c,
+				singleton: &gpuv1.ClusterPolicy{ObjectMeta: metav1.ObjectMeta{
+					Name:        "cluster-policy",
+					Annotations: map[string]string{"nvidia.com/reconcile-paused": tc.annotation},
+				}},
+				stateNames: []string{"state-dcgm-exporter"},
+				controls: []controlFunc{{
+					func(n ClusterPolicyController) (gpuv1.State, error) {
+						if err := n.client.Create(n.ctx, configMap.DeepCopy()); err != nil {
+							return gpuv1.NotReady, err
+						}
+						return gpuv1.Ready, nil
+					},
+				}},
+			}
+
+			state, err := n.step()
+			require.NoError(t, err)
+			require.Equal(t, gpuv1.Ready, state)
+			require.Equal(t, 1, n.idx)
+
+			err = c.Get(t.Context(), ctrlclient.ObjectKeyFromObject(configMap), &corev1.ConfigMap{})
+			if tc.expectCreated {
+				require.NoError(t, err)
+			} else {
+				require.True(t, apierrors.IsNotFound(err), "expected the config map not to be created, got %v", err)
+			}
+		})
+	}
+}
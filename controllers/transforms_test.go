@@ -631,6 +631,32 @@ func TestApplyUpdateStrategyConfig(t *testing.T) {
 			errorExpected: false,
 			expectedDs:    NewDaemonset().WithUpdateStrategy(appsv1.DaemonSetUpdateStrategy{Type: appsv1.OnDeleteDaemonSetStrategyType}),
 		},
+		{
+			description: "managed rollout, managed daemonset",
+			ds:          NewDaemonset().WithName("nvidia-device-plugin-daemonset"),
+			dsSpec: gpuv1.DaemonsetsSpec{
+				UpdateStrategy: "RollingUpdate",
+				RollingUpdate:  &gpuv1.RollingUpdateSpec{MaxUnavailable: "1"},
+				ManagedRollout: &gpuv1.ManagedRolloutSpec{Enabled: newBoolPtr(true)},
+			},
+			errorExpected: false,
+			expectedDs: NewDaemonset().WithName("nvidia-device-plugin-daemonset").
+				WithUpdateStrategy(appsv1.DaemonSetUpdateStrategy{Type: appsv1.OnDeleteDaemonSetStrategyType}),
+		},
+		{
+			description: "managed rollout, other daemonset",
+			ds:          NewDaemonset(),
+			dsSpec: gpuv1.DaemonsetsSpec{
+				UpdateStrategy: "RollingUpdate",
+				RollingUpdate:  &gpuv1.RollingUpdateSpec{MaxUnavailable: "1"},
+				ManagedRollout: &gpuv1.ManagedRolloutSpec{Enabled: newBoolPtr(true)},
+			},
+			errorExpected: false,
+			expectedDs: NewDaemonset().WithUpdateStrategy(appsv1.DaemonSetUpdateStrategy{
+				Type:          appsv1.RollingUpdateDaemonSetStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateDaemonSet{MaxUnavailable: &intstr.IntOrString{Type: intstr.Int, IntVal: 1}},
+			}),
+		},
 	}
 
 	for _, tc := range testCases {
@@ -654,6 +680,7 @@ func TestApplyCommonDaemonSetConfig(t *testing.T) {
 		description   string
 		ds            Daemonset
 		dsSpec        gpuv1.DaemonsetsSpec
+		gpuHealth     *gpuv1.GPUHealthSpec
 		errorExpected bool
 		expectedDs    Daemonset
 	}{
@@ -707,6 +734,32 @@ func TestApplyCommonDaemonSetConfig(t *testing.T) {
 				},
 			}),
 		},
+		{
+			description: "unhealthy GPU nodes tolerated when tainted",
+			ds:          NewDaemonset(),
+			dsSpec: gpuv1.DaemonsetsSpec{
+				Tolerations: []corev1.Toleration{
+					{
+						Key:      "nvidia.com/gpu",
+						Operator: corev1.TolerationOpExists,
+						Effect:   corev1.TaintEffectNoSchedule,
+					},
+				},
+			},
+			gpuHealth: &gpuv1.GPUHealthSpec{Enabled: newBoolPtr(true)},
+			expectedDs: NewDaemonset().WithTolerations([]corev1.Toleration{
+				{
+					Key:      "nvidia.com/gpu",
+					Operator: corev1.TolerationOpExists,
+					Effect:   corev1.TaintEffectNoSchedule,
+				},
+				{
+					Key:      "nvidia.com/gpu.unhealthy",
+					Operator: corev1.TolerationOpExists,
+					Effect:   corev1.TaintEffectNoSchedule,
+				},
+			}),
+		},
 		{
 			description: "invalid updatestrategy configured",
 			ds:          NewDaemonset(),
@@ -741,6 +794,7 @@ func TestApplyCommonDaemonSetConfig(t *testing.T) {
 		t.Run(tc.description, func(t *testing.T) {
 			cpSpec := &gpuv1.ClusterPolicySpec{
 				Daemonsets: tc.dsSpec,
+				GPUHealth:  tc.gpuHealth,
 			}
 			err := applyCommonDaemonsetConfig(tc.ds.DaemonSet, cpSpec)
 			if tc.errorExpected {
@@ -2794,6 +2848,61 @@ func TestTransformValidatorComponent(t *testing.T) {
 			component:   "plugin",
 			expectedPod: NewPod().WithInitContainer(corev1.Container{Name: "dummy"}),
 		},
+		{
+			description: "p2p validation",
+			pod: NewPod().
+				WithInitContainer(corev1.Container{Name: "p2p-validation"}).
+				WithRuntimeClassName("nvidia"),
+			cpSpec: &gpuv1.ClusterPolicySpec{
+				Validator: gpuv1.ValidatorSpec{
+					Repository:       "nvcr.io/nvidia/cloud-native",
+					Image:            "gpu-operator-validator",
+					Version:          "v1.0.0",
+					ImagePullPolicy:  "IfNotPresent",
+					ImagePullSecrets: []string{"pull-secret1"},
+					P2P: gpuv1.P2PValidatorSpec{
+						Enabled:                newBoolPtr(true),
+						MinBandwidthGBps:       20,
+						MaxLatencyMicroseconds: 5,
+						Env:                    []gpuv1.EnvVar{{Name: "foo", Value: "bar"}},
+					},
+				},
+			},
+			component: "p2p",
+			expectedPod: NewPod().WithInitContainer(corev1.Container{
+				Name:            "p2p-validation",
+				Image:           "nvcr.io/nvidia/cloud-native/gpu-operator-validator:v1.0.0",
+				ImagePullPolicy: corev1.PullIfNotPresent,
+				Env: []corev1.EnvVar{
+					{Name: ValidatorImageEnvName, Value: "nvcr.io/nvidia/cloud-native/gpu-operator-validator:v1.0.0"},
+					{Name: ValidatorImagePullPolicyEnvName, Value: "IfNotPresent"},
+					{Name: ValidatorImagePullSecretsEnvName, Value: "pull-secret1"},
+					{Name: ValidatorRuntimeClassEnvName, Value: "nvidia"},
+					{Name: P2PMinBandwidthEnvName, Value: "20"},
+					{Name: P2PMaxLatencyEnvName, Value: "5"},
+					{Name: "foo", Value: "bar"},
+				},
+				SecurityContext: &corev1.SecurityContext{
+					RunAsUser: rootUID,
+				},
+			}).WithRuntimeClassName("nvidia"),
+		},
+		{
+			description: "p2p validation removed by default",
+			pod: NewPod().
+				WithInitContainer(corev1.Container{Name: "p2p-validation"}).
+				WithInitContainer(corev1.Container{Name: "dummy"}),
+			cpSpec: &gpuv1.ClusterPolicySpec{
+				Validator: gpuv1.ValidatorSpec{
+					Repository:      "nvcr.io/nvidia/cloud-native",
+					Image:           "gpu-operator-validator",
+					Version:         "v1.0.0",
+					ImagePullPolicy: "IfNotPresent",
+				},
+			},
+			component:   "p2p",
+			expectedPod: NewPod().WithInitContainer(corev1.Container{Name: "dummy"}),
+		},
 		{
 			description: "driver validation",
 			pod:         NewPod().WithInitContainer(corev1.Container{Name: "driver-validation"}),
@@ -3136,6 +3245,74 @@ func TestTransformValidator(t *testing.T) {
 				}).
 				WithPullSecret("pull-secret"),
 		},
+		{
+			description: "revalidation sidecar removed by default",
+			ds: NewDaemonset().
+				WithContainer(corev1.Container{Name: "dummy"}).
+				WithContainer(corev1.Container{Name: "revalidation"}),
+			cpSpec: &gpuv1.ClusterPolicySpec{
+				Validator: gpuv1.ValidatorSpec{
+					Repository:      "nvcr.io/nvidia/cloud-native",
+					Image:           "gpu-operator-validator",
+					Version:         "v1.0.0",
+					ImagePullPolicy: "IfNotPresent",
+				},
+			},
+			expectedDs: NewDaemonset().
+				WithContainer(corev1.Container{
+					Name:            "dummy",
+					Image:           "nvcr.io/nvidia/cloud-native/gpu-operator-validator:v1.0.0",
+					ImagePullPolicy: corev1.PullIfNotPresent,
+					SecurityContext: &corev1.SecurityContext{
+						RunAsUser: rootUID,
+					},
+				}).
+				WithRuntimeClassName("nvidia"),
+		},
+		{
+			description: "revalidation sidecar",
+			ds: NewDaemonset().
+				WithContainer(corev1.Container{Name: "dummy"}).
+				WithContainer(corev1.Container{Name: "revalidation"}),
+			cpSpec: &gpuv1.ClusterPolicySpec{
+				Validator: gpuv1.ValidatorSpec{
+					Repository:      "nvcr.io/nvidia/cloud-native",
+					Image:           "gpu-operator-validator",
+					Version:         "v1.0.0",
+					ImagePullPolicy: "IfNotPresent",
+					Revalidation: gpuv1.RevalidationSpec{
+						Enabled:       newBoolPtr(true),
+						FailureAction: gpuv1.RevalidationFailureActionTaint,
+					},
+				},
+				DevicePlugin: gpuv1.DevicePluginSpec{Enabled: newBoolPtr(false)},
+			},
+			expectedDs: NewDaemonset().
+				WithContainer(corev1.Container{
+					Name:            "dummy",
+					Image:           "nvcr.io/nvidia/cloud-native/gpu-operator-validator:v1.0.0",
+					ImagePullPolicy: corev1.PullIfNotPresent,
+					SecurityContext: &corev1.SecurityContext{
+						RunAsUser: rootUID,
+					},
+				}).
+				WithContainer(corev1.Container{
+					Name:            "revalidation",
+					Image:           "nvcr.io/nvidia/cloud-native/gpu-operator-validator:v1.0.0",
+					ImagePullPolicy: corev1.PullIfNotPresent,
+					Env: []corev1.EnvVar{
+						{Name: RevalidationIntervalEnvName, Value: "300"},
+						{Name: RevalidationComponentsEnvName, Value: "driver,cuda"},
+					},
+					SecurityContext: &corev1.SecurityContext{
+						RunAsUser: rootUID,
+					},
+				}).
+				WithTolerations([]corev1.Toleration{
+					{Key: revalidationFailedKey, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+				}).
+				WithRuntimeClassName("nvidia"),
+		},
 	}
 
 	for _, tc := range testCases {
@@ -4763,7 +4940,7 @@ func assertSubscriptionHostPathVolumesForTransform(t *testing.T, volumes []corev
 
 // baseDriverDaemonSetSpec returns a minimal DaemonSetSpec representative of
 // the post-transformation driver DaemonSet in the ClusterPolicy path.
-// Only fields relevant to extractDriverInstallConfig extraction are
+// Only fields relevant to DriverInstallStateFromPodSpec extraction are
 // populated; non-digest fields are omitted for brevity.
 func baseDriverDaemonSetSpec() *appsv1.DaemonSetSpec {
 	return &appsv1.DaemonSetSpec{
@@ -4818,7 +4995,7 @@ func baseDriverDaemonSetSpec() *appsv1.DaemonSetSpec {
 // (wantChange=false) do NOT alter the digest, while driver-relevant changes
 // (wantChange=true) DO alter it.
 func TestDriverConfigDigest(t *testing.T) {
-	baseDigest := utils.GetObjectHashIgnoreEmptyKeys(extractDriverInstallConfig(&baseDriverDaemonSetSpec().Template.Spec))
+	baseDigest := utils.GetObjectHashIgnoreEmptyKeys(driverconfig.DriverInstallStateFromPodSpec(&baseDriverDaemonSetSpec().Template.Spec))
 
 	tests := []struct {
 		name       string
@@ -4963,7 +5140,7 @@ func TestDriverConfigDigest(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			spec := baseDriverDaemonSetSpec()
 			tc.modify(spec)
-			digest := utils.GetObjectHashIgnoreEmptyKeys(extractDriverInstallConfig(&spec.Template.Spec))
+			digest := utils.GetObjectHashIgnoreEmptyKeys(driverconfig.DriverInstallStateFromPodSpec(&spec.Template.Spec))
 			if tc.wantChange {
 				assert.NotEqual(t, baseDigest, digest, "digest SHOULD change")
 			} else {
@@ -4992,7 +5169,7 @@ func TestExtractDriverInstallConfigExtraction(t *testing.T) {
 		}},
 	}
 
-	cfg := extractDriverInstallConfig(&spec.Template.Spec)
+	cfg := driverconfig.DriverInstallStateFromPodSpec(&spec.Template.Spec)
 
 	assert.Equal(t, "nvcr.io/nvidia/driver:525.85.03-ubuntu22.04", cfg.DriverImage)
 	assert.Equal(t, "nvcr.io/nvidia/cloud-native/k8s-driver-manager:v0.6.2", cfg.DriverManagerImage)
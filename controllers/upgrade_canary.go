@@ -0,0 +1,193 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/NVIDIA/k8s-operator-libs/pkg/consts"
+	"github.com/NVIDIA/k8s-operator-libs/pkg/upgrade"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1"
+)
+
+const (
+	// UpgradeCanaryValidatedAnnotation records on a canary node when the upgraded driver
+	// was first found validated on it, which starts the soak period of the canary phase
+	UpgradeCanaryValidatedAnnotation = "nvidia.com/gpu-driver-upgrade-canary.validated-at"
+	// ValidatorAppLabelValue indicates the app label value of the operator validator pods
+	ValidatorAppLabelValue = "nvidia-operator-validator"
+)
+
+// gateCanaryPhase holds back the driver upgrade of the nodes that are not canary nodes
+// until the upgraded driver is validated on every canary node and stayed so for the
+// soak duration. If the upgrade fails on a canary node, the upgrade of every node not
+// started yet is held back until the failure is resolved.
+func (r *UpgradeReconciler) gateCanaryPhase(ctx context.Context, reqLogger logr.Logger, canary *gpuv1.DriverUpgradeCanarySpec, gate *upgradeGate) error {
+	if canary == nil {
+		return nil
+	}
+
+	canaries, err := selectCanaryNodes(canary, gate.state)
+	if err != nil {
+		return err
+	}
+	if len(canaries) == 0 {
+		reqLogger.Info("No node matches the canary node selector, holding back the driver upgrade")
+	}
+
+	validated, err := r.getValidatedNodes(ctx)
+	if err != nil {
+		return err
+	}
+
+	halted := false
+	allValidated := len(canaries) > 0
+	var soakStart time.Time
+	for _, nodeState := range canaries {
+		node := nodeState.Node
+		nodeUpgradeState := node.Labels[upgrade.GetUpgradeStateLabelKey()]
+		if nodeUpgradeState == upgrade.UpgradeStateFailed {
+			halted = true
+		}
+		if nodeUpgradeState != upgrade.UpgradeStateDone || !validated[node.Name] {
+			allValidated = false
+			if err := r.setUpgradeCanaryValidatedAnnotation(ctx, node, nil); err != nil {
+				return err
+			}
+			continue
+		}
+
+		validatedAt, err := time.Parse(time.RFC3339, node.Annotations[UpgradeCanaryValidatedAnnotation])
+		if err != nil {
+			validatedAt = time.Now()
+			if err := r.setUpgradeCanaryValidatedAnnotation(ctx, node, &validatedAt); err != nil {
+				return err
+			}
+		}
+		if validatedAt.After(soakStart) {
+			soakStart = validatedAt
+		}
+	}
+
+	if halted {
+		reqLogger.Error(fmt.Errorf("driver upgrade failed on a canary node"), "Halting the driver upgrade")
+		gate.halted = true
+		gate.hold(func(*corev1.Node) bool { return true })
+		return nil
+	}
+
+	if allValidated {
+		remaining := time.Until(soakStart.Add(canary.GetSoakDuration()))
+		if remaining <= 0 {
+			reqLogger.V(consts.LogLevelInfo).Info("Driver upgrade validated on all canary nodes, upgrading the remaining nodes")
+			return nil
+		}
+		reqLogger.Info("Driver upgrade validated on all canary nodes, waiting for the soak period to end", "remaining", remaining)
+		gate.requeueBy(remaining)
+	}
+
+	isCanary := make(map[string]bool, len(canaries))
+	for _, nodeState := range canaries {
+		isCanary[nodeState.Node.Name] = true
+	}
+	gate.hold(func(node *corev1.Node) bool {
+		return !isCanary[node.Name]
+	})
+	return nil
+}
+
+// selectCanaryNodes returns the states of the canary nodes among the nodes in state
+func selectCanaryNodes(canary *gpuv1.DriverUpgradeCanarySpec, state *upgrade.ClusterUpgradeState) ([]*upgrade.NodeUpgradeState, error) {
+	selector := labels.Everything()
+	if canary.NodeSelector != nil {
+		var err error
+		selector, err = metav1.LabelSelectorAsSelector(canary.NodeSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid canary node selector: %w", err)
+		}
+	}
+
+	var canaries []*upgrade.NodeUpgradeState
+	for _, nodeStates := range state.NodeStates {
+		for _, nodeState := range nodeStates {
+			if selector.Matches(labels.Set(nodeState.Node.Labels)) {
+				canaries = append(canaries, nodeState)
+			}
+		}
+	}
+	sort.Slice(canaries, func(i, j int) bool {
+		return canaries[i].Node.Name < canaries[j].Node.Name
+	})
+
+	if count := canary.GetCount(); count > 0 && len(canaries) > count {
+		canaries = canaries[:count]
+	}
+	return canaries, nil
+}
+
+// getValidatedNodes returns the names of the nodes on which the operator validator
+// pod is ready, that is on which the driver and CUDA validations passed
+func (r *UpgradeReconciler) getValidatedNodes(ctx context.Context) (map[string]bool, error) {
+	podList := &corev1.PodList{}
+	err := r.List(ctx, podList, client.InNamespace(r.OperatorNamespace), client.MatchingLabels{DriverLabelKey: ValidatorAppLabelValue})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list validator pods: %w", err)
+	}
+
+	validated := make(map[string]bool)
+	for _, pod := range podList.Items {
+		for _, cond := range pod.Status.Conditions {
+			if cond.Type == corev1.PodReady && cond.Status == corev1.ConditionTrue {
+				validated[pod.Spec.NodeName] = true
+			}
+		}
+	}
+	return validated, nil
+}
+
+// setUpgradeCanaryValidatedAnnotation sets the canary validated annotation of node to
+// validatedAt, or removes it if validatedAt is nil
+func (r *UpgradeReconciler) setUpgradeCanaryValidatedAnnotation(ctx context.Context, node *corev1.Node, validatedAt *time.Time) error {
+	_, present := node.Annotations[UpgradeCanaryValidatedAnnotation]
+	if validatedAt == nil && !present {
+		return nil
+	}
+
+	patch := client.MergeFrom(node.DeepCopy())
+	if validatedAt == nil {
+		delete(node.Annotations, UpgradeCanaryValidatedAnnotation)
+	} else {
+		if node.Annotations == nil {
+			node.Annotations = map[string]string{}
+		}
+		node.Annotations[UpgradeCanaryValidatedAnnotation] = validatedAt.UTC().Format(time.RFC3339)
+	}
+	if err := r.Patch(ctx, node, patch); err != nil {
+		r.Log.Error(err, "Failed to update canary validated annotation of node", "node", node.Name)
+		return err
+	}
+	return nil
+}
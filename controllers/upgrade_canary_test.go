@@ -0,0 +1,259 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/k8s-operator-libs/pkg/upgrade"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1"
+	nvidiav1alpha1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1alpha1"
+	gpuconsts "github.com/NVIDIA/gpu-operator/internal/consts"
+)
+
+func canaryTestNode(name, upgradeState string, nodeLabels map[string]string) *corev1.Node {
+	// the upgrade state label key depends on the driver name
+	upgrade.SetDriverName("gpu")
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:   name,
+		Labels: map[string]string{upgrade.GetUpgradeStateLabelKey(): upgradeState},
+	}}
+	for key, value := range nodeLabels {
+		node.Labels[key] = value
+	}
+	return node
+}
+
+func validatorPod(nodeName string, ready bool) *corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "nvidia-operator-validator-" + nodeName,
+			Namespace: testOperatorNamespace,
+			Labels:    map[string]string{DriverLabelKey: ValidatorAppLabelValue},
+		},
+		Spec:   corev1.PodSpec{NodeName: nodeName},
+		Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}}},
+	}
+}
+
+func appliedNodeNames(state *upgrade.ClusterUpgradeState, stateKey string) []string {
+	var names []string
+	for _, nodeState := range state.NodeStates[stateKey] {
+		names = append(names, nodeState.Node.Name)
+	}
+	return names
+}
+
+func canaryHaltedMetric(t *testing.T, r *UpgradeReconciler) float64 {
+	t.Helper()
+	m := &dto.Metric{}
+	require.NoError(t, r.OperatorMetrics.upgradeCanaryHalted.Write(m))
+	return m.GetGauge().GetValue()
+}
+
+func TestSelectCanaryNodes(t *testing.T) {
+	rack := map[string]string{"example.com/rack": "canary"}
+	state := upgrade.NewClusterUpgradeState()
+	state.NodeStates[upgrade.UpgradeStateUpgradeRequired] = []*upgrade.NodeUpgradeState{
+		{Node: canaryTestNode("node-c", upgrade.UpgradeStateUpgradeRequired, rack)},
+		{Node: canaryTestNode("node-a", upgrade.UpgradeStateUpgradeRequired, nil)},
+	}
+	state.NodeStates[upgrade.UpgradeStateDone] = []*upgrade.NodeUpgradeState{
+		{Node: canaryTestNode("node-b", upgrade.UpgradeStateDone, rack)},
+	}
+
+	tests := []struct {
+		name     string
+		canary   *gpuv1.DriverUpgradeCanarySpec
+		expected []string
+	}{
+		{
+			name:     "one node by default",
+			canary:   &gpuv1.DriverUpgradeCanarySpec{},
+			expected: []string{"node-a"},
+		},
+		{
+			name:     "first nodes by name",
+			canary:   &gpuv1.DriverUpgradeCanarySpec{Count: 2},
+			expected: []string{"node-a", "node-b"},
+		},
+		{
+			name:     "all nodes matching the selector",
+			canary:   &gpuv1.DriverUpgradeCanarySpec{NodeSelector: &metav1.LabelSelector{MatchLabels: rack}},
+			expected: []string{"node-b", "node-c"},
+		},
+		{
+			name:     "first nodes matching the selector",
+			canary:   &gpuv1.DriverUpgradeCanarySpec{NodeSelector: &metav1.LabelSelector{MatchLabels: rack}, Count: 1},
+			expected: []string{"node-b"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			canaries, err := selectCanaryNodes(tc.canary, &state)
+			require.NoError(t, err)
+			var names []string
+			for _, nodeState := range canaries {
+				names = append(names, nodeState.Node.Name)
+			}
+			assert.Equal(t, tc.expected, names)
+		})
+	}
+}
+
+func TestUpgradeReconcileCanaryPhase(t *testing.T) {
+	canary := &gpuv1.DriverUpgradeCanarySpec{Count: 1, SoakDuration: &metav1.Duration{Duration: time.Hour}}
+
+	t.Run("only canary nodes are upgraded first", func(t *testing.T) {
+		cp := newUpgradeTestClusterPolicy(func(p *gpuv1.DriverUpgradePolicySpec) { p.Canary = canary })
+		r, stateManager := newUpgradeStateTestReconciler(t, []client.Object{cp}, upgradeNodeStates(
+			canaryTestNode("node-a", upgrade.UpgradeStateUpgradeRequired, nil),
+			canaryTestNode("node-b", upgrade.UpgradeStateUpgradeRequired, nil),
+			canaryTestNode("node-c", upgrade.UpgradeStateUpgradeRequired, nil))...)
+
+		result, err := r.Reconcile(t.Context(), upgradeSingletonRequest())
+		require.NoError(t, err)
+		assert.Equal(t, plannedRequeueInterval, result.RequeueAfter)
+
+		require.Len(t, stateManager.appliedStates, 1)
+		assert.Equal(t, []string{"node-a"}, appliedNodeNames(stateManager.appliedStates[0], upgrade.UpgradeStateUpgradeRequired))
+		require.NotNil(t, stateManager.appliedPolicies[0].MaxUnavailable)
+		assert.Equal(t, intstr.Int, stateManager.appliedPolicies[0].MaxUnavailable.Type)
+		assert.Zero(t, canaryHaltedMetric(t, r))
+	})
+
+	t.Run("validated canary starts the soak period", func(t *testing.T) {
+		cp := newUpgradeTestClusterPolicy(func(p *gpuv1.DriverUpgradePolicySpec) { p.Canary = canary })
+		r, stateManager := newUpgradeStateTestReconciler(t, []client.Object{cp, validatorPod("node-a", true)}, upgradeNodeStates(
+			canaryTestNode("node-a", upgrade.UpgradeStateDone, nil),
+			canaryTestNode("node-b", upgrade.UpgradeStateUpgradeRequired, nil))...)
+
+		_, err := r.Reconcile(t.Context(), upgradeSingletonRequest())
+		require.NoError(t, err)
+		assert.Empty(t, appliedNodeNames(stateManager.appliedStates[0], upgrade.UpgradeStateUpgradeRequired))
+
+		node := &corev1.Node{}
+		require.NoError(t, r.Get(t.Context(), types.NamespacedName{Name: "node-a"}, node))
+		validatedAt, err := time.Parse(time.RFC3339, node.Annotations[UpgradeCanaryValidatedAnnotation])
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now(), validatedAt, time.Minute)
+	})
+
+	t.Run("canary that failed validation holds back the upgrade", func(t *testing.T) {
+		cp := newUpgradeTestClusterPolicy(func(p *gpuv1.DriverUpgradePolicySpec) { p.Canary = canary })
+		r, stateManager := newUpgradeStateTestReconciler(t, []client.Object{cp, validatorPod("node-a", false)}, upgradeNodeStates(
+			canaryTestNode("node-a", upgrade.UpgradeStateDone, nil),
+			canaryTestNode("node-b", upgrade.UpgradeStateUpgradeRequired, nil))...)
+
+		_, err := r.Reconcile(t.Context(), upgradeSingletonRequest())
+		require.NoError(t, err)
+		assert.Empty(t, appliedNodeNames(stateManager.appliedStates[0], upgrade.UpgradeStateUpgradeRequired))
+
+		node := &corev1.Node{}
+		require.NoError(t, r.Get(t.Context(), types.NamespacedName{Name: "node-a"}, node))
+		assert.NotContains(t, node.Annotations, UpgradeCanaryValidatedAnnotation)
+	})
+
+	t.Run("soak period in progress", func(t *testing.T) {
+		cp := newUpgradeTestClusterPolicy(func(p *gpuv1.DriverUpgradePolicySpec) { p.Canary = canary })
+		nodeA := canaryTestNode("node-a", upgrade.UpgradeStateDone, nil)
+		nodeA.Annotations = map[string]string{
+			UpgradeCanaryValidatedAnnotation: time.Now().Add(-time.Hour + time.Minute).UTC().Format(time.RFC3339),
+		}
+		r, stateManager := newUpgradeStateTestReconciler(t, []client.Object{cp, validatorPod("node-a", true)}, upgradeNodeStates(
+			nodeA,
+			canaryTestNode("node-b", upgrade.UpgradeStateUpgradeRequired, nil))...)
+
+		result, err := r.Reconcile(t.Context(), upgradeSingletonRequest())
+		require.NoError(t, err)
+		assert.Empty(t, appliedNodeNames(stateManager.appliedStates[0], upgrade.UpgradeStateUpgradeRequired))
+		assert.Less(t, result.RequeueAfter, plannedRequeueInterval)
+	})
+
+	t.Run("rest of the nodes are upgraded after the soak period", func(t *testing.T) {
+		cp := newUpgradeTestClusterPolicy(func(p *gpuv1.DriverUpgradePolicySpec) { p.Canary = canary })
+		nodeA := canaryTestNode("node-a", upgrade.UpgradeStateDone, nil)
+		nodeA.Annotations = map[string]string{
+			UpgradeCanaryValidatedAnnotation: time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339),
+		}
+		r, stateManager := newUpgradeStateTestReconciler(t, []client.Object{cp, validatorPod("node-a", true)}, upgradeNodeStates(
+			nodeA,
+			canaryTestNode("node-b", upgrade.UpgradeStateUpgradeRequired, nil))...)
+
+		result, err := r.Reconcile(t.Context(), upgradeSingletonRequest())
+		require.NoError(t, err)
+		assert.Equal(t, plannedRequeueInterval, result.RequeueAfter)
+		assert.Equal(t, []string{"node-b"}, appliedNodeNames(stateManager.appliedStates[0], upgrade.UpgradeStateUpgradeRequired))
+		assert.Nil(t, stateManager.appliedPolicies[0].MaxUnavailable)
+	})
+
+	t.Run("failed canary halts the upgrade", func(t *testing.T) {
+		cp := newUpgradeTestClusterPolicy(func(p *gpuv1.DriverUpgradePolicySpec) {
+			p.Canary = &gpuv1.DriverUpgradeCanarySpec{Count: 2}
+		})
+		r, stateManager := newUpgradeStateTestReconciler(t, []client.Object{cp}, upgradeNodeStates(
+			canaryTestNode("node-a", upgrade.UpgradeStateFailed, nil),
+			canaryTestNode("node-b", upgrade.UpgradeStateUpgradeRequired, nil),
+			canaryTestNode("node-c", upgrade.UpgradeStateUpgradeRequired, nil))...)
+
+		_, err := r.Reconcile(t.Context(), upgradeSingletonRequest())
+		require.NoError(t, err)
+		assert.Empty(t, appliedNodeNames(stateManager.appliedStates[0], upgrade.UpgradeStateUpgradeRequired))
+		assert.Equal(t, []string{"node-a"}, appliedNodeNames(stateManager.appliedStates[0], upgrade.UpgradeStateFailed))
+		assert.EqualValues(t, upgradeCanaryHalted, canaryHaltedMetric(t, r))
+	})
+}
+
+func TestUpgradeReconcileNVIDIADriverCanaryPhase(t *testing.T) {
+	canaryLabels := map[string]string{"example.com/canary": "true"}
+	nvd := &nvidiav1alpha1.NVIDIADriver{
+		ObjectMeta: metav1.ObjectMeta{Name: "gpu-driver"},
+		Spec: nvidiav1alpha1.NVIDIADriverSpec{
+			UpgradePolicy: &nvidiav1alpha1.DriverUpgradePolicySpec{
+				AutoUpgrade: true,
+				Canary:      &nvidiav1alpha1.DriverUpgradeCanarySpec{NodeSelector: &metav1.LabelSelector{MatchLabels: canaryLabels}},
+			},
+		},
+	}
+	owned := map[string]string{gpuconsts.NVIDIADriverOwnerLabel: nvd.Name}
+	nodeA := canaryTestNode("node-a", upgrade.UpgradeStateUpgradeRequired, owned)
+	nodeZ := canaryTestNode("node-z", upgrade.UpgradeStateUpgradeRequired, owned)
+	for key, value := range canaryLabels {
+		nodeZ.Labels[key] = value
+	}
+	r, stateManager := newUpgradeStateTestReconciler(t, []client.Object{nvd}, upgradeNodeStates(nodeA, nodeZ)...)
+
+	_, err := r.Reconcile(t.Context(), upgradeSingletonRequest())
+	require.NoError(t, err)
+	require.Len(t, stateManager.appliedStates, 1)
+	assert.Equal(t, []string{"node-z"}, appliedNodeNames(stateManager.appliedStates[0], upgrade.UpgradeStateUpgradeRequired))
+}
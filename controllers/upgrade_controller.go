@@ -19,6 +19,7 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -26,6 +27,7 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/events"
 	"k8s.io/client-go/util/workqueue"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -46,6 +48,8 @@ import (
 	gpuv1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1"
 	nvidiav1alpha1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1alpha1"
 	gpuconsts "github.com/NVIDIA/gpu-operator/internal/consts"
+	"github.com/NVIDIA/gpu-operator/internal/image"
+	"github.com/NVIDIA/gpu-operator/internal/pause"
 )
 
 // UpgradeReconciler reconciles Driver Daemon Sets for upgrade
@@ -56,12 +60,25 @@ type UpgradeReconciler struct {
 	StateManager      upgrade.ClusterUpgradeStateManager
 	OperatorMetrics   *OperatorMetrics
 	OperatorNamespace string
+	// APIReader reads objects out of the namespaces cached by the client, e.g. the pods
+	// listed when planning driver upgrades
+	APIReader client.Reader
+	// GPUPodFilter returns true for the pods using GPUs
+	GPUPodFilter func(pod corev1.Pod) bool
+
+	recorder events.EventRecorder
+	// webhookCalls are the driver upgrade webhook calls running in the background, by
+	// stage and node
+	webhookCalls sync.Map
 }
 
 const (
 	// upgradeControllerSingletonName is the request name every watch enqueues; the
 	// reconciler resolves the active configuration source itself.
 	upgradeControllerSingletonName = "driver-upgrade"
+	// driverStateName is the component name under which the reconcile-paused
+	// annotation pauses driver upgrades along with the driver state
+	driverStateName = "state-driver"
 
 	plannedRequeueInterval = time.Minute * 2
 	// DriverLabelKey indicates pod label key of the driver
@@ -79,10 +96,14 @@ const (
 //nolint
 // +kubebuilder:rbac:groups=mellanox.com,resources=*,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch;update;patch
-// +kubebuilder:rbac:groups="",resources=pods,verbs=list
+// +kubebuilder:rbac:groups="",resources=pods,verbs=list;delete
+// +kubebuilder:rbac:groups="",resources=pods/eviction,verbs=create
 // +kubebuilder:rbac:groups=resource.k8s.io,resources=resourceclaims,verbs=get;list;watch
 // +kubebuilder:rbac:groups=apps,resources=deployments;daemonsets;replicasets;statefulsets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps,resources=deployments/finalizers,verbs=update
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups=nvidia.com,resources=nodeupgradehistories,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=nvidia.com,resources=nodeupgradehistories/status,verbs=get;update;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -109,6 +130,9 @@ func (r *UpgradeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		reqLogger.V(consts.LogLevelInfo).Info("Advanced driver upgrade policy is not supported when 'sandboxWorkloads.enabled=true'" +
 			"in ClusterPolicy, cleaning up upgrade state and skipping reconciliation")
 		r.OperatorMetrics.driverAutoUpgradeEnabled.Set(driverAutoUpgradeDisabled)
+		if pause.IsPaused(clusterPolicy, driverStateName) {
+			return ctrl.Result{}, nil
+		}
 		return ctrl.Result{}, r.removeNodeUpgradeStateLabels(ctx)
 	}
 
@@ -118,13 +142,22 @@ func (r *UpgradeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 // reconcileClusterPolicyDriverUpgrades handles driver upgrade reconciliation when the
 // ClusterPolicy CR is used for driver management.
 func (r *UpgradeReconciler) reconcileClusterPolicyDriverUpgrades(ctx context.Context, reqLogger logr.Logger, clusterPolicy *gpuv1.ClusterPolicy) (ctrl.Result, error) {
-	if clusterPolicy.Spec.Driver.UpgradePolicy == nil ||
-		!clusterPolicy.Spec.Driver.UpgradePolicy.AutoUpgrade {
+	planned := clusterPolicy.Spec.Driver.IsUpgradePlanEnabled()
+	if !planned && (clusterPolicy.Spec.Driver.UpgradePolicy == nil ||
+		!clusterPolicy.Spec.Driver.UpgradePolicy.AutoUpgrade) {
 		reqLogger.V(consts.LogLevelInfo).Info("Advanced driver upgrade policy is disabled, cleaning up upgrade state and skipping reconciliation")
 		r.OperatorMetrics.driverAutoUpgradeEnabled.Set(driverAutoUpgradeDisabled)
+		r.clearClusterPolicyUpgradeStatus(ctx, clusterPolicy)
+		if pause.IsPaused(clusterPolicy, driverStateName) {
+			return ctrl.Result{}, nil
+		}
 		return ctrl.Result{}, r.removeNodeUpgradeStateLabels(ctx)
 	}
-	r.OperatorMetrics.driverAutoUpgradeEnabled.Set(driverAutoUpgradeEnabled)
+	if planned {
+		r.OperatorMetrics.driverAutoUpgradeEnabled.Set(driverAutoUpgradeDisabled)
+	} else {
+		r.OperatorMetrics.driverAutoUpgradeEnabled.Set(driverAutoUpgradeEnabled)
+	}
 
 	var driverLabel map[string]string
 
@@ -176,6 +209,21 @@ func (r *UpgradeReconciler) reconcileClusterPolicyDriverUpgrades(ctx context.Con
 			fmt.Sprintf("%s,%s", clusterPolicy.Spec.Driver.UpgradePolicy.DrainSpec.PodSelector, UpgradeSkipDrainLabelSelector)
 	}
 
+	if planned {
+		reqLogger.Info("Driver upgrade plan mode is enabled, planning the upgrade without upgrading any node")
+		plan, err := r.planDriverUpgrade(ctx, state, &clusterPolicy.Spec.Driver.UpgradePolicy.DriverUpgradePolicySpec,
+			clusterPolicy.Spec.Driver.GetCanary(), clusterPolicy.Spec.Driver.GetUpgradeTopology(), maxUnavailable)
+		if err != nil {
+			r.Log.Error(err, "Failed to plan the driver upgrade")
+			return ctrl.Result{}, err
+		}
+		status := driverUpgradeStatusOf(clusterPolicy.Status.Upgrade)
+		setDriverUpgradePlan(status, plan, metav1.Now())
+		clearDriverUpgradeProgress(status)
+		r.updateClusterPolicyUpgradeStatus(ctx, clusterPolicy.Name, status)
+		return ctrl.Result{Requeue: true, RequeueAfter: plannedRequeueInterval}, nil
+	}
+
 	// log metrics with the current state
 	r.OperatorMetrics.upgradesInProgress.Set(float64(r.StateManager.GetUpgradesInProgress(state)))
 	r.OperatorMetrics.upgradesDone.Set(float64(r.StateManager.GetUpgradesDone(state)))
@@ -183,7 +231,81 @@ func (r *UpgradeReconciler) reconcileClusterPolicyDriverUpgrades(ctx context.Con
 	r.OperatorMetrics.upgradesFailed.Set(float64(r.StateManager.GetUpgradesFailed(state)))
 	r.OperatorMetrics.upgradesPending.Set(float64(r.StateManager.GetUpgradesPending(state)))
 
-	err = r.StateManager.ApplyState(ctx, state, clusterPolicy.Spec.Driver.UpgradePolicy)
+	if pause.IsPaused(clusterPolicy, driverStateName) {
+		reqLogger.Info("Reconciliation of the driver is paused, skipping upgrade state changes")
+		return ctrl.Result{Requeue: true, RequeueAfter: plannedRequeueInterval}, nil
+	}
+
+	owner := fmt.Sprintf("%s/%s", gpuv1.ClusterPolicyCRDName, clusterPolicy.Name)
+	status := driverUpgradeStatusOf(clusterPolicy.Status.Upgrade)
+	status.Plan = nil
+	if err := r.setDriverUpgradeProgress(ctx, status, state, clusterPolicy.Spec.Driver.Version, time.Now()); err != nil {
+		r.Log.Error(err, "Failed to get the progress of the driver upgrade")
+		return ctrl.Result{}, err
+	}
+	imagePath, err := gpuv1.ImagePath(&clusterPolicy.Spec.Driver)
+	if err != nil {
+		reqLogger.V(consts.LogLevelWarning).Info("Failed to get the driver image path", "error", err)
+	}
+	rolledBack, err := r.reconcileDriverRollback(ctx, reqLogger, &driverRollback{
+		obj:  clusterPolicy,
+		spec: clusterPolicy.Spec.Driver.GetRollback(),
+		current: gpuv1.DriverRevision{
+			Repository: clusterPolicy.Spec.Driver.Repository,
+			Image:      clusterPolicy.Spec.Driver.Image,
+			Version:    clusterPolicy.Spec.Driver.Version,
+		},
+		imagePath: imagePath,
+		setImage: func(revision *gpuv1.DriverRevision) {
+			clusterPolicy.Spec.Driver.Repository = revision.Repository
+			clusterPolicy.Spec.Driver.Image = revision.Image
+			clusterPolicy.Spec.Driver.Version = revision.Version
+		},
+	}, state, totalNodes, status)
+	if err != nil {
+		r.Log.Error(err, "Failed to check the rollback of the driver upgrade")
+		return ctrl.Result{}, err
+	}
+	if rolledBack {
+		// The driver DaemonSets are about to be rolled back, do not start upgrading any
+		// node to the failed driver in the meantime
+		r.updateClusterPolicyUpgradeStatus(ctx, clusterPolicy.Name, status)
+		r.recordNodeUpgradeHistory(ctx, owner, state)
+		return ctrl.Result{Requeue: true, RequeueAfter: plannedRequeueInterval}, nil
+	}
+
+	gate := newUpgradeGate(state, &clusterPolicy.Spec.Driver.UpgradePolicy.DriverUpgradePolicySpec, maxUnavailable)
+	if err := r.gateCanaryPhase(ctx, reqLogger, clusterPolicy.Spec.Driver.GetCanary(), gate); err != nil {
+		r.Log.Error(err, "Failed to check the canary phase of the driver upgrade")
+		return ctrl.Result{}, err
+	}
+	r.setUpgradeCanaryHaltedMetric(gate.halted)
+
+	nextWindow, err := gateMaintenanceWindows(reqLogger, clusterPolicy.Spec.Driver.GetMaintenanceWindows(), time.Now(), gate)
+	if err != nil {
+		r.Log.Error(err, "Failed to check the maintenance windows of the driver upgrade")
+		return ctrl.Result{}, err
+	}
+	r.OperatorMetrics.upgradeMaintenanceWindowStart.Reset()
+	r.OperatorMetrics.upgradeMaintenanceWindowEnd.Reset()
+	r.setMaintenanceWindowMetrics(gpuv1.ClusterPolicyCRDName, clusterPolicy.Name, nextWindow)
+
+	gateTopology(reqLogger, clusterPolicy.Spec.Driver.GetUpgradeTopology(), gate)
+
+	if err := r.gateUpgradeHooks(ctx, reqLogger, owner, clusterPolicy, clusterPolicy.Spec.Driver.GetHooks(), gate); err != nil {
+		r.Log.Error(err, "Failed to run the hooks of the driver upgrade")
+		return ctrl.Result{}, err
+	}
+	if err := r.gateGPUWorkloads(ctx, reqLogger, clusterPolicy.Spec.Driver.GetUpgradeGPUWorkloadWait(), gate); err != nil {
+		r.Log.Error(err, "Failed to wait for the GPU workloads of the driver upgrade")
+		return ctrl.Result{}, err
+	}
+	status.NextMaintenanceWindow = maintenanceWindowStatus(nextWindow)
+	r.updateClusterPolicyUpgradeStatus(ctx, clusterPolicy.Name, status)
+
+	err = r.StateManager.ApplyState(ctx, gate.state, gate.policy)
+	// The nodes of state carry the upgrade state applied, even if applying failed midway
+	r.recordNodeUpgradeHistory(ctx, owner, state)
 	if err != nil {
 		r.Log.Error(err, "Failed to apply cluster upgrade state")
 		return ctrl.Result{}, err
@@ -193,7 +315,7 @@ func (r *UpgradeReconciler) reconcileClusterPolicyDriverUpgrades(ctx context.Con
 	// might become stuck until the new reconcile loop is scheduled.
 	// Since node/ds/clusterpolicy updates from outside of the upgrade flow
 	// are not guaranteed, for safety reconcile loop should be requeued every few minutes.
-	return ctrl.Result{Requeue: true, RequeueAfter: plannedRequeueInterval}, nil
+	return ctrl.Result{Requeue: true, RequeueAfter: gate.requeueAfter}, nil
 }
 
 // reconcileNVIDIADriverUpgrades handles driver upgrade reconciliation when the NVIDIADriver CRD
@@ -202,6 +324,9 @@ func (r *UpgradeReconciler) reconcileNVIDIADriverUpgrades(ctx context.Context, r
 	var (
 		upgradesInProgress, upgradesDone, upgradesAvailable, upgradesFailed, upgradesPending int
 	)
+	canaryHalted := false
+	requeueAfter := plannedRequeueInterval
+	now := time.Now()
 
 	nvidiaDriverList := &nvidiav1alpha1.NVIDIADriverList{}
 	if err := r.List(ctx, nvidiaDriverList); err != nil {
@@ -209,22 +334,33 @@ func (r *UpgradeReconciler) reconcileNVIDIADriverUpgrades(ctx context.Context, r
 	}
 
 	// Check if all NVIDIADriver instances have disabled automatic upgrades
-	noAutoUpgradesEnabled := true
+	noAutoUpgradesEnabled, noUpgradesPlanned := true, true
 	for _, nvd := range nvidiaDriverList.Items {
 		upgradePolicy := nvd.Spec.GetUpgradePolicyWithDefaults()
-		if upgradePolicy.AutoUpgrade {
+		if upgradePolicy.AutoUpgrade && !nvd.Spec.IsUpgradePlanEnabled() {
 			noAutoUpgradesEnabled = false
-			break
+		}
+		if nvd.Spec.IsUpgradePlanEnabled() {
+			noUpgradesPlanned = false
 		}
 	}
 
-	if noAutoUpgradesEnabled {
+	if noAutoUpgradesEnabled && noUpgradesPlanned {
 		reqLogger.V(consts.LogLevelInfo).Info("No NVIDIADriver instance has upgrade policy enabled, cleaning up upgrade state and skipping reconciliation")
 		r.OperatorMetrics.driverAutoUpgradeEnabled.Set(driverAutoUpgradeDisabled)
-		return ctrl.Result{}, r.removeNodeUpgradeStateLabels(ctx)
+		for _, nvd := range nvidiaDriverList.Items {
+			r.clearNVIDIADriverUpgradeStatus(ctx, &nvd)
+		}
+		return ctrl.Result{}, r.removeNodeUpgradeStateLabelsForUnpausedNVDs(ctx, nvidiaDriverList.Items)
 	}
 
-	r.OperatorMetrics.driverAutoUpgradeEnabled.Set(driverAutoUpgradeEnabled)
+	if noAutoUpgradesEnabled {
+		r.OperatorMetrics.driverAutoUpgradeEnabled.Set(driverAutoUpgradeDisabled)
+	} else {
+		r.OperatorMetrics.driverAutoUpgradeEnabled.Set(driverAutoUpgradeEnabled)
+	}
+	r.OperatorMetrics.upgradeMaintenanceWindowStart.Reset()
+	r.OperatorMetrics.upgradeMaintenanceWindowEnd.Reset()
 
 	// Build a cluster-wide upgrade state using only the component label so that ALL
 	// driver pods are captured, including orphaned pods (e.g. pods left over from a
@@ -256,7 +392,13 @@ func (r *UpgradeReconciler) reconcileNVIDIADriverUpgrades(ctx context.Context, r
 	// Apply the upgrade policy for each NVIDIADriver instance using its partitioned cluster upgrade state
 	for _, nvd := range nvidiaDriverList.Items {
 		upgradePolicy := nvd.Spec.GetUpgradePolicyWithDefaults()
-		if !upgradePolicy.AutoUpgrade {
+		paused := pause.IsPaused(&nvd, driverStateName)
+		planned := nvd.Spec.IsUpgradePlanEnabled()
+		if !upgradePolicy.AutoUpgrade && !planned {
+			r.clearNVIDIADriverUpgradeStatus(ctx, &nvd)
+			if paused {
+				continue
+			}
 			reqLogger.V(consts.LogLevelInfo).Info("Auto upgrade is disabled for NVIDIADriver, cleaning up upgrade state for nodes it manages",
 				"name", nvd.Name)
 			if err := r.removeNodeUpgradeStateLabelsForNVD(ctx, nvd.Name); err != nil {
@@ -268,7 +410,13 @@ func (r *UpgradeReconciler) reconcileNVIDIADriverUpgrades(ctx context.Context, r
 
 		state, ok := statesByNVD[nvd.Name]
 		if !ok {
-			continue
+			if !planned {
+				r.clearNVIDIADriverUpgradeStatus(ctx, &nvd)
+				continue
+			}
+			// The plan of an NVIDIADriver managing no node is empty
+			emptyState := upgrade.NewClusterUpgradeState()
+			state = &emptyState
 		}
 
 		reqLogger.V(consts.LogLevelDebug).Info("Current cluster upgrade state for NVIDIADriver",
@@ -281,12 +429,6 @@ func (r *UpgradeReconciler) reconcileNVIDIADriverUpgrades(ctx context.Context, r
 			return ctrl.Result{}, err
 		}
 
-		upgradesInProgress += r.StateManager.GetUpgradesInProgress(state)
-		upgradesDone += r.StateManager.GetUpgradesDone(state)
-		upgradesAvailable += r.StateManager.GetUpgradesAvailable(state, upgradePolicy.MaxParallelUpgrades, maxUnavailable)
-		upgradesFailed += r.StateManager.GetUpgradesFailed(state)
-		upgradesPending += r.StateManager.GetUpgradesPending(state)
-
 		// We want to skip the operator itself during the drain because the upgrade process might hang
 		// if the operator is evicted and can't be rescheduled to any other node, e.g. in a single-node cluster.
 		// It's safe to do because the goal of the node draining during the upgrade is to
@@ -297,8 +439,99 @@ func (r *UpgradeReconciler) reconcileNVIDIADriverUpgrades(ctx context.Context, r
 			upgradePolicy.DrainSpec.PodSelector = fmt.Sprintf("%s,%s", upgradePolicy.DrainSpec.PodSelector, UpgradeSkipDrainLabelSelector)
 		}
 
+		if planned {
+			reqLogger.Info("Driver upgrade plan mode is enabled for NVIDIADriver, planning the upgrade without upgrading any node", "name", nvd.Name)
+			plan, err := r.planDriverUpgrade(ctx, state, upgradePolicy, nvd.Spec.GetUpgradeCanary(), nvd.Spec.GetUpgradeTopology(), maxUnavailable)
+			if err != nil {
+				r.Log.Error(err, "Failed to plan the driver upgrade for NVIDIADriver", "name", nvd.Name)
+				return ctrl.Result{}, err
+			}
+			status := driverUpgradeStatusOf(nvd.Status.Upgrade)
+			setDriverUpgradePlan(status, plan, metav1.NewTime(now))
+			clearDriverUpgradeProgress(status)
+			r.updateNVIDIADriverUpgradeStatus(ctx, nvd.Name, status)
+			continue
+		}
+
+		upgradesInProgress += r.StateManager.GetUpgradesInProgress(state)
+		upgradesDone += r.StateManager.GetUpgradesDone(state)
+		upgradesAvailable += r.StateManager.GetUpgradesAvailable(state, upgradePolicy.MaxParallelUpgrades, maxUnavailable)
+		upgradesFailed += r.StateManager.GetUpgradesFailed(state)
+		upgradesPending += r.StateManager.GetUpgradesPending(state)
+
+		if paused {
+			reqLogger.Info("Reconciliation of the driver is paused, skipping upgrade state changes for NVIDIADriver", "name", nvd.Name)
+			continue
+		}
+
+		owner := fmt.Sprintf("%s/%s", nvidiav1alpha1.NVIDIADriverCRDName, nvd.Name)
+		status := driverUpgradeStatusOf(nvd.Status.Upgrade)
+		status.Plan = nil
+		if err := r.setDriverUpgradeProgress(ctx, status, state, nvd.Spec.Version, now); err != nil {
+			r.Log.Error(err, "Failed to get the progress of the driver upgrade for NVIDIADriver", "name", nvd.Name)
+			return ctrl.Result{}, err
+		}
+		imagePath, err := image.ImagePath(nvd.Spec.Repository, nvd.Spec.Image, nvd.Spec.Version, "")
+		if err != nil {
+			reqLogger.V(consts.LogLevelWarning).Info("Failed to get the driver image path of NVIDIADriver", "name", nvd.Name, "error", err)
+		}
+		rolledBack, err := r.reconcileDriverRollback(ctx, reqLogger.WithValues("name", nvd.Name), &driverRollback{
+			obj:  &nvd,
+			spec: nvd.Spec.GetUpgradeRollback(),
+			current: gpuv1.DriverRevision{
+				Repository: nvd.Spec.Repository,
+				Image:      nvd.Spec.Image,
+				Version:    nvd.Spec.Version,
+			},
+			imagePath: imagePath,
+			setImage: func(revision *gpuv1.DriverRevision) {
+				nvd.Spec.Repository = revision.Repository
+				nvd.Spec.Image = revision.Image
+				nvd.Spec.Version = revision.Version
+			},
+		}, state, totalNodes, status)
+		if err != nil {
+			r.Log.Error(err, "Failed to check the rollback of the driver upgrade for NVIDIADriver", "name", nvd.Name)
+			return ctrl.Result{}, err
+		}
+		if rolledBack {
+			r.updateNVIDIADriverUpgradeStatus(ctx, nvd.Name, status)
+			r.recordNodeUpgradeHistory(ctx, owner, state)
+			continue
+		}
+
+		gate := newUpgradeGate(state, upgradePolicy, maxUnavailable)
+		if err := r.gateCanaryPhase(ctx, reqLogger.WithValues("name", nvd.Name), nvd.Spec.GetUpgradeCanary(), gate); err != nil {
+			r.Log.Error(err, "Failed to check the canary phase of the driver upgrade for NVIDIADriver", "name", nvd.Name)
+			return ctrl.Result{}, err
+		}
+		canaryHalted = canaryHalted || gate.halted
+
+		nextWindow, err := gateMaintenanceWindows(reqLogger.WithValues("name", nvd.Name), nvd.Spec.GetUpgradeMaintenanceWindows(), now, gate)
+		if err != nil {
+			r.Log.Error(err, "Failed to check the maintenance windows of the driver upgrade for NVIDIADriver", "name", nvd.Name)
+			return ctrl.Result{}, err
+		}
+		r.setMaintenanceWindowMetrics(nvidiav1alpha1.NVIDIADriverCRDName, nvd.Name, nextWindow)
+
+		gateTopology(reqLogger.WithValues("name", nvd.Name), nvd.Spec.GetUpgradeTopology(), gate)
+
+		if err := r.gateUpgradeHooks(ctx, reqLogger.WithValues("name", nvd.Name), owner, &nvd, nvd.Spec.GetUpgradeHooks(), gate); err != nil {
+			r.Log.Error(err, "Failed to run the hooks of the driver upgrade for NVIDIADriver", "name", nvd.Name)
+			return ctrl.Result{}, err
+		}
+		if err := r.gateGPUWorkloads(ctx, reqLogger.WithValues("name", nvd.Name), nvd.Spec.GetUpgradeGPUWorkloadWait(), gate); err != nil {
+			r.Log.Error(err, "Failed to wait for the GPU workloads of the driver upgrade for NVIDIADriver", "name", nvd.Name)
+			return ctrl.Result{}, err
+		}
+		status.NextMaintenanceWindow = maintenanceWindowStatus(nextWindow)
+		r.updateNVIDIADriverUpgradeStatus(ctx, nvd.Name, status)
+		requeueAfter = min(requeueAfter, gate.requeueAfter)
+
 		reqLogger.Info("Applying upgrade policy for NVIDIADriver", "name", nvd.Name)
-		if err := r.StateManager.ApplyState(ctx, state, upgradePolicy); err != nil {
+		err = r.StateManager.ApplyState(ctx, gate.state, gate.policy)
+		r.recordNodeUpgradeHistory(ctx, owner, state)
+		if err != nil {
 			r.Log.Error(err, "Failed to apply cluster upgrade state for NVIDIADriver", "name", nvd.Name)
 			return ctrl.Result{}, err
 		}
@@ -311,12 +544,21 @@ func (r *UpgradeReconciler) reconcileNVIDIADriverUpgrades(ctx context.Context, r
 	r.OperatorMetrics.upgradesAvailable.Set(float64(upgradesAvailable))
 	r.OperatorMetrics.upgradesFailed.Set(float64(upgradesFailed))
 	r.OperatorMetrics.upgradesPending.Set(float64(upgradesPending))
+	r.setUpgradeCanaryHaltedMetric(canaryHalted)
 
 	// In some cases if node state changes fail to apply, upgrade process
 	// might become stuck until the new reconcile loop is scheduled.
 	// Since node/ds/clusterpolicy updates from outside of the upgrade flow
 	// are not guaranteed, for safety reconcile loop should be requeued every few minutes.
-	return ctrl.Result{Requeue: true, RequeueAfter: plannedRequeueInterval}, nil
+	return ctrl.Result{Requeue: true, RequeueAfter: requeueAfter}, nil
+}
+
+func (r *UpgradeReconciler) setUpgradeCanaryHaltedMetric(halted bool) {
+	if halted {
+		r.OperatorMetrics.upgradeCanaryHalted.Set(upgradeCanaryHalted)
+		return
+	}
+	r.OperatorMetrics.upgradeCanaryHalted.Set(upgradeCanaryNotHalted)
 }
 
 // removeNodeUpgradeStateLabels loops over nodes in the cluster and removes "nvidia.com/gpu-driver-upgrade-state"
@@ -349,6 +591,32 @@ func (r *UpgradeReconciler) removeNodeUpgradeStateLabels(ctx context.Context) er
 	return nil
 }
 
+// removeNodeUpgradeStateLabelsForUnpausedNVDs removes the upgrade-state label from all nodes,
+// unless the reconciliation of the driver is paused on one of the NVIDIADriver instances.
+// In that case only the nodes owned by the other instances are cleaned up.
+func (r *UpgradeReconciler) removeNodeUpgradeStateLabelsForUnpausedNVDs(ctx context.Context, nvds []nvidiav1alpha1.NVIDIADriver) error {
+	anyPaused := false
+	for i := range nvds {
+		if pause.IsPaused(&nvds[i], driverStateName) {
+			anyPaused = true
+			break
+		}
+	}
+	if !anyPaused {
+		return r.removeNodeUpgradeStateLabels(ctx)
+	}
+
+	for i := range nvds {
+		if pause.IsPaused(&nvds[i], driverStateName) {
+			continue
+		}
+		if err := r.removeNodeUpgradeStateLabelsForNVD(ctx, nvds[i].Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // removeNodeUpgradeStateLabelsForNVD removes the upgrade-state label from all nodes owned by
 // the given NVIDIADriver CR. It is used for cleanup when autoUpgrade is disabled for that CR.
 func (r *UpgradeReconciler) removeNodeUpgradeStateLabelsForNVD(ctx context.Context, nvdName string) error {
@@ -380,6 +648,8 @@ func (r *UpgradeReconciler) removeNodeUpgradeStateLabelsForNVD(ctx context.Conte
 //
 //nolint:dupl
 func (r *UpgradeReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
+	r.recorder = mgr.GetEventRecorder("nvidia-gpu-operator")
+
 	// Create a new controller
 	c, err := controller.New("upgrade-controller", mgr, controller.Options{Reconciler: r, MaxConcurrentReconciles: 1,
 		RateLimiter: workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](minDelayCR, maxDelayCR)})
@@ -399,7 +669,10 @@ func (r *UpgradeReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manag
 		mgr.GetCache(),
 		&gpuv1.ClusterPolicy{},
 		handler.TypedEnqueueRequestsFromMapFunc(cpMapFn),
-		predicate.TypedGenerationChangedPredicate[*gpuv1.ClusterPolicy]{}),
+		predicate.Or[*gpuv1.ClusterPolicy](
+			predicate.TypedGenerationChangedPredicate[*gpuv1.ClusterPolicy]{},
+			pause.AnnotationChangedPredicate[*gpuv1.ClusterPolicy](),
+		)),
 	)
 	if err != nil {
 		return err
@@ -413,7 +686,10 @@ func (r *UpgradeReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manag
 		mgr.GetCache(),
 		&nvidiav1alpha1.NVIDIADriver{},
 		handler.TypedEnqueueRequestsFromMapFunc(nvdMapFn),
-		predicate.TypedGenerationChangedPredicate[*nvidiav1alpha1.NVIDIADriver]{}),
+		predicate.Or[*nvidiav1alpha1.NVIDIADriver](
+			predicate.TypedGenerationChangedPredicate[*nvidiav1alpha1.NVIDIADriver]{},
+			pause.AnnotationChangedPredicate[*nvidiav1alpha1.NVIDIADriver](),
+		)),
 	)
 	if err != nil {
 		return err
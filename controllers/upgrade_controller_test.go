@@ -25,10 +25,13 @@ import (
 	promcli "github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -128,6 +131,7 @@ type fakeUpgradeStateManager struct {
 	buildNamespace  string
 	buildLabels     map[string]string
 	applyCalls      int
+	appliedStates   []*upgrade.ClusterUpgradeState
 	appliedPolicies []*upgrade_v1alpha1.DriverUpgradePolicySpec
 }
 
@@ -163,8 +167,9 @@ func (f *fakeUpgradeStateManager) BuildState(_ context.Context, namespace string
 	return &f.state, nil
 }
 
-func (f *fakeUpgradeStateManager) ApplyState(_ context.Context, _ *upgrade.ClusterUpgradeState, upgradePolicy *upgrade_v1alpha1.DriverUpgradePolicySpec) error {
+func (f *fakeUpgradeStateManager) ApplyState(_ context.Context, state *upgrade.ClusterUpgradeState, upgradePolicy *upgrade_v1alpha1.DriverUpgradePolicySpec) error {
 	f.applyCalls++
+	f.appliedStates = append(f.appliedStates, state)
 	f.appliedPolicies = append(f.appliedPolicies, upgradePolicy)
 	return nil
 }
@@ -183,6 +188,10 @@ func newTestOperatorMetrics() *OperatorMetrics {
 		upgradesAvailable:        newGauge("test_upgrades_available"),
 		upgradesFailed:           newGauge("test_upgrades_failed"),
 		upgradesPending:          newGauge("test_upgrades_pending"),
+		upgradeCanaryHalted:      newGauge("test_upgrade_canary_halted"),
+
+		upgradeMaintenanceWindowStart: promcli.NewGaugeVec(promcli.GaugeOpts{Name: "test_upgrade_maintenance_window_start"}, []string{"kind", "name"}),
+		upgradeMaintenanceWindowEnd:   promcli.NewGaugeVec(promcli.GaugeOpts{Name: "test_upgrade_maintenance_window_end"}, []string{"kind", "name"}),
 	}
 }
 
@@ -196,15 +205,84 @@ func newTestUpgradeReconciler(t *testing.T, objs ...client.Object) (*UpgradeReco
 	require.NoError(t, gpuv1.AddToScheme(scheme))
 	require.NoError(t, nvidiav1alpha1.AddToScheme(scheme))
 	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, batchv1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
 
 	stateManager := &fakeUpgradeStateManager{state: upgrade.NewClusterUpgradeState()}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&gpuv1.ClusterPolicy{}, &nvidiav1alpha1.NVIDIADriver{}, &nvidiav1alpha1.NodeUpgradeHistory{}).
+		WithIndex(&corev1.Pod{}, podNodeNameField, func(obj client.Object) []string {
+			return []string{obj.(*corev1.Pod).Spec.NodeName}
+		}).
+		Build()
 	r := &UpgradeReconciler{
-		Client:            fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+		Client:            c,
 		Log:               logr.Discard(),
 		Scheme:            scheme,
 		StateManager:      stateManager,
 		OperatorMetrics:   newTestOperatorMetrics(),
 		OperatorNamespace: testOperatorNamespace,
+		APIReader:         c,
+		GPUPodFilter: func(pod corev1.Pod) bool {
+			for _, container := range pod.Spec.Containers {
+				if _, ok := container.Resources.Limits["nvidia.com/gpu"]; ok {
+					return true
+				}
+			}
+			return false
+		},
+		recorder: events.NewFakeRecorder(100),
+	}
+	return r, stateManager
+}
+
+const (
+	goodDriverVersion = "570.172.08"
+	badDriverVersion  = "580.65.06"
+)
+
+// newUpgradeTestClusterPolicy returns a ClusterPolicy with driver auto upgrade enabled,
+// running the driver version an upgrade rolls out. mutate, when set, completes its
+// driver upgrade policy.
+func newUpgradeTestClusterPolicy(mutate func(*gpuv1.DriverUpgradePolicySpec)) *gpuv1.ClusterPolicy {
+	cp := &gpuv1.ClusterPolicy{ObjectMeta: metav1.ObjectMeta{Name: "cluster-policy"}}
+	cp.Spec.Driver.Repository = "nvcr.io/nvidia"
+	cp.Spec.Driver.Image = "driver"
+	cp.Spec.Driver.Version = badDriverVersion
+	cp.Spec.Driver.UpgradePolicy = &gpuv1.DriverUpgradePolicySpec{
+		DriverUpgradePolicySpec: upgrade_v1alpha1.DriverUpgradePolicySpec{AutoUpgrade: true},
+	}
+	if mutate != nil {
+		mutate(cp.Spec.Driver.UpgradePolicy)
+	}
+	return cp
+}
+
+// upgradeNodeStates returns the upgrade states of nodes, without driver pods
+func upgradeNodeStates(nodes ...*corev1.Node) []*upgrade.NodeUpgradeState {
+	nodeStates := make([]*upgrade.NodeUpgradeState, 0, len(nodes))
+	for _, node := range nodes {
+		nodeStates = append(nodeStates, &upgrade.NodeUpgradeState{Node: node})
+	}
+	return nodeStates
+}
+
+// newUpgradeStateTestReconciler builds a reconciler whose upgrade state holds nodeStates,
+// bucketed by their upgrade state label, and whose client holds their nodes and driver pods
+func newUpgradeStateTestReconciler(t *testing.T, objs []client.Object, nodeStates ...*upgrade.NodeUpgradeState) (*UpgradeReconciler, *fakeUpgradeStateManager) {
+	t.Helper()
+	for _, nodeState := range nodeStates {
+		objs = append(objs, nodeState.Node)
+		if nodeState.DriverPod != nil {
+			objs = append(objs, nodeState.DriverPod)
+		}
+	}
+	r, stateManager := newTestUpgradeReconciler(t, objs...)
+	for _, nodeState := range nodeStates {
+		stateKey := nodeState.Node.Labels[upgrade.GetUpgradeStateLabelKey()]
+		stateManager.state.NodeStates[stateKey] = append(stateManager.state.NodeStates[stateKey], nodeState)
 	}
 	return r, stateManager
 }
@@ -305,7 +383,7 @@ func TestUpgradeReconcileNVIDIADriverAutoUpgradeDisabledScope(t *testing.T) {
 func TestUpgradeReconcileClusterPolicyPathsUnchanged(t *testing.T) {
 	t.Run("legacy driver builds state with the daemonset app label", func(t *testing.T) {
 		cp := &gpuv1.ClusterPolicy{ObjectMeta: metav1.ObjectMeta{Name: "cluster-policy"}}
-		cp.Spec.Driver.UpgradePolicy = &upgrade_v1alpha1.DriverUpgradePolicySpec{AutoUpgrade: true}
+		cp.Spec.Driver.UpgradePolicy = &gpuv1.DriverUpgradePolicySpec{DriverUpgradePolicySpec: upgrade_v1alpha1.DriverUpgradePolicySpec{AutoUpgrade: true}}
 		r, stateManager := newTestUpgradeReconciler(t, cp)
 
 		result, err := r.Reconcile(context.Background(), upgradeSingletonRequest())
@@ -344,3 +422,69 @@ func TestUpgradeReconcileClusterPolicyPathsUnchanged(t *testing.T) {
 		assert.Equal(t, map[string]string{AppComponentLabelKey: DriverAppComponentLabelValue}, stateManager.buildLabels)
 	})
 }
+
+func TestUpgradeReconcilePaused(t *testing.T) {
+	paused := map[string]string{gpuconsts.ReconcilePausedAnnotation: "state-driver"}
+
+	t.Run("paused ClusterPolicy skips applying the upgrade state", func(t *testing.T) {
+		cp := &gpuv1.ClusterPolicy{ObjectMeta: metav1.ObjectMeta{Name: "cluster-policy", Annotations: paused}}
+		cp.Spec.Driver.UpgradePolicy = &gpuv1.DriverUpgradePolicySpec{DriverUpgradePolicySpec: upgrade_v1alpha1.DriverUpgradePolicySpec{AutoUpgrade: true}}
+		r, stateManager := newTestUpgradeReconciler(t, cp)
+
+		result, err := r.Reconcile(context.Background(), upgradeSingletonRequest())
+		require.NoError(t, err)
+		assert.Equal(t, plannedRequeueInterval, result.RequeueAfter)
+		assert.Equal(t, 1, stateManager.buildCalls)
+		assert.Zero(t, stateManager.applyCalls)
+	})
+
+	t.Run("paused ClusterPolicy keeps upgrade-state labels", func(t *testing.T) {
+		cp := &gpuv1.ClusterPolicy{ObjectMeta: metav1.ObjectMeta{Name: "cluster-policy", Annotations: paused}}
+		node := nodeWithUpgradeState("node-1", "")
+		r, _ := newTestUpgradeReconciler(t, cp, node)
+
+		_, err := r.Reconcile(context.Background(), upgradeSingletonRequest())
+		require.NoError(t, err)
+
+		updated := &corev1.Node{}
+		require.NoError(t, r.Get(context.Background(), types.NamespacedName{Name: node.Name}, updated))
+		assert.Contains(t, updated.Labels, upgrade.GetUpgradeStateLabelKey())
+	})
+
+	t.Run("paused NVIDIADriver is skipped while others are upgraded", func(t *testing.T) {
+		active := &nvidiav1alpha1.NVIDIADriver{ObjectMeta: metav1.ObjectMeta{Name: "active-driver"}}
+		frozen := &nvidiav1alpha1.NVIDIADriver{ObjectMeta: metav1.ObjectMeta{Name: "paused-driver", Annotations: paused}}
+		activeNode := nodeWithUpgradeState("node-active", active.Name)
+		frozenNode := nodeWithUpgradeState("node-paused", frozen.Name)
+		r, stateManager := newTestUpgradeReconciler(t, active, frozen, activeNode, frozenNode)
+		stateManager.state.NodeStates["upgrade-required"] = []*upgrade.NodeUpgradeState{{Node: activeNode}, {Node: frozenNode}}
+
+		_, err := r.Reconcile(context.Background(), upgradeSingletonRequest())
+		require.NoError(t, err)
+		assert.Equal(t, 1, stateManager.applyCalls)
+	})
+
+	t.Run("paused NVIDIADriver keeps upgrade-state labels when auto upgrade is disabled", func(t *testing.T) {
+		disabled := &nvidiav1alpha1.DriverUpgradePolicySpec{AutoUpgrade: false}
+		active := &nvidiav1alpha1.NVIDIADriver{
+			ObjectMeta: metav1.ObjectMeta{Name: "active-driver"},
+			Spec:       nvidiav1alpha1.NVIDIADriverSpec{UpgradePolicy: disabled},
+		}
+		frozen := &nvidiav1alpha1.NVIDIADriver{
+			ObjectMeta: metav1.ObjectMeta{Name: "paused-driver", Annotations: paused},
+			Spec:       nvidiav1alpha1.NVIDIADriverSpec{UpgradePolicy: disabled},
+		}
+		activeNode := nodeWithUpgradeState("node-active", active.Name)
+		frozenNode := nodeWithUpgradeState("node-paused", frozen.Name)
+		r, _ := newTestUpgradeReconciler(t, active, frozen, activeNode, frozenNode)
+
+		_, err := r.Reconcile(context.Background(), upgradeSingletonRequest())
+		require.NoError(t, err)
+
+		updated := &corev1.Node{}
+		require.NoError(t, r.Get(context.Background(), types.NamespacedName{Name: activeNode.Name}, updated))
+		assert.NotContains(t, updated.Labels, upgrade.GetUpgradeStateLabelKey())
+		require.NoError(t, r.Get(context.Background(), types.NamespacedName{Name: frozenNode.Name}, updated))
+		assert.Contains(t, updated.Labels, upgrade.GetUpgradeStateLabelKey())
+	})
+}
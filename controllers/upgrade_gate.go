@@ -0,0 +1,105 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"time"
+
+	upgrade_v1alpha1 "github.com/NVIDIA/k8s-operator-libs/api/upgrade/v1alpha1"
+	"github.com/NVIDIA/k8s-operator-libs/pkg/upgrade"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// upgradeGate is the upgrade state and policy handed to the upgrade state manager,
+// once the nodes whose upgrade cannot start yet are held back
+type upgradeGate struct {
+	// state is the upgrade state to apply, without the nodes held back
+	state *upgrade.ClusterUpgradeState
+	// policy is the upgrade policy to apply along with state
+	policy *upgrade_v1alpha1.DriverUpgradePolicySpec
+	// maxUnavailable is the number of nodes that can be unavailable, computed from the
+	// full upgrade state
+	maxUnavailable int
+	// halted is set when the upgrade failed on a canary node
+	halted bool
+	// requeueAfter is when the held back nodes have to be checked again
+	requeueAfter time.Duration
+}
+
+func newUpgradeGate(state *upgrade.ClusterUpgradeState, policy *upgrade_v1alpha1.DriverUpgradePolicySpec, maxUnavailable int) *upgradeGate {
+	return &upgradeGate{
+		state:          state,
+		policy:         policy,
+		maxUnavailable: maxUnavailable,
+		requeueAfter:   plannedRequeueInterval,
+	}
+}
+
+// hold leaves out of the state to apply the nodes waiting for their upgrade to start
+// that hold returns true for
+func (g *upgradeGate) hold(hold func(node *corev1.Node) bool) {
+	g.holdIn(upgrade.UpgradeStateUpgradeRequired, hold)
+}
+
+// holdIn leaves out of the state to apply the nodes in the upgrade state stateKey that
+// hold returns true for. As the upgrade state manager computes maxUnavailable
+// from the nodes in the state it is given, the policy to apply is updated to carry
+// maxUnavailable as an absolute value. Nodes held back while their upgrade is in
+// progress are cordoned, so they still count against maxUnavailable and
+// maxParallelUpgrades.
+func (g *upgradeGate) holdIn(stateKey string, hold func(node *corev1.Node) bool) {
+	state := upgrade.NewClusterUpgradeState()
+	held := 0
+	for key, nodeStates := range g.state.NodeStates {
+		if key != stateKey {
+			state.NodeStates[key] = nodeStates
+			continue
+		}
+		for _, nodeState := range nodeStates {
+			if hold(nodeState.Node) {
+				held++
+				continue
+			}
+			state.NodeStates[key] = append(state.NodeStates[key], nodeState)
+		}
+	}
+	g.state = &state
+
+	policy := g.policy.DeepCopy()
+	parallelUpgradesExhausted := false
+	if stateKey != upgrade.UpgradeStateUpgradeRequired && held > 0 {
+		g.maxUnavailable = max(g.maxUnavailable-held, 0)
+		if policy.MaxParallelUpgrades > 0 {
+			policy.MaxParallelUpgrades = max(policy.MaxParallelUpgrades-held, 0)
+			parallelUpgradesExhausted = policy.MaxParallelUpgrades == 0
+		}
+	}
+	maxUnavailable := intstr.FromInt(g.maxUnavailable)
+	policy.MaxUnavailable = &maxUnavailable
+	g.policy = policy
+
+	// 0 parallel upgrades means no limit, so no new upgrade must start instead
+	if parallelUpgradesExhausted {
+		g.hold(func(*corev1.Node) bool { return true })
+	}
+}
+
+// requeueBy makes sure the upgrade is reconciled again within d
+func (g *upgradeGate) requeueBy(d time.Duration) {
+	g.requeueAfter = min(g.requeueAfter, d)
+}
@@ -0,0 +1,184 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/NVIDIA/k8s-operator-libs/pkg/upgrade"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	nvidiav1alpha1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1alpha1"
+)
+
+// maxNodeUpgradeRecords is the number of driver upgrades kept in the history of a node
+const maxNodeUpgradeRecords = 10
+
+// recordNodeUpgradeHistory records the driver upgrade state of every node of state in
+// the NodeUpgradeHistory named after the node. It is called once the state is applied,
+// as the upgrade state manager updates the nodes of state with their new upgrade state.
+// owner is the custom resource the driver of the nodes is configured by, as kind/name.
+// Failures are logged, as they must not block the upgrade.
+func (r *UpgradeReconciler) recordNodeUpgradeHistory(ctx context.Context, owner string, state *upgrade.ClusterUpgradeState) {
+	now := metav1.Now()
+	for _, nodeStates := range state.NodeStates {
+		for _, nodeState := range nodeStates {
+			if err := r.recordNodeUpgrade(ctx, owner, nodeState, now); err != nil {
+				r.Log.Error(err, "Failed to record the driver upgrade history of node", "node", nodeState.Node.Name)
+			}
+		}
+	}
+}
+
+// recordNodeUpgrade records the current driver upgrade state of the node of nodeState
+func (r *UpgradeReconciler) recordNodeUpgrade(ctx context.Context, owner string, nodeState *upgrade.NodeUpgradeState, now metav1.Time) error {
+	node := nodeState.Node
+	upgradeState := node.Labels[upgrade.GetUpgradeStateLabelKey()]
+	if upgradeState == upgrade.UpgradeStateUnknown {
+		return nil
+	}
+
+	history := &nvidiav1alpha1.NodeUpgradeHistory{}
+	err := r.Get(ctx, types.NamespacedName{Name: node.Name}, history)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	exists := err == nil
+
+	records, changed := updateNodeUpgradeRecords(slices.Clone(history.Status.Records), owner, nodeState, upgradeState, now)
+	if !changed {
+		return nil
+	}
+
+	if !exists {
+		history = &nvidiav1alpha1.NodeUpgradeHistory{ObjectMeta: metav1.ObjectMeta{Name: node.Name}}
+		// The history is garbage collected along with its node
+		if err := controllerutil.SetOwnerReference(node, history, r.Scheme); err != nil {
+			return err
+		}
+		if err := r.Create(ctx, history); err != nil {
+			return err
+		}
+	}
+	history.Status.Records = records
+	return r.Status().Update(ctx, history)
+}
+
+// updateNodeUpgradeRecords updates records, the driver upgrade history of the node of
+// nodeState, with the upgrade state the node is in at now. A record is started when
+// the upgrade of the node starts, as the node leaves upgrade-required where it may wait
+// for long, and completed once it succeeds or fails. It returns the updated records and
+// whether they changed.
+func updateNodeUpgradeRecords(records []nvidiav1alpha1.NodeUpgradeRecord, owner string, nodeState *upgrade.NodeUpgradeState,
+	upgradeState string, now metav1.Time) ([]nvidiav1alpha1.NodeUpgradeRecord, bool) {
+	changed := false
+	if len(records) == 0 || records[0].Result != nvidiav1alpha1.NodeUpgradeInProgress {
+		// Nodes waiting for their upgrade to start, upgraded or still failing have no
+		// upgrade in progress to record
+		if upgradeState == upgrade.UpgradeStateUpgradeRequired || upgradeState == upgrade.UpgradeStateDone ||
+			(upgradeState == upgrade.UpgradeStateFailed && len(records) > 0) {
+			return records, false
+		}
+		record := nvidiav1alpha1.NodeUpgradeRecord{
+			Owner:     owner,
+			Result:    nvidiav1alpha1.NodeUpgradeInProgress,
+			StartTime: now,
+		}
+		if nodeState.DriverPod != nil {
+			record.FromImage = driverContainerImage(&nodeState.DriverPod.Spec)
+		}
+		records = slices.Insert(records, 0, record)
+		if len(records) > maxNodeUpgradeRecords {
+			records = records[:maxNodeUpgradeRecords]
+		}
+		changed = true
+	}
+
+	record := &records[0]
+	// The DaemonSet may change while the node is upgraded, e.g. when the driver is rolled back
+	if nodeState.DriverDaemonSet != nil {
+		if image := driverContainerImage(&nodeState.DriverDaemonSet.Spec.Template.Spec); image != record.ToImage {
+			record.ToImage = image
+			changed = true
+		}
+	}
+	if record.State == upgradeState {
+		return records, changed
+	}
+
+	previousState := record.State
+	if previousState == upgrade.UpgradeStateDrainRequired && len(record.Transitions) > 0 {
+		drainStart := record.Transitions[len(record.Transitions)-1].Time
+		record.DrainDuration = &metav1.Duration{Duration: now.Sub(drainStart.Time)}
+	}
+	record.State = upgradeState
+	record.Transitions = append(record.Transitions, nvidiav1alpha1.NodeUpgradeTransition{State: upgradeState, Time: now})
+
+	switch upgradeState {
+	case upgrade.UpgradeStateDone:
+		record.Result = nvidiav1alpha1.NodeUpgradeSucceeded
+		record.CompletionTime = &now
+	case upgrade.UpgradeStateFailed:
+		record.Result = nvidiav1alpha1.NodeUpgradeFailed
+		record.CompletionTime = &now
+		record.Message = nodeUpgradeFailureMessage(previousState, nodeState)
+	}
+	return records, true
+}
+
+// nodeUpgradeFailureMessage describes why the driver upgrade of the node of nodeState
+// failed in previousState
+func nodeUpgradeFailureMessage(previousState string, nodeState *upgrade.NodeUpgradeState) string {
+	message := "Driver upgrade failed"
+	if previousState != "" {
+		message = fmt.Sprintf("Driver upgrade failed in state %s", previousState)
+	}
+	for _, stage := range []string{driverUpgradeHookPreDrain, driverUpgradeHookPostValidation} {
+		if nodeState.Node.Annotations[driverUpgradeHookAnnotationKey(stage)] == driverUpgradeHookFailed {
+			message = fmt.Sprintf("%s: %s hook failed", message, stage)
+		}
+	}
+	pod := nodeState.DriverPod
+	if pod == nil {
+		return message
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name != driverContainerName || status.State.Waiting == nil {
+			continue
+		}
+		message = fmt.Sprintf("%s: driver container is waiting with reason %s", message, status.State.Waiting.Reason)
+		if status.State.Waiting.Message != "" {
+			message = fmt.Sprintf("%s, %s", message, status.State.Waiting.Message)
+		}
+	}
+	return message
+}
+
+// driverContainerImage returns the image of the driver container of podSpec
+func driverContainerImage(podSpec *corev1.PodSpec) string {
+	container := findContainerByName(podSpec.Containers, driverContainerName)
+	if container == nil {
+		return ""
+	}
+	return container.Image
+}
@@ -0,0 +1,160 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/k8s-operator-libs/pkg/upgrade"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1"
+	nvidiav1alpha1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1alpha1"
+)
+
+// historyNodeState returns the upgrade state of a node running the good driver, out of
+// a DaemonSet with the bad one
+func historyNodeState(upgradeState string) *upgrade.NodeUpgradeState {
+	nodeState := rollbackNodeState("node-a", upgradeState, nil, "bad", "good", badDriverVersion)
+	nodeState.DriverPod.Spec = driverPodSpec("good", goodDriverVersion)
+	return nodeState
+}
+
+func TestUpdateNodeUpgradeRecords(t *testing.T) {
+	const owner = "ClusterPolicy/cluster-policy"
+	start := time.Now()
+	at := func(d time.Duration) metav1.Time { return metav1.NewTime(start.Add(d)) }
+
+	nodeState := historyNodeState(upgrade.UpgradeStateDone)
+	records, changed := updateNodeUpgradeRecords(nil, owner, nodeState, upgrade.UpgradeStateDone, at(0))
+	assert.False(t, changed, "a node upgraded before has no upgrade to record")
+	assert.Empty(t, records)
+
+	records, changed = updateNodeUpgradeRecords(records, owner, nodeState, upgrade.UpgradeStateUpgradeRequired, at(0))
+	assert.False(t, changed, "a node waiting for its upgrade to start has no upgrade to record")
+	assert.Empty(t, records)
+
+	steps := []struct {
+		state string
+		at    time.Duration
+	}{
+		{upgrade.UpgradeStateUpgradeRequired, 0},
+		{upgrade.UpgradeStateCordonRequired, time.Minute},
+		{upgrade.UpgradeStateDrainRequired, 2 * time.Minute},
+		{upgrade.UpgradeStateDrainRequired, 4 * time.Minute},
+		{upgrade.UpgradeStatePodRestartRequired, 7 * time.Minute},
+		{upgrade.UpgradeStateDone, 9 * time.Minute},
+	}
+	for _, step := range steps {
+		records, _ = updateNodeUpgradeRecords(records, owner, nodeState, step.state, at(step.at))
+	}
+	require.Len(t, records, 1)
+	record := records[0]
+	assert.Equal(t, nvidiav1alpha1.NodeUpgradeSucceeded, record.Result)
+	assert.Equal(t, owner, record.Owner)
+	assert.Equal(t, "nvcr.io/nvidia/driver:"+goodDriverVersion+"-ubuntu22.04", record.FromImage)
+	assert.Equal(t, "nvcr.io/nvidia/driver:"+badDriverVersion+"-ubuntu22.04", record.ToImage)
+	assert.Equal(t, at(time.Minute), record.StartTime)
+	require.NotNil(t, record.CompletionTime)
+	assert.Equal(t, at(9*time.Minute), *record.CompletionTime)
+	require.NotNil(t, record.DrainDuration)
+	assert.Equal(t, 5*time.Minute, record.DrainDuration.Duration)
+	require.Len(t, record.Transitions, 4)
+	assert.Equal(t, upgrade.UpgradeStateCordonRequired, record.Transitions[0].State)
+	assert.Equal(t, upgrade.UpgradeStateDrainRequired, record.Transitions[1].State)
+	assert.Equal(t, at(2*time.Minute), record.Transitions[1].Time)
+
+	_, changed = updateNodeUpgradeRecords(records, owner, nodeState, upgrade.UpgradeStateDone, at(10*time.Minute))
+	assert.False(t, changed)
+
+	t.Run("failed upgrade", func(t *testing.T) {
+		nodeState := historyNodeState(upgrade.UpgradeStateFailed)
+		nodeState.DriverPod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+			Name: driverContainerName,
+			State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{
+				Reason:  "CrashLoopBackOff",
+				Message: "back-off restarting failed container",
+			}},
+		}}
+		records, _ := updateNodeUpgradeRecords(nil, owner, nodeState, upgrade.UpgradeStatePodRestartRequired, at(0))
+		records, changed := updateNodeUpgradeRecords(records, owner, nodeState, upgrade.UpgradeStateFailed, at(time.Minute))
+		require.True(t, changed)
+		require.Len(t, records, 1)
+		assert.Equal(t, nvidiav1alpha1.NodeUpgradeFailed, records[0].Result)
+		assert.Equal(t, "Driver upgrade failed in state pod-restart-required: driver container is waiting with reason "+
+			"CrashLoopBackOff, back-off restarting failed container", records[0].Message)
+
+		_, changed = updateNodeUpgradeRecords(records, owner, nodeState, upgrade.UpgradeStateFailed, at(2*time.Minute))
+		assert.False(t, changed, "a node still failing has no new upgrade to record")
+	})
+
+	t.Run("bounded history", func(t *testing.T) {
+		var records []nvidiav1alpha1.NodeUpgradeRecord
+		for i := range maxNodeUpgradeRecords + 2 {
+			records, _ = updateNodeUpgradeRecords(records, owner, nodeState, upgrade.UpgradeStateCordonRequired, at(time.Duration(i)*time.Hour))
+			records, _ = updateNodeUpgradeRecords(records, owner, nodeState, upgrade.UpgradeStateDone, at(time.Duration(i)*time.Hour+time.Minute))
+		}
+		require.Len(t, records, maxNodeUpgradeRecords)
+		assert.Equal(t, at(time.Duration(maxNodeUpgradeRecords+1)*time.Hour), records[0].StartTime)
+	})
+}
+
+func TestUpgradeReconcileNodeUpgradeHistory(t *testing.T) {
+	nodeState := historyNodeState(upgrade.UpgradeStateUpgradeRequired)
+	r, stateManager := newUpgradeStateTestReconciler(t, []client.Object{newUpgradeTestClusterPolicy(nil)}, nodeState)
+
+	_, err := r.Reconcile(t.Context(), upgradeSingletonRequest())
+	require.NoError(t, err)
+
+	history := &nvidiav1alpha1.NodeUpgradeHistory{}
+	err = r.Get(t.Context(), types.NamespacedName{Name: "node-a"}, history)
+	assert.True(t, apierrors.IsNotFound(err), "no history is recorded until the upgrade of the node starts")
+
+	// the upgrade state manager updates the nodes of the state it applies
+	nodeState.Node.Labels[upgrade.GetUpgradeStateLabelKey()] = upgrade.UpgradeStateCordonRequired
+	stateManager.state = upgrade.NewClusterUpgradeState()
+	stateManager.state.NodeStates[upgrade.UpgradeStateCordonRequired] = []*upgrade.NodeUpgradeState{nodeState}
+	_, err = r.Reconcile(t.Context(), upgradeSingletonRequest())
+	require.NoError(t, err)
+
+	require.NoError(t, r.Get(t.Context(), types.NamespacedName{Name: "node-a"}, history))
+	require.Len(t, history.OwnerReferences, 1)
+	assert.Equal(t, "Node", history.OwnerReferences[0].Kind)
+	assert.Equal(t, "node-a", history.OwnerReferences[0].Name)
+	require.Len(t, history.Status.Records, 1)
+	assert.Equal(t, gpuv1.ClusterPolicyCRDName+"/cluster-policy", history.Status.Records[0].Owner)
+	assert.Equal(t, nvidiav1alpha1.NodeUpgradeInProgress, history.Status.Records[0].Result)
+	assert.Equal(t, upgrade.UpgradeStateCordonRequired, history.Status.Records[0].State)
+
+	nodeState.Node.Labels[upgrade.GetUpgradeStateLabelKey()] = upgrade.UpgradeStateDone
+	stateManager.state = upgrade.NewClusterUpgradeState()
+	stateManager.state.NodeStates[upgrade.UpgradeStateDone] = []*upgrade.NodeUpgradeState{nodeState}
+	_, err = r.Reconcile(t.Context(), upgradeSingletonRequest())
+	require.NoError(t, err)
+
+	require.NoError(t, r.Get(t.Context(), types.NamespacedName{Name: "node-a"}, history))
+	require.Len(t, history.Status.Records, 1)
+	assert.Equal(t, nvidiav1alpha1.NodeUpgradeSucceeded, history.Status.Records[0].Result)
+	assert.Len(t, history.Status.Records[0].Transitions, 2)
+}
@@ -0,0 +1,424 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/NVIDIA/k8s-operator-libs/pkg/upgrade"
+	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1"
+	"github.com/NVIDIA/gpu-operator/internal/utils"
+)
+
+const (
+	// driverUpgradeHookPreDrain is the stage of the hook run before draining nodes
+	driverUpgradeHookPreDrain = "pre-drain"
+	// driverUpgradeHookPostValidation is the stage of the hook run before uncordoning nodes
+	driverUpgradeHookPostValidation = "post-validation"
+
+	driverUpgradeHookSucceeded = "Succeeded"
+	driverUpgradeHookFailed    = "Failed"
+
+	// driverUpgradeHookAnnotationKeyFmt is the format of the node annotation key holding
+	// the result of the driver upgrade hook of a stage
+	driverUpgradeHookAnnotationKeyFmt = "nvidia.com/%s-driver-upgrade-hook.%s"
+	// driverUpgradeHookStageLabelKey is the label key of hook Jobs holding their stage
+	driverUpgradeHookStageLabelKey = "nvidia.com/gpu-driver-upgrade-hook.stage"
+	// driverUpgradeHookNodeAnnotationKey is the annotation key of hook Jobs holding their node
+	driverUpgradeHookNodeAnnotationKey = "nvidia.com/gpu-driver-upgrade-hook.node"
+
+	// driverUpgradeHookPollInterval is how often running hook Jobs are checked
+	driverUpgradeHookPollInterval = 30 * time.Second
+	// driverUpgradeWebhookPollInterval is how often webhooks called in the background are checked
+	driverUpgradeWebhookPollInterval = 5 * time.Second
+	// driverUpgradeHookServiceAccountName is the service account the pods of hook Jobs run
+	// with. The operator grants it no permissions.
+	driverUpgradeHookServiceAccountName = "nvidia-driver-upgrade-hook"
+)
+
+// driverUpgradeHookStage is a stage of the driver upgrade of nodes a hook is run at
+type driverUpgradeHookStage struct {
+	name string
+	// upgradeState is the upgrade state nodes are held back in until the hook succeeds
+	upgradeState string
+	// hook is the hook to run, nil if there is none
+	hook *gpuv1.DriverUpgradeHook
+}
+
+// driverUpgradeWebhookCall is a call of a driver upgrade webhook running in the background
+type driverUpgradeWebhookCall struct {
+	// done is closed once the webhook answered or the call failed
+	done    chan struct{}
+	result  string
+	message string
+}
+
+// driverUpgradeHookRequest is the body of the requests sent to driver upgrade webhooks
+type driverUpgradeHookRequest struct {
+	Stage string `json:"stage"`
+	Node  string `json:"node"`
+	Owner string `json:"owner"`
+}
+
+func driverUpgradeHookStages(hooks *gpuv1.DriverUpgradeHooksSpec) []driverUpgradeHookStage {
+	stages := []driverUpgradeHookStage{
+		{name: driverUpgradeHookPreDrain, upgradeState: upgrade.UpgradeStateWaitForJobsRequired},
+		{name: driverUpgradeHookPostValidation, upgradeState: upgrade.UpgradeStateUncordonRequired},
+	}
+	if hooks != nil {
+		stages[0].hook = hooks.PreDrain
+		stages[1].hook = hooks.PostValidation
+	}
+	return stages
+}
+
+func driverUpgradeHookAnnotationKey(stage string) string {
+	return fmt.Sprintf(driverUpgradeHookAnnotationKeyFmt, upgrade.DriverName, stage)
+}
+
+// gateUpgradeHooks runs the driver upgrade hooks on the nodes at their stage, and holds
+// back the nodes until their hook succeeds. Nodes whose hook fails are moved to the
+// upgrade-failed state unless the hook failure is ignored, and held back there. owner is
+// the custom resource the driver of the nodes is configured by, as kind/name, and ownerObj
+// that custom resource, which owns the hook Jobs.
+func (r *UpgradeReconciler) gateUpgradeHooks(ctx context.Context, reqLogger logr.Logger, owner string, ownerObj client.Object,
+	hooks *gpuv1.DriverUpgradeHooksSpec, gate *upgradeGate) error {
+	stages := driverUpgradeHookStages(hooks)
+	if err := r.resetDriverUpgradeHooks(ctx, gate.state, stages, ownerObj, hooks == nil); err != nil {
+		return err
+	}
+	if hooks == nil {
+		return nil
+	}
+
+	gate.holdIn(upgrade.UpgradeStateFailed, func(node *corev1.Node) bool {
+		return failedDriverUpgradeHook(node, stages)
+	})
+
+	for _, stage := range stages {
+		if stage.hook == nil {
+			continue
+		}
+		held := map[string]bool{}
+		for _, nodeState := range gate.state.NodeStates[stage.upgradeState] {
+			result, err := r.runDriverUpgradeHook(ctx, reqLogger, owner, ownerObj, stage, nodeState.Node)
+			if err != nil {
+				return err
+			}
+			switch {
+			case result == "" && stage.hook.Webhook != nil:
+				held[nodeState.Node.Name] = true
+				gate.requeueBy(driverUpgradeWebhookPollInterval)
+			case result == "":
+				held[nodeState.Node.Name] = true
+				gate.requeueBy(driverUpgradeHookPollInterval)
+			case result == driverUpgradeHookFailed && stage.hook.GetFailurePolicy() == gpuv1.DriverUpgradeHookFail:
+				held[nodeState.Node.Name] = true
+			}
+		}
+		if len(held) > 0 {
+			reqLogger.Info("Holding back nodes until their driver upgrade hook succeeds", "stage", stage.name, "nodes", len(held))
+			gate.holdIn(stage.upgradeState, func(node *corev1.Node) bool { return held[node.Name] })
+		}
+	}
+	return nil
+}
+
+// resetDriverUpgradeHooks clears the hook results of the nodes of state that are not
+// being upgraded, so that the hooks run again on their next upgrade, and deletes their hook
+// Jobs left over. All the hook Jobs of ownerObj are deleted if its hooks were removed.
+func (r *UpgradeReconciler) resetDriverUpgradeHooks(ctx context.Context, state *upgrade.ClusterUpgradeState,
+	stages []driverUpgradeHookStage, ownerObj client.Object, hooksRemoved bool) error {
+	jobs := &batchv1.JobList{}
+	if err := r.List(ctx, jobs, client.InNamespace(r.OperatorNamespace), client.HasLabels{driverUpgradeHookStageLabelKey}); err != nil {
+		return err
+	}
+	jobsByNode := map[string][]*batchv1.Job{}
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		if hooksRemoved && metav1.IsControlledBy(job, ownerObj) {
+			if err := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+				return err
+			}
+			continue
+		}
+		node := job.Annotations[driverUpgradeHookNodeAnnotationKey]
+		jobsByNode[node] = append(jobsByNode[node], job)
+	}
+
+	for _, stateKey := range []string{upgrade.UpgradeStateUnknown, upgrade.UpgradeStateDone, upgrade.UpgradeStateUpgradeRequired} {
+		for _, nodeState := range state.NodeStates[stateKey] {
+			node := nodeState.Node
+			for _, job := range jobsByNode[node.Name] {
+				if err := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+					return err
+				}
+			}
+
+			patch := client.MergeFrom(node.DeepCopy())
+			changed := false
+			for _, stage := range stages {
+				key := driverUpgradeHookAnnotationKey(stage.name)
+				if _, ok := node.Annotations[key]; ok {
+					delete(node.Annotations, key)
+					changed = true
+				}
+			}
+			if !changed {
+				continue
+			}
+			if err := r.Patch(ctx, node, patch); err != nil {
+				r.Log.Error(err, "Failed to clear the driver upgrade hook results of node", "node", node.Name)
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// failedDriverUpgradeHook returns whether a hook failed the driver upgrade of node
+func failedDriverUpgradeHook(node *corev1.Node, stages []driverUpgradeHookStage) bool {
+	for _, stage := range stages {
+		if stage.hook != nil && stage.hook.GetFailurePolicy() == gpuv1.DriverUpgradeHookFail &&
+			node.Annotations[driverUpgradeHookAnnotationKey(stage.name)] == driverUpgradeHookFailed {
+			return true
+		}
+	}
+	return false
+}
+
+// runDriverUpgradeHook runs the hook of stage on node, unless its result is already
+// known. It returns the result of the hook, empty while the hook is running. The result
+// is recorded on the node, which is moved to the upgrade-failed state if the hook failed
+// and its failure is not ignored.
+func (r *UpgradeReconciler) runDriverUpgradeHook(ctx context.Context, reqLogger logr.Logger, owner string, ownerObj client.Object,
+	stage driverUpgradeHookStage, node *corev1.Node) (string, error) {
+	key := driverUpgradeHookAnnotationKey(stage.name)
+	if result, ok := node.Annotations[key]; ok {
+		return result, nil
+	}
+
+	var result, message string
+	if stage.hook.Webhook != nil {
+		result, message = r.driverUpgradeWebhookResult(ctx, stage, owner, node.Name)
+		if result == "" {
+			return "", nil
+		}
+	} else {
+		var err error
+		result, message, err = r.runDriverUpgradeHookJob(ctx, stage, ownerObj, node.Name)
+		if err != nil || result == "" {
+			return "", err
+		}
+	}
+	reqLogger.Info("Driver upgrade hook done", "stage", stage.name, "node", node.Name, "result", result, "message", message)
+
+	patch := client.MergeFrom(node.DeepCopy())
+	if node.Annotations == nil {
+		node.Annotations = map[string]string{}
+	}
+	node.Annotations[key] = result
+	if result == driverUpgradeHookFailed && stage.hook.GetFailurePolicy() == gpuv1.DriverUpgradeHookFail {
+		node.Labels[upgrade.GetUpgradeStateLabelKey()] = upgrade.UpgradeStateFailed
+	}
+	if err := r.Patch(ctx, node, patch); err != nil {
+		r.Log.Error(err, "Failed to record the driver upgrade hook result of node", "node", node.Name)
+		return "", err
+	}
+	if result == driverUpgradeHookFailed {
+		r.recorder.Eventf(node, nil, corev1.EventTypeWarning, "DriverUpgradeHookFailed", "RunDriverUpgradeHook",
+			"Driver upgrade %s hook failed: %s", stage.name, message)
+	}
+	return result, nil
+}
+
+// driverUpgradeWebhookResult calls the webhook of stage for node in the background, so that
+// slow webhooks do not block the reconciliation, or checks on the call once started. It
+// returns the result of the hook, empty while the call is pending, and why it failed.
+func (r *UpgradeReconciler) driverUpgradeWebhookResult(ctx context.Context, stage driverUpgradeHookStage, owner, node string) (string, string) {
+	key := stage.name + "/" + node
+	value, started := r.webhookCalls.LoadOrStore(key, &driverUpgradeWebhookCall{done: make(chan struct{})})
+	call := value.(*driverUpgradeWebhookCall)
+	if !started {
+		go func() {
+			call.result, call.message = callDriverUpgradeWebhook(ctx, stage, owner, node)
+			close(call.done)
+		}()
+	}
+	select {
+	case <-call.done:
+		r.webhookCalls.Delete(key)
+		return call.result, call.message
+	default:
+		return "", ""
+	}
+}
+
+// callDriverUpgradeWebhook calls the webhook of stage for node. It returns the result of
+// the hook and why it failed.
+func callDriverUpgradeWebhook(ctx context.Context, stage driverUpgradeHookStage, owner, node string) (string, string) {
+	ctx, cancel := context.WithTimeout(ctx, stage.hook.GetTimeout())
+	defer cancel()
+
+	body, err := json.Marshal(driverUpgradeHookRequest{Stage: stage.name, Node: node, Owner: owner})
+	if err != nil {
+		return driverUpgradeHookFailed, err.Error()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, stage.hook.Webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return driverUpgradeHookFailed, err.Error()
+	}
+	req.Header.Set("Content-Type", "application/json")
+	httpClient := &http.Client{Timeout: stage.hook.GetTimeout()}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return driverUpgradeHookFailed, err.Error()
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return driverUpgradeHookFailed, fmt.Sprintf("webhook answered with status %s", resp.Status)
+	}
+	return driverUpgradeHookSucceeded, ""
+}
+
+// runDriverUpgradeHookJob creates the Job of the hook of stage for node, owned by ownerObj,
+// or checks on it once created. It returns the result of the hook, empty while the Job is
+// running, and why it failed. The Job is deleted once done.
+func (r *UpgradeReconciler) runDriverUpgradeHookJob(ctx context.Context, stage driverUpgradeHookStage, ownerObj client.Object, node string) (string, string, error) {
+	name := fmt.Sprintf("nvidia-driver-upgrade-%s-%s", stage.name, utils.GetStringHash(node))
+	job := &batchv1.Job{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: r.OperatorNamespace, Name: name}, job)
+	if apierrors.IsNotFound(err) {
+		if err := r.ensureDriverUpgradeHookServiceAccount(ctx); err != nil {
+			return "", "", err
+		}
+		job = newDriverUpgradeHookJob(stage, node, name, r.OperatorNamespace)
+		if err := controllerutil.SetControllerReference(ownerObj, job, r.Scheme); err != nil {
+			return "", "", err
+		}
+		if err := r.Create(ctx, job); err != nil {
+			r.Log.Error(err, "Failed to create driver upgrade hook Job", "job", name, "node", node)
+			return "", "", err
+		}
+		return "", "", nil
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	result, message := driverUpgradeHookJobResult(job, stage.hook.GetTimeout(), time.Now())
+	if result == "" {
+		return "", "", nil
+	}
+	// The result is kept on the node from now on
+	if err := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+		r.Log.Error(err, "Failed to delete driver upgrade hook Job", "job", name)
+		return "", "", err
+	}
+	return result, message, nil
+}
+
+// ensureDriverUpgradeHookServiceAccount creates the service account of the hook Jobs if
+// it does not exist
+func (r *UpgradeReconciler) ensureDriverUpgradeHookServiceAccount(ctx context.Context) error {
+	sa := &corev1.ServiceAccount{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: r.OperatorNamespace, Name: driverUpgradeHookServiceAccountName}, sa)
+	if !apierrors.IsNotFound(err) {
+		return err
+	}
+	sa = &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{
+		Name:      driverUpgradeHookServiceAccountName,
+		Namespace: r.OperatorNamespace,
+	}}
+	if err := r.Create(ctx, sa); client.IgnoreAlreadyExists(err) != nil {
+		r.Log.Error(err, "Failed to create the driver upgrade hook service account")
+		return err
+	}
+	return nil
+}
+
+// newDriverUpgradeHookJob returns the Job, named name, running the hook of stage for node.
+// Its pods run with the hook service account whatever the template sets, so that a hook
+// cannot borrow the permissions of the operator or its operands.
+func newDriverUpgradeHookJob(stage driverUpgradeHookStage, node, name, namespace string) *batchv1.Job {
+	template := stage.hook.Job.DeepCopy()
+	job := &batchv1.Job{
+		ObjectMeta: template.ObjectMeta,
+		Spec:       template.Spec,
+	}
+	job.Name = name
+	job.GenerateName = ""
+	job.Namespace = namespace
+	if job.Labels == nil {
+		job.Labels = map[string]string{}
+	}
+	job.Labels[driverUpgradeHookStageLabelKey] = stage.name
+	if job.Annotations == nil {
+		job.Annotations = map[string]string{}
+	}
+	job.Annotations[driverUpgradeHookNodeAnnotationKey] = node
+
+	podSpec := &job.Spec.Template.Spec
+	podSpec.ServiceAccountName = driverUpgradeHookServiceAccountName
+	podSpec.DeprecatedServiceAccount = ""
+	if podSpec.RestartPolicy == "" {
+		podSpec.RestartPolicy = corev1.RestartPolicyNever
+	}
+	for i := range podSpec.Containers {
+		podSpec.Containers[i].Env = append(podSpec.Containers[i].Env,
+			corev1.EnvVar{Name: "NODE_NAME", Value: node},
+			corev1.EnvVar{Name: "DRIVER_UPGRADE_HOOK_STAGE", Value: stage.name})
+	}
+	if stage.hook.RunOnNode {
+		podSpec.NodeName = node
+	}
+	return job
+}
+
+// driverUpgradeHookJobResult returns the result of the hook run by job at now, empty
+// while it is running, and why it failed
+func driverUpgradeHookJobResult(job *batchv1.Job, timeout time.Duration, now time.Time) (string, string) {
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			return driverUpgradeHookSucceeded, ""
+		case batchv1.JobFailed:
+			return driverUpgradeHookFailed, fmt.Sprintf("Job %s failed: %s", job.Name, condition.Message)
+		}
+	}
+	if now.Sub(job.CreationTimestamp.Time) > timeout {
+		return driverUpgradeHookFailed, fmt.Sprintf("Job %s did not complete within %s", job.Name, timeout)
+	}
+	return "", ""
+}
@@ -0,0 +1,344 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	upgrade_v1alpha1 "github.com/NVIDIA/k8s-operator-libs/api/upgrade/v1alpha1"
+	"github.com/NVIDIA/k8s-operator-libs/pkg/upgrade"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1"
+)
+
+// webhookServer answers driver upgrade webhooks with status, and records their requests
+func webhookServer(t *testing.T, status int, requests *[]driverUpgradeHookRequest) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var body driverUpgradeHookRequest
+		assert.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+		*requests = append(*requests, body)
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// waitForDriverUpgradeWebhook waits for the webhook call of stage for node started by r
+func waitForDriverUpgradeWebhook(t *testing.T, r *UpgradeReconciler, stage, node string) {
+	t.Helper()
+	value, ok := r.webhookCalls.Load(stage + "/" + node)
+	require.True(t, ok, "the webhook is called in the background")
+	select {
+	case <-value.(*driverUpgradeWebhookCall).done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("the webhook call did not complete")
+	}
+}
+
+func TestUpgradeGateHoldIn(t *testing.T) {
+	state := upgrade.NewClusterUpgradeState()
+	state.NodeStates[upgrade.UpgradeStateWaitForJobsRequired] = []*upgrade.NodeUpgradeState{
+		{Node: canaryTestNode("node-a", upgrade.UpgradeStateWaitForJobsRequired, nil)},
+		{Node: canaryTestNode("node-b", upgrade.UpgradeStateWaitForJobsRequired, nil)},
+	}
+	state.NodeStates[upgrade.UpgradeStateUpgradeRequired] = []*upgrade.NodeUpgradeState{
+		{Node: canaryTestNode("node-c", upgrade.UpgradeStateUpgradeRequired, nil)},
+	}
+	isNodeA := func(node *corev1.Node) bool { return node.Name == "node-a" }
+
+	t.Run("held nodes count against maxUnavailable", func(t *testing.T) {
+		gate := newUpgradeGate(&state, &upgrade_v1alpha1.DriverUpgradePolicySpec{MaxParallelUpgrades: 2}, 3)
+		gate.holdIn(upgrade.UpgradeStateWaitForJobsRequired, isNodeA)
+
+		assert.Equal(t, []string{"node-b"}, appliedNodeNames(gate.state, upgrade.UpgradeStateWaitForJobsRequired))
+		assert.Equal(t, []string{"node-c"}, appliedNodeNames(gate.state, upgrade.UpgradeStateUpgradeRequired))
+		assert.Equal(t, 1, gate.policy.MaxParallelUpgrades)
+		require.NotNil(t, gate.policy.MaxUnavailable)
+		assert.Equal(t, 2, gate.policy.MaxUnavailable.IntValue())
+	})
+
+	t.Run("no upgrade starts once parallel upgrades are exhausted", func(t *testing.T) {
+		gate := newUpgradeGate(&state, &upgrade_v1alpha1.DriverUpgradePolicySpec{MaxParallelUpgrades: 1}, 3)
+		gate.holdIn(upgrade.UpgradeStateWaitForJobsRequired, isNodeA)
+
+		assert.Equal(t, []string{"node-b"}, appliedNodeNames(gate.state, upgrade.UpgradeStateWaitForJobsRequired))
+		assert.Empty(t, appliedNodeNames(gate.state, upgrade.UpgradeStateUpgradeRequired))
+		assert.Zero(t, gate.policy.MaxParallelUpgrades)
+	})
+}
+
+func TestUpgradeReconcileWebhookHooks(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		failurePolicy gpuv1.DriverUpgradeHookFailurePolicy
+		result        string
+		upgradeState  string
+		applied       bool
+	}{
+		{
+			name:          "hook succeeded",
+			status:        http.StatusOK,
+			failurePolicy: gpuv1.DriverUpgradeHookFail,
+			result:        driverUpgradeHookSucceeded,
+			upgradeState:  upgrade.UpgradeStateWaitForJobsRequired,
+			applied:       true,
+		},
+		{
+			name:          "hook failed",
+			status:        http.StatusInternalServerError,
+			failurePolicy: gpuv1.DriverUpgradeHookFail,
+			result:        driverUpgradeHookFailed,
+			upgradeState:  upgrade.UpgradeStateFailed,
+		},
+		{
+			name:          "hook failure ignored",
+			status:        http.StatusInternalServerError,
+			failurePolicy: gpuv1.DriverUpgradeHookIgnore,
+			result:        driverUpgradeHookFailed,
+			upgradeState:  upgrade.UpgradeStateWaitForJobsRequired,
+			applied:       true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var requests []driverUpgradeHookRequest
+			server := webhookServer(t, tc.status, &requests)
+			cp := newUpgradeTestClusterPolicy(func(p *gpuv1.DriverUpgradePolicySpec) {
+				p.Hooks = &gpuv1.DriverUpgradeHooksSpec{
+					PreDrain: &gpuv1.DriverUpgradeHook{
+						Webhook:       &gpuv1.DriverUpgradeWebhook{URL: server.URL},
+						FailurePolicy: tc.failurePolicy,
+					},
+				}
+			})
+			r, stateManager := newUpgradeStateTestReconciler(t, []client.Object{cp}, upgradeNodeStates(
+				canaryTestNode("node-a", upgrade.UpgradeStateWaitForJobsRequired, nil))...)
+
+			result, err := r.Reconcile(t.Context(), upgradeSingletonRequest())
+			require.NoError(t, err)
+			assert.Equal(t, driverUpgradeWebhookPollInterval, result.RequeueAfter)
+			assert.Empty(t, appliedNodeNames(stateManager.appliedStates[0], upgrade.UpgradeStateWaitForJobsRequired),
+				"the node is held back while the webhook is called")
+
+			waitForDriverUpgradeWebhook(t, r, driverUpgradeHookPreDrain, "node-a")
+			_, err = r.Reconcile(t.Context(), upgradeSingletonRequest())
+			require.NoError(t, err)
+
+			assert.Equal(t, []driverUpgradeHookRequest{{
+				Stage: driverUpgradeHookPreDrain,
+				Node:  "node-a",
+				Owner: gpuv1.ClusterPolicyCRDName + "/cluster-policy",
+			}}, requests)
+
+			node := &corev1.Node{}
+			require.NoError(t, r.Get(t.Context(), types.NamespacedName{Name: "node-a"}, node))
+			assert.Equal(t, tc.result, node.Annotations[driverUpgradeHookAnnotationKey(driverUpgradeHookPreDrain)])
+			assert.Equal(t, tc.upgradeState, node.Labels[upgrade.GetUpgradeStateLabelKey()])
+
+			applied := appliedNodeNames(stateManager.appliedStates[1], upgrade.UpgradeStateWaitForJobsRequired)
+			if tc.applied {
+				assert.Equal(t, []string{"node-a"}, applied)
+			} else {
+				assert.Empty(t, applied)
+			}
+
+			recorder := r.recorder.(*events.FakeRecorder)
+			if tc.result == driverUpgradeHookFailed {
+				require.Len(t, recorder.Events, 1)
+				assert.Contains(t, <-recorder.Events, "DriverUpgradeHookFailed")
+			} else {
+				assert.Empty(t, recorder.Events)
+			}
+		})
+	}
+}
+
+func TestUpgradeReconcileJobHooks(t *testing.T) {
+	cp := newUpgradeTestClusterPolicy(func(p *gpuv1.DriverUpgradePolicySpec) {
+		p.Hooks = &gpuv1.DriverUpgradeHooksSpec{
+			PostValidation: &gpuv1.DriverUpgradeHook{
+				Job: &batchv1.JobTemplateSpec{
+					Spec: batchv1.JobSpec{Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							ServiceAccountName: "gpu-operator",
+							Containers:         []corev1.Container{{Name: "check", Image: "busybox"}},
+						},
+					}},
+				},
+				RunOnNode: true,
+			},
+		}
+	})
+	r, stateManager := newUpgradeStateTestReconciler(t, []client.Object{cp}, upgradeNodeStates(
+		canaryTestNode("node-a", upgrade.UpgradeStateUncordonRequired, nil))...)
+
+	result, err := r.Reconcile(t.Context(), upgradeSingletonRequest())
+	require.NoError(t, err)
+	assert.Equal(t, driverUpgradeHookPollInterval, result.RequeueAfter)
+	assert.Empty(t, appliedNodeNames(stateManager.appliedStates[0], upgrade.UpgradeStateUncordonRequired))
+
+	jobs := &batchv1.JobList{}
+	require.NoError(t, r.List(t.Context(), jobs, client.InNamespace(testOperatorNamespace)))
+	require.Len(t, jobs.Items, 1)
+	job := &jobs.Items[0]
+	assert.Equal(t, driverUpgradeHookPostValidation, job.Labels[driverUpgradeHookStageLabelKey])
+	assert.Equal(t, "node-a", job.Annotations[driverUpgradeHookNodeAnnotationKey])
+	podSpec := job.Spec.Template.Spec
+	assert.Equal(t, "node-a", podSpec.NodeName)
+	assert.Equal(t, corev1.RestartPolicyNever, podSpec.RestartPolicy)
+	assert.Equal(t, driverUpgradeHookServiceAccountName, podSpec.ServiceAccountName, "the hook cannot run as the operator")
+	assert.True(t, metav1.IsControlledBy(job, cp), "the Job is owned by the ClusterPolicy")
+	require.NoError(t, r.Get(t.Context(), types.NamespacedName{Namespace: testOperatorNamespace, Name: driverUpgradeHookServiceAccountName},
+		&corev1.ServiceAccount{}))
+	assert.Contains(t, podSpec.Containers[0].Env, corev1.EnvVar{Name: "NODE_NAME", Value: "node-a"})
+	assert.Contains(t, podSpec.Containers[0].Env, corev1.EnvVar{Name: "DRIVER_UPGRADE_HOOK_STAGE", Value: driverUpgradeHookPostValidation})
+
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+	require.NoError(t, r.Status().Update(t.Context(), job))
+
+	_, err = r.Reconcile(t.Context(), upgradeSingletonRequest())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"node-a"}, appliedNodeNames(stateManager.appliedStates[1], upgrade.UpgradeStateUncordonRequired))
+
+	node := &corev1.Node{}
+	require.NoError(t, r.Get(t.Context(), types.NamespacedName{Name: "node-a"}, node))
+	assert.Equal(t, driverUpgradeHookSucceeded, node.Annotations[driverUpgradeHookAnnotationKey(driverUpgradeHookPostValidation)])
+	err = r.Get(t.Context(), client.ObjectKeyFromObject(job), &batchv1.Job{})
+	assert.True(t, apierrors.IsNotFound(err), "the Job is deleted once done")
+}
+
+func TestUpgradeReconcileResetHooks(t *testing.T) {
+	hooks := &gpuv1.DriverUpgradeHooksSpec{
+		PreDrain: &gpuv1.DriverUpgradeHook{Webhook: &gpuv1.DriverUpgradeWebhook{URL: "http://127.0.0.1:1"}},
+	}
+	key := driverUpgradeHookAnnotationKey(driverUpgradeHookPreDrain)
+	nodeA := canaryTestNode("node-a", upgrade.UpgradeStateDone, nil)
+	nodeA.Annotations = map[string]string{key: driverUpgradeHookSucceeded}
+	nodeB := canaryTestNode("node-b", upgrade.UpgradeStateFailed, nil)
+	nodeB.Annotations = map[string]string{key: driverUpgradeHookFailed}
+	leftover := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+		Name:        "nvidia-driver-upgrade-pre-drain-leftover",
+		Namespace:   testOperatorNamespace,
+		Labels:      map[string]string{driverUpgradeHookStageLabelKey: driverUpgradeHookPreDrain},
+		Annotations: map[string]string{driverUpgradeHookNodeAnnotationKey: "node-a"},
+	}}
+	cp := newUpgradeTestClusterPolicy(func(p *gpuv1.DriverUpgradePolicySpec) { p.Hooks = hooks })
+	r, stateManager := newUpgradeStateTestReconciler(t, []client.Object{cp, leftover}, upgradeNodeStates(nodeA, nodeB)...)
+
+	_, err := r.Reconcile(t.Context(), upgradeSingletonRequest())
+	require.NoError(t, err)
+
+	node := &corev1.Node{}
+	require.NoError(t, r.Get(t.Context(), types.NamespacedName{Name: "node-a"}, node))
+	assert.NotContains(t, node.Annotations, key, "hook results are cleared once the upgrade is done")
+	err = r.Get(t.Context(), client.ObjectKeyFromObject(leftover), &batchv1.Job{})
+	assert.True(t, apierrors.IsNotFound(err), "hook Jobs left over are deleted")
+
+	require.NoError(t, r.Get(t.Context(), types.NamespacedName{Name: "node-b"}, node))
+	assert.Equal(t, driverUpgradeHookFailed, node.Annotations[key])
+	assert.Empty(t, appliedNodeNames(stateManager.appliedStates[0], upgrade.UpgradeStateFailed),
+		"nodes failed by a hook are held until the hook result is removed")
+}
+
+func TestDriverUpgradeHookJobResult(t *testing.T) {
+	created := time.Now()
+	tests := []struct {
+		name       string
+		conditions []batchv1.JobCondition
+		at         time.Duration
+		expected   string
+	}{
+		{
+			name:     "running",
+			at:       time.Minute,
+			expected: "",
+		},
+		{
+			name:       "complete",
+			conditions: []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}},
+			at:         time.Minute,
+			expected:   driverUpgradeHookSucceeded,
+		},
+		{
+			name:       "failed",
+			conditions: []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue}},
+			at:         time.Minute,
+			expected:   driverUpgradeHookFailed,
+		},
+		{
+			name:     "timed out",
+			at:       11 * time.Minute,
+			expected: driverUpgradeHookFailed,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			job := &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{Name: "hook", CreationTimestamp: metav1.NewTime(created)},
+				Status:     batchv1.JobStatus{Conditions: tc.conditions},
+			}
+			result, _ := driverUpgradeHookJobResult(job, gpuv1.DefaultDriverUpgradeHookTimeout, created.Add(tc.at))
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestUpgradeReconcileRemovedHooks(t *testing.T) {
+	cp := newUpgradeTestClusterPolicy(func(p *gpuv1.DriverUpgradePolicySpec) {})
+	newJob := func(name string) *batchv1.Job {
+		return &batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   testOperatorNamespace,
+			Labels:      map[string]string{driverUpgradeHookStageLabelKey: driverUpgradeHookPreDrain},
+			Annotations: map[string]string{driverUpgradeHookNodeAnnotationKey: "node-a"},
+		}}
+	}
+	r, _ := newUpgradeStateTestReconciler(t, []client.Object{cp}, upgradeNodeStates(
+		canaryTestNode("node-a", upgrade.UpgradeStateWaitForJobsRequired, nil))...)
+	require.NoError(t, r.Get(t.Context(), client.ObjectKeyFromObject(cp), cp))
+	owned := newJob("nvidia-driver-upgrade-pre-drain-owned")
+	require.NoError(t, controllerutil.SetControllerReference(cp, owned, r.Scheme))
+	require.NoError(t, r.Create(t.Context(), owned))
+	other := newJob("nvidia-driver-upgrade-pre-drain-other")
+	require.NoError(t, r.Create(t.Context(), other))
+
+	_, err := r.Reconcile(t.Context(), upgradeSingletonRequest())
+	require.NoError(t, err)
+
+	err = r.Get(t.Context(), client.ObjectKeyFromObject(owned), &batchv1.Job{})
+	assert.True(t, apierrors.IsNotFound(err), "the hook Jobs of the ClusterPolicy are deleted once its hooks are removed")
+	assert.NoError(t, r.Get(t.Context(), client.ObjectKeyFromObject(other), &batchv1.Job{}),
+		"the hook Jobs of nodes being upgraded are kept")
+}
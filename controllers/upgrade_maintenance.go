@@ -0,0 +1,87 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1"
+	"github.com/NVIDIA/gpu-operator/internal/maintenance"
+)
+
+// gateMaintenanceWindows holds back the driver upgrade of every node whose upgrade has
+// not started yet while none of the maintenance windows is open. It returns the window
+// open at now or, if none is, the next one to open, or nil if no window is configured.
+func gateMaintenanceWindows(reqLogger logr.Logger, windows []gpuv1.DriverUpgradeMaintenanceWindow, now time.Time, gate *upgradeGate) (*maintenance.Occurrence, error) {
+	if len(windows) == 0 {
+		return nil, nil
+	}
+
+	parsed := make([]*maintenance.Window, 0, len(windows))
+	for _, w := range windows {
+		window, err := maintenance.NewWindow(w.Schedule, w.Duration.Duration, w.TimeZone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid maintenance window: %w", err)
+		}
+		parsed = append(parsed, window)
+	}
+
+	holdAll := func(*corev1.Node) bool { return true }
+	next, ok := maintenance.Next(parsed, now)
+	if !ok {
+		reqLogger.Info("None of the maintenance windows ever opens, holding back new driver upgrades")
+		gate.hold(holdAll)
+		return nil, nil
+	}
+	if next.Contains(now) {
+		gate.requeueBy(next.End.Sub(now))
+		return &next, nil
+	}
+
+	reqLogger.Info("Outside of the maintenance windows, holding back new driver upgrades", "nextWindow", next.Start)
+	gate.hold(holdAll)
+	gate.requeueBy(next.Start.Sub(now))
+	return &next, nil
+}
+
+// maintenanceWindowStatus returns the status of the maintenance window occurrence o
+func maintenanceWindowStatus(o *maintenance.Occurrence) *gpuv1.MaintenanceWindowStatus {
+	if o == nil {
+		return nil
+	}
+	return &gpuv1.MaintenanceWindowStatus{
+		Start: metav1.NewTime(o.Start),
+		End:   metav1.NewTime(o.End),
+	}
+}
+
+// setMaintenanceWindowMetrics reports the next maintenance window of the driver
+// upgrades configured by the custom resource kind/name
+func (r *UpgradeReconciler) setMaintenanceWindowMetrics(kind, name string, o *maintenance.Occurrence) {
+	if o == nil {
+		r.OperatorMetrics.upgradeMaintenanceWindowStart.DeleteLabelValues(kind, name)
+		r.OperatorMetrics.upgradeMaintenanceWindowEnd.DeleteLabelValues(kind, name)
+		return
+	}
+	r.OperatorMetrics.upgradeMaintenanceWindowStart.WithLabelValues(kind, name).Set(float64(o.Start.Unix()))
+	r.OperatorMetrics.upgradeMaintenanceWindowEnd.WithLabelValues(kind, name).Set(float64(o.End.Unix()))
+}
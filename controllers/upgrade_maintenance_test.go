@@ -0,0 +1,175 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	upgrade_v1alpha1 "github.com/NVIDIA/k8s-operator-libs/api/upgrade/v1alpha1"
+	"github.com/NVIDIA/k8s-operator-libs/pkg/upgrade"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1"
+	nvidiav1alpha1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1alpha1"
+	gpuconsts "github.com/NVIDIA/gpu-operator/internal/consts"
+)
+
+// closedMaintenanceWindow returns a daily maintenance window of an hour opening in
+// about two hours
+func closedMaintenanceWindow() gpuv1.DriverUpgradeMaintenanceWindow {
+	start := time.Now().UTC().Add(2 * time.Hour)
+	return gpuv1.DriverUpgradeMaintenanceWindow{
+		Schedule: fmt.Sprintf("%d %d * * *", start.Minute(), start.Hour()),
+		Duration: metav1.Duration{Duration: time.Hour},
+	}
+}
+
+// openMaintenanceWindow returns a maintenance window open at all times
+func openMaintenanceWindow() gpuv1.DriverUpgradeMaintenanceWindow {
+	return gpuv1.DriverUpgradeMaintenanceWindow{
+		Schedule: "* * * * *",
+		Duration: metav1.Duration{Duration: time.Hour},
+	}
+}
+
+func TestGateMaintenanceWindows(t *testing.T) {
+	now := time.Date(2026, time.March, 10, 12, 0, 0, 0, time.UTC)
+	windows := []gpuv1.DriverUpgradeMaintenanceWindow{{
+		Schedule: "0 22 * * *",
+		Duration: metav1.Duration{Duration: 2 * time.Hour},
+	}}
+	newGate := func() *upgradeGate {
+		state := upgrade.NewClusterUpgradeState()
+		state.NodeStates[upgrade.UpgradeStateUpgradeRequired] = []*upgrade.NodeUpgradeState{
+			{Node: canaryTestNode("node-a", upgrade.UpgradeStateUpgradeRequired, nil)},
+		}
+		state.NodeStates[upgrade.UpgradeStateCordonRequired] = []*upgrade.NodeUpgradeState{
+			{Node: canaryTestNode("node-b", upgrade.UpgradeStateCordonRequired, nil)},
+		}
+		return newUpgradeGate(&state, &upgrade_v1alpha1.DriverUpgradePolicySpec{AutoUpgrade: true}, 1)
+	}
+
+	t.Run("no maintenance window", func(t *testing.T) {
+		gate := newGate()
+		next, err := gateMaintenanceWindows(logr.Discard(), nil, now, gate)
+		require.NoError(t, err)
+		assert.Nil(t, next)
+		assert.Equal(t, []string{"node-a"}, appliedNodeNames(gate.state, upgrade.UpgradeStateUpgradeRequired))
+		assert.Equal(t, plannedRequeueInterval, gate.requeueAfter)
+	})
+
+	t.Run("outside of the maintenance windows", func(t *testing.T) {
+		gate := newGate()
+		next, err := gateMaintenanceWindows(logr.Discard(), windows, now, gate)
+		require.NoError(t, err)
+		require.NotNil(t, next)
+		assert.Equal(t, now.Add(10*time.Hour), next.Start)
+		assert.Empty(t, appliedNodeNames(gate.state, upgrade.UpgradeStateUpgradeRequired))
+		assert.Equal(t, []string{"node-b"}, appliedNodeNames(gate.state, upgrade.UpgradeStateCordonRequired))
+		assert.Equal(t, plannedRequeueInterval, gate.requeueAfter)
+	})
+
+	t.Run("maintenance window opening soon", func(t *testing.T) {
+		gate := newGate()
+		_, err := gateMaintenanceWindows(logr.Discard(), windows, now.Add(10*time.Hour-time.Minute), gate)
+		require.NoError(t, err)
+		assert.Equal(t, time.Minute, gate.requeueAfter)
+	})
+
+	t.Run("inside of a maintenance window", func(t *testing.T) {
+		gate := newGate()
+		next, err := gateMaintenanceWindows(logr.Discard(), windows, now.Add(11*time.Hour), gate)
+		require.NoError(t, err)
+		require.NotNil(t, next)
+		assert.True(t, next.Contains(now.Add(11*time.Hour)))
+		assert.Equal(t, []string{"node-a"}, appliedNodeNames(gate.state, upgrade.UpgradeStateUpgradeRequired))
+		assert.Nil(t, gate.policy.MaxUnavailable)
+	})
+
+	t.Run("invalid maintenance window", func(t *testing.T) {
+		invalid := []gpuv1.DriverUpgradeMaintenanceWindow{{Schedule: "@nightly", Duration: metav1.Duration{Duration: time.Hour}}}
+		_, err := gateMaintenanceWindows(logr.Discard(), invalid, now, newGate())
+		require.Error(t, err)
+	})
+}
+
+func TestUpgradeReconcileMaintenanceWindows(t *testing.T) {
+	t.Run("nodes are upgraded inside of a maintenance window", func(t *testing.T) {
+		cp := newUpgradeTestClusterPolicy(func(p *gpuv1.DriverUpgradePolicySpec) {
+			p.MaintenanceWindows = []gpuv1.DriverUpgradeMaintenanceWindow{openMaintenanceWindow()}
+		})
+		r, stateManager := newUpgradeStateTestReconciler(t, []client.Object{cp}, upgradeNodeStates(
+			canaryTestNode("node-a", upgrade.UpgradeStateUpgradeRequired, nil))...)
+
+		_, err := r.Reconcile(t.Context(), upgradeSingletonRequest())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"node-a"}, appliedNodeNames(stateManager.appliedStates[0], upgrade.UpgradeStateUpgradeRequired))
+	})
+
+	t.Run("new upgrades are held back outside of the maintenance windows", func(t *testing.T) {
+		cp := newUpgradeTestClusterPolicy(func(p *gpuv1.DriverUpgradePolicySpec) {
+			p.MaintenanceWindows = []gpuv1.DriverUpgradeMaintenanceWindow{closedMaintenanceWindow()}
+		})
+		r, stateManager := newUpgradeStateTestReconciler(t, []client.Object{cp}, upgradeNodeStates(
+			canaryTestNode("node-a", upgrade.UpgradeStateUpgradeRequired, nil),
+			canaryTestNode("node-b", upgrade.UpgradeStateDrainRequired, nil))...)
+
+		_, err := r.Reconcile(t.Context(), upgradeSingletonRequest())
+		require.NoError(t, err)
+		assert.Empty(t, appliedNodeNames(stateManager.appliedStates[0], upgrade.UpgradeStateUpgradeRequired))
+		assert.Equal(t, []string{"node-b"}, appliedNodeNames(stateManager.appliedStates[0], upgrade.UpgradeStateDrainRequired))
+
+		updated := &gpuv1.ClusterPolicy{}
+		require.NoError(t, r.Get(t.Context(), types.NamespacedName{Name: cp.Name}, updated))
+		require.NotNil(t, updated.Status.Upgrade)
+		require.NotNil(t, updated.Status.Upgrade.NextMaintenanceWindow)
+		window := updated.Status.Upgrade.NextMaintenanceWindow
+		assert.WithinDuration(t, time.Now().Add(2*time.Hour), window.Start.Time, time.Minute)
+		assert.Equal(t, time.Hour, window.End.Sub(window.Start.Time))
+	})
+
+	t.Run("NVIDIADriver outside of the maintenance windows", func(t *testing.T) {
+		nvd := &nvidiav1alpha1.NVIDIADriver{
+			ObjectMeta: metav1.ObjectMeta{Name: "gpu-driver"},
+			Spec: nvidiav1alpha1.NVIDIADriverSpec{
+				UpgradePolicy: &nvidiav1alpha1.DriverUpgradePolicySpec{
+					AutoUpgrade:        true,
+					MaintenanceWindows: []nvidiav1alpha1.DriverUpgradeMaintenanceWindow{closedMaintenanceWindow()},
+				},
+			},
+		}
+		owned := map[string]string{gpuconsts.NVIDIADriverOwnerLabel: nvd.Name}
+		r, stateManager := newUpgradeStateTestReconciler(t, []client.Object{nvd}, upgradeNodeStates(
+			canaryTestNode("node-a", upgrade.UpgradeStateUpgradeRequired, owned))...)
+
+		_, err := r.Reconcile(t.Context(), upgradeSingletonRequest())
+		require.NoError(t, err)
+		assert.Empty(t, appliedNodeNames(stateManager.appliedStates[0], upgrade.UpgradeStateUpgradeRequired))
+
+		updated := &nvidiav1alpha1.NVIDIADriver{}
+		require.NoError(t, r.Get(t.Context(), types.NamespacedName{Name: nvd.Name}, updated))
+		require.NotNil(t, updated.Status.Upgrade)
+		assert.NotNil(t, updated.Status.Upgrade.NextMaintenanceWindow)
+	})
+}
@@ -0,0 +1,215 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	upgrade_v1alpha1 "github.com/NVIDIA/k8s-operator-libs/api/upgrade/v1alpha1"
+	"github.com/NVIDIA/k8s-operator-libs/pkg/consts"
+	"github.com/NVIDIA/k8s-operator-libs/pkg/upgrade"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1"
+)
+
+// podNodeNameField is the pod field selecting the pods of a node
+const podNodeNameField = "spec.nodeName"
+
+// planDriverUpgrade computes the driver upgrade of the nodes of state without upgrading
+// any of them. Nodes are upgraded canary nodes first, then one topology domain at a time
+// when upgrades are batched by topology, then in name order, in batches sized by the
+// maxParallelUpgrades and maxUnavailable of policy.
+func (r *UpgradeReconciler) planDriverUpgrade(ctx context.Context, state *upgrade.ClusterUpgradeState,
+	policy *upgrade_v1alpha1.DriverUpgradePolicySpec, canary *gpuv1.DriverUpgradeCanarySpec,
+	topology *gpuv1.DriverUpgradeTopologySpec, maxUnavailable int) (*gpuv1.DriverUpgradePlan, error) {
+	nodes, err := r.outdatedDriverNodes(ctx, state)
+	if err != nil {
+		return nil, err
+	}
+
+	evicted, err := drainedPodSelector(policy)
+	if err != nil {
+		return nil, err
+	}
+	if evicted != nil {
+		for i := range nodes {
+			if nodes[i].Evictions, err = r.driverUpgradeEvictions(ctx, nodes[i].Name, evicted); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	isCanary := map[string]bool{}
+	if canary != nil {
+		canaries, err := selectCanaryNodes(canary, state)
+		if err != nil {
+			return nil, err
+		}
+		for _, nodeState := range canaries {
+			isCanary[nodeState.Node.Name] = true
+		}
+	}
+	domains := map[string]string{}
+	if topology != nil {
+		for _, nodeStates := range state.NodeStates {
+			for _, nodeState := range nodeStates {
+				domains[nodeState.Node.Name] = nodeState.Node.Labels[topology.TopologyKey]
+			}
+		}
+	}
+	sort.SliceStable(nodes, func(i, j int) bool {
+		if isCanary[nodes[i].Name] != isCanary[nodes[j].Name] {
+			return isCanary[nodes[i].Name]
+		}
+		return topologyDomainLess(domains[nodes[i].Name], domains[nodes[j].Name])
+	})
+
+	batchSize := min(len(nodes), maxUnavailable)
+	if policy.MaxParallelUpgrades > 0 {
+		batchSize = min(batchSize, policy.MaxParallelUpgrades)
+	}
+	if topology != nil {
+		batchSize = min(batchSize, topology.GetMaxUnavailablePerDomain())
+	}
+	plan := &gpuv1.DriverUpgradePlan{
+		NodesToUpgrade: int32(len(nodes)),
+		BatchSize:      int32(batchSize),
+	}
+	if batchSize == 0 {
+		return plan, nil
+	}
+	for start := 0; start < len(nodes); {
+		// Canary nodes are not batched along with the other nodes, as those wait for the
+		// canary phase, nor nodes of different topology domains
+		batch := gpuv1.DriverUpgradePlanBatch{Canary: isCanary[nodes[start].Name], Domain: domains[nodes[start].Name]}
+		end := start
+		for end < len(nodes) && end-start < batchSize &&
+			isCanary[nodes[end].Name] == batch.Canary && domains[nodes[end].Name] == batch.Domain {
+			end++
+		}
+		batch.Nodes = nodes[start:end]
+		plan.Batches = append(plan.Batches, batch)
+		start = end
+	}
+	return plan, nil
+}
+
+// outdatedDriverNodes returns the nodes of state whose driver pod does not run the
+// current revision of its DaemonSet, in name order
+func (r *UpgradeReconciler) outdatedDriverNodes(ctx context.Context, state *upgrade.ClusterUpgradeState) ([]gpuv1.DriverUpgradePlanNode, error) {
+	daemonsetRevisionHashes := map[string]string{}
+
+	var nodes []gpuv1.DriverUpgradePlanNode
+	for _, nodeStates := range state.NodeStates {
+		for _, nodeState := range nodeStates {
+			if nodeState.DriverPod == nil {
+				continue
+			}
+			podRevisionHash, err := getPodControllerRevisionHash(ctx, nodeState.DriverPod)
+			if err != nil {
+				r.Log.V(consts.LogLevelWarning).Info("Failed to get the driver pod revision hash, leaving the node out of the upgrade plan",
+					"node", nodeState.Node.Name, "error", err)
+				continue
+			}
+
+			// Orphaned driver pods are replaced by the pods of the new driver DaemonSet
+			daemonsetRevisionHash := ""
+			if ds := nodeState.DriverDaemonSet; ds != nil {
+				hash, ok := daemonsetRevisionHashes[ds.Name]
+				if !ok {
+					hash, err = getDaemonsetControllerRevisionHash(ctx, r.Client, r.Log, r.OperatorNamespace, ds)
+					if err != nil {
+						return nil, err
+					}
+					daemonsetRevisionHashes[ds.Name] = hash
+				}
+				if hash == podRevisionHash {
+					continue
+				}
+				daemonsetRevisionHash = hash
+			}
+			nodes = append(nodes, gpuv1.DriverUpgradePlanNode{
+				Name:            nodeState.Node.Name,
+				CurrentRevision: podRevisionHash,
+				TargetRevision:  daemonsetRevisionHash,
+			})
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+	return nodes, nil
+}
+
+// drainedPodSelector returns the selector of the pods evicted from the nodes upgraded
+// with policy, or nil if pods are neither deleted nor drained
+func drainedPodSelector(policy *upgrade_v1alpha1.DriverUpgradePolicySpec) (labels.Selector, error) {
+	drain := policy.DrainSpec != nil && policy.DrainSpec.Enable
+	if policy.PodDeletion == nil && !drain {
+		return nil, nil
+	}
+	if policy.DrainSpec == nil {
+		return labels.Everything(), nil
+	}
+	selector, err := labels.Parse(policy.DrainSpec.PodSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid drain pod selector: %w", err)
+	}
+	return selector, nil
+}
+
+// driverUpgradeEvictions returns the GPU pods of node matching selector, which the
+// upgrade of node evicts, as namespace/name
+func (r *UpgradeReconciler) driverUpgradeEvictions(ctx context.Context, node string, selector labels.Selector) ([]string, error) {
+	podList := &corev1.PodList{}
+	err := r.APIReader.List(ctx, podList, client.MatchingFields{podNodeNameField: node}, client.MatchingLabelsSelector{Selector: selector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the pods of node %s: %w", node, err)
+	}
+
+	var evictions []string
+	for _, pod := range podList.Items {
+		// DaemonSet pods are not evicted
+		if owner := metav1.GetControllerOf(&pod); owner != nil && owner.Kind == "DaemonSet" {
+			continue
+		}
+		if !r.GPUPodFilter(pod) {
+			continue
+		}
+		evictions = append(evictions, fmt.Sprintf("%s/%s", pod.Namespace, pod.Name))
+	}
+	sort.Strings(evictions)
+	return evictions, nil
+}
+
+// setDriverUpgradePlan sets plan in status, keeping the time of the current plan if the
+// plan did not change, so that the status is not updated on every reconciliation
+func setDriverUpgradePlan(status *gpuv1.DriverUpgradeStatus, plan *gpuv1.DriverUpgradePlan, now metav1.Time) {
+	plan.Time = now
+	if status.Plan != nil {
+		plan.Time = status.Plan.Time
+		if !apiequality.Semantic.DeepEqual(status.Plan, plan) {
+			plan.Time = now
+		}
+	}
+	status.Plan = plan
+}
@@ -0,0 +1,242 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	upgrade_v1alpha1 "github.com/NVIDIA/k8s-operator-libs/api/upgrade/v1alpha1"
+	"github.com/NVIDIA/k8s-operator-libs/pkg/upgrade"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1"
+	nvidiav1alpha1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1alpha1"
+	gpuconsts "github.com/NVIDIA/gpu-operator/internal/consts"
+)
+
+const planDaemonSetName = "nvidia-driver-daemonset"
+
+// planObjects returns the driver DaemonSet, whose current revision is "new", along with
+// its controller revisions
+func planObjects() (*appsv1.DaemonSet, []client.Object) {
+	selector := map[string]string{DriverLabelKey: DriverLabelValue}
+	ds := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: planDaemonSetName, Namespace: testOperatorNamespace},
+		Spec:       appsv1.DaemonSetSpec{Selector: &metav1.LabelSelector{MatchLabels: selector}},
+	}
+	objs := []client.Object{ds}
+	for revision, hash := range []string{"old", "new"} {
+		objs = append(objs, &appsv1.ControllerRevision{
+			ObjectMeta: metav1.ObjectMeta{Name: planDaemonSetName + "-" + hash, Namespace: testOperatorNamespace, Labels: selector},
+			Revision:   int64(revision + 1),
+		})
+	}
+	return ds, objs
+}
+
+// planNodeState returns the state of a node whose driver pod of ds runs revision hash
+func planNodeState(name, hash string, ds *appsv1.DaemonSet, nodeLabels map[string]string) *upgrade.NodeUpgradeState {
+	return &upgrade.NodeUpgradeState{
+		Node: canaryTestNode(name, "", nodeLabels),
+		DriverPod: &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      planDaemonSetName + "-" + name,
+				Namespace: testOperatorNamespace,
+				Labels:    map[string]string{PodControllerRevisionHashLabelKey: hash},
+			},
+			Spec: corev1.PodSpec{NodeName: name},
+		},
+		DriverDaemonSet: ds,
+	}
+}
+
+func workloadPod(name, node string, gpus int64, podLabels map[string]string) *corev1.Pod {
+	container := corev1.Container{Name: "main"}
+	if gpus > 0 {
+		container.Resources.Limits = corev1.ResourceList{"nvidia.com/gpu": *resource.NewQuantity(gpus, resource.DecimalSI)}
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "team", Labels: podLabels},
+		Spec:       corev1.PodSpec{NodeName: node, Containers: []corev1.Container{container}},
+	}
+}
+
+func TestPlanDriverUpgrade(t *testing.T) {
+	ds, objs := planObjects()
+	canaryLabels := map[string]string{"example.com/canary": "true"}
+	state := upgrade.NewClusterUpgradeState()
+	state.NodeStates[upgrade.UpgradeStateUnknown] = []*upgrade.NodeUpgradeState{
+		planNodeState("node-b", "old", ds, nil),
+		planNodeState("node-a", "old", ds, nil),
+		planNodeState("node-c", "new", ds, nil),
+		planNodeState("node-d", "old", ds, canaryLabels),
+		planNodeState("node-e", "old", ds, nil),
+	}
+	daemonSetPod := workloadPod("monitor", "node-a", 1, nil)
+	daemonSetPod.OwnerReferences = []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "DaemonSet", Name: "monitor", UID: "uid", Controller: ptr.To(true)}}
+	objs = append(objs,
+		workloadPod("train", "node-a", 8, nil),
+		workloadPod("web", "node-a", 0, nil),
+		workloadPod("pinned", "node-a", 1, map[string]string{"nvidia.com/gpu-driver-upgrade-drain.skip": "true"}),
+		daemonSetPod,
+		workloadPod("infer", "node-d", 1, nil),
+	)
+	r, _ := newTestUpgradeReconciler(t, objs...)
+
+	policy := &upgrade_v1alpha1.DriverUpgradePolicySpec{
+		MaxParallelUpgrades: 2,
+		PodDeletion:         &upgrade_v1alpha1.PodDeletionSpec{},
+		DrainSpec:           &upgrade_v1alpha1.DrainSpec{PodSelector: UpgradeSkipDrainLabelSelector},
+	}
+	canary := &gpuv1.DriverUpgradeCanarySpec{NodeSelector: &metav1.LabelSelector{MatchLabels: canaryLabels}}
+	plan, err := r.planDriverUpgrade(t.Context(), &state, policy, canary, nil, 5)
+	require.NoError(t, err)
+	assert.EqualValues(t, 4, plan.NodesToUpgrade)
+	assert.EqualValues(t, 2, plan.BatchSize)
+
+	outdated := func(name string, evictions ...string) gpuv1.DriverUpgradePlanNode {
+		return gpuv1.DriverUpgradePlanNode{Name: name, CurrentRevision: "old", TargetRevision: "new", Evictions: evictions}
+	}
+	assert.Equal(t, []gpuv1.DriverUpgradePlanBatch{
+		{Canary: true, Nodes: []gpuv1.DriverUpgradePlanNode{outdated("node-d", "team/infer")}},
+		{Nodes: []gpuv1.DriverUpgradePlanNode{outdated("node-a", "team/train"), outdated("node-b")}},
+		{Nodes: []gpuv1.DriverUpgradePlanNode{outdated("node-e")}},
+	}, plan.Batches)
+
+	t.Run("batches bounded by maxUnavailable", func(t *testing.T) {
+		plan, err := r.planDriverUpgrade(t.Context(), &state, policy, nil, nil, 1)
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, plan.BatchSize)
+		assert.Len(t, plan.Batches, 4)
+	})
+
+	t.Run("batches bounded by topology domain", func(t *testing.T) {
+		zone := func(name, hash, zone string) *upgrade.NodeUpgradeState {
+			return planNodeState(name, hash, ds, map[string]string{"topology.kubernetes.io/zone": zone})
+		}
+		state := upgrade.NewClusterUpgradeState()
+		state.NodeStates[upgrade.UpgradeStateUnknown] = []*upgrade.NodeUpgradeState{
+			zone("node-a", "old", "zone-b"),
+			zone("node-b", "old", "zone-a"),
+			zone("node-c", "old", "zone-b"),
+			zone("node-d", "old", "zone-a"),
+			zone("node-e", "old", "zone-a"),
+			planNodeState("node-f", "old", ds, nil),
+		}
+		topology := &gpuv1.DriverUpgradeTopologySpec{TopologyKey: "topology.kubernetes.io/zone", MaxUnavailablePerDomain: 2}
+		plan, err := r.planDriverUpgrade(t.Context(), &state, &upgrade_v1alpha1.DriverUpgradePolicySpec{}, nil, topology, 5)
+		require.NoError(t, err)
+		assert.EqualValues(t, 2, plan.BatchSize)
+
+		var batches [][]string
+		var domains []string
+		for _, batch := range plan.Batches {
+			var names []string
+			for _, node := range batch.Nodes {
+				names = append(names, node.Name)
+			}
+			batches = append(batches, names)
+			domains = append(domains, batch.Domain)
+		}
+		assert.Equal(t, [][]string{{"node-b", "node-d"}, {"node-e"}, {"node-a", "node-c"}, {"node-f"}}, batches)
+		assert.Equal(t, []string{"zone-a", "zone-a", "zone-b", ""}, domains)
+	})
+
+	t.Run("no evictions without pod deletion nor drain", func(t *testing.T) {
+		plan, err := r.planDriverUpgrade(t.Context(), &state, &upgrade_v1alpha1.DriverUpgradePolicySpec{}, nil, nil, 5)
+		require.NoError(t, err)
+		assert.EqualValues(t, 4, plan.BatchSize)
+		require.Len(t, plan.Batches, 1)
+		assert.Empty(t, plan.Batches[0].Nodes[0].Evictions)
+	})
+}
+
+func TestUpgradeReconcilePlan(t *testing.T) {
+	ds, objs := planObjects()
+	cp := newUpgradeTestClusterPolicy(nil)
+	cp.Spec.Driver.UpgradePolicy.AutoUpgrade = false
+	cp.Spec.Driver.UpgradePolicy.Plan = true
+	r, stateManager := newUpgradeStateTestReconciler(t, append(objs, cp),
+		planNodeState("node-a", "old", ds, nil),
+		planNodeState("node-b", "new", ds, nil))
+
+	_, err := r.Reconcile(t.Context(), upgradeSingletonRequest())
+	require.NoError(t, err)
+	assert.Empty(t, stateManager.appliedStates, "no node is upgraded in plan mode")
+
+	instance := &gpuv1.ClusterPolicy{}
+	require.NoError(t, r.Get(t.Context(), types.NamespacedName{Name: cp.Name}, instance))
+	require.NotNil(t, instance.Status.Upgrade)
+	require.NotNil(t, instance.Status.Upgrade.Plan)
+	assert.EqualValues(t, 1, instance.Status.Upgrade.Plan.NodesToUpgrade)
+	assert.WithinDuration(t, time.Now(), instance.Status.Upgrade.Plan.Time.Time, time.Minute)
+
+	node := &corev1.Node{}
+	require.NoError(t, r.Get(t.Context(), types.NamespacedName{Name: "node-a"}, node))
+	assert.Empty(t, node.Labels[upgrade.GetUpgradeStateLabelKey()])
+
+	// the plan time only changes along with the plan
+	planTime := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	instance.Status.Upgrade.Plan.Time = planTime
+	require.NoError(t, r.Status().Update(t.Context(), instance))
+	_, err = r.Reconcile(t.Context(), upgradeSingletonRequest())
+	require.NoError(t, err)
+	require.NoError(t, r.Get(t.Context(), types.NamespacedName{Name: cp.Name}, instance))
+	assert.True(t, planTime.Equal(&instance.Status.Upgrade.Plan.Time))
+
+	t.Run("plan is removed once plan mode is disabled", func(t *testing.T) {
+		instance.Spec.Driver.UpgradePolicy.Plan = false
+		require.NoError(t, r.Update(t.Context(), instance))
+		_, err := r.Reconcile(t.Context(), upgradeSingletonRequest())
+		require.NoError(t, err)
+		require.NoError(t, r.Get(t.Context(), types.NamespacedName{Name: cp.Name}, instance))
+		assert.Nil(t, instance.Status.Upgrade)
+	})
+}
+
+func TestUpgradeReconcileNVIDIADriverPlan(t *testing.T) {
+	ds, objs := planObjects()
+	nvd := &nvidiav1alpha1.NVIDIADriver{
+		ObjectMeta: metav1.ObjectMeta{Name: "gpu-driver"},
+		Spec: nvidiav1alpha1.NVIDIADriverSpec{
+			UpgradePolicy: &nvidiav1alpha1.DriverUpgradePolicySpec{AutoUpgrade: true, Plan: true},
+		},
+	}
+	owned := map[string]string{gpuconsts.NVIDIADriverOwnerLabel: nvd.Name}
+	r, stateManager := newUpgradeStateTestReconciler(t, append(objs, nvd),
+		planNodeState("node-a", "old", ds, owned),
+		planNodeState("node-b", "new", ds, owned))
+
+	_, err := r.Reconcile(t.Context(), upgradeSingletonRequest())
+	require.NoError(t, err)
+	assert.Empty(t, stateManager.appliedStates)
+
+	instance := &nvidiav1alpha1.NVIDIADriver{}
+	require.NoError(t, r.Get(t.Context(), types.NamespacedName{Name: nvd.Name}, instance))
+	require.NotNil(t, instance.Status.Upgrade)
+	require.NotNil(t, instance.Status.Upgrade.Plan)
+	assert.EqualValues(t, 1, instance.Status.Upgrade.Plan.NodesToUpgrade)
+}
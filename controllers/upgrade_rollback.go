@@ -0,0 +1,285 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/NVIDIA/k8s-operator-libs/pkg/consts"
+	"github.com/NVIDIA/k8s-operator-libs/pkg/upgrade"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1"
+	driverconfig "github.com/NVIDIA/gpu-operator/internal/config"
+	"github.com/NVIDIA/gpu-operator/internal/image"
+)
+
+const (
+	// maxDriverRevisionHistory is the number of driver revisions kept in the upgrade status
+	maxDriverRevisionHistory = 5
+	// driverContainerName is the name of the driver container of the driver pods
+	driverContainerName = "nvidia-driver-ctr"
+	// driverRollbackAnnotation is the annotation of the custom resource configuring the
+	// driver holding its last automatic rollback. It is set by the patch rolling the driver
+	// image back, so that the rollback is known even if the status update that follows fails.
+	driverRollbackAnnotation = "nvidia.com/gpu-driver-rollback"
+)
+
+// driverRollback is the driver configured by a custom resource, whose failed upgrades
+// can be rolled back
+type driverRollback struct {
+	// obj is the custom resource the driver is configured by
+	obj client.Object
+	// spec holds the rollback settings, nil if rollback is disabled
+	spec *gpuv1.DriverUpgradeRollbackSpec
+	// current is the driver image currently configured by obj
+	current gpuv1.DriverRevision
+	// imagePath is the path of the driver image currently configured by obj
+	imagePath string
+	// setImage configures obj with the driver image of revision
+	setImage func(revision *gpuv1.DriverRevision)
+}
+
+// reconcileDriverRollback records the driver image in the history of status once it is
+// rolled out to all the nodes of state. If the upgrade to a driver configuration that
+// was never rolled out to all nodes fails on more nodes than allowed, the driver image
+// is rolled back to the last one in the history, unless that image is the current one.
+// It returns whether the driver was rolled back.
+func (r *UpgradeReconciler) reconcileDriverRollback(ctx context.Context, reqLogger logr.Logger, d *driverRollback,
+	state *upgrade.ClusterUpgradeState, totalNodes int, status *gpuv1.DriverUpgradeStatus) (bool, error) {
+	digests := driverConfigDigests(state)
+	if d.imagePath != "" && isDriverRolloutComplete(state, d.imagePath) {
+		revision := d.current
+		revision.ConfigDigests = digests
+		revision.Time = metav1.Now()
+		recordDriverRevision(status, revision)
+	}
+
+	if rollback := annotatedDriverRollback(reqLogger, d.obj); rollback != nil {
+		status.LastRollback = rollback
+	}
+
+	if d.spec == nil {
+		return false, nil
+	}
+
+	if err := r.restartRolledBackDriverPods(ctx, reqLogger, state, status.LastRollback); err != nil {
+		return false, err
+	}
+
+	failedNodes := len(state.NodeStates[upgrade.UpgradeStateFailed])
+	maxFailedNodes := 0
+	if d.spec.MaxFailedNodes != nil {
+		var err error
+		maxFailedNodes, err = intstr.GetScaledValueFromIntOrPercent(d.spec.MaxFailedNodes, totalNodes, false)
+		if err != nil {
+			return false, fmt.Errorf("invalid maxFailedNodes: %w", err)
+		}
+	}
+	if failedNodes <= maxFailedNodes {
+		return false, nil
+	}
+
+	// Failures with a configuration rolled out to all nodes before are not caused by
+	// the upgrade
+	if slices.ContainsFunc(status.History, func(revision gpuv1.DriverRevision) bool {
+		return slices.Equal(revision.ConfigDigests, digests)
+	}) {
+		return false, nil
+	}
+	// A configuration the driver was already rolled back from was put back on purpose
+	if status.LastRollback != nil && slices.Equal(status.LastRollback.FailedConfigDigests, digests) {
+		reqLogger.Info("Driver upgrade failed again after a rollback, not rolling back twice", "failedNodes", failedNodes)
+		return false, nil
+	}
+	if len(status.History) == 0 {
+		reqLogger.Info("Driver upgrade failed on too many nodes, but no driver image to roll back to was recorded",
+			"failedNodes", failedNodes)
+		return false, nil
+	}
+	revision := status.History[0].DeepCopy()
+	// Only the driver image is rolled back: a failed change of the rest of the driver
+	// configuration can not be undone by the rollback
+	if revision.Repository == d.current.Repository && revision.Image == d.current.Image && revision.Version == d.current.Version {
+		reqLogger.Info("Driver upgrade failed on too many nodes, but the driver image to roll back to is the current one",
+			"failedNodes", failedNodes, "image", driverRevisionImage(revision))
+		r.recorder.Eventf(d.obj, nil, corev1.EventTypeWarning, "DriverRollbackSkipped", "RollbackDriver",
+			"Driver upgrade failed on %d nodes, but only the driver image is rolled back and %s is already configured",
+			failedNodes, driverRevisionImage(revision))
+		return false, nil
+	}
+
+	rollback := &gpuv1.DriverRollbackStatus{
+		Time:                metav1.Now(),
+		FailedNodes:         int32(failedNodes),
+		FailedConfigDigests: digests,
+		Revision:            *revision,
+	}
+	data, err := json.Marshal(rollback)
+	if err != nil {
+		return false, err
+	}
+	// The rollback is recorded along with the driver image in a single patch: were it only
+	// recorded in the status afterwards, a failed status update would let the rolled back
+	// configuration, failing again, be taken for a new upgrade and rolled back once more.
+	// A GitOps tool reverting the driver image of the spec puts the failed image back, which
+	// the recorded rollback keeps from being rolled back again.
+	patch := client.MergeFrom(d.obj.DeepCopyObject().(client.Object))
+	d.setImage(revision)
+	annotations := d.obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[driverRollbackAnnotation] = string(data)
+	d.obj.SetAnnotations(annotations)
+	if err := r.Patch(ctx, d.obj, patch); err != nil {
+		r.Log.Error(err, "Failed to roll back the driver image", "name", d.obj.GetName())
+		return false, err
+	}
+
+	reqLogger.Info("Driver upgrade failed on too many nodes, rolled the driver back",
+		"failedNodes", failedNodes, "image", driverRevisionImage(revision))
+	status.LastRollback = rollback
+	r.recorder.Eventf(d.obj, nil, corev1.EventTypeWarning, "DriverRolledBack", "RollbackDriver",
+		"Driver upgrade failed on %d nodes, rolled the driver back to %s", failedNodes, driverRevisionImage(revision))
+	return true, nil
+}
+
+// annotatedDriverRollback returns the last rollback of the driver configured by obj, as
+// recorded in its annotation, or nil if it has none
+func annotatedDriverRollback(reqLogger logr.Logger, obj client.Object) *gpuv1.DriverRollbackStatus {
+	value, ok := obj.GetAnnotations()[driverRollbackAnnotation]
+	if !ok {
+		return nil
+	}
+	rollback := &gpuv1.DriverRollbackStatus{}
+	if err := json.Unmarshal([]byte(value), rollback); err != nil {
+		reqLogger.V(consts.LogLevelWarning).Info("Ignoring invalid driver rollback annotation",
+			"annotation", driverRollbackAnnotation, "error", err)
+		return nil
+	}
+	return rollback
+}
+
+// restartRolledBackDriverPods deletes the driver pods of the nodes the upgrade failed on
+// once their DaemonSet is rolled back, so that they restart with the driver rolled back
+// to and the upgrade state manager can move the nodes out of the failed state
+func (r *UpgradeReconciler) restartRolledBackDriverPods(ctx context.Context, reqLogger logr.Logger,
+	state *upgrade.ClusterUpgradeState, rollback *gpuv1.DriverRollbackStatus) error {
+	if rollback == nil {
+		return nil
+	}
+
+	for _, nodeState := range state.NodeStates[upgrade.UpgradeStateFailed] {
+		if nodeState.DriverDaemonSet == nil || nodeState.DriverPod == nil {
+			continue
+		}
+		digest := driverconfig.DriverConfigDigestFromPodSpec(&nodeState.DriverDaemonSet.Spec.Template.Spec)
+		if !slices.Contains(rollback.Revision.ConfigDigests, digest) ||
+			driverconfig.DriverConfigDigestFromPodSpec(&nodeState.DriverPod.Spec) == digest {
+			continue
+		}
+		reqLogger.Info("Restarting the driver pod of a node the upgrade failed on after the rollback",
+			"node", nodeState.Node.Name, "pod", nodeState.DriverPod.Name)
+		if err := r.Delete(ctx, nodeState.DriverPod); client.IgnoreNotFound(err) != nil {
+			r.Log.Error(err, "Failed to delete driver pod", "pod", nodeState.DriverPod.Name)
+			return err
+		}
+	}
+	return nil
+}
+
+// driverConfigDigests returns the sorted DRIVER_CONFIG_DIGEST values of the driver
+// DaemonSets of the nodes in state
+func driverConfigDigests(state *upgrade.ClusterUpgradeState) []string {
+	digests := []string{}
+	for _, nodeStates := range state.NodeStates {
+		for _, nodeState := range nodeStates {
+			if nodeState.DriverDaemonSet == nil {
+				continue
+			}
+			digest := driverconfig.DriverConfigDigestFromPodSpec(&nodeState.DriverDaemonSet.Spec.Template.Spec)
+			if digest != "" && !slices.Contains(digests, digest) {
+				digests = append(digests, digest)
+			}
+		}
+	}
+	slices.Sort(digests)
+	return digests
+}
+
+// isDriverRolloutComplete returns whether the upgrade is done on every node of state,
+// each of them running the driver pod of its DaemonSet with the image at imagePath
+func isDriverRolloutComplete(state *upgrade.ClusterUpgradeState, imagePath string) bool {
+	nodes := 0
+	for stateKey, nodeStates := range state.NodeStates {
+		if len(nodeStates) == 0 {
+			continue
+		}
+		if stateKey != upgrade.UpgradeStateDone {
+			return false
+		}
+		for _, nodeState := range nodeStates {
+			if nodeState.DriverDaemonSet == nil || nodeState.DriverPod == nil {
+				return false
+			}
+			podSpec := &nodeState.DriverDaemonSet.Spec.Template.Spec
+			digest := driverconfig.DriverConfigDigestFromPodSpec(podSpec)
+			if digest == "" || driverconfig.DriverConfigDigestFromPodSpec(&nodeState.DriverPod.Spec) != digest {
+				return false
+			}
+			container := findContainerByName(podSpec.Containers, driverContainerName)
+			if container == nil || !strings.HasPrefix(container.Image, imagePath) {
+				return false
+			}
+			nodes++
+		}
+	}
+	return nodes > 0
+}
+
+// recordDriverRevision adds revision at the head of the history of status, unless its
+// configuration is the last one recorded
+func recordDriverRevision(status *gpuv1.DriverUpgradeStatus, revision gpuv1.DriverRevision) {
+	if len(status.History) > 0 && slices.Equal(status.History[0].ConfigDigests, revision.ConfigDigests) {
+		return
+	}
+	history := slices.DeleteFunc(status.History, func(r gpuv1.DriverRevision) bool {
+		return slices.Equal(r.ConfigDigests, revision.ConfigDigests)
+	})
+	status.History = append([]gpuv1.DriverRevision{revision}, history...)
+	if len(status.History) > maxDriverRevisionHistory {
+		status.History = status.History[:maxDriverRevisionHistory]
+	}
+}
+
+// driverRevisionImage describes the driver image of revision
+func driverRevisionImage(revision *gpuv1.DriverRevision) string {
+	path, err := image.ImagePath(revision.Repository, revision.Image, revision.Version, "")
+	if err != nil {
+		return fmt.Sprintf("the driver configuration %s", strings.Join(revision.ConfigDigests, ","))
+	}
+	return path
+}
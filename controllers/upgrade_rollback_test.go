@@ -0,0 +1,339 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/NVIDIA/k8s-operator-libs/pkg/upgrade"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1"
+	nvidiav1alpha1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1alpha1"
+	driverconfig "github.com/NVIDIA/gpu-operator/internal/config"
+	gpuconsts "github.com/NVIDIA/gpu-operator/internal/consts"
+)
+
+func driverPodSpec(digest, version string) corev1.PodSpec {
+	return corev1.PodSpec{Containers: []corev1.Container{{
+		Name:  driverContainerName,
+		Image: fmt.Sprintf("nvcr.io/nvidia/driver:%s-ubuntu22.04", version),
+		Env:   []corev1.EnvVar{{Name: driverconfig.DriverConfigDigestEnvName, Value: digest}},
+	}}}
+}
+
+// rollbackNodeState returns the upgrade state of a node running a driver pod with
+// podDigest, out of a DaemonSet with dsDigest
+func rollbackNodeState(name, upgradeState string, nodeLabels map[string]string, dsDigest, podDigest, version string) *upgrade.NodeUpgradeState {
+	ds := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "nvidia-driver-daemonset", Namespace: testOperatorNamespace}}
+	ds.Spec.Template.Spec = driverPodSpec(dsDigest, version)
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "nvidia-driver-daemonset-" + name, Namespace: testOperatorNamespace},
+		Spec:       driverPodSpec(podDigest, version),
+	}
+	pod.Spec.NodeName = name
+	return &upgrade.NodeUpgradeState{
+		Node:            canaryTestNode(name, upgradeState, nodeLabels),
+		DriverPod:       pod,
+		DriverDaemonSet: ds,
+	}
+}
+
+func goodDriverRevision() gpuv1.DriverRevision {
+	return gpuv1.DriverRevision{
+		Repository:    "nvcr.io/nvidia",
+		Image:         "driver",
+		Version:       goodDriverVersion,
+		ConfigDigests: []string{"good"},
+		Time:          metav1.Now(),
+	}
+}
+
+func TestRecordDriverRevision(t *testing.T) {
+	status := &gpuv1.DriverUpgradeStatus{}
+	for i := range maxDriverRevisionHistory + 2 {
+		recordDriverRevision(status, gpuv1.DriverRevision{ConfigDigests: []string{fmt.Sprint(i)}})
+	}
+	require.Len(t, status.History, maxDriverRevisionHistory)
+	assert.Equal(t, []string{fmt.Sprint(maxDriverRevisionHistory + 1)}, status.History[0].ConfigDigests)
+
+	// recording the last configuration again is a no-op
+	recordDriverRevision(status, gpuv1.DriverRevision{ConfigDigests: []string{fmt.Sprint(maxDriverRevisionHistory + 1)}, Version: "other"})
+	assert.Empty(t, status.History[0].Version)
+
+	// recording an older configuration moves it to the head
+	recordDriverRevision(status, gpuv1.DriverRevision{ConfigDigests: []string{"3"}})
+	require.Len(t, status.History, maxDriverRevisionHistory)
+	assert.Equal(t, []string{"3"}, status.History[0].ConfigDigests)
+	assert.Equal(t, []string{fmt.Sprint(maxDriverRevisionHistory + 1)}, status.History[1].ConfigDigests)
+}
+
+func TestIsDriverRolloutComplete(t *testing.T) {
+	imagePath := "nvcr.io/nvidia/driver:" + goodDriverVersion
+	newState := func(nodeStates ...*upgrade.NodeUpgradeState) *upgrade.ClusterUpgradeState {
+		state := upgrade.NewClusterUpgradeState()
+		for _, nodeState := range nodeStates {
+			stateKey := nodeState.Node.Labels[upgrade.GetUpgradeStateLabelKey()]
+			state.NodeStates[stateKey] = append(state.NodeStates[stateKey], nodeState)
+		}
+		return &state
+	}
+
+	tests := []struct {
+		name     string
+		state    *upgrade.ClusterUpgradeState
+		expected bool
+	}{
+		{
+			name:     "no node",
+			state:    newState(),
+			expected: false,
+		},
+		{
+			name: "upgrade done on all nodes",
+			state: newState(
+				rollbackNodeState("node-a", upgrade.UpgradeStateDone, nil, "good", "good", goodDriverVersion),
+				rollbackNodeState("node-b", upgrade.UpgradeStateDone, nil, "good", "good", goodDriverVersion)),
+			expected: true,
+		},
+		{
+			name: "upgrade in progress",
+			state: newState(
+				rollbackNodeState("node-a", upgrade.UpgradeStateDone, nil, "good", "good", goodDriverVersion),
+				rollbackNodeState("node-b", upgrade.UpgradeStateUpgradeRequired, nil, "good", "old", goodDriverVersion)),
+			expected: false,
+		},
+		{
+			name: "driver pod out of sync",
+			state: newState(
+				rollbackNodeState("node-a", upgrade.UpgradeStateDone, nil, "good", "old", goodDriverVersion)),
+			expected: false,
+		},
+		{
+			name: "DaemonSet not rendered with the configured image yet",
+			state: newState(
+				rollbackNodeState("node-a", upgrade.UpgradeStateDone, nil, "good", "good", badDriverVersion)),
+			expected: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, isDriverRolloutComplete(tc.state, imagePath))
+		})
+	}
+}
+
+func TestUpgradeReconcileDriverRollback(t *testing.T) {
+	rollback := &gpuv1.DriverUpgradeRollbackSpec{}
+
+	t.Run("driver is rolled back when the upgrade fails", func(t *testing.T) {
+		cp := newUpgradeTestClusterPolicy(func(p *gpuv1.DriverUpgradePolicySpec) { p.Rollback = rollback })
+		cp.Status.Upgrade = &gpuv1.DriverUpgradeStatus{History: []gpuv1.DriverRevision{goodDriverRevision()}}
+		r, stateManager := newUpgradeStateTestReconciler(t, []client.Object{cp},
+			rollbackNodeState("node-a", upgrade.UpgradeStateFailed, nil, "bad", "bad", badDriverVersion),
+			rollbackNodeState("node-b", upgrade.UpgradeStateUpgradeRequired, nil, "bad", "good", badDriverVersion))
+
+		_, err := r.Reconcile(t.Context(), upgradeSingletonRequest())
+		require.NoError(t, err)
+		assert.Zero(t, stateManager.applyCalls)
+
+		updated := &gpuv1.ClusterPolicy{}
+		require.NoError(t, r.Get(t.Context(), types.NamespacedName{Name: cp.Name}, updated))
+		assert.Equal(t, goodDriverVersion, updated.Spec.Driver.Version)
+		require.NotNil(t, updated.Status.Upgrade.LastRollback)
+		assert.EqualValues(t, 1, updated.Status.Upgrade.LastRollback.FailedNodes)
+		assert.Equal(t, []string{"bad"}, updated.Status.Upgrade.LastRollback.FailedConfigDigests)
+		assert.Equal(t, goodDriverVersion, updated.Status.Upgrade.LastRollback.Revision.Version)
+		// the rollback is recorded by the patch of the driver image
+		annotated := annotatedDriverRollback(r.Log, updated)
+		require.NotNil(t, annotated)
+		assert.Equal(t, []string{"bad"}, annotated.FailedConfigDigests)
+
+		recorder := r.recorder.(*events.FakeRecorder)
+		require.Len(t, recorder.Events, 1)
+		assert.Contains(t, <-recorder.Events, "DriverRolledBack")
+	})
+
+	t.Run("failed nodes within the limit", func(t *testing.T) {
+		maxFailed := intstr.FromInt(1)
+		cp := newUpgradeTestClusterPolicy(func(p *gpuv1.DriverUpgradePolicySpec) {
+			p.Rollback = &gpuv1.DriverUpgradeRollbackSpec{MaxFailedNodes: &maxFailed}
+		})
+		cp.Status.Upgrade = &gpuv1.DriverUpgradeStatus{History: []gpuv1.DriverRevision{goodDriverRevision()}}
+		r, stateManager := newUpgradeStateTestReconciler(t, []client.Object{cp},
+			rollbackNodeState("node-a", upgrade.UpgradeStateFailed, nil, "bad", "bad", badDriverVersion),
+			rollbackNodeState("node-b", upgrade.UpgradeStateUpgradeRequired, nil, "bad", "good", badDriverVersion))
+
+		_, err := r.Reconcile(t.Context(), upgradeSingletonRequest())
+		require.NoError(t, err)
+		assert.Equal(t, 1, stateManager.applyCalls)
+
+		updated := &gpuv1.ClusterPolicy{}
+		require.NoError(t, r.Get(t.Context(), types.NamespacedName{Name: cp.Name}, updated))
+		assert.Equal(t, badDriverVersion, updated.Spec.Driver.Version)
+		assert.Nil(t, updated.Status.Upgrade.LastRollback)
+	})
+
+	t.Run("failures with a configuration rolled out before", func(t *testing.T) {
+		cp := newUpgradeTestClusterPolicy(func(p *gpuv1.DriverUpgradePolicySpec) { p.Rollback = rollback })
+		cp.Status.Upgrade = &gpuv1.DriverUpgradeStatus{History: []gpuv1.DriverRevision{goodDriverRevision()}}
+		cp.Spec.Driver.Version = goodDriverVersion
+		r, _ := newUpgradeStateTestReconciler(t, []client.Object{cp},
+			rollbackNodeState("node-a", upgrade.UpgradeStateFailed, nil, "good", "good", goodDriverVersion))
+
+		_, err := r.Reconcile(t.Context(), upgradeSingletonRequest())
+		require.NoError(t, err)
+
+		updated := &gpuv1.ClusterPolicy{}
+		require.NoError(t, r.Get(t.Context(), types.NamespacedName{Name: cp.Name}, updated))
+		assert.Nil(t, updated.Status.Upgrade.LastRollback)
+	})
+
+	t.Run("failed configuration change keeping the driver image", func(t *testing.T) {
+		cp := newUpgradeTestClusterPolicy(func(p *gpuv1.DriverUpgradePolicySpec) { p.Rollback = rollback })
+		cp.Status.Upgrade = &gpuv1.DriverUpgradeStatus{History: []gpuv1.DriverRevision{goodDriverRevision()}}
+		cp.Spec.Driver.Version = goodDriverVersion
+		r, _ := newUpgradeStateTestReconciler(t, []client.Object{cp},
+			rollbackNodeState("node-a", upgrade.UpgradeStateFailed, nil, "bad", "bad", goodDriverVersion))
+
+		_, err := r.Reconcile(t.Context(), upgradeSingletonRequest())
+		require.NoError(t, err)
+
+		updated := &gpuv1.ClusterPolicy{}
+		require.NoError(t, r.Get(t.Context(), types.NamespacedName{Name: cp.Name}, updated))
+		assert.Nil(t, updated.Status.Upgrade.LastRollback)
+
+		recorder := r.recorder.(*events.FakeRecorder)
+		require.Len(t, recorder.Events, 1)
+		assert.Contains(t, <-recorder.Events, "DriverRollbackSkipped")
+	})
+
+	t.Run("driver is not rolled back twice from the same configuration", func(t *testing.T) {
+		cp := newUpgradeTestClusterPolicy(func(p *gpuv1.DriverUpgradePolicySpec) { p.Rollback = rollback })
+		cp.Status.Upgrade = &gpuv1.DriverUpgradeStatus{
+			History:      []gpuv1.DriverRevision{goodDriverRevision()},
+			LastRollback: &gpuv1.DriverRollbackStatus{FailedNodes: 1, FailedConfigDigests: []string{"bad"}, Revision: goodDriverRevision()},
+		}
+		r, _ := newUpgradeStateTestReconciler(t, []client.Object{cp},
+			rollbackNodeState("node-a", upgrade.UpgradeStateFailed, nil, "bad", "bad", badDriverVersion))
+
+		_, err := r.Reconcile(t.Context(), upgradeSingletonRequest())
+		require.NoError(t, err)
+
+		updated := &gpuv1.ClusterPolicy{}
+		require.NoError(t, r.Get(t.Context(), types.NamespacedName{Name: cp.Name}, updated))
+		assert.Equal(t, badDriverVersion, updated.Spec.Driver.Version)
+	})
+
+	t.Run("rollback recorded only in the annotation", func(t *testing.T) {
+		cp := newUpgradeTestClusterPolicy(func(p *gpuv1.DriverUpgradePolicySpec) { p.Rollback = rollback })
+		cp.Status.Upgrade = &gpuv1.DriverUpgradeStatus{History: []gpuv1.DriverRevision{goodDriverRevision()}}
+		// the failed configuration was put back, e.g. by a GitOps tool, before the status
+		// was updated with the rollback
+		cp.Annotations = map[string]string{
+			driverRollbackAnnotation: `{"time":"2026-01-02T03:04:05Z","failedNodes":1,"failedConfigDigests":["bad"],` +
+				`"revision":{"version":"` + goodDriverVersion + `","configDigests":["good"],"time":null}}`,
+		}
+		r, _ := newUpgradeStateTestReconciler(t, []client.Object{cp},
+			rollbackNodeState("node-a", upgrade.UpgradeStateFailed, nil, "bad", "bad", badDriverVersion))
+
+		_, err := r.Reconcile(t.Context(), upgradeSingletonRequest())
+		require.NoError(t, err)
+
+		updated := &gpuv1.ClusterPolicy{}
+		require.NoError(t, r.Get(t.Context(), types.NamespacedName{Name: cp.Name}, updated))
+		assert.Equal(t, badDriverVersion, updated.Spec.Driver.Version)
+		require.NotNil(t, updated.Status.Upgrade.LastRollback)
+		assert.Equal(t, []string{"bad"}, updated.Status.Upgrade.LastRollback.FailedConfigDigests)
+	})
+
+	t.Run("driver pods of failed nodes restart after the rollback", func(t *testing.T) {
+		cp := newUpgradeTestClusterPolicy(func(p *gpuv1.DriverUpgradePolicySpec) { p.Rollback = rollback })
+		cp.Status.Upgrade = &gpuv1.DriverUpgradeStatus{
+			History:      []gpuv1.DriverRevision{goodDriverRevision()},
+			LastRollback: &gpuv1.DriverRollbackStatus{FailedNodes: 1, FailedConfigDigests: []string{"bad"}, Revision: goodDriverRevision()},
+		}
+		cp.Spec.Driver.Version = goodDriverVersion
+		nodeState := rollbackNodeState("node-a", upgrade.UpgradeStateFailed, nil, "good", "bad", badDriverVersion)
+		r, _ := newUpgradeStateTestReconciler(t, []client.Object{cp}, nodeState)
+
+		_, err := r.Reconcile(t.Context(), upgradeSingletonRequest())
+		require.NoError(t, err)
+
+		err = r.Get(t.Context(), client.ObjectKeyFromObject(nodeState.DriverPod), &corev1.Pod{})
+		assert.True(t, apierrors.IsNotFound(err))
+	})
+
+	t.Run("driver image is recorded once rolled out to all nodes", func(t *testing.T) {
+		cp := newUpgradeTestClusterPolicy(nil)
+		cp.Spec.Driver.Version = goodDriverVersion
+		r, _ := newUpgradeStateTestReconciler(t, []client.Object{cp},
+			rollbackNodeState("node-a", upgrade.UpgradeStateDone, nil, "good", "good", goodDriverVersion))
+
+		_, err := r.Reconcile(t.Context(), upgradeSingletonRequest())
+		require.NoError(t, err)
+
+		updated := &gpuv1.ClusterPolicy{}
+		require.NoError(t, r.Get(t.Context(), types.NamespacedName{Name: cp.Name}, updated))
+		require.NotNil(t, updated.Status.Upgrade)
+		require.Len(t, updated.Status.Upgrade.History, 1)
+		assert.Equal(t, goodDriverVersion, updated.Status.Upgrade.History[0].Version)
+		assert.Equal(t, []string{"good"}, updated.Status.Upgrade.History[0].ConfigDigests)
+	})
+}
+
+func TestUpgradeReconcileNVIDIADriverRollback(t *testing.T) {
+	nvd := &nvidiav1alpha1.NVIDIADriver{
+		ObjectMeta: metav1.ObjectMeta{Name: "gpu-driver"},
+		Spec: nvidiav1alpha1.NVIDIADriverSpec{
+			Repository: "nvcr.io/nvidia",
+			Image:      "driver",
+			Version:    badDriverVersion,
+			UpgradePolicy: &nvidiav1alpha1.DriverUpgradePolicySpec{
+				AutoUpgrade: true,
+				Rollback:    &nvidiav1alpha1.DriverUpgradeRollbackSpec{},
+			},
+		},
+		Status: nvidiav1alpha1.NVIDIADriverStatus{
+			Upgrade: &nvidiav1alpha1.DriverUpgradeStatus{History: []gpuv1.DriverRevision{goodDriverRevision()}},
+		},
+	}
+	owned := map[string]string{gpuconsts.NVIDIADriverOwnerLabel: nvd.Name}
+	r, stateManager := newUpgradeStateTestReconciler(t, []client.Object{nvd},
+		rollbackNodeState("node-a", upgrade.UpgradeStateFailed, owned, "bad", "bad", badDriverVersion))
+
+	_, err := r.Reconcile(t.Context(), upgradeSingletonRequest())
+	require.NoError(t, err)
+	assert.Zero(t, stateManager.applyCalls)
+
+	updated := &nvidiav1alpha1.NVIDIADriver{}
+	require.NoError(t, r.Get(t.Context(), types.NamespacedName{Name: nvd.Name}, updated))
+	assert.Equal(t, goodDriverVersion, updated.Spec.Version)
+	require.NotNil(t, updated.Status.Upgrade.LastRollback)
+	assert.Equal(t, []string{"bad"}, updated.Status.Upgrade.LastRollback.FailedConfigDigests)
+}
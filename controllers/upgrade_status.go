@@ -0,0 +1,223 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/NVIDIA/k8s-operator-libs/pkg/upgrade"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1"
+	nvidiav1alpha1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1alpha1"
+)
+
+// driverUpgradeStatusOf returns a copy of the upgrade status to update, allocating an
+// empty one if status is nil
+func driverUpgradeStatusOf(status *gpuv1.DriverUpgradeStatus) *gpuv1.DriverUpgradeStatus {
+	if status == nil {
+		return &gpuv1.DriverUpgradeStatus{}
+	}
+	return status.DeepCopy()
+}
+
+// driverUpgradeStatusOrNil returns status, or nil if there is nothing to report
+func driverUpgradeStatusOrNil(status *gpuv1.DriverUpgradeStatus) *gpuv1.DriverUpgradeStatus {
+	if status == nil || apiequality.Semantic.DeepEqual(*status, gpuv1.DriverUpgradeStatus{}) {
+		return nil
+	}
+	return status
+}
+
+// updateClusterPolicyUpgradeStatus sets the upgrade status of the ClusterPolicy name
+func (r *UpgradeReconciler) updateClusterPolicyUpgradeStatus(ctx context.Context, name string, status *gpuv1.DriverUpgradeStatus) {
+	// Fetch latest instance and update the upgrade status to avoid version mismatch
+	instance := &gpuv1.ClusterPolicy{}
+	if err := r.Get(ctx, types.NamespacedName{Name: name}, instance); err != nil {
+		r.Log.Error(err, "Failed to get ClusterPolicy instance for upgrade status update")
+		return
+	}
+	status = driverUpgradeStatusOrNil(status)
+	if apiequality.Semantic.DeepEqual(instance.Status.Upgrade, status) {
+		return
+	}
+	instance.Status.Upgrade = status
+	if err := r.Status().Update(ctx, instance); err != nil {
+		r.Log.Error(err, "Failed to update ClusterPolicy upgrade status")
+	}
+}
+
+// updateNVIDIADriverUpgradeStatus sets the upgrade status of the NVIDIADriver name
+func (r *UpgradeReconciler) updateNVIDIADriverUpgradeStatus(ctx context.Context, name string, status *nvidiav1alpha1.DriverUpgradeStatus) {
+	// Fetch latest instance and update the upgrade status to avoid version mismatch
+	instance := &nvidiav1alpha1.NVIDIADriver{}
+	if err := r.Get(ctx, types.NamespacedName{Name: name}, instance); err != nil {
+		r.Log.Error(err, "Failed to get NVIDIADriver instance for upgrade status update", "name", name)
+		return
+	}
+	status = driverUpgradeStatusOrNil(status)
+	if apiequality.Semantic.DeepEqual(instance.Status.Upgrade, status) {
+		return
+	}
+	instance.Status.Upgrade = status
+	if err := r.Status().Update(ctx, instance); err != nil {
+		r.Log.Error(err, "Failed to update NVIDIADriver upgrade status", "name", name)
+	}
+}
+
+// clearClusterPolicyUpgradeStatus removes the upgrade plan and progress left in the status
+// of clusterPolicy once driver upgrades are disabled
+func (r *UpgradeReconciler) clearClusterPolicyUpgradeStatus(ctx context.Context, clusterPolicy *gpuv1.ClusterPolicy) {
+	if clusterPolicy.Status.Upgrade == nil {
+		return
+	}
+	status := clusterPolicy.Status.Upgrade.DeepCopy()
+	status.Plan = nil
+	clearDriverUpgradeProgress(status)
+	r.updateClusterPolicyUpgradeStatus(ctx, clusterPolicy.Name, status)
+}
+
+// clearNVIDIADriverUpgradeStatus removes the upgrade plan and progress left in the status
+// of nvd once driver upgrades are disabled
+func (r *UpgradeReconciler) clearNVIDIADriverUpgradeStatus(ctx context.Context, nvd *nvidiav1alpha1.NVIDIADriver) {
+	if nvd.Status.Upgrade == nil {
+		return
+	}
+	status := nvd.Status.Upgrade.DeepCopy()
+	status.Plan = nil
+	clearDriverUpgradeProgress(status)
+	r.updateNVIDIADriverUpgradeStatus(ctx, nvd.Name, status)
+}
+
+// setDriverUpgradeProgress sets in status the progress of the upgrade of the nodes of state
+// to the driver targetVersion at now
+func (r *UpgradeReconciler) setDriverUpgradeProgress(ctx context.Context, status *gpuv1.DriverUpgradeStatus,
+	state *upgrade.ClusterUpgradeState, targetVersion string, now time.Time) error {
+	list := &nvidiav1alpha1.NodeUpgradeHistoryList{}
+	if err := r.List(ctx, list); err != nil {
+		return fmt.Errorf("failed to list node upgrade histories: %w", err)
+	}
+	// The last driver upgrade of each node
+	records := make(map[string]*nvidiav1alpha1.NodeUpgradeRecord, len(list.Items))
+	for i := range list.Items {
+		if history := &list.Items[i]; len(history.Status.Records) > 0 {
+			records[history.Name] = &history.Status.Records[0]
+		}
+	}
+	updateDriverUpgradeProgress(status, state, records, targetVersion, now)
+	return nil
+}
+
+// updateDriverUpgradeProgress sets in status the progress of the upgrade of the nodes of
+// state to the driver targetVersion at now. records are the last driver upgrades of the
+// nodes, by node name. The completion time is estimated from the number of nodes upgraded
+// since the upgrade started.
+func updateDriverUpgradeProgress(status *gpuv1.DriverUpgradeStatus, state *upgrade.ClusterUpgradeState,
+	records map[string]*nvidiav1alpha1.NodeUpgradeRecord, targetVersion string, now time.Time) {
+	counts := &gpuv1.DriverUpgradeNodeCounts{}
+	var failedNodes []gpuv1.DriverUpgradeFailedNode
+	var doneNodes []string
+	for stateKey, nodeStates := range state.NodeStates {
+		count := int32(len(nodeStates))
+		counts.Total += count
+		switch stateKey {
+		case upgrade.UpgradeStateUnknown:
+		case upgrade.UpgradeStateUpgradeRequired:
+			counts.Pending += count
+		case upgrade.UpgradeStateCordonRequired:
+			counts.CordonRequired += count
+		case upgrade.UpgradeStateWaitForJobsRequired, upgrade.UpgradeStatePodDeletionRequired, upgrade.UpgradeStateDrainRequired:
+			counts.DrainRequired += count
+		case upgrade.UpgradeStateValidationRequired:
+			counts.ValidationRequired += count
+		case upgrade.UpgradeStateDone:
+			counts.Done += count
+			for _, nodeState := range nodeStates {
+				doneNodes = append(doneNodes, nodeState.Node.Name)
+			}
+		case upgrade.UpgradeStateFailed:
+			counts.Failed += count
+			for _, nodeState := range nodeStates {
+				failedNodes = append(failedNodes, gpuv1.DriverUpgradeFailedNode{
+					Name:   nodeState.Node.Name,
+					Reason: driverUpgradeFailureReason(nodeState, records[nodeState.Node.Name]),
+				})
+			}
+		default:
+			counts.InProgress += count
+		}
+	}
+	sort.Slice(failedNodes, func(i, j int) bool {
+		return failedNodes[i].Name < failedNodes[j].Name
+	})
+
+	remaining := counts.Pending + counts.CordonRequired + counts.DrainRequired + counts.InProgress + counts.ValidationRequired
+	if remaining+counts.Failed == 0 || status.TargetVersion != targetVersion {
+		status.StartTime = nil
+	}
+	status.TargetVersion = targetVersion
+	status.Nodes = counts
+	status.FailedNodes = failedNodes
+	status.EstimatedCompletionTime = nil
+	if remaining+counts.Failed == 0 {
+		return
+	}
+	if status.StartTime == nil {
+		// The status keeps times to the second
+		startTime := metav1.NewTime(now.Truncate(time.Second))
+		status.StartTime = &startTime
+	}
+
+	upgraded := 0
+	for _, name := range doneNodes {
+		record := records[name]
+		if record != nil && record.Result == nvidiav1alpha1.NodeUpgradeSucceeded &&
+			record.CompletionTime != nil && !record.CompletionTime.Before(status.StartTime) {
+			upgraded++
+		}
+	}
+	if upgraded == 0 || remaining == 0 {
+		return
+	}
+	elapsed := now.Sub(status.StartTime.Time)
+	// Estimates are rounded to the minute to not update the status on every reconciliation
+	estimate := metav1.NewTime(now.Add(elapsed * time.Duration(remaining) / time.Duration(upgraded)).Round(time.Minute))
+	status.EstimatedCompletionTime = &estimate
+}
+
+// driverUpgradeFailureReason describes why the driver upgrade of the node of nodeState
+// failed, given the last driver upgrade recorded for the node
+func driverUpgradeFailureReason(nodeState *upgrade.NodeUpgradeState, record *nvidiav1alpha1.NodeUpgradeRecord) string {
+	if record != nil && record.Result == nvidiav1alpha1.NodeUpgradeFailed && record.Message != "" {
+		return record.Message
+	}
+	return nodeUpgradeFailureMessage("", nodeState)
+}
+
+// clearDriverUpgradeProgress removes the upgrade progress from status
+func clearDriverUpgradeProgress(status *gpuv1.DriverUpgradeStatus) {
+	status.TargetVersion = ""
+	status.Nodes = nil
+	status.StartTime = nil
+	status.EstimatedCompletionTime = nil
+	status.FailedNodes = nil
+}
@@ -0,0 +1,162 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/k8s-operator-libs/pkg/upgrade"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1"
+	nvidiav1alpha1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1alpha1"
+)
+
+func progressTestState(nodes map[string]string) *upgrade.ClusterUpgradeState {
+	state := upgrade.NewClusterUpgradeState()
+	for name, stateKey := range nodes {
+		state.NodeStates[stateKey] = append(state.NodeStates[stateKey], &upgrade.NodeUpgradeState{Node: canaryTestNode(name, stateKey, nil)})
+	}
+	return &state
+}
+
+func TestUpdateDriverUpgradeProgress(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	succeededAt := func(at time.Time) *nvidiav1alpha1.NodeUpgradeRecord {
+		completionTime := metav1.NewTime(at)
+		return &nvidiav1alpha1.NodeUpgradeRecord{Result: nvidiav1alpha1.NodeUpgradeSucceeded, CompletionTime: &completionTime}
+	}
+
+	t.Run("nodes are counted by upgrade state", func(t *testing.T) {
+		state := progressTestState(map[string]string{
+			"node-a": upgrade.UpgradeStateUpgradeRequired,
+			"node-b": upgrade.UpgradeStateCordonRequired,
+			"node-c": upgrade.UpgradeStateWaitForJobsRequired,
+			"node-d": upgrade.UpgradeStateDrainRequired,
+			"node-e": upgrade.UpgradeStatePodRestartRequired,
+			"node-f": upgrade.UpgradeStateValidationRequired,
+			"node-g": upgrade.UpgradeStateDone,
+			"node-h": upgrade.UpgradeStateFailed,
+			"node-i": upgrade.UpgradeStateUnknown,
+		})
+		records := map[string]*nvidiav1alpha1.NodeUpgradeRecord{
+			"node-h": {Result: nvidiav1alpha1.NodeUpgradeFailed, Message: "Driver upgrade failed in state validation-required"},
+		}
+		status := &gpuv1.DriverUpgradeStatus{}
+		updateDriverUpgradeProgress(status, state, records, "570.124.06", now)
+
+		assert.Equal(t, "570.124.06", status.TargetVersion)
+		assert.Equal(t, &gpuv1.DriverUpgradeNodeCounts{
+			Total: 9, Pending: 1, CordonRequired: 1, DrainRequired: 2, InProgress: 1, ValidationRequired: 1, Done: 1, Failed: 1,
+		}, status.Nodes)
+		assert.Equal(t, []gpuv1.DriverUpgradeFailedNode{
+			{Name: "node-h", Reason: "Driver upgrade failed in state validation-required"},
+		}, status.FailedNodes)
+		require.NotNil(t, status.StartTime)
+		assert.True(t, now.Equal(status.StartTime.Time))
+		assert.Nil(t, status.EstimatedCompletionTime, "no node was upgraded since the upgrade started")
+	})
+
+	t.Run("completion is estimated from the nodes upgraded since the start", func(t *testing.T) {
+		startTime := metav1.NewTime(now.Add(-time.Hour))
+		status := &gpuv1.DriverUpgradeStatus{TargetVersion: "570.124.06", StartTime: &startTime}
+		state := progressTestState(map[string]string{
+			"node-a": upgrade.UpgradeStateDone,
+			"node-b": upgrade.UpgradeStateDone,
+			"node-c": upgrade.UpgradeStateDone,
+			"node-d": upgrade.UpgradeStateDrainRequired,
+			"node-e": upgrade.UpgradeStateUpgradeRequired,
+			"node-f": upgrade.UpgradeStateUpgradeRequired,
+			"node-g": upgrade.UpgradeStateUpgradeRequired,
+		})
+		records := map[string]*nvidiav1alpha1.NodeUpgradeRecord{
+			"node-a": succeededAt(now.Add(-40 * time.Minute)),
+			"node-b": succeededAt(now.Add(-10 * time.Minute)),
+			// upgraded before the upgrade started
+			"node-c": succeededAt(now.Add(-48 * time.Hour)),
+		}
+		updateDriverUpgradeProgress(status, state, records, "570.124.06", now)
+
+		assert.True(t, startTime.Equal(status.StartTime))
+		require.NotNil(t, status.EstimatedCompletionTime)
+		assert.True(t, now.Add(2*time.Hour).Equal(status.EstimatedCompletionTime.Time), "4 nodes left at 2 nodes per hour")
+	})
+
+	t.Run("new target version restarts the upgrade", func(t *testing.T) {
+		startTime := metav1.NewTime(now.Add(-time.Hour))
+		status := &gpuv1.DriverUpgradeStatus{TargetVersion: "570.124.06", StartTime: &startTime}
+		state := progressTestState(map[string]string{"node-a": upgrade.UpgradeStateDone, "node-b": upgrade.UpgradeStateUpgradeRequired})
+		records := map[string]*nvidiav1alpha1.NodeUpgradeRecord{"node-a": succeededAt(now.Add(-10 * time.Minute))}
+		updateDriverUpgradeProgress(status, state, records, "575.57.08", now)
+
+		assert.Equal(t, "575.57.08", status.TargetVersion)
+		require.NotNil(t, status.StartTime)
+		assert.True(t, now.Equal(status.StartTime.Time))
+		assert.Nil(t, status.EstimatedCompletionTime)
+	})
+
+	t.Run("completed upgrade has no start time", func(t *testing.T) {
+		startTime := metav1.NewTime(now.Add(-time.Hour))
+		estimate := metav1.NewTime(now.Add(time.Minute))
+		status := &gpuv1.DriverUpgradeStatus{TargetVersion: "570.124.06", StartTime: &startTime, EstimatedCompletionTime: &estimate}
+		state := progressTestState(map[string]string{"node-a": upgrade.UpgradeStateDone})
+		updateDriverUpgradeProgress(status, state, nil, "570.124.06", now)
+
+		assert.Equal(t, &gpuv1.DriverUpgradeNodeCounts{Total: 1, Done: 1}, status.Nodes)
+		assert.Nil(t, status.StartTime)
+		assert.Nil(t, status.EstimatedCompletionTime)
+	})
+
+	t.Run("failure reason without upgrade record", func(t *testing.T) {
+		status := &gpuv1.DriverUpgradeStatus{}
+		state := progressTestState(map[string]string{"node-a": upgrade.UpgradeStateFailed})
+		updateDriverUpgradeProgress(status, state, nil, "570.124.06", now)
+
+		assert.Equal(t, []gpuv1.DriverUpgradeFailedNode{{Name: "node-a", Reason: "Driver upgrade failed"}}, status.FailedNodes)
+		assert.NotNil(t, status.StartTime, "upgrade failed on some nodes is not complete")
+	})
+}
+
+func TestUpgradeReconcileProgress(t *testing.T) {
+	cp := newUpgradeTestClusterPolicy(nil)
+	cp.Spec.Driver.Version = "570.124.06"
+	r, _ := newUpgradeStateTestReconciler(t, []client.Object{cp}, upgradeNodeStates(
+		canaryTestNode("node-a", upgrade.UpgradeStateUpgradeRequired, nil),
+		canaryTestNode("node-b", upgrade.UpgradeStateDone, nil))...)
+
+	_, err := r.Reconcile(t.Context(), upgradeSingletonRequest())
+	require.NoError(t, err)
+	updated := &gpuv1.ClusterPolicy{}
+	require.NoError(t, r.Get(t.Context(), types.NamespacedName{Name: cp.Name}, updated))
+	require.NotNil(t, updated.Status.Upgrade)
+	assert.Equal(t, "570.124.06", updated.Status.Upgrade.TargetVersion)
+	assert.Equal(t, &gpuv1.DriverUpgradeNodeCounts{Total: 2, Pending: 1, Done: 1}, updated.Status.Upgrade.Nodes)
+	assert.NotNil(t, updated.Status.Upgrade.StartTime)
+
+	// Progress is cleared once automatic upgrades are disabled
+	updated.Spec.Driver.UpgradePolicy.AutoUpgrade = false
+	require.NoError(t, r.Update(t.Context(), updated))
+	_, err = r.Reconcile(t.Context(), upgradeSingletonRequest())
+	require.NoError(t, err)
+	require.NoError(t, r.Get(t.Context(), types.NamespacedName{Name: cp.Name}, updated))
+	assert.Nil(t, updated.Status.Upgrade)
+}
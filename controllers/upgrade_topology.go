@@ -0,0 +1,113 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"sort"
+
+	"github.com/NVIDIA/k8s-operator-libs/pkg/upgrade"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1"
+)
+
+// gateTopology holds back the driver upgrade of the nodes outside of the topology domains
+// being upgraded, and of the nodes of those domains beyond their maxUnavailablePerDomain.
+// The domains being upgraded are the domains of the nodes whose upgrade is in progress,
+// failed upgrades included, or once there is none, the first domain with nodes waiting
+// for their upgrade.
+func gateTopology(reqLogger logr.Logger, topology *gpuv1.DriverUpgradeTopologySpec, gate *upgradeGate) {
+	if topology == nil {
+		return
+	}
+
+	inProgress := map[string]int{}
+	for stateKey, nodeStates := range gate.state.NodeStates {
+		if !driverUpgradeInProgress(stateKey) {
+			continue
+		}
+		for _, nodeState := range nodeStates {
+			inProgress[nodeState.Node.Labels[topology.TopologyKey]]++
+		}
+	}
+
+	var pending []*corev1.Node
+	for _, nodeState := range gate.state.NodeStates[upgrade.UpgradeStateUpgradeRequired] {
+		pending = append(pending, nodeState.Node)
+	}
+	if len(pending) == 0 {
+		return
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].Name < pending[j].Name
+	})
+
+	available := map[string]int{}
+	for domain, count := range inProgress {
+		available[domain] = topology.GetMaxUnavailablePerDomain() - count
+	}
+	if len(available) == 0 {
+		domain := pending[0].Labels[topology.TopologyKey]
+		for _, node := range pending[1:] {
+			if next := node.Labels[topology.TopologyKey]; topologyDomainLess(next, domain) {
+				domain = next
+			}
+		}
+		available[domain] = topology.GetMaxUnavailablePerDomain()
+	}
+
+	domains := make([]string, 0, len(available))
+	for domain := range available {
+		domains = append(domains, domain)
+	}
+	sort.Slice(domains, func(i, j int) bool {
+		return topologyDomainLess(domains[i], domains[j])
+	})
+	reqLogger.Info("Upgrading the driver one topology domain at a time", "topologyKey", topology.TopologyKey, "domains", domains)
+
+	started := map[string]bool{}
+	for _, node := range pending {
+		domain := node.Labels[topology.TopologyKey]
+		if available[domain] > 0 {
+			started[node.Name] = true
+			available[domain]--
+		}
+	}
+	gate.hold(func(node *corev1.Node) bool {
+		return !started[node.Name]
+	})
+}
+
+// driverUpgradeInProgress returns whether the driver upgrade of the nodes in the upgrade
+// state stateKey is in progress
+func driverUpgradeInProgress(stateKey string) bool {
+	switch stateKey {
+	case upgrade.UpgradeStateUnknown, upgrade.UpgradeStateUpgradeRequired, upgrade.UpgradeStateDone:
+		return false
+	}
+	return true
+}
+
+// topologyDomainLess orders topology domains by name, the domain of the nodes without
+// topology label, which is empty, last
+func topologyDomainLess(a, b string) bool {
+	if a == "" || b == "" {
+		return b == "" && a != ""
+	}
+	return a < b
+}
@@ -0,0 +1,126 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"testing"
+
+	upgrade_v1alpha1 "github.com/NVIDIA/k8s-operator-libs/api/upgrade/v1alpha1"
+	"github.com/NVIDIA/k8s-operator-libs/pkg/upgrade"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1"
+)
+
+const testTopologyKey = "topology.kubernetes.io/zone"
+
+func zoneNode(name, upgradeState, zone string) *corev1.Node {
+	var nodeLabels map[string]string
+	if zone != "" {
+		nodeLabels = map[string]string{testTopologyKey: zone}
+	}
+	return canaryTestNode(name, upgradeState, nodeLabels)
+}
+
+func TestGateTopology(t *testing.T) {
+	newGate := func(nodes ...*corev1.Node) *upgradeGate {
+		state := upgrade.NewClusterUpgradeState()
+		for _, node := range nodes {
+			stateKey := node.Labels[upgrade.GetUpgradeStateLabelKey()]
+			state.NodeStates[stateKey] = append(state.NodeStates[stateKey], &upgrade.NodeUpgradeState{Node: node})
+		}
+		return newUpgradeGate(&state, &upgrade_v1alpha1.DriverUpgradePolicySpec{AutoUpgrade: true}, 10)
+	}
+	topology := &gpuv1.DriverUpgradeTopologySpec{TopologyKey: testTopologyKey, MaxUnavailablePerDomain: 2}
+
+	t.Run("no topology", func(t *testing.T) {
+		gate := newGate(
+			zoneNode("node-a", upgrade.UpgradeStateUpgradeRequired, "zone-b"),
+			zoneNode("node-b", upgrade.UpgradeStateUpgradeRequired, "zone-a"))
+		gateTopology(logr.Discard(), nil, gate)
+		assert.Len(t, appliedNodeNames(gate.state, upgrade.UpgradeStateUpgradeRequired), 2)
+	})
+
+	t.Run("first domain starts", func(t *testing.T) {
+		gate := newGate(
+			zoneNode("node-a", upgrade.UpgradeStateUpgradeRequired, ""),
+			zoneNode("node-b", upgrade.UpgradeStateUpgradeRequired, "zone-b"),
+			zoneNode("node-c", upgrade.UpgradeStateUpgradeRequired, "zone-a"),
+			zoneNode("node-d", upgrade.UpgradeStateUpgradeRequired, "zone-a"),
+			zoneNode("node-e", upgrade.UpgradeStateUpgradeRequired, "zone-a"),
+			zoneNode("node-f", upgrade.UpgradeStateDone, "zone-c"))
+		gateTopology(logr.Discard(), topology, gate)
+		assert.Equal(t, []string{"node-c", "node-d"}, appliedNodeNames(gate.state, upgrade.UpgradeStateUpgradeRequired))
+		assert.Equal(t, []string{"node-f"}, appliedNodeNames(gate.state, upgrade.UpgradeStateDone))
+	})
+
+	t.Run("domain in progress finishes first", func(t *testing.T) {
+		gate := newGate(
+			zoneNode("node-a", upgrade.UpgradeStateUpgradeRequired, "zone-a"),
+			zoneNode("node-b", upgrade.UpgradeStateUpgradeRequired, "zone-b"),
+			zoneNode("node-c", upgrade.UpgradeStateUpgradeRequired, "zone-b"),
+			zoneNode("node-d", upgrade.UpgradeStateDrainRequired, "zone-b"))
+		gateTopology(logr.Discard(), topology, gate)
+		assert.Equal(t, []string{"node-b"}, appliedNodeNames(gate.state, upgrade.UpgradeStateUpgradeRequired))
+		assert.Equal(t, []string{"node-d"}, appliedNodeNames(gate.state, upgrade.UpgradeStateDrainRequired))
+	})
+
+	t.Run("failed upgrade holds back the other domains", func(t *testing.T) {
+		gate := newGate(
+			zoneNode("node-a", upgrade.UpgradeStateUpgradeRequired, "zone-a"),
+			zoneNode("node-b", upgrade.UpgradeStateFailed, "zone-b"),
+			zoneNode("node-c", upgrade.UpgradeStateValidationRequired, "zone-b"))
+		gateTopology(logr.Discard(), topology, gate)
+		assert.Empty(t, appliedNodeNames(gate.state, upgrade.UpgradeStateUpgradeRequired))
+	})
+
+	t.Run("nodes without domain are upgraded last", func(t *testing.T) {
+		gate := newGate(
+			zoneNode("node-a", upgrade.UpgradeStateUpgradeRequired, ""),
+			zoneNode("node-b", upgrade.UpgradeStateUpgradeRequired, ""),
+			zoneNode("node-c", upgrade.UpgradeStateUpgradeRequired, ""),
+			zoneNode("node-d", upgrade.UpgradeStateDone, "zone-a"))
+		gateTopology(logr.Discard(), &gpuv1.DriverUpgradeTopologySpec{TopologyKey: testTopologyKey}, gate)
+		assert.Equal(t, []string{"node-a"}, appliedNodeNames(gate.state, upgrade.UpgradeStateUpgradeRequired))
+	})
+}
+
+func TestUpgradeReconcileTopology(t *testing.T) {
+	cp := newUpgradeTestClusterPolicy(nil)
+	cp.Spec.Driver.UpgradePolicy.Topology = &gpuv1.DriverUpgradeTopologySpec{TopologyKey: testTopologyKey}
+	r, stateManager := newUpgradeStateTestReconciler(t, []client.Object{cp}, upgradeNodeStates(
+		zoneNode("node-a", upgrade.UpgradeStateUpgradeRequired, "zone-b"),
+		zoneNode("node-b", upgrade.UpgradeStateUpgradeRequired, "zone-a"),
+		zoneNode("node-c", upgrade.UpgradeStateUpgradeRequired, "zone-a"))...)
+
+	_, err := r.Reconcile(t.Context(), upgradeSingletonRequest())
+	require.NoError(t, err)
+	require.Len(t, stateManager.appliedStates, 1)
+	assert.Equal(t, []string{"node-b"}, appliedNodeNames(stateManager.appliedStates[0], upgrade.UpgradeStateUpgradeRequired))
+}
+
+func TestTopologyDomainLess(t *testing.T) {
+	assert.True(t, topologyDomainLess("zone-a", "zone-b"))
+	assert.False(t, topologyDomainLess("zone-b", "zone-a"))
+	assert.True(t, topologyDomainLess("zone-b", ""))
+	assert.False(t, topologyDomainLess("", "zone-a"))
+	assert.False(t, topologyDomainLess("", ""))
+}
@@ -0,0 +1,202 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/NVIDIA/k8s-operator-libs/pkg/consts"
+	"github.com/NVIDIA/k8s-operator-libs/pkg/upgrade"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1"
+)
+
+const (
+	// UpgradeGPUWorkloadWaitStartedAnnotation records on a node when its driver upgrade
+	// started waiting for its GPU workloads to finish
+	UpgradeGPUWorkloadWaitStartedAnnotation = "nvidia.com/gpu-driver-upgrade-gpu-workload-wait.started-at"
+
+	// gpuWorkloadWaitPollInterval is how often the GPU workloads waited for are checked
+	gpuWorkloadWaitPollInterval = 30 * time.Second
+)
+
+// gateGPUWorkloads holds back the nodes waiting for their jobs until their GPU workloads
+// finish or the wait times out, so that the GPU workloads are not evicted when the node
+// is drained. Meanwhile, the pods of the nodes that are not waited for are evicted if
+// drain is enabled.
+func (r *UpgradeReconciler) gateGPUWorkloads(ctx context.Context, reqLogger logr.Logger, wait *gpuv1.DriverUpgradeGPUWorkloadWaitSpec, gate *upgradeGate) error {
+	if err := r.resetGPUWorkloadWaits(ctx, gate.state); err != nil {
+		return err
+	}
+	if wait == nil {
+		return nil
+	}
+
+	var evicted labels.Selector
+	if drain := gate.policy.DrainSpec; drain != nil && drain.Enable {
+		selector, err := labels.Parse(drain.PodSelector)
+		if err != nil {
+			return fmt.Errorf("invalid drain pod selector: %w", err)
+		}
+		evicted = selector
+	}
+
+	now := time.Now()
+	held := map[string]bool{}
+	for _, nodeState := range gate.state.NodeStates[upgrade.UpgradeStateWaitForJobsRequired] {
+		node := nodeState.Node
+		startedAt, err := r.startGPUWorkloadWait(ctx, node, now)
+		if err != nil {
+			return err
+		}
+		workloads, others, err := r.listGPUWorkloads(ctx, node.Name, wait)
+		if err != nil {
+			return err
+		}
+		if len(workloads) == 0 {
+			continue
+		}
+
+		if timeout := wait.GetTimeout(); timeout > 0 {
+			remaining := startedAt.Add(timeout).Sub(now)
+			if remaining <= 0 {
+				reqLogger.Info("GPU workloads did not finish in time, draining the node anyway",
+					"node", node.Name, "workloads", len(workloads), "timeout", timeout)
+				continue
+			}
+			gate.requeueBy(remaining)
+		}
+		reqLogger.V(consts.LogLevelInfo).Info("Waiting for the GPU workloads of node to finish", "node", node.Name, "workloads", len(workloads))
+		held[node.Name] = true
+		gate.requeueBy(gpuWorkloadWaitPollInterval)
+
+		if evicted != nil {
+			r.evictPods(ctx, reqLogger, others, evicted)
+		}
+	}
+	if len(held) > 0 {
+		gate.holdIn(upgrade.UpgradeStateWaitForJobsRequired, func(node *corev1.Node) bool { return held[node.Name] })
+	}
+	return nil
+}
+
+// resetGPUWorkloadWaits clears the GPU workload wait start of the nodes of state that are
+// not being upgraded, so that the wait starts over on their next upgrade
+func (r *UpgradeReconciler) resetGPUWorkloadWaits(ctx context.Context, state *upgrade.ClusterUpgradeState) error {
+	for _, stateKey := range []string{upgrade.UpgradeStateUnknown, upgrade.UpgradeStateDone, upgrade.UpgradeStateUpgradeRequired} {
+		for _, nodeState := range state.NodeStates[stateKey] {
+			if err := r.setGPUWorkloadWaitStartedAnnotation(ctx, nodeState.Node, nil); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// startGPUWorkloadWait returns when the driver upgrade of node started waiting for its
+// GPU workloads, recording now on the node if the wait starts
+func (r *UpgradeReconciler) startGPUWorkloadWait(ctx context.Context, node *corev1.Node, now time.Time) (time.Time, error) {
+	startedAt, err := time.Parse(time.RFC3339, node.Annotations[UpgradeGPUWorkloadWaitStartedAnnotation])
+	if err == nil {
+		return startedAt, nil
+	}
+	return now, r.setGPUWorkloadWaitStartedAnnotation(ctx, node, &now)
+}
+
+// listGPUWorkloads returns the GPU workloads of node waited for, and the other pods of node
+// that would be drained
+func (r *UpgradeReconciler) listGPUWorkloads(ctx context.Context, node string, wait *gpuv1.DriverUpgradeGPUWorkloadWaitSpec) ([]corev1.Pod, []corev1.Pod, error) {
+	podList := &corev1.PodList{}
+	if err := r.APIReader.List(ctx, podList, client.MatchingFields{podNodeNameField: node}); err != nil {
+		return nil, nil, fmt.Errorf("failed to list the pods of node %s: %w", node, err)
+	}
+
+	var workloads, others []corev1.Pod
+	for _, pod := range podList.Items {
+		owner := metav1.GetControllerOf(&pod)
+		// DaemonSet and static pods are not drained
+		if owner != nil && owner.Kind == "DaemonSet" {
+			continue
+		}
+		if _, ok := pod.Annotations[corev1.MirrorPodAnnotationKey]; ok {
+			continue
+		}
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+
+		waited := r.GPUPodFilter(pod)
+		if waited && len(wait.OwnerKinds) > 0 {
+			waited = owner != nil && slices.Contains(wait.OwnerKinds, owner.Kind)
+		}
+		if waited {
+			workloads = append(workloads, pod)
+		} else {
+			others = append(others, pod)
+		}
+	}
+	return workloads, others, nil
+}
+
+// evictPods evicts the pods matching selector. Pods that cannot be evicted yet, e.g. because
+// of their disruption budget, are evicted on a later reconciliation.
+func (r *UpgradeReconciler) evictPods(ctx context.Context, reqLogger logr.Logger, pods []corev1.Pod, selector labels.Selector) {
+	for i := range pods {
+		pod := &pods[i]
+		if pod.DeletionTimestamp != nil || !selector.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		eviction := &policyv1.Eviction{ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace}}
+		if err := r.SubResource("eviction").Create(ctx, pod, eviction); client.IgnoreNotFound(err) != nil {
+			reqLogger.V(consts.LogLevelWarning).Info("Failed to evict pod while waiting for the GPU workloads of its node",
+				"pod", client.ObjectKeyFromObject(pod), "error", err)
+		}
+	}
+}
+
+// setGPUWorkloadWaitStartedAnnotation sets the GPU workload wait started annotation of
+// node to startedAt, or removes it if startedAt is nil
+func (r *UpgradeReconciler) setGPUWorkloadWaitStartedAnnotation(ctx context.Context, node *corev1.Node, startedAt *time.Time) error {
+	_, present := node.Annotations[UpgradeGPUWorkloadWaitStartedAnnotation]
+	if startedAt == nil && !present {
+		return nil
+	}
+
+	patch := client.MergeFrom(node.DeepCopy())
+	if startedAt == nil {
+		delete(node.Annotations, UpgradeGPUWorkloadWaitStartedAnnotation)
+	} else {
+		if node.Annotations == nil {
+			node.Annotations = map[string]string{}
+		}
+		node.Annotations[UpgradeGPUWorkloadWaitStartedAnnotation] = startedAt.UTC().Format(time.RFC3339)
+	}
+	if err := r.Patch(ctx, node, patch); err != nil {
+		r.Log.Error(err, "Failed to update GPU workload wait started annotation of node", "node", node.Name)
+		return err
+	}
+	return nil
+}
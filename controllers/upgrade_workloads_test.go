@@ -0,0 +1,127 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	upgrade_v1alpha1 "github.com/NVIDIA/k8s-operator-libs/api/upgrade/v1alpha1"
+	"github.com/NVIDIA/k8s-operator-libs/pkg/upgrade"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1"
+)
+
+func newGPUWorkloadWaitClusterPolicy(wait *gpuv1.DriverUpgradeGPUWorkloadWaitSpec) *gpuv1.ClusterPolicy {
+	return newUpgradeTestClusterPolicy(func(p *gpuv1.DriverUpgradePolicySpec) {
+		p.GPUWorkloadWait = wait
+		p.DrainSpec = &upgrade_v1alpha1.DrainSpec{Enable: true}
+	})
+}
+
+// ownedWorkloadPod returns a workload pod controlled by an object of kind
+func ownedWorkloadPod(name, node string, gpus int64, kind string) *corev1.Pod {
+	pod := workloadPod(name, node, gpus, nil)
+	pod.OwnerReferences = []metav1.OwnerReference{{APIVersion: "v1", Kind: kind, Name: name, UID: "uid", Controller: ptr.To(true)}}
+	pod.Status.Phase = corev1.PodRunning
+	return pod
+}
+
+func TestUpgradeReconcileGPUWorkloadWait(t *testing.T) {
+	podExists := func(t *testing.T, r *UpgradeReconciler, pod *corev1.Pod) bool {
+		t.Helper()
+		err := r.Get(t.Context(), client.ObjectKeyFromObject(pod), &corev1.Pod{})
+		if apierrors.IsNotFound(err) {
+			return false
+		}
+		require.NoError(t, err)
+		return true
+	}
+
+	t.Run("node is held until its GPU workloads finish", func(t *testing.T) {
+		train := ownedWorkloadPod("train", "node-a", 8, "Job")
+		web := ownedWorkloadPod("web", "node-a", 0, "ReplicaSet")
+		monitor := ownedWorkloadPod("monitor", "node-a", 0, "DaemonSet")
+		pinned := workloadPod("pinned", "node-a", 0, map[string]string{"nvidia.com/gpu-driver-upgrade-drain.skip": "true"})
+		cp := newGPUWorkloadWaitClusterPolicy(&gpuv1.DriverUpgradeGPUWorkloadWaitSpec{})
+		r, stateManager := newUpgradeStateTestReconciler(t, []client.Object{cp, train, web, monitor, pinned}, upgradeNodeStates(
+			canaryTestNode("node-a", upgrade.UpgradeStateWaitForJobsRequired, nil))...)
+
+		result, err := r.Reconcile(t.Context(), upgradeSingletonRequest())
+		require.NoError(t, err)
+		assert.Equal(t, gpuWorkloadWaitPollInterval, result.RequeueAfter)
+		assert.Empty(t, appliedNodeNames(stateManager.appliedStates[0], upgrade.UpgradeStateWaitForJobsRequired))
+
+		node := &corev1.Node{}
+		require.NoError(t, r.Get(t.Context(), types.NamespacedName{Name: "node-a"}, node))
+		assert.Contains(t, node.Annotations, UpgradeGPUWorkloadWaitStartedAnnotation)
+		assert.True(t, podExists(t, r, train), "GPU workloads are not evicted")
+		assert.False(t, podExists(t, r, web), "other pods are evicted right away")
+		assert.True(t, podExists(t, r, monitor), "DaemonSet pods are not evicted")
+		assert.True(t, podExists(t, r, pinned), "pods skipping drain are not evicted")
+
+		train.Status.Phase = corev1.PodSucceeded
+		require.NoError(t, r.Status().Update(t.Context(), train))
+		_, err = r.Reconcile(t.Context(), upgradeSingletonRequest())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"node-a"}, appliedNodeNames(stateManager.appliedStates[1], upgrade.UpgradeStateWaitForJobsRequired))
+	})
+
+	t.Run("node is drained once the wait times out", func(t *testing.T) {
+		node := canaryTestNode("node-a", upgrade.UpgradeStateWaitForJobsRequired, nil)
+		node.Annotations = map[string]string{
+			UpgradeGPUWorkloadWaitStartedAnnotation: time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339),
+		}
+		cp := newGPUWorkloadWaitClusterPolicy(&gpuv1.DriverUpgradeGPUWorkloadWaitSpec{})
+		r, stateManager := newUpgradeStateTestReconciler(t, []client.Object{cp, ownedWorkloadPod("train", "node-a", 8, "Job")}, upgradeNodeStates(node)...)
+
+		_, err := r.Reconcile(t.Context(), upgradeSingletonRequest())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"node-a"}, appliedNodeNames(stateManager.appliedStates[0], upgrade.UpgradeStateWaitForJobsRequired))
+	})
+
+	t.Run("only GPU workloads of the owner kinds are waited for", func(t *testing.T) {
+		cp := newGPUWorkloadWaitClusterPolicy(&gpuv1.DriverUpgradeGPUWorkloadWaitSpec{OwnerKinds: []string{"PyTorchJob"}})
+		r, stateManager := newUpgradeStateTestReconciler(t, []client.Object{cp, ownedWorkloadPod("infer", "node-a", 1, "ReplicaSet")}, upgradeNodeStates(
+			canaryTestNode("node-a", upgrade.UpgradeStateWaitForJobsRequired, nil),
+			canaryTestNode("node-b", upgrade.UpgradeStateWaitForJobsRequired, nil))...)
+		require.NoError(t, r.Create(t.Context(), ownedWorkloadPod("train", "node-b", 8, "PyTorchJob")))
+
+		_, err := r.Reconcile(t.Context(), upgradeSingletonRequest())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"node-a"}, appliedNodeNames(stateManager.appliedStates[0], upgrade.UpgradeStateWaitForJobsRequired))
+	})
+
+	t.Run("wait start is cleared once the upgrade is done", func(t *testing.T) {
+		node := canaryTestNode("node-a", upgrade.UpgradeStateDone, nil)
+		node.Annotations = map[string]string{UpgradeGPUWorkloadWaitStartedAnnotation: time.Now().UTC().Format(time.RFC3339)}
+		r, _ := newUpgradeStateTestReconciler(t, []client.Object{newGPUWorkloadWaitClusterPolicy(nil)}, upgradeNodeStates(node)...)
+
+		_, err := r.Reconcile(t.Context(), upgradeSingletonRequest())
+		require.NoError(t, err)
+		require.NoError(t, r.Get(t.Context(), types.NamespacedName{Name: "node-a"}, node))
+		assert.NotContains(t, node.Annotations, UpgradeGPUWorkloadWaitStartedAnnotation)
+	})
+}
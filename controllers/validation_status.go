@@ -0,0 +1,127 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1"
+	"github.com/NVIDIA/gpu-operator/internal/validation"
+)
+
+// maxValidationFailedNodes is the number of nodes listed as failed per component in the
+// validation status
+const maxValidationFailedNodes = 10
+
+// nodeStatusExporterAppLabelValue is the app label of the node status exporter pods
+const nodeStatusExporterAppLabelValue = "nvidia-node-status-exporter"
+
+// getValidationSummaries returns the validation summaries published by the node status
+// exporter pods, by node
+func getValidationSummaries(ctx context.Context, c client.Reader, namespace string) (map[string]string, error) {
+	pods := &corev1.PodList{}
+	if err := c.List(ctx, pods, client.InNamespace(namespace),
+		client.MatchingLabels{DriverLabelKey: nodeStatusExporterAppLabelValue}); err != nil {
+		return nil, fmt.Errorf("failed to list node status exporter pods: %w", err)
+	}
+	summaries := map[string]string{}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		value, ok := pod.Annotations[validation.SummaryAnnotation]
+		if pod.Spec.NodeName == "" || !ok {
+			continue
+		}
+		summaries[pod.Spec.NodeName] = value
+	}
+	return summaries, nil
+}
+
+// validationStatus summarizes the validation summaries of the nodes. It returns nil if no
+// node published any.
+func validationStatus(summaries map[string]string, logger logr.Logger) *gpuv1.ValidationStatus {
+	nodes := make([]string, 0, len(summaries))
+	for node := range summaries {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+
+	components := map[string]*gpuv1.ComponentValidationStatus{}
+	driverVersions := map[string]bool{}
+	for _, node := range nodes {
+		summary, err := validation.ParseNodeSummary(summaries[node])
+		if err != nil {
+			logger.Error(err, "Ignoring invalid validation summary of node", "node", node)
+			continue
+		}
+		for name, report := range summary {
+			component, ok := components[name]
+			if !ok {
+				component = &gpuv1.ComponentValidationStatus{Name: name}
+				components[name] = component
+			}
+			if report.DriverVersion != "" {
+				driverVersions[report.DriverVersion] = true
+			}
+			switch report.Result {
+			case validation.ResultPassed:
+				component.Passed++
+			case validation.ResultRunning:
+				component.Running++
+			case validation.ResultFailed:
+				component.Failed++
+				if len(component.FailedNodes) >= maxValidationFailedNodes {
+					continue
+				}
+				failedNode := gpuv1.ValidationFailedNode{
+					Name:       node,
+					ErrorClass: string(report.ErrorClass),
+					Message:    report.Error,
+				}
+				if report.CompletionTime != nil {
+					// The status keeps times to the second
+					completionTime := metav1.NewTime(report.CompletionTime.Truncate(time.Second))
+					failedNode.CompletionTime = &completionTime
+				}
+				component.FailedNodes = append(component.FailedNodes, failedNode)
+			}
+		}
+	}
+	if len(components) == 0 {
+		return nil
+	}
+
+	status := &gpuv1.ValidationStatus{}
+	for version := range driverVersions {
+		status.DriverVersions = append(status.DriverVersions, version)
+	}
+	sort.Strings(status.DriverVersions)
+	for _, component := range components {
+		status.Components = append(status.Components, *component)
+	}
+	sort.Slice(status.Components, func(i, j int) bool {
+		return status.Components[i].Name < status.Components[j].Name
+	})
+	return status
+}
@@ -0,0 +1,142 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1"
+	"github.com/NVIDIA/gpu-operator/internal/validation"
+)
+
+// encodeSummary returns the published form of summary
+func encodeSummary(t *testing.T, summary validation.NodeSummary) string {
+	t.Helper()
+	value, err := json.Marshal(summary)
+	require.NoError(t, err)
+	return string(value)
+}
+
+func TestValidationStatus(t *testing.T) {
+	completionTime := time.Date(2026, 3, 2, 12, 0, 0, 500, time.UTC)
+	passed := validation.ComponentSummary{Result: validation.ResultPassed, DriverVersion: "570.124.06"}
+
+	t.Run("no node published validation reports", func(t *testing.T) {
+		assert.Nil(t, validationStatus(map[string]string{}, ctrl.Log))
+	})
+
+	t.Run("validations are counted by component", func(t *testing.T) {
+		summaries := map[string]string{
+			"node-c": encodeSummary(t, validation.NodeSummary{
+				"driver": {Result: validation.ResultFailed, DriverVersion: "575.57.08", ErrorClass: validation.ErrorClassTimeout,
+					Error: "timed out waiting for the condition", CompletionTime: &completionTime},
+				"toolkit": {Result: validation.ResultRunning},
+			}),
+			"node-a": encodeSummary(t, validation.NodeSummary{"driver": passed, "toolkit": passed}),
+			"node-b": encodeSummary(t, validation.NodeSummary{"driver": passed}),
+			"node-d": "{",
+		}
+		expectedCompletionTime := metav1.NewTime(completionTime.Truncate(time.Second))
+		assert.Equal(t, &gpuv1.ValidationStatus{
+			DriverVersions: []string{"570.124.06", "575.57.08"},
+			Components: []gpuv1.ComponentValidationStatus{
+				{
+					Name:   "driver",
+					Passed: 2,
+					Failed: 1,
+					FailedNodes: []gpuv1.ValidationFailedNode{{
+						Name:           "node-c",
+						ErrorClass:     string(validation.ErrorClassTimeout),
+						Message:        "timed out waiting for the condition",
+						CompletionTime: &expectedCompletionTime,
+					}},
+				},
+				{Name: "toolkit", Passed: 1, Running: 1},
+			},
+		}, validationStatus(summaries, ctrl.Log))
+	})
+
+	t.Run("failed nodes are capped", func(t *testing.T) {
+		summaries := map[string]string{}
+		for i := 0; i < maxValidationFailedNodes+2; i++ {
+			summaries[string(rune('a'+i))] = encodeSummary(t, validation.NodeSummary{
+				"cuda": {Result: validation.ResultFailed, ErrorClass: validation.ErrorClassCommandFailed},
+			})
+		}
+		status := validationStatus(summaries, ctrl.Log)
+		require.NotNil(t, status)
+		require.Len(t, status.Components, 1)
+		assert.EqualValues(t, maxValidationFailedNodes+2, status.Components[0].Failed)
+		assert.Len(t, status.Components[0].FailedNodes, maxValidationFailedNodes)
+		assert.Equal(t, "a", status.Components[0].FailedNodes[0].Name)
+	})
+}
+
+func TestValidationStatusReconcile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, gpuv1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	exporterPod := func(name, node string, summary validation.NodeSummary) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   gpuHealthTestNamespace,
+				Labels:      map[string]string{DriverLabelKey: nodeStatusExporterAppLabelValue},
+				Annotations: map[string]string{validation.SummaryAnnotation: encodeSummary(t, summary)},
+			},
+			Spec: corev1.PodSpec{NodeName: node},
+		}
+	}
+	passed := validation.ComponentSummary{Result: validation.ResultPassed}
+	otherPod := exporterPod("other", "node-c", validation.NodeSummary{"driver": passed})
+	otherPod.Labels[DriverLabelKey] = ValidatorAppLabelValue
+
+	c := fake.NewClientBuilder().WithScheme(scheme).
+		WithStatusSubresource(&gpuv1.ClusterPolicy{}).
+		WithObjects(
+			&gpuv1.ClusterPolicy{ObjectMeta: metav1.ObjectMeta{Name: "cluster-policy"}},
+			exporterPod("exporter-a", "node-a", validation.NodeSummary{"driver": passed}),
+			exporterPod("exporter-b", "node-b", validation.NodeSummary{"driver": passed, "cuda": passed}),
+			otherPod,
+		).Build()
+	r := &ValidationStatusReconciler{Client: c, Scheme: scheme, Namespace: gpuHealthTestNamespace, Log: logr.Discard()}
+
+	_, err := r.Reconcile(t.Context(), ctrl.Request{NamespacedName: types.NamespacedName{Name: validationStatusControllerSingletonName}})
+	require.NoError(t, err)
+
+	clusterPolicy := &gpuv1.ClusterPolicy{}
+	require.NoError(t, c.Get(t.Context(), types.NamespacedName{Name: "cluster-policy"}, clusterPolicy))
+	assert.Equal(t, &gpuv1.ValidationStatus{
+		Components: []gpuv1.ComponentValidationStatus{
+			{Name: "cuda", Passed: 1},
+			{Name: "driver", Passed: 2},
+		},
+	}, clusterPolicy.Status.Validation)
+}
@@ -0,0 +1,137 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1"
+	"github.com/NVIDIA/gpu-operator/internal/validation"
+)
+
+// validationStatusControllerSingletonName is the request name every watch enqueues; a single
+// reconciliation summarizes the validations of all the nodes.
+const validationStatusControllerSingletonName = "validation-status"
+
+// ValidationStatusReconciler keeps the validation summary of the ClusterPolicy status up to
+// date with the summaries the node status exporter pods publish. It only reads the exporter
+// pods from the cache, so the summaries changing does not run the ClusterPolicy reconciliation.
+type ValidationStatusReconciler struct {
+	client.Client
+	Scheme    *runtime.Scheme
+	Namespace string
+	Log       logr.Logger
+}
+
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+
+// Reconcile updates the validation summary of the ClusterPolicy status.
+func (r *ValidationStatusReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	clusterPolicies := &gpuv1.ClusterPolicyList{}
+	if err := r.List(ctx, clusterPolicies); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list ClusterPolicy: %w", err)
+	}
+	instance := getSingletonClusterPolicy(clusterPolicies.Items)
+	if instance == nil {
+		return ctrl.Result{}, nil
+	}
+
+	summaries, err := getValidationSummaries(ctx, r.Client, r.Namespace)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	status := validationStatus(summaries, r.Log)
+	if apiequality.Semantic.DeepEqual(instance.Status.Validation, status) {
+		return ctrl.Result{}, nil
+	}
+	instance.Status.Validation = status
+	// a conflict with the ClusterPolicy reconciliation requeues the request
+	if err := r.Status().Update(ctx, instance); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update ClusterPolicy validation status: %w", err)
+	}
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ValidationStatusReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
+	c, err := controller.New("validation-status-controller", mgr, controller.Options{
+		Reconciler:              r,
+		MaxConcurrentReconciles: 1,
+		RateLimiter:             workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](minDelayCR, maxDelayCR),
+	})
+	if err != nil {
+		return fmt.Errorf("error creating validation-status controller: %w", err)
+	}
+
+	mapToSingleton := func(_ context.Context, _ client.Object) []reconcile.Request {
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: validationStatusControllerSingletonName}}}
+	}
+
+	if err := c.Watch(source.Kind(
+		mgr.GetCache(),
+		&gpuv1.ClusterPolicy{},
+		handler.TypedEnqueueRequestsFromMapFunc(func(ctx context.Context, cp *gpuv1.ClusterPolicy) []reconcile.Request {
+			return mapToSingleton(ctx, cp)
+		}),
+		singletonCRPredicate[*gpuv1.ClusterPolicy](),
+	)); err != nil {
+		return fmt.Errorf("error watching ClusterPolicy: %w", err)
+	}
+
+	// The node status exporter pods publish the summaries, a deleted pod no longer
+	// publishes the summary of its node.
+	exporterPodPredicate := predicate.TypedFuncs[*corev1.Pod]{
+		CreateFunc: func(e event.TypedCreateEvent[*corev1.Pod]) bool {
+			return false
+		},
+		UpdateFunc: func(e event.TypedUpdateEvent[*corev1.Pod]) bool {
+			return e.ObjectNew.GetLabels()[DriverLabelKey] == nodeStatusExporterAppLabelValue &&
+				e.ObjectOld.GetAnnotations()[validation.SummaryAnnotation] != e.ObjectNew.GetAnnotations()[validation.SummaryAnnotation]
+		},
+		DeleteFunc: func(e event.TypedDeleteEvent[*corev1.Pod]) bool {
+			_, ok := e.Object.GetAnnotations()[validation.SummaryAnnotation]
+			return e.Object.GetLabels()[DriverLabelKey] == nodeStatusExporterAppLabelValue && ok
+		},
+	}
+	if err := c.Watch(source.Kind(
+		mgr.GetCache(),
+		&corev1.Pod{},
+		handler.TypedEnqueueRequestsFromMapFunc(func(ctx context.Context, p *corev1.Pod) []reconcile.Request {
+			return mapToSingleton(ctx, p)
+		}),
+		exporterPodPredicate,
+	)); err != nil {
+		return fmt.Errorf("error watching Pods: %w", err)
+	}
+	return nil
+}
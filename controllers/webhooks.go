@@ -0,0 +1,237 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1"
+	nvidiav1alpha1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1alpha1"
+	"github.com/NVIDIA/gpu-operator/internal/validator"
+)
+
+// +kubebuilder:webhook:path=/mutate-nvidia-com-v1-clusterpolicy,mutating=true,failurePolicy=fail,sideEffects=None,groups=nvidia.com,resources=clusterpolicies,verbs=create;update,versions=v1,name=mclusterpolicy.nvidia.com,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/mutate-nvidia-com-v1alpha1-gpucluster,mutating=true,failurePolicy=fail,sideEffects=None,groups=nvidia.com,resources=gpuclusters,verbs=create;update,versions=v1alpha1,name=mgpucluster.nvidia.com,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-nvidia-com-v1-clusterpolicy,mutating=false,failurePolicy=fail,sideEffects=None,groups=nvidia.com,resources=clusterpolicies,verbs=create;update,versions=v1,name=vclusterpolicy.nvidia.com,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-nvidia-com-v1alpha1-nvidiadriver,mutating=false,failurePolicy=fail,sideEffects=None,groups=nvidia.com,resources=nvidiadrivers,verbs=create;update,versions=v1alpha1,name=vnvidiadriver.nvidia.com,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-nvidia-com-v1alpha1-gpucluster,mutating=false,failurePolicy=fail,sideEffects=None,groups=nvidia.com,resources=gpuclusters,verbs=create,versions=v1alpha1,name=vgpucluster.nvidia.com,admissionReviewVersions=v1
+
+// SetupWebhooksWithManager registers the admission webhooks for the operator CRDs on
+// the manager's webhook server. The defaulting webhooks write the effective defaults
+// into the stored object. The validating webhooks run the same checks the reconcilers
+// perform, so invalid objects are rejected at admission instead of surfacing later
+// as reconcile errors. The NVIDIADriver conversion webhook is served along with them,
+// v1alpha1 being the hub its other versions convert through.
+func SetupWebhooksWithManager(mgr ctrl.Manager) error {
+	if err := ctrl.NewWebhookManagedBy(mgr, &gpuv1.ClusterPolicy{}).
+		WithDefaulter(&clusterPolicyDefaulter{}).
+		WithValidator(&clusterPolicyValidator{client: mgr.GetClient()}).
+		Complete(); err != nil {
+		return err
+	}
+
+	if err := ctrl.NewWebhookManagedBy(mgr, &nvidiav1alpha1.NVIDIADriver{}).
+		WithValidator(&nvidiaDriverValidator{nodeSelectorValidator: validator.NewNodeSelectorValidator(mgr.GetClient())}).
+		Complete(); err != nil {
+		return err
+	}
+
+	return ctrl.NewWebhookManagedBy(mgr, &nvidiav1alpha1.GPUCluster{}).
+		WithDefaulter(&gpuClusterDefaulter{}).
+		WithValidator(&gpuClusterValidator{client: mgr.GetClient()}).
+		Complete()
+}
+
+// clusterPolicyDefaulter sets the effective defaults on ClusterPolicy objects on admission
+type clusterPolicyDefaulter struct{}
+
+var _ admission.Defaulter[*gpuv1.ClusterPolicy] = &clusterPolicyDefaulter{}
+
+// Default sets the enabled flags, host paths and image repositories of a ClusterPolicy
+func (d *clusterPolicyDefaulter) Default(_ context.Context, cp *gpuv1.ClusterPolicy) error {
+	cp.Spec.SetDefaults()
+
+	spec := &cp.Spec
+	for _, i := range []struct {
+		repository, image *string
+		version           string
+		envName           string
+	}{
+		{&spec.Driver.Repository, &spec.Driver.Image, spec.Driver.Version, "DRIVER_IMAGE"},
+		{&spec.Toolkit.Repository, &spec.Toolkit.Image, spec.Toolkit.Version, "CONTAINER_TOOLKIT_IMAGE"},
+		{&spec.DevicePlugin.Repository, &spec.DevicePlugin.Image, spec.DevicePlugin.Version, "DEVICE_PLUGIN_IMAGE"},
+		{&spec.DCGMExporter.Repository, &spec.DCGMExporter.Image, spec.DCGMExporter.Version, "DCGM_EXPORTER_IMAGE"},
+		{&spec.DCGM.Repository, &spec.DCGM.Image, spec.DCGM.Version, "DCGM_IMAGE"},
+		{&spec.NodeStatusExporter.Repository, &spec.NodeStatusExporter.Image, spec.NodeStatusExporter.Version, "VALIDATOR_IMAGE"},
+		{&spec.GPUFeatureDiscovery.Repository, &spec.GPUFeatureDiscovery.Image, spec.GPUFeatureDiscovery.Version, "GFD_IMAGE"},
+		{&spec.MIGManager.Repository, &spec.MIGManager.Image, spec.MIGManager.Version, "MIG_MANAGER_IMAGE"},
+		{&spec.Validator.Repository, &spec.Validator.Image, spec.Validator.Version, "VALIDATOR_IMAGE"},
+		{&spec.VFIOManager.Repository, &spec.VFIOManager.Image, spec.VFIOManager.Version, "VFIO_MANAGER_IMAGE"},
+		{&spec.SandboxDevicePlugin.Repository, &spec.SandboxDevicePlugin.Image, spec.SandboxDevicePlugin.Version, "SANDBOX_DEVICE_PLUGIN_IMAGE"},
+		{&spec.VGPUManager.Repository, &spec.VGPUManager.Image, spec.VGPUManager.Version, "VGPU_MANAGER_IMAGE"},
+		{&spec.VGPUDeviceManager.Repository, &spec.VGPUDeviceManager.Image, spec.VGPUDeviceManager.Version, "VGPU_DEVICE_MANAGER_IMAGE"},
+		{&spec.KataManager.Repository, &spec.KataManager.Image, spec.KataManager.Version, "KATA_MANAGER_IMAGE"},
+		{&spec.CCManager.Repository, &spec.CCManager.Image, spec.CCManager.Version, "CC_MANAGER_IMAGE"},
+	} {
+		setImageDefaults(i.repository, i.image, i.version, i.envName)
+	}
+	return nil
+}
+
+// gpuClusterDefaulter sets the effective defaults on GPUCluster objects on admission
+type gpuClusterDefaulter struct{}
+
+var _ admission.Defaulter[*nvidiav1alpha1.GPUCluster] = &gpuClusterDefaulter{}
+
+// Default sets the enabled flags, healthcheck ports, host paths and image repositories
+// of a GPUCluster
+func (d *gpuClusterDefaulter) Default(_ context.Context, gc *nvidiav1alpha1.GPUCluster) error {
+	gc.Spec.SetDefaults()
+
+	dra, dcgm, dcgmExporter := &gc.Spec.DRADriver, gc.Spec.DCGM, gc.Spec.DCGMExporter
+	setImageDefaults(&dra.Repository, &dra.Image, dra.Version, "DRA_DRIVER_IMAGE")
+	setImageDefaults(&dcgm.Repository, &dcgm.Image, dcgm.Version, "DCGM_IMAGE")
+	setImageDefaults(&dcgmExporter.Repository, &dcgmExporter.Image, dcgmExporter.Version, "DCGM_EXPORTER_IMAGE")
+	return nil
+}
+
+// setImageDefaults completes the image of a component that pins a version but omits
+// the repository or image name, taking the missing parts from the operator's default
+// image in envName. A component that sets no version is left untouched so that it keeps
+// following the default image across operator upgrades.
+func setImageDefaults(repository, image *string, version string, envName string) {
+	if version == "" || (*repository != "" && *image != "") {
+		return
+	}
+	defaultRepository, defaultImage, ok := splitImagePath(os.Getenv(envName))
+	if !ok {
+		return
+	}
+	if *repository == "" {
+		*repository = defaultRepository
+	}
+	if *image == "" {
+		*image = defaultImage
+	}
+}
+
+// splitImagePath splits an image reference such as nvcr.io/nvidia/driver:580 into its
+// repository (nvcr.io/nvidia) and image name (driver), dropping the tag or digest.
+func splitImagePath(path string) (string, string, bool) {
+	path, _, _ = strings.Cut(path, "@")
+	idx := strings.LastIndex(path, "/")
+	if idx <= 0 {
+		return "", "", false
+	}
+	repository, image := path[:idx], path[idx+1:]
+	image, _, _ = strings.Cut(image, ":")
+	if image == "" {
+		return "", "", false
+	}
+	return repository, image, true
+}
+
+// clusterPolicyValidator validates ClusterPolicy objects on admission
+type clusterPolicyValidator struct {
+	client client.Reader
+}
+
+var _ admission.Validator[*gpuv1.ClusterPolicy] = &clusterPolicyValidator{}
+
+// ValidateCreate rejects an invalid spec and a ClusterPolicy created alongside a GPUCluster
+func (v *clusterPolicyValidator) ValidateCreate(ctx context.Context, cp *gpuv1.ClusterPolicy) (admission.Warnings, error) {
+	if err := validateClusterPolicySpec(&cp.Spec); err != nil {
+		return nil, err
+	}
+	// TODO: remove once both ClusterPolicy and GPUCluster can co-exist
+	return nil, checkConflictingGPUCluster(ctx, v.client)
+}
+
+// ValidateUpdate rejects an invalid spec. The GPUCluster conflict is only enforced on
+// create so that an existing ClusterPolicy can still be edited or cleaned up.
+func (v *clusterPolicyValidator) ValidateUpdate(_ context.Context, _, cp *gpuv1.ClusterPolicy) (admission.Warnings, error) {
+	return nil, validateClusterPolicySpec(&cp.Spec)
+}
+
+// ValidateDelete is a no-op, deletion is always allowed
+func (v *clusterPolicyValidator) ValidateDelete(_ context.Context, _ *gpuv1.ClusterPolicy) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// nvidiaDriverValidator validates NVIDIADriver objects on admission
+type nvidiaDriverValidator struct {
+	nodeSelectorValidator validator.Validator
+}
+
+var _ admission.Validator[*nvidiav1alpha1.NVIDIADriver] = &nvidiaDriverValidator{}
+
+// ValidateCreate rejects a driver whose nodeSelector is invalid or conflicts with other drivers
+func (v *nvidiaDriverValidator) ValidateCreate(ctx context.Context, cr *nvidiav1alpha1.NVIDIADriver) (admission.Warnings, error) {
+	return nil, v.nodeSelectorValidator.Validate(ctx, cr)
+}
+
+// ValidateUpdate rejects a driver whose nodeSelector is invalid or conflicts with other drivers
+func (v *nvidiaDriverValidator) ValidateUpdate(ctx context.Context, _, cr *nvidiav1alpha1.NVIDIADriver) (admission.Warnings, error) {
+	// Let the finalizer removal through for a driver that is already being deleted
+	if cr.HasDeletionTimestamp() {
+		return nil, nil
+	}
+	return nil, v.nodeSelectorValidator.Validate(ctx, cr)
+}
+
+// ValidateDelete is a no-op, deletion is always allowed
+func (v *nvidiaDriverValidator) ValidateDelete(_ context.Context, _ *nvidiav1alpha1.NVIDIADriver) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// gpuClusterValidator validates GPUCluster objects on admission
+type gpuClusterValidator struct {
+	client client.Reader
+}
+
+var _ admission.Validator[*nvidiav1alpha1.GPUCluster] = &gpuClusterValidator{}
+
+// ValidateCreate rejects a GPUCluster whose prerequisites are not met
+func (v *gpuClusterValidator) ValidateCreate(ctx context.Context, _ *nvidiav1alpha1.GPUCluster) (admission.Warnings, error) {
+	msg, err := validateGPUClusterPrerequisites(ctx, v.client)
+	if err != nil {
+		return nil, err
+	}
+	if msg != "" {
+		return nil, errors.New(msg)
+	}
+	return nil, nil
+}
+
+// ValidateUpdate allows all updates. The prerequisites are only enforced on create so
+// that an existing GPUCluster can still be edited or cleaned up; the reconciler keeps
+// reporting unmet prerequisites through the PrerequisiteNotMet condition.
+func (v *gpuClusterValidator) ValidateUpdate(_ context.Context, _, _ *nvidiav1alpha1.GPUCluster) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// ValidateDelete is a no-op, deletion is always allowed
+func (v *gpuClusterValidator) ValidateDelete(_ context.Context, _ *nvidiav1alpha1.GPUCluster) (admission.Warnings, error) {
+	return nil, nil
+}
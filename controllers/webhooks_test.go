@@ -0,0 +1,220 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1"
+	nvidiav1alpha1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1alpha1"
+	"github.com/NVIDIA/gpu-operator/internal/validator"
+)
+
+func newWebhookTestClient(t *testing.T, objs ...client.Object) client.Client {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, nvidiav1alpha1.AddToScheme(scheme))
+	require.NoError(t, gpuv1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+func TestClusterPolicyValidator(t *testing.T) {
+	invalidSpec := gpuv1.ClusterPolicySpec{
+		CDI: gpuv1.CDIConfigSpec{
+			Enabled:          ptr.To(false),
+			NRIPluginEnabled: ptr.To(true),
+		},
+	}
+
+	tests := []struct {
+		description string
+		objs        []client.Object
+		spec        gpuv1.ClusterPolicySpec
+		createErr   string
+		updateErr   string
+	}{
+		{
+			description: "valid spec",
+		},
+		{
+			description: "invalid spec",
+			spec:        invalidSpec,
+			createErr:   "the NRI Plugin cannot be enabled when CDI is disabled",
+			updateErr:   "the NRI Plugin cannot be enabled when CDI is disabled",
+		},
+		{
+			description: "GPUCluster present",
+			objs:        []client.Object{&nvidiav1alpha1.GPUCluster{ObjectMeta: metav1.ObjectMeta{Name: "gpu-cluster"}}},
+			createErr:   `conflicting GPUCluster resource "gpu-cluster" detected; ClusterPolicy and GPUCluster cannot co-exist`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			v := &clusterPolicyValidator{client: newWebhookTestClient(t, tc.objs...)}
+			cp := &gpuv1.ClusterPolicy{ObjectMeta: metav1.ObjectMeta{Name: "cluster-policy"}, Spec: tc.spec}
+
+			_, err := v.ValidateCreate(t.Context(), cp)
+			if tc.createErr != "" {
+				require.EqualError(t, err, tc.createErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			_, err = v.ValidateUpdate(t.Context(), cp, cp)
+			if tc.updateErr != "" {
+				require.EqualError(t, err, tc.updateErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNVIDIADriverValidator(t *testing.T) {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a", Labels: map[string]string{"nodepool": "a"}}}
+	existing := &nvidiav1alpha1.NVIDIADriver{
+		ObjectMeta: metav1.ObjectMeta{Name: "driver-a"},
+		Spec:       nvidiav1alpha1.NVIDIADriverSpec{NodeSelector: map[string]string{"nodepool": "a"}},
+	}
+	c := newWebhookTestClient(t, node, existing)
+	v := &nvidiaDriverValidator{nodeSelectorValidator: validator.NewNodeSelectorValidator(c)}
+
+	conflicting := &nvidiav1alpha1.NVIDIADriver{
+		ObjectMeta: metav1.ObjectMeta{Name: "driver-b"},
+		Spec:       nvidiav1alpha1.NVIDIADriverSpec{NodeSelector: map[string]string{"nodepool": "a"}},
+	}
+	_, err := v.ValidateCreate(t.Context(), conflicting)
+	require.EqualError(t, err, "multiple NVIDIADrivers match the same node node-a: [driver-a driver-b]")
+
+	nonConflicting := &nvidiav1alpha1.NVIDIADriver{
+		ObjectMeta: metav1.ObjectMeta{Name: "driver-b"},
+		Spec:       nvidiav1alpha1.NVIDIADriverSpec{NodeSelector: map[string]string{"nodepool": "b"}},
+	}
+	_, err = v.ValidateCreate(t.Context(), nonConflicting)
+	require.NoError(t, err)
+
+	// Moving the existing driver onto another pool is allowed
+	updated := existing.DeepCopy()
+	updated.Spec.NodeSelector = map[string]string{"nodepool": "b"}
+	_, err = v.ValidateUpdate(t.Context(), existing, updated)
+	require.NoError(t, err)
+
+	secondDefault := &nvidiav1alpha1.NVIDIADriver{ObjectMeta: metav1.ObjectMeta{Name: "default-b"}, Spec: nvidiav1alpha1.NVIDIADriverSpec{Default: true}}
+	c = newWebhookTestClient(t, &nvidiav1alpha1.NVIDIADriver{ObjectMeta: metav1.ObjectMeta{Name: "default-a"}, Spec: nvidiav1alpha1.NVIDIADriverSpec{Default: true}})
+	v = &nvidiaDriverValidator{nodeSelectorValidator: validator.NewNodeSelectorValidator(c)}
+	_, err = v.ValidateCreate(t.Context(), secondDefault)
+	require.ErrorIs(t, err, validator.ErrMultipleDefaultNVIDIADrivers)
+}
+
+func TestGPUClusterValidator(t *testing.T) {
+	gc := &nvidiav1alpha1.GPUCluster{ObjectMeta: metav1.ObjectMeta{Name: "gpu-cluster"}}
+
+	v := &gpuClusterValidator{client: newWebhookTestClient(t)}
+	_, err := v.ValidateCreate(t.Context(), gc)
+	require.NoError(t, err)
+
+	cp := &gpuv1.ClusterPolicy{ObjectMeta: metav1.ObjectMeta{Name: "cluster-policy"}}
+	v = &gpuClusterValidator{client: newWebhookTestClient(t, cp)}
+	_, err = v.ValidateCreate(t.Context(), gc)
+	require.EqualError(t, err, `A ClusterPolicy CR "cluster-policy" exists; a ClusterPolicy CR and GPUCluster CR may not exist at the same time`)
+
+	// Updates are not blocked, the reconciler reports the unmet prerequisite instead
+	_, err = v.ValidateUpdate(t.Context(), gc, gc)
+	require.NoError(t, err)
+}
+
+func TestSplitImagePath(t *testing.T) {
+	tests := []struct {
+		path       string
+		repository string
+		image      string
+		ok         bool
+	}{
+		{path: "nvcr.io/nvidia/driver:580.65.06", repository: "nvcr.io/nvidia", image: "driver", ok: true},
+		{path: "nvcr.io/nvidia/k8s/dcgm-exporter@sha256:abc", repository: "nvcr.io/nvidia/k8s", image: "dcgm-exporter", ok: true},
+		{path: "registry:5000/nvidia/gpu-operator:v1", repository: "registry:5000/nvidia", image: "gpu-operator", ok: true},
+		{path: "driver:580"},
+		{path: ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.path, func(t *testing.T) {
+			repository, image, ok := splitImagePath(tc.path)
+			require.Equal(t, tc.ok, ok)
+			require.Equal(t, tc.repository, repository)
+			require.Equal(t, tc.image, image)
+		})
+	}
+}
+
+func TestGPUClusterDefaulter(t *testing.T) {
+	t.Setenv("DRA_DRIVER_IMAGE", "nvcr.io/nvidia/k8s-dra-driver-gpu:v25.8.0")
+	t.Setenv("DCGM_EXPORTER_IMAGE", "nvcr.io/nvidia/k8s/dcgm-exporter:4.2.3")
+
+	gc := &nvidiav1alpha1.GPUCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "gpu-cluster"},
+		Spec: nvidiav1alpha1.GPUClusterSpec{
+			// Only the version is pinned, the repository and image come from the default image
+			DRADriver:    nvidiav1alpha1.DRADriverSpec{Version: "v25.10.0"},
+			DCGMExporter: &gpuv1.DCGMExporterSpec{},
+		},
+	}
+	require.NoError(t, (&gpuClusterDefaulter{}).Default(t.Context(), gc))
+
+	require.Equal(t, "nvcr.io/nvidia", gc.Spec.DRADriver.Repository)
+	require.Equal(t, "k8s-dra-driver-gpu", gc.Spec.DRADriver.Image)
+	require.Equal(t, "v25.10.0", gc.Spec.DRADriver.Version)
+	// Without a pinned version the default image keeps being followed
+	require.Empty(t, gc.Spec.DCGMExporter.Repository)
+	require.Equal(t, ptr.To(true), gc.Spec.DCGMExporter.Enabled)
+	require.Equal(t, ptr.To(false), gc.Spec.DCGM.Enabled)
+	require.Equal(t, ptr.To(int32(51516)), gc.Spec.DRADriver.GPUs.KubeletPlugin.Healthcheck.Port)
+	require.Equal(t, ptr.To(int32(51515)), gc.Spec.DRADriver.ComputeDomains.KubeletPlugin.Healthcheck.Port)
+	require.Equal(t, "/var/lib/kubelet", gc.Spec.HostPaths.KubeletRootDir)
+}
+
+func TestClusterPolicyDefaulter(t *testing.T) {
+	t.Setenv("DRIVER_IMAGE", "nvcr.io/nvidia/driver:580.65.06")
+
+	cp := &gpuv1.ClusterPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-policy"},
+		Spec: gpuv1.ClusterPolicySpec{
+			Driver:  gpuv1.DriverSpec{Version: "580.82.07"},
+			Toolkit: gpuv1.ToolkitSpec{Repository: "example.com/nvidia", Image: "container-toolkit", Version: "v1.18.0"},
+		},
+	}
+	require.NoError(t, (&clusterPolicyDefaulter{}).Default(t.Context(), cp))
+
+	require.Equal(t, "nvcr.io/nvidia", cp.Spec.Driver.Repository)
+	require.Equal(t, "driver", cp.Spec.Driver.Image)
+	require.Equal(t, "example.com/nvidia", cp.Spec.Toolkit.Repository)
+	require.Equal(t, ptr.To(true), cp.Spec.Driver.Enabled)
+	require.Equal(t, ptr.To(false), cp.Spec.VGPUManager.Enabled)
+	require.Equal(t, "/var/lib/kubelet", cp.Spec.HostPaths.KubeletRootDir)
+}
@@ -56,6 +56,23 @@ func (u *clusterPolicyUpdater) SetConditionsError(ctx context.Context, cr any, r
 	return u.setConditions(ctx, clusterPolicyCr, Error, reason, message)
 }
 
+func (u *clusterPolicyUpdater) SetConditionDrifted(ctx context.Context, cr any, drifted bool, reason, message string) error {
+	clusterPolicyCr, ok := cr.(*nvidiav1.ClusterPolicy)
+	if !ok {
+		return fmt.Errorf("provided object is not a *nvidiav1.ClusterPolicy")
+	}
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		instance := &nvidiav1.ClusterPolicy{}
+		if err := u.client.Get(ctx, types.NamespacedName{Name: clusterPolicyCr.Name}, instance); err != nil {
+			return fmt.Errorf("failed to get ClusterPolicy instance for status update: %w", err)
+		}
+		if !setDriftedCondition(&instance.Status.Conditions, drifted, reason, message) {
+			return nil
+		}
+		return u.client.Status().Update(ctx, instance)
+	})
+}
+
 // updateConditions updates the conditions of the ClusterPolicy CR
 func (u *clusterPolicyUpdater) updateConditions(ctx context.Context, cr *nvidiav1.ClusterPolicy, statusType, reason, message string) error {
 	// Fetch latest instance and update state to avoid version mismatch
@@ -94,6 +111,7 @@ func (u *clusterPolicyUpdater) updateConditions(ctx context.Context, cr *nvidiav
 	default:
 		return fmt.Errorf("unknown status type provided: %s", statusType)
 	}
+	setPausedCondition(&instance.Status.Conditions, instance)
 
 	return u.client.Status().Update(ctx, instance)
 }
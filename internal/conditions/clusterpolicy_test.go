@@ -26,6 +26,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -230,3 +231,59 @@ func TestClusterPolicyUpdater_UpdateError(t *testing.T) {
 	require.Error(t, err)
 	assert.ErrorContains(t, err, "status update boom")
 }
+
+func TestClusterPolicyUpdater_PausedCondition(t *testing.T) {
+	clusterPolicy := newClusterPolicy("cluster-policy")
+	clusterPolicy.Annotations = map[string]string{"nvidia.com/reconcile-paused": "state-dcgm-exporter"}
+	c := newClusterPolicyClient(t, clusterPolicy)
+	u := NewClusterPolicyUpdater(c)
+	ctx := context.Background()
+
+	require.NoError(t, u.SetConditionsReady(ctx, clusterPolicy, Reconciled, "ok"))
+
+	got := &nvidiav1.ClusterPolicy{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: clusterPolicy.Name}, got))
+	paused := meta.FindStatusCondition(got.Status.Conditions, Paused)
+	require.NotNil(t, paused)
+	assert.Equal(t, metav1.ConditionTrue, paused.Status)
+	assert.Equal(t, ReconcilePaused, paused.Reason)
+	assert.Equal(t, "Reconciliation of state-dcgm-exporter is paused by the nvidia.com/reconcile-paused annotation", paused.Message)
+
+	// removing the annotation removes the condition
+	got.Annotations = nil
+	require.NoError(t, c.Update(ctx, got))
+	require.NoError(t, u.SetConditionsReady(ctx, got, Reconciled, "ok"))
+
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: clusterPolicy.Name}, got))
+	assert.Nil(t, meta.FindStatusCondition(got.Status.Conditions, Paused))
+}
+
+func TestClusterPolicyUpdater_SetConditionDrifted(t *testing.T) {
+	clusterPolicy := newClusterPolicy("cluster-policy")
+	c := newClusterPolicyClient(t, clusterPolicy)
+	u := NewClusterPolicyUpdater(c)
+	ctx := context.Background()
+
+	require.NoError(t, u.SetConditionsReady(ctx, clusterPolicy, Reconciled, "ok"))
+	require.NoError(t, u.SetConditionDrifted(ctx, clusterPolicy, true, DriftDetected, "DaemonSet gpu-operator/nvidia-dcgm-exporter drifted"))
+
+	got := &nvidiav1.ClusterPolicy{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: clusterPolicy.Name}, got))
+	want := []metav1.Condition{
+		{Type: Ready, Status: metav1.ConditionTrue, Reason: Reconciled, Message: "ok"},
+		{Type: Error, Status: metav1.ConditionFalse, Reason: Ready},
+		{Type: Drifted, Status: metav1.ConditionTrue, Reason: DriftDetected, Message: "DaemonSet gpu-operator/nvidia-dcgm-exporter drifted"},
+	}
+	diff := cmp.Diff(want, got.Status.Conditions,
+		cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime", "ObservedGeneration"))
+	assert.Empty(t, diff, "unexpected conditions (-want +got):\n%s", diff)
+
+	// the Drifted condition survives the next Ready update
+	require.NoError(t, u.SetConditionsReady(ctx, clusterPolicy, Reconciled, "ok"))
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: clusterPolicy.Name}, got))
+	assert.True(t, meta.IsStatusConditionTrue(got.Status.Conditions, Drifted))
+
+	require.NoError(t, u.SetConditionDrifted(ctx, clusterPolicy, false, NoDriftDetected, ""))
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: clusterPolicy.Name}, got))
+	assert.True(t, meta.IsStatusConditionFalse(got.Status.Conditions, Drifted))
+}
@@ -18,6 +18,11 @@ package conditions
 
 import (
 	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/NVIDIA/gpu-operator/internal/pause"
 )
 
 const (
@@ -25,10 +30,48 @@ const (
 	Ready = "Ready"
 	// Error condition type indicates one or more of the resources managed by the controller are in error state
 	Error = "Error"
+	// Paused condition type indicates that the reconciliation of the resource, or of some of
+	// its components, is paused by the reconcile-paused annotation
+	Paused = "Paused"
+	// Drifted condition type indicates that objects managed by the controller were edited
+	// out of band and no longer match their desired state
+	Drifted = "Drifted"
 )
 
 // Updater interface
 type Updater interface {
 	SetConditionsReady(ctx context.Context, cr any, reason, message string) error
 	SetConditionsError(ctx context.Context, cr any, reason, message string) error
+	SetConditionDrifted(ctx context.Context, cr any, drifted bool, reason, message string) error
+}
+
+// setPausedCondition reports the Paused condition of obj from its reconcile-paused
+// annotation. The condition is removed once nothing is paused anymore.
+func setPausedCondition(conditions *[]metav1.Condition, obj metav1.Object) {
+	message := pause.Message(obj)
+	if message == "" {
+		meta.RemoveStatusCondition(conditions, Paused)
+		return
+	}
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:    Paused,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReconcilePaused,
+		Message: message,
+	})
+}
+
+// setDriftedCondition reports the Drifted condition and returns whether the conditions
+// changed
+func setDriftedCondition(conditions *[]metav1.Condition, drifted bool, reason, message string) bool {
+	status := metav1.ConditionFalse
+	if drifted {
+		status = metav1.ConditionTrue
+	}
+	return meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:    Drifted,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	})
 }
@@ -34,4 +34,12 @@ const (
 	DriverNotReady = "DriverNotReady"
 	// PrerequisiteNotMet indicates that a configuration prerequisite for reconciliation has not been met
 	PrerequisiteNotMet = "PrerequisiteNotMet"
+	// ReconcilePaused indicates that reconciliation is paused by the reconcile-paused annotation
+	ReconcilePaused = "ReconcilePaused"
+	// DriftDetected indicates that managed objects no longer match their desired state
+	DriftDetected = "DriftDetected"
+	// DriftCorrected indicates that drifted objects were restored to their desired state
+	DriftCorrected = "DriftCorrected"
+	// NoDriftDetected indicates that all managed objects match their desired state
+	NoDriftDetected = "NoDriftDetected"
 )
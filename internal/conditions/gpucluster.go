@@ -56,6 +56,23 @@ func (u *gpuClusterUpdater) SetConditionsError(ctx context.Context, cr any, reas
 	return u.setConditions(ctx, gpuClusterCr, Error, reason, message)
 }
 
+func (u *gpuClusterUpdater) SetConditionDrifted(ctx context.Context, cr any, drifted bool, reason, message string) error {
+	gpuClusterCr, ok := cr.(*nvidiav1alpha1.GPUCluster)
+	if !ok {
+		return fmt.Errorf("provided object is not a *nvidiav1alpha1.GPUCluster")
+	}
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		instance := &nvidiav1alpha1.GPUCluster{}
+		if err := u.client.Get(ctx, types.NamespacedName{Name: gpuClusterCr.Name}, instance); err != nil {
+			return fmt.Errorf("failed to get GPUCluster instance for status update: %w", err)
+		}
+		if !setDriftedCondition(&instance.Status.Conditions, drifted, reason, message) {
+			return nil
+		}
+		return u.client.Status().Update(ctx, instance)
+	})
+}
+
 func (u *gpuClusterUpdater) updateConditions(ctx context.Context, cr *nvidiav1alpha1.GPUCluster, statusType, reason, message string) error {
 	// Refetch to avoid a resourceVersion conflict.
 	instance := &nvidiav1alpha1.GPUCluster{}
@@ -100,6 +117,7 @@ func (u *gpuClusterUpdater) updateConditions(ctx context.Context, cr *nvidiav1al
 	default:
 		return fmt.Errorf("unknown status type provided: %s", statusType)
 	}
+	setPausedCondition(&instance.Status.Conditions, instance)
 
 	return u.client.Status().Update(ctx, instance)
 }
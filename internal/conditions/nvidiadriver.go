@@ -62,6 +62,23 @@ func (u *nvDriverUpdater) SetConditionsError(ctx context.Context, cr any, reason
 	return u.setConditions(ctx, nvDriverCr, Error, reason, message)
 }
 
+func (u *nvDriverUpdater) SetConditionDrifted(ctx context.Context, cr any, drifted bool, reason, message string) error {
+	nvDriverCr, ok := cr.(*nvidiav1alpha1.NVIDIADriver)
+	if !ok {
+		return fmt.Errorf("provided object is not a *nvidiav1alpha1.NVIDIADriver")
+	}
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		instance := &nvidiav1alpha1.NVIDIADriver{}
+		if err := u.client.Get(ctx, types.NamespacedName{Name: nvDriverCr.Name}, instance); err != nil {
+			return fmt.Errorf("failed to get NVIDIADriver instance for status update: %w", err)
+		}
+		if !setDriftedCondition(&instance.Status.Conditions, drifted, reason, message) {
+			return nil
+		}
+		return u.client.Status().Update(ctx, instance)
+	})
+}
+
 // updateConditions updates the conditions of the NVIDIADriver CR
 func (u *nvDriverUpdater) updateConditions(ctx context.Context, cr *nvidiav1alpha1.NVIDIADriver, statusType, reason, message string) error {
 	// Fetch latest instance and update state to avoid version mismatch
@@ -108,6 +125,7 @@ func (u *nvDriverUpdater) updateConditions(ctx context.Context, cr *nvidiav1alph
 	default:
 		return fmt.Errorf("unknown status type provided: %s", statusType)
 	}
+	setPausedCondition(&instance.Status.Conditions, instance)
 
 	return u.client.Status().Update(ctx, instance)
 }
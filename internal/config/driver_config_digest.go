@@ -17,6 +17,7 @@
 package config
 
 import (
+	"reflect"
 	"sort"
 
 	corev1 "k8s.io/api/core/v1"
@@ -68,7 +69,7 @@ func DriverConfigDigestFromPodSpec(spec *corev1.PodSpec) string {
 //   - Reorder fields:             no digest change → no reinstall
 //
 // This struct is shared by two code paths:
-//   - ClusterPolicy (extractDriverInstallConfig): extracts fields from a
+//   - ClusterPolicy (DriverInstallStateFromPodSpec): extracts fields from a
 //     fully-transformed DaemonSet's PodSpec. Fields like KernelModuleType and proxy
 //     settings are captured implicitly through env vars and volumes rather than
 //     as top-level struct fields.
@@ -140,6 +141,100 @@ type DriverInstallState struct {
 	HostRoot string
 }
 
+// DriverInstallStateFromPodSpec extracts driver-relevant fields from a
+// post-transformation PodSpec (ClusterPolicy path). Fields like
+// KernelModuleType and proxy settings are captured implicitly via the
+// per-container env var maps rather than as top-level struct fields. The
+// DRIVER_CONFIG_DIGEST env is ignored, so a pod spec carrying the digest yields the
+// state the digest was computed from.
+func DriverInstallStateFromPodSpec(podSpec *corev1.PodSpec) *DriverInstallState {
+	config := &DriverInstallState{}
+
+	for i := range podSpec.InitContainers {
+		c := &podSpec.InitContainers[i]
+		if c.Name == "k8s-driver-manager" {
+			config.DriverManagerImage = c.Image
+			config.ManagerEnv = extractDriverEnvVars(c.Env)
+		}
+	}
+
+	for i := range podSpec.Containers {
+		c := &podSpec.Containers[i]
+		switch c.Name {
+		case "nvidia-driver-ctr":
+			config.DriverImage = c.Image
+			config.DriverCommand = c.Command
+			config.DriverArgs = c.Args
+			config.DriverEnv = extractDriverEnvVars(c.Env)
+			for _, ef := range c.EnvFrom {
+				if ef.SecretRef != nil {
+					config.SecretEnvSource = ef.SecretRef.Name
+				}
+			}
+			config.AdditionalVolumeMounts = ExtractVolumeMounts(c.VolumeMounts)
+		case "nvidia-peermem-ctr":
+			config.PeermemImage = c.Image
+			config.GPUDirectRDMAEnabled = true
+		case "nvidia-fs-ctr":
+			config.GDSImage = c.Image
+			config.GDSEnabled = true
+			config.GDSEnv = extractDriverEnvVars(c.Env)
+		case "nvidia-gdrcopy-ctr":
+			config.GDRCopyImage = c.Image
+			config.GDRCopyEnabled = true
+			config.GDRCopyEnv = extractDriverEnvVars(c.Env)
+		case "openshift-driver-toolkit-ctr":
+			config.DTKImage = c.Image
+			config.DTKEnabled = true
+		}
+	}
+
+	config.AdditionalVolumes = ExtractVolumes(podSpec.Volumes)
+	for _, v := range podSpec.Volumes {
+		if v.Name == "host-root" && v.HostPath != nil {
+			config.HostRoot = v.HostPath.Path
+		}
+	}
+
+	return config
+}
+
+// extractDriverEnvVars is ExtractEnvVars without the DRIVER_CONFIG_DIGEST env
+func extractDriverEnvVars(envs []corev1.EnvVar) []EnvVar {
+	var filtered []corev1.EnvVar
+	for _, e := range envs {
+		if e.Name != DriverConfigDigestEnvName {
+			filtered = append(filtered, e)
+		}
+	}
+	return ExtractEnvVars(filtered)
+}
+
+// ChangedFields returns the names of the DriverInstallState fields that differ
+// between s and other, in declaration order. Like the digest, a nil and an empty
+// value of a field are considered equal.
+func (s *DriverInstallState) ChangedFields(other *DriverInstallState) []string {
+	if s == nil {
+		s = &DriverInstallState{}
+	}
+	if other == nil {
+		other = &DriverInstallState{}
+	}
+
+	var changed []string
+	a, b := reflect.ValueOf(s).Elem(), reflect.ValueOf(other).Elem()
+	for i := 0; i < a.NumField(); i++ {
+		fa, fb := a.Field(i), b.Field(i)
+		if fa.Kind() == reflect.Slice && fa.Len() == 0 && fb.Len() == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(fa.Interface(), fb.Interface()) {
+			changed = append(changed, a.Type().Field(i).Name)
+		}
+	}
+	return changed
+}
+
 // VolumeConfig and VolumeMountConfig are purposefully not corev1.Volume /
 // corev1.VolumeMount. Including them would make the digest change whenever Kubernetes
 // adds a new field to the struct, even if the operator's configuration is
@@ -387,3 +387,69 @@ func TestDriverConfigDigestFromPodSpec(t *testing.T) {
 		})
 	}
 }
+
+func TestDriverInstallStateChangedFields(t *testing.T) {
+	base := &DriverInstallState{
+		DriverImage: "nvcr.io/nvidia/driver:580.65.06",
+		DriverEnv:   []EnvVar{{Name: "A", Value: "a"}},
+		HostRoot:    "/",
+	}
+
+	tests := []struct {
+		name     string
+		modify   func(*DriverInstallState)
+		expected []string
+	}{
+		{
+			name:     "no changes",
+			modify:   func(s *DriverInstallState) {},
+			expected: nil,
+		},
+		{
+			name:     "nil and empty slices are equal",
+			modify:   func(s *DriverInstallState) { s.DriverArgs = []string{} },
+			expected: nil,
+		},
+		{
+			name: "changed fields in declaration order",
+			modify: func(s *DriverInstallState) {
+				s.HostRoot = "/host"
+				s.DriverImage = "nvcr.io/nvidia/driver:580.82.07"
+				s.DriverEnv = append(s.DriverEnv, EnvVar{Name: "B", Value: "b"})
+				s.GDSEnabled = true
+			},
+			expected: []string{"DriverImage", "DriverEnv", "GDSEnabled", "HostRoot"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			other := *base
+			other.DriverEnv = append([]EnvVar{}, base.DriverEnv...)
+			tc.modify(&other)
+			assert.Equal(t, tc.expected, base.ChangedFields(&other))
+		})
+	}
+
+	assert.Equal(t, []string{"DriverImage", "DriverEnv", "HostRoot"}, base.ChangedFields(nil))
+}
+
+func TestDriverInstallStateFromPodSpecIgnoresDigest(t *testing.T) {
+	spec := &corev1.PodSpec{
+		InitContainers: []corev1.Container{{
+			Name: "k8s-driver-manager",
+			Env:  []corev1.EnvVar{{Name: "ENABLE_GPU_POD_EVICTION", Value: "true"}},
+		}},
+		Containers: []corev1.Container{{
+			Name:  "nvidia-driver-ctr",
+			Image: "nvcr.io/nvidia/driver:580.65.06",
+		}},
+	}
+	expected := DriverInstallStateFromPodSpec(spec)
+
+	withDigest := spec.DeepCopy()
+	withDigest.InitContainers[0].Env = append(withDigest.InitContainers[0].Env, corev1.EnvVar{Name: DriverConfigDigestEnvName, Value: "123"})
+	withDigest.Containers[0].Env = append(withDigest.Containers[0].Env, corev1.EnvVar{Name: DriverConfigDigestEnvName, Value: "123"})
+
+	assert.Equal(t, expected, DriverInstallStateFromPodSpec(withDigest))
+	assert.Equal(t, []EnvVar{{Name: "ENABLE_GPU_POD_EVICTION", Value: "true"}}, expected.ManagerEnv)
+}
@@ -46,8 +46,15 @@ const (
 	OcpDriverToolkitIdentificationLabel = "openshift.driver-toolkit"
 	NfdOSTreeVersionLabelKey            = "feature.node.kubernetes.io/system-os_release.OSTREE_VERSION"
 
+	// FieldManager is the field manager of the fields the operator owns in the objects
+	// it applies
+	FieldManager = "gpu-operator"
+
 	// NvidiaAnnotationHashKey indicates annotation name for last applied hash by gpu-operator
 	NvidiaAnnotationHashKey = "nvidia.com/last-applied-hash"
+	// ReconcilePausedAnnotation pauses the reconciliation of a custom resource when set to
+	// "true", or of the states it lists when set to a comma separated list of state names
+	ReconcilePausedAnnotation = "nvidia.com/reconcile-paused"
 
 	// VGPULicensingConfigMountPath indicates target mount path for vGPU licensing configuration file
 	VGPULicensingConfigMountPath = "/drivers/gridd.conf"
@@ -69,6 +76,9 @@ const (
 	DefaultNVIDIADriverName = "default"
 	// NVIDIADriverOwnerLabel is an operator-managed node label used to route each GPU node to one NVIDIADriver.
 	NVIDIADriverOwnerLabel = "nvidia.com/gpu-operator.driver.owner"
+	// GPUUnhealthyTaintKey is the key of the NoSchedule taint of the GPU nodes found unhealthy. The
+	// operands tolerate it so they can be restarted on the nodes to remediate them.
+	GPUUnhealthyTaintKey = "nvidia.com/gpu.unhealthy"
 
 	// MinimumGDSVersionForOpenRM indicates the minimum GDS version that is supported only with OpenRM driver
 	MinimumGDSVersionForOpenRM = "v2.17.5"
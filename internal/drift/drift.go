@@ -0,0 +1,255 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+// Package drift detects operator managed objects that were edited out of band, by
+// comparing the live objects against the objects rendered from the custom resources.
+package drift
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/NVIDIA/gpu-operator/internal/consts"
+	"github.com/NVIDIA/gpu-operator/internal/fieldpath"
+)
+
+// Policy defines what happens to drifted objects
+type Policy string
+
+const (
+	// PolicyReport only reports drifted objects
+	PolicyReport Policy = "report"
+	// PolicyCorrect reports drifted objects and restores their desired state
+	PolicyCorrect Policy = "correct"
+)
+
+// ParsePolicy returns the Policy named by s
+func ParsePolicy(s string) (Policy, error) {
+	switch p := Policy(s); p {
+	case PolicyReport, PolicyCorrect:
+		return p, nil
+	}
+	return "", fmt.Errorf("invalid drift policy %q, must be one of %q or %q", s, PolicyReport, PolicyCorrect)
+}
+
+// supportedKinds are the kinds checked for drift. ServiceAccounts are left out as
+// the cluster adds its own secrets to them.
+var supportedKinds = map[schema.GroupKind]bool{
+	appsv1.SchemeGroupVersion.WithKind("DaemonSet").GroupKind():          true,
+	corev1.SchemeGroupVersion.WithKind("ConfigMap").GroupKind():          true,
+	rbacv1.SchemeGroupVersion.WithKind("Role").GroupKind():               true,
+	rbacv1.SchemeGroupVersion.WithKind("RoleBinding").GroupKind():        true,
+	rbacv1.SchemeGroupVersion.WithKind("ClusterRole").GroupKind():        true,
+	rbacv1.SchemeGroupVersion.WithKind("ClusterRoleBinding").GroupKind(): true,
+}
+
+// ignoredFields are the top level fields that are not compared. The metadata is
+// compared through its labels and annotations only.
+var ignoredFields = map[string]bool{
+	"apiVersion": true,
+	"kind":       true,
+	"metadata":   true,
+	"status":     true,
+}
+
+// IsSupported returns whether obj is of a kind checked for drift
+func IsSupported(obj *unstructured.Unstructured) bool {
+	return supportedKinds[obj.GroupVersionKind().GroupKind()]
+}
+
+// Detect returns the paths of the fields of desired whose value differs in live, or
+// nil if live has not drifted. Fields only set in live, such as the ones defaulted by
+// the API server, are not compared, except for the elements of named lists: a
+// container or an environment variable added to live is drift.
+//
+// An object whose hash annotation differs from the desired one has not been updated
+// by its controller yet, rather than having drifted, and is reported as not drifted.
+func Detect(live, desired *unstructured.Unstructured) []string {
+	desiredHash, ok := desired.GetAnnotations()[consts.NvidiaAnnotationHashKey]
+	if ok && live.GetAnnotations()[consts.NvidiaAnnotationHashKey] != desiredHash {
+		return nil
+	}
+
+	var paths []string
+	compareStringMaps("metadata.labels", live.GetLabels(), desired.GetLabels(), &paths)
+	compareStringMaps("metadata.annotations", live.GetAnnotations(), desired.GetAnnotations(), &paths)
+	for _, key := range fieldpath.SortedKeys(desired.Object) {
+		if ignoredFields[key] {
+			continue
+		}
+		compareValues(key, live.Object[key], desired.Object[key], &paths)
+	}
+	return paths
+}
+
+func compareStringMaps(path string, live, desired map[string]string, paths *[]string) {
+	keys := make([]string, 0, len(desired))
+	for key := range desired {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if key == consts.NvidiaAnnotationHashKey {
+			continue
+		}
+		if value, ok := live[key]; !ok || value != desired[key] {
+			*paths = append(*paths, fieldpath.Join(path, key))
+		}
+	}
+}
+
+// compareValues appends path to paths if the live value does not match the desired one
+func compareValues(path string, live, desired interface{}, paths *[]string) {
+	if isZero(desired) {
+		if _, ok := desired.([]interface{}); ok && !isZero(live) {
+			*paths = append(*paths, path)
+		}
+		return
+	}
+
+	switch desiredValue := desired.(type) {
+	case map[string]interface{}:
+		liveValue, ok := live.(map[string]interface{})
+		if !ok {
+			*paths = append(*paths, path)
+			return
+		}
+		for _, key := range fieldpath.SortedKeys(desiredValue) {
+			compareValues(fieldpath.Join(path, key), liveValue[key], desiredValue[key], paths)
+		}
+	case []interface{}:
+		liveValue, ok := live.([]interface{})
+		if !ok {
+			*paths = append(*paths, path)
+			return
+		}
+		compareLists(path, liveValue, desiredValue, paths)
+	default:
+		if !equalScalars(live, desired) {
+			*paths = append(*paths, path)
+		}
+	}
+}
+
+// compareLists compares lists whose elements all carry a unique name, such as
+// containers, env or volumes, by name and any other list by position
+func compareLists(path string, live, desired []interface{}, paths *[]string) {
+	desiredNames, desiredNamed := fieldpath.ElementNames(desired)
+	liveNames, liveNamed := fieldpath.ElementNames(live)
+	if desiredNamed && liveNamed {
+		liveByName := map[string]interface{}{}
+		for i, name := range liveNames {
+			liveByName[name] = live[i]
+		}
+		desiredByName := map[string]bool{}
+		for i, name := range desiredNames {
+			desiredByName[name] = true
+			elemPath := fieldpath.Element(path, name)
+			liveElem, ok := liveByName[name]
+			if !ok {
+				*paths = append(*paths, elemPath)
+				continue
+			}
+			compareValues(elemPath, liveElem, desired[i], paths)
+		}
+		for _, name := range liveNames {
+			if !desiredByName[name] {
+				*paths = append(*paths, fieldpath.Element(path, name))
+			}
+		}
+		return
+	}
+
+	if len(live) != len(desired) {
+		*paths = append(*paths, path)
+		return
+	}
+	for i := range desired {
+		compareValues(fieldpath.Index(path, i), live[i], desired[i], paths)
+	}
+}
+
+// equalScalars compares two scalar values, treating numbers of different types and
+// equivalent resource quantities, such as 1000m and 1, as equal
+func equalScalars(live, desired interface{}) bool {
+	if reflect.DeepEqual(live, desired) {
+		return true
+	}
+	liveNumber, liveIsNumber := toFloat(live)
+	desiredNumber, desiredIsNumber := toFloat(desired)
+	if liveIsNumber && desiredIsNumber {
+		return liveNumber == desiredNumber
+	}
+
+	liveString, liveIsString := live.(string)
+	desiredString, desiredIsString := desired.(string)
+	if liveIsString && desiredIsString {
+		liveQuantity, err := resource.ParseQuantity(liveString)
+		if err != nil {
+			return false
+		}
+		desiredQuantity, err := resource.ParseQuantity(desiredString)
+		if err != nil {
+			return false
+		}
+		return liveQuantity.Cmp(desiredQuantity) == 0
+	}
+	return false
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// isZero returns whether value is unset, which the API server does not distinguish
+// from an empty value
+func isZero(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return len(v) == 0
+	case []interface{}:
+		return len(v) == 0
+	case string:
+		return v == ""
+	case bool:
+		return !v
+	}
+	number, ok := toFloat(value)
+	return ok && number == 0
+}
@@ -0,0 +1,169 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package drift
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+
+	"github.com/NVIDIA/gpu-operator/internal/consts"
+	"github.com/NVIDIA/gpu-operator/internal/utils"
+)
+
+func newDaemonSet() *appsv1.DaemonSet {
+	return &appsv1.DaemonSet{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "DaemonSet"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "nvidia-dcgm-exporter",
+			Namespace:   "gpu-operator",
+			Labels:      map[string]string{"app": "nvidia-dcgm-exporter"},
+			Annotations: map[string]string{consts.NvidiaAnnotationHashKey: "1234"},
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:  "nvidia-dcgm-exporter",
+						Image: "nvcr.io/nvidia/k8s/dcgm-exporter:4.2.3",
+						Env:   []corev1.EnvVar{{Name: "DCGM_EXPORTER_LISTEN", Value: ":9400"}},
+						Resources: corev1.ResourceRequirements{
+							Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+						},
+					}},
+				},
+			},
+		},
+	}
+}
+
+// detect returns the fields of desired drifted in live
+func detect(t *testing.T, live, desired runtime.Object) []string {
+	t.Helper()
+	liveObj, err := utils.ToUnstructured(live)
+	require.NoError(t, err)
+	desiredObj, err := utils.ToUnstructured(desired)
+	require.NoError(t, err)
+	return Detect(liveObj, desiredObj)
+}
+
+func TestParsePolicy(t *testing.T) {
+	policy, err := ParsePolicy("report")
+	require.NoError(t, err)
+	require.Equal(t, PolicyReport, policy)
+
+	policy, err = ParsePolicy("correct")
+	require.NoError(t, err)
+	require.Equal(t, PolicyCorrect, policy)
+
+	_, err = ParsePolicy("ignore")
+	require.Error(t, err)
+}
+
+func TestIsSupported(t *testing.T) {
+	testCases := []struct {
+		obj       runtime.Object
+		supported bool
+	}{
+		{obj: newDaemonSet(), supported: true},
+		{obj: &corev1.ConfigMap{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"}}, supported: true},
+		{obj: &corev1.ServiceAccount{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ServiceAccount"}}, supported: false},
+	}
+	for _, tc := range testCases {
+		obj, err := utils.ToUnstructured(tc.obj)
+		require.NoError(t, err)
+		require.Equal(t, tc.supported, IsSupported(obj), obj.GetKind())
+	}
+}
+
+func TestDetect(t *testing.T) {
+	testCases := []struct {
+		description string
+		edit        func(ds *appsv1.DaemonSet)
+		expected    []string
+	}{
+		{
+			description: "unchanged",
+			edit:        func(ds *appsv1.DaemonSet) {},
+		},
+		{
+			description: "fields defaulted by the API server",
+			edit: func(ds *appsv1.DaemonSet) {
+				ds.Spec.RevisionHistoryLimit = ptr.To[int32](10)
+				ds.Spec.UpdateStrategy.Type = appsv1.RollingUpdateDaemonSetStrategyType
+				ds.Spec.Template.Spec.Containers[0].ImagePullPolicy = corev1.PullIfNotPresent
+				ds.Spec.Template.Spec.Containers[0].Resources.Limits[corev1.ResourceMemory] = resource.MustParse("1024Mi")
+				ds.Labels["extra"] = "label"
+			},
+		},
+		{
+			description: "image edited",
+			edit: func(ds *appsv1.DaemonSet) {
+				ds.Spec.Template.Spec.Containers[0].Image = "nvcr.io/nvidia/k8s/dcgm-exporter:hotfix"
+			},
+			expected: []string{"spec.template.spec.containers[nvidia-dcgm-exporter].image"},
+		},
+		{
+			description: "env var added and label removed",
+			edit: func(ds *appsv1.DaemonSet) {
+				ds.Spec.Template.Spec.Containers[0].Env = append(ds.Spec.Template.Spec.Containers[0].Env,
+					corev1.EnvVar{Name: "DCGM_EXPORTER_DEBUG", Value: "true"})
+				delete(ds.Labels, "app")
+			},
+			expected: []string{
+				"metadata.labels.app",
+				"spec.template.spec.containers[nvidia-dcgm-exporter].env[DCGM_EXPORTER_DEBUG]",
+			},
+		},
+		{
+			description: "pending update of the owner",
+			edit: func(ds *appsv1.DaemonSet) {
+				ds.Annotations[consts.NvidiaAnnotationHashKey] = "5678"
+				ds.Spec.Template.Spec.Containers[0].Image = "nvcr.io/nvidia/k8s/dcgm-exporter:4.2.2"
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			desired := newDaemonSet()
+			live := newDaemonSet()
+			tc.edit(live)
+
+			require.Equal(t, tc.expected, detect(t, live, desired))
+		})
+	}
+}
+
+func TestDetectConfigMap(t *testing.T) {
+	desired := &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{Name: "default-mig-parted-config", Namespace: "gpu-operator"},
+		Data:       map[string]string{"config.yaml": "version: v1"},
+	}
+	live := desired.DeepCopy()
+	live.Data["config.yaml"] = "version: v2"
+	live.Data["extra.yaml"] = "{}"
+
+	require.Equal(t, []string{`data["config.yaml"]`}, detect(t, live, desired))
+}
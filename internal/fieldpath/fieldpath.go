@@ -0,0 +1,86 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+// Package fieldpath helps walking the fields of unstructured objects and naming them
+// with paths such as spec.template.spec.containers[driver].image.
+package fieldpath
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+var identifierRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Join returns the path of the field key of the object found at path. Keys that are
+// not identifiers, such as label names, are quoted.
+func Join(path string, key string) string {
+	if !identifierRegexp.MatchString(key) {
+		return fmt.Sprintf("%s[%q]", path, key)
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// Element returns the path of the element named name of the list found at path
+func Element(path string, name string) string {
+	return fmt.Sprintf("%s[%s]", path, name)
+}
+
+// Index returns the path of the element at index i of the list found at path
+func Index(path string, i int) string {
+	return fmt.Sprintf("%s[%d]", path, i)
+}
+
+// SortedKeys returns the keys of all maps, sorted and without duplicates
+func SortedKeys(maps ...map[string]interface{}) []string {
+	keySet := map[string]bool{}
+	for _, m := range maps {
+		for key := range m {
+			keySet[key] = true
+		}
+	}
+	keys := make([]string, 0, len(keySet))
+	for key := range keySet {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ElementNames returns the names of the elements of list, if every element is an
+// object with a unique, non-empty name, such as containers, env or volumes. Such
+// lists are compared by name rather than by position. An empty list is named.
+func ElementNames(list []interface{}) ([]string, bool) {
+	names := make([]string, 0, len(list))
+	seen := map[string]bool{}
+	for _, elem := range list {
+		m, ok := elem.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		name, ok := m["name"].(string)
+		if !ok || name == "" || seen[name] {
+			return nil, false
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names, true
+}
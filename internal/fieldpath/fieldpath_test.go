@@ -0,0 +1,80 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package fieldpath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaths(t *testing.T) {
+	require.Equal(t, "spec", Join("", "spec"))
+	require.Equal(t, "spec.template", Join("spec", "template"))
+	require.Equal(t, `metadata.labels["app.kubernetes.io/name"]`, Join("metadata.labels", "app.kubernetes.io/name"))
+	require.Equal(t, "spec.containers[driver]", Element("spec.containers", "driver"))
+	require.Equal(t, "spec.args[1]", Index("spec.args", 1))
+}
+
+func TestSortedKeys(t *testing.T) {
+	require.Equal(t, []string{"a", "b", "c"}, SortedKeys(
+		map[string]interface{}{"c": 1, "a": 2},
+		map[string]interface{}{"b": 3, "a": 4},
+	))
+	require.Empty(t, SortedKeys())
+}
+
+func TestElementNames(t *testing.T) {
+	testCases := []struct {
+		description   string
+		list          []interface{}
+		expectedNames []string
+		expectedNamed bool
+	}{
+		{
+			description:   "empty list",
+			list:          []interface{}{},
+			expectedNames: []string{},
+			expectedNamed: true,
+		},
+		{
+			description:   "named elements",
+			list:          []interface{}{map[string]interface{}{"name": "b"}, map[string]interface{}{"name": "a"}},
+			expectedNames: []string{"b", "a"},
+			expectedNamed: true,
+		},
+		{
+			description: "duplicate names",
+			list:        []interface{}{map[string]interface{}{"name": "a"}, map[string]interface{}{"name": "a"}},
+		},
+		{
+			description: "unnamed element",
+			list:        []interface{}{map[string]interface{}{"name": "a"}, map[string]interface{}{"key": "b"}},
+		},
+		{
+			description: "scalar elements",
+			list:        []interface{}{"a", "b"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			names, named := ElementNames(tc.list)
+			require.Equal(t, tc.expectedNamed, named)
+			require.Equal(t, tc.expectedNames, names)
+		})
+	}
+}
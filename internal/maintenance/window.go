@@ -0,0 +1,101 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+// Package maintenance computes recurring maintenance windows, given as a cron schedule
+// of their start, a duration and a time zone.
+package maintenance
+
+import (
+	"fmt"
+	"time"
+
+	// the operator image does not ship the time zone database
+	_ "time/tzdata"
+)
+
+// Window is a recurring maintenance window
+type Window struct {
+	schedule *Schedule
+	duration time.Duration
+	location *time.Location
+}
+
+// Occurrence is a single occurrence of a maintenance window
+type Occurrence struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Contains returns whether t is within o
+func (o Occurrence) Contains(t time.Time) bool {
+	return !t.Before(o.Start) && t.Before(o.End)
+}
+
+// NewWindow returns the window starting at the times matching the cron schedule, in
+// the IANA time zone timeZone, and staying open for duration. An empty time zone
+// stands for UTC.
+func NewWindow(schedule string, duration time.Duration, timeZone string) (*Window, error) {
+	s, err := ParseSchedule(schedule)
+	if err != nil {
+		return nil, err
+	}
+	if duration <= 0 {
+		return nil, fmt.Errorf("invalid duration %s of the window starting at %q, must be positive", duration, schedule)
+	}
+	location, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time zone %q: %w", timeZone, err)
+	}
+	return &Window{schedule: s, duration: duration, location: location}, nil
+}
+
+// Next returns the occurrence of w open at t or, if there is none, the first one
+// starting after t. It returns false if w never starts.
+func (w *Window) Next(t time.Time) (Occurrence, bool) {
+	start := w.schedule.Next(t.In(w.location).Add(-w.duration))
+	if start.IsZero() {
+		return Occurrence{}, false
+	}
+	return Occurrence{Start: start, End: start.Add(w.duration)}, true
+}
+
+// Next returns the occurrence of windows open at t, the one ending last if several
+// are, or else the first one starting after t. It returns false if no window ever
+// starts.
+func Next(windows []*Window, t time.Time) (Occurrence, bool) {
+	var next Occurrence
+	found := false
+	for _, w := range windows {
+		o, ok := w.Next(t)
+		if !ok {
+			continue
+		}
+		switch {
+		case !found:
+			next = o
+		case o.Contains(t) && next.Contains(t):
+			if o.End.After(next.End) {
+				next = o
+			}
+		case o.Contains(t):
+			next = o
+		case !next.Contains(t) && o.Start.Before(next.Start):
+			next = o
+		}
+		found = true
+	}
+	return next, found
+}
@@ -0,0 +1,120 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package maintenance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func mustParseTime(t *testing.T, value string) time.Time {
+	t.Helper()
+	parsed, err := time.Parse(time.RFC3339, value)
+	require.NoError(t, err)
+	return parsed
+}
+
+func TestParseSchedule(t *testing.T) {
+	for _, spec := range []string{"* * * * *", "0 2 * * sat", "*/15 1-5 1,15 jan-jun 1-5", "30 22 * * 7", "0 0/6 * * *"} {
+		_, err := ParseSchedule(spec)
+		require.NoError(t, err, spec)
+	}
+
+	for _, spec := range []string{"", "0 2 * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "5-1 * * * *", "*/0 * * * *", "@daily"} {
+		_, err := ParseSchedule(spec)
+		require.Error(t, err, spec)
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	testCases := []struct {
+		spec     string
+		from     string
+		expected string
+	}{
+		{spec: "0 2 * * *", from: "2026-03-10T01:00:00Z", expected: "2026-03-10T02:00:00Z"},
+		{spec: "0 2 * * *", from: "2026-03-10T02:00:00Z", expected: "2026-03-11T02:00:00Z"},
+		{spec: "*/15 * * * *", from: "2026-03-10T02:07:30Z", expected: "2026-03-10T02:15:00Z"},
+		{spec: "0 2 * * sat", from: "2026-03-10T00:00:00Z", expected: "2026-03-14T02:00:00Z"},
+		{spec: "0 0 31 * *", from: "2026-04-01T00:00:00Z", expected: "2026-05-31T00:00:00Z"},
+		// day of month and day of week both restricted match either of them
+		{spec: "0 0 1 * mon", from: "2026-03-10T00:00:00Z", expected: "2026-03-16T00:00:00Z"},
+		{spec: "0 0 29 2 *", from: "2026-03-01T00:00:00Z", expected: "2028-02-29T00:00:00Z"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.spec+" from "+tc.from, func(t *testing.T) {
+			s, err := ParseSchedule(tc.spec)
+			require.NoError(t, err)
+			require.Equal(t, mustParseTime(t, tc.expected), s.Next(mustParseTime(t, tc.from)).UTC())
+		})
+	}
+
+	s, err := ParseSchedule("0 0 30 2 *")
+	require.NoError(t, err)
+	require.True(t, s.Next(mustParseTime(t, "2026-03-01T00:00:00Z")).IsZero())
+}
+
+func TestWindowNext(t *testing.T) {
+	w, err := NewWindow("0 2 * * sat", 4*time.Hour, "Europe/Berlin")
+	require.NoError(t, err)
+
+	// Saturday 2026-03-14 02:00 in Berlin is 01:00 UTC
+	open, ok := w.Next(mustParseTime(t, "2026-03-14T03:00:00Z"))
+	require.True(t, ok)
+	require.Equal(t, mustParseTime(t, "2026-03-14T01:00:00Z"), open.Start.UTC())
+	require.Equal(t, mustParseTime(t, "2026-03-14T05:00:00Z"), open.End.UTC())
+	require.True(t, open.Contains(mustParseTime(t, "2026-03-14T03:00:00Z")))
+
+	next, ok := w.Next(mustParseTime(t, "2026-03-14T05:00:00Z"))
+	require.True(t, ok)
+	require.Equal(t, mustParseTime(t, "2026-03-21T01:00:00Z"), next.Start.UTC())
+	require.False(t, next.Contains(mustParseTime(t, "2026-03-14T05:00:00Z")))
+
+	_, err = NewWindow("0 2 * * sat", 0, "")
+	require.Error(t, err)
+	_, err = NewWindow("0 2 * * sat", time.Hour, "Mars/Olympus_Mons")
+	require.Error(t, err)
+}
+
+func TestNext(t *testing.T) {
+	nightly, err := NewWindow("0 22 * * *", 2*time.Hour, "")
+	require.NoError(t, err)
+	weekend, err := NewWindow("0 20 * * sat", 12*time.Hour, "")
+	require.NoError(t, err)
+	windows := []*Window{nightly, weekend}
+
+	// the first window to start
+	next, ok := Next(windows, mustParseTime(t, "2026-03-10T12:00:00Z"))
+	require.True(t, ok)
+	require.Equal(t, mustParseTime(t, "2026-03-10T22:00:00Z"), next.Start.UTC())
+
+	// an open window wins over one starting later
+	next, ok = Next(windows, mustParseTime(t, "2026-03-14T21:00:00Z"))
+	require.True(t, ok)
+	require.Equal(t, mustParseTime(t, "2026-03-14T20:00:00Z"), next.Start.UTC())
+
+	// of the open windows, the one ending last wins
+	next, ok = Next(windows, mustParseTime(t, "2026-03-14T23:00:00Z"))
+	require.True(t, ok)
+	require.Equal(t, mustParseTime(t, "2026-03-15T08:00:00Z"), next.End.UTC())
+
+	_, ok = Next(nil, mustParseTime(t, "2026-03-10T12:00:00Z"))
+	require.False(t, ok)
+}
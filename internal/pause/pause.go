@@ -0,0 +1,93 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+// Package pause implements the reconcile-paused annotation, which freezes the
+// reconciliation of a custom resource, or of some of its components, while an operand
+// is hand-patched during an incident.
+package pause
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/NVIDIA/gpu-operator/internal/consts"
+)
+
+// Components returns the components whose reconciliation is paused by the
+// annotation on obj. all is true when every component is paused, that is when the
+// annotation is "true". Otherwise the annotation is a comma separated list of state
+// names, e.g. "state-dcgm-exporter,state-device-plugin".
+func Components(obj metav1.Object) (all bool, components []string) {
+	value := strings.TrimSpace(obj.GetAnnotations()[consts.ReconcilePausedAnnotation])
+	switch strings.ToLower(value) {
+	case "", "false":
+		return false, nil
+	case "true":
+		return true, nil
+	}
+	for _, component := range strings.Split(value, ",") {
+		if component = strings.TrimSpace(component); component != "" && !slices.Contains(components, component) {
+			components = append(components, component)
+		}
+	}
+	return false, components
+}
+
+// IsPaused returns whether the reconciliation of component is paused by the
+// annotation on obj
+func IsPaused(obj metav1.Object, component string) bool {
+	all, components := Components(obj)
+	return all || slices.Contains(components, component)
+}
+
+// IsAnyPaused returns whether the annotation on obj pauses any component
+func IsAnyPaused(obj metav1.Object) bool {
+	all, components := Components(obj)
+	return all || len(components) > 0
+}
+
+// Message describes what the annotation on obj pauses, or returns "" if nothing is
+// paused
+func Message(obj metav1.Object) string {
+	all, components := Components(obj)
+	switch {
+	case all:
+		return fmt.Sprintf("Reconciliation is paused by the %s annotation", consts.ReconcilePausedAnnotation)
+	case len(components) > 0:
+		return fmt.Sprintf("Reconciliation of %s is paused by the %s annotation", strings.Join(components, ", "), consts.ReconcilePausedAnnotation)
+	}
+	return ""
+}
+
+// AnnotationChangedPredicate triggers a reconciliation when the reconcile-paused
+// annotation changes, which does not bump the generation of the object
+func AnnotationChangedPredicate[T client.Object]() predicate.TypedPredicate[T] {
+	return predicate.TypedFuncs[T]{
+		CreateFunc:  func(event.TypedCreateEvent[T]) bool { return false },
+		DeleteFunc:  func(event.TypedDeleteEvent[T]) bool { return false },
+		GenericFunc: func(event.TypedGenericEvent[T]) bool { return false },
+		UpdateFunc: func(e event.TypedUpdateEvent[T]) bool {
+			return e.ObjectOld.GetAnnotations()[consts.ReconcilePausedAnnotation] !=
+				e.ObjectNew.GetAnnotations()[consts.ReconcilePausedAnnotation]
+		},
+	}
+}
@@ -0,0 +1,114 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package pause
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/NVIDIA/gpu-operator/internal/consts"
+)
+
+func objectWithAnnotation(value *string) *corev1.ConfigMap {
+	obj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test"}}
+	if value != nil {
+		obj.Annotations = map[string]string{consts.ReconcilePausedAnnotation: *value}
+	}
+	return obj
+}
+
+func ptr(s string) *string {
+	return &s
+}
+
+func TestComponents(t *testing.T) {
+	testCases := []struct {
+		description        string
+		annotation         *string
+		expectedAll        bool
+		expectedComponents []string
+		expectedMessage    string
+	}{
+		{
+			description: "no annotation",
+		},
+		{
+			description: "empty annotation",
+			annotation:  ptr(""),
+		},
+		{
+			description: "false",
+			annotation:  ptr("False"),
+		},
+		{
+			description:     "true",
+			annotation:      ptr(" true "),
+			expectedAll:     true,
+			expectedMessage: "Reconciliation is paused by the nvidia.com/reconcile-paused annotation",
+		},
+		{
+			description:        "component list",
+			annotation:         ptr("state-dcgm-exporter, state-device-plugin,,state-dcgm-exporter"),
+			expectedComponents: []string{"state-dcgm-exporter", "state-device-plugin"},
+			expectedMessage:    "Reconciliation of state-dcgm-exporter, state-device-plugin is paused by the nvidia.com/reconcile-paused annotation",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			obj := objectWithAnnotation(tc.annotation)
+			all, components := Components(obj)
+			require.Equal(t, tc.expectedAll, all)
+			require.Equal(t, tc.expectedComponents, components)
+			require.Equal(t, tc.expectedMessage, Message(obj))
+			require.Equal(t, tc.expectedAll || len(tc.expectedComponents) > 0, IsAnyPaused(obj))
+		})
+	}
+}
+
+func TestIsPaused(t *testing.T) {
+	require.True(t, IsPaused(objectWithAnnotation(ptr("true")), "state-driver"))
+	require.True(t, IsPaused(objectWithAnnotation(ptr("state-dcgm-exporter,state-driver")), "state-driver"))
+	require.False(t, IsPaused(objectWithAnnotation(ptr("state-dcgm-exporter")), "state-driver"))
+	require.False(t, IsPaused(objectWithAnnotation(nil), "state-driver"))
+}
+
+func TestAnnotationChangedPredicate(t *testing.T) {
+	p := AnnotationChangedPredicate[*corev1.ConfigMap]()
+
+	require.False(t, p.Create(event.TypedCreateEvent[*corev1.ConfigMap]{Object: objectWithAnnotation(ptr("true"))}))
+	require.False(t, p.Delete(event.TypedDeleteEvent[*corev1.ConfigMap]{Object: objectWithAnnotation(ptr("true"))}))
+	require.True(t, p.Update(event.TypedUpdateEvent[*corev1.ConfigMap]{
+		ObjectOld: objectWithAnnotation(nil),
+		ObjectNew: objectWithAnnotation(ptr("true")),
+	}))
+	require.True(t, p.Update(event.TypedUpdateEvent[*corev1.ConfigMap]{
+		ObjectOld: objectWithAnnotation(ptr("state-driver")),
+		ObjectNew: objectWithAnnotation(nil),
+	}))
+
+	unchanged := objectWithAnnotation(ptr("true"))
+	unchanged.Labels = map[string]string{"foo": "bar"}
+	require.False(t, p.Update(event.TypedUpdateEvent[*corev1.ConfigMap]{
+		ObjectOld: objectWithAnnotation(ptr("true")),
+		ObjectNew: unchanged,
+	}))
+}
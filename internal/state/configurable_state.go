@@ -65,7 +65,7 @@ func (s *configurableState) Sync(ctx context.Context, customResource interface{}
 	}
 
 	if len(objs) == 0 {
-		return s.handleStateObjectsDeletion(ctx)
+		return s.handleStateObjectsDeletion(ctx, cr)
 	}
 
 	return s.syncObjects(ctx, cr, objs)
@@ -75,6 +75,19 @@ func (s *configurableState) GetWatchSources(mgr ctrlManager) map[string]SyncingS
 	return gpuClusterDaemonSetSource(mgr)
 }
 
+func (s *configurableState) renderDesiredObjects(ctx context.Context, customResource interface{}, infoCatalog InfoCatalog) ([]*unstructured.Unstructured, error) {
+	cr, ok := customResource.(*nvidiav1alpha1.GPUCluster)
+	if !ok {
+		return nil, fmt.Errorf("GPUCluster CR not provided as input to renderDesiredObjects()")
+	}
+
+	objs, err := s.getManifestObjects(ctx, cr, infoCatalog)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create k8s objects from manifests: %w", err)
+	}
+	return objs, s.prepareObjs(cr, objs)
+}
+
 func (s *configurableState) getManifestObjects(ctx context.Context, cr *nvidiav1alpha1.GPUCluster, infoCatalog InfoCatalog) ([]*unstructured.Unstructured, error) {
 	if !s.isEnabled(cr) {
 		return []*unstructured.Unstructured{}, nil
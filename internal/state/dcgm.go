@@ -55,7 +55,7 @@ func NewStateDCGM(
 }
 
 func buildDCGMRenderData(_ context.Context, s *configurableState, cr *nvidiav1alpha1.GPUCluster, imagePath, apiVersion, openshiftVersion string) (interface{}, error) {
-	daemonsets := cr.Spec.Daemonsets
+	daemonsets := gpuClusterDaemonsets(cr)
 	return &dcgmRenderData{
 		DCGM:                    &dcgmSpec{Spec: cr.Spec.DCGM, ImagePath: imagePath},
 		Daemonsets:              &daemonsets,
@@ -26,6 +26,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
+	nvidiav1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1"
 	nvidiav1alpha1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1alpha1"
 	"github.com/NVIDIA/gpu-operator/internal/consts"
 )
@@ -41,7 +42,7 @@ const (
 
 	dcgmExporterDefaultCollectors     = "/etc/dcgm-exporter/dcp-metrics-included.csv"
 	dcgmExporterCustomCollectors      = "/etc/dcgm-exporter/dcgm-metrics.csv"
-	dcgmExporterDefaultKubeletRootDir = "/var/lib/kubelet"
+	dcgmExporterDefaultKubeletRootDir = nvidiav1.DefaultKubeletRootDir
 	dcgmExporterDefaultJobMappingDir  = "/var/lib/dcgm-exporter/job-mapping"
 )
 
@@ -120,7 +121,7 @@ func buildDCGMExporterRenderData(ctx context.Context, s *configurableState, cr *
 		}
 	}
 
-	daemonsets := cr.Spec.Daemonsets
+	daemonsets := gpuClusterDaemonsets(cr)
 	return &dcgmExporterRenderData{
 		DCGMExporter:                 &dcgmExporterSpec{Spec: spec, ImagePath: imagePath},
 		Daemonsets:                   &daemonsets,
@@ -40,8 +40,8 @@ const (
 
 	// Default gRPC health service ports of the kubelet-plugin containers, matching the
 	// upstream k8s-dra-driver-gpu Helm chart.
-	defaultGPUsHealthcheckPort           = int32(51516)
-	defaultComputeDomainsHealthcheckPort = int32(51515)
+	defaultGPUsHealthcheckPort           = nvidiav1alpha1.DefaultGPUsHealthcheckPort
+	defaultComputeDomainsHealthcheckPort = nvidiav1alpha1.DefaultComputeDomainsHealthcheckPort
 )
 
 // resolveHealthcheckPort returns the spec-provided health service port, or the
@@ -100,6 +100,19 @@ func (s *stateDRADriver) GetWatchSources(mgr ctrlManager) map[string]SyncingSour
 	return gpuClusterDaemonSetSource(mgr)
 }
 
+func (s *stateDRADriver) renderDesiredObjects(ctx context.Context, customResource interface{}, infoCatalog InfoCatalog) ([]*unstructured.Unstructured, error) {
+	cr, ok := customResource.(*nvidiav1alpha1.GPUCluster)
+	if !ok {
+		return nil, fmt.Errorf("GPUCluster CR not provided as input to renderDesiredObjects()")
+	}
+
+	objs, err := s.getManifestObjects(ctx, cr, infoCatalog)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create k8s objects from manifests: %w", err)
+	}
+	return objs, s.prepareObjs(cr, objs)
+}
+
 func (s *stateDRADriver) getManifestObjects(ctx context.Context, cr *nvidiav1alpha1.GPUCluster, infoCatalog InfoCatalog) ([]*unstructured.Unstructured, error) {
 	apiVersion, err := draResourceAPIVersion(infoCatalog)
 	if err != nil {
@@ -112,7 +125,7 @@ func (s *stateDRADriver) getManifestObjects(ctx context.Context, cr *nvidiav1alp
 	}
 
 	hostPaths := cr.Spec.HostPaths
-	daemonsets := cr.Spec.Daemonsets
+	daemonsets := gpuClusterDaemonsets(cr)
 	openshiftVersion, err := clusterOpenshiftVersion(infoCatalog)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get OpenShift version: %w", err)
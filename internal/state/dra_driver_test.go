@@ -518,3 +518,21 @@ func TestDRADriverKubeletPluginScheduling(t *testing.T) {
 	assert.Equal(t, "true", ds.Spec.Template.Spec.NodeSelector["nvidia.com/gpu.deploy.dra-driver"])
 	assert.NotContains(t, ds.Spec.Template.Spec.NodeSelector, "nvidia.com/gpu-operator.resource-allocation.mode")
 }
+
+func TestDRADriverToleratesUnhealthyGPUNodes(t *testing.T) {
+	s := newTestDRAState(t)
+	cr := sampleGPUCluster()
+	cr.Spec.Daemonsets.Tolerations = []corev1.Toleration{{Key: "ds-tol", Operator: corev1.TolerationOpExists}}
+
+	objs, err := s.getManifestObjects(context.Background(), cr, draSupportedCatalog())
+	require.NoError(t, err)
+	assert.NotContains(t, tolKeys(findDaemonSet(t, objs).Spec.Template.Spec.Tolerations), "nvidia.com/gpu.unhealthy")
+
+	// the kubelet-plugin must be restarted on the tainted nodes to remediate them
+	cr.Spec.GPUHealth = &nvidiav1.GPUHealthSpec{Enabled: ptr.To(true)}
+	objs, err = s.getManifestObjects(context.Background(), cr, draSupportedCatalog())
+	require.NoError(t, err)
+	assert.Subset(t, tolKeys(findDaemonSet(t, objs).Spec.Template.Spec.Tolerations), []string{"ds-tol", "nvidia.com/gpu.unhealthy"})
+	// the tolerations of the custom resource are left as is
+	assert.Len(t, cr.Spec.Daemonsets.Tolerations, 1)
+}
@@ -53,7 +53,7 @@ func NewStateDRAValidation(
 func buildValidatorRenderData(_ context.Context, s *configurableState, cr *nvidiav1alpha1.GPUCluster, imagePath, apiVersion, openshiftVersion string) (interface{}, error) {
 	// Reuse the DRA driver spec for the image pull settings.
 	spec := cr.Spec.DRADriver
-	daemonsets := cr.Spec.Daemonsets
+	daemonsets := gpuClusterDaemonsets(cr)
 	return &validatorRenderData{
 		Validator:               &draDriverSpec{Spec: &spec, ImagePath: imagePath},
 		Daemonsets:              &daemonsets,
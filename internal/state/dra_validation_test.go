@@ -22,6 +22,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
@@ -59,6 +60,10 @@ func TestDRAValidationRender(t *testing.T) {
 	require.Equal(t, "nvidia-operator-validator", ds.Spec.Template.Labels["app"])
 	// The validator proves the DRA driver by consuming an adminAccess GPU claim.
 	require.NotEmpty(t, ds.Spec.Template.Spec.ResourceClaims)
+	// The dra-validation initContainer runs the DRA-native CUDA workload validation.
+	require.Len(t, ds.Spec.Template.Spec.InitContainers, 2)
+	require.Equal(t, "dra-validation", ds.Spec.Template.Spec.InitContainers[1].Name)
+	require.Contains(t, ds.Spec.Template.Spec.InitContainers[1].Env, corev1.EnvVar{Name: "COMPONENT", Value: "dra"})
 	require.NotEmpty(t, ds.Spec.Template.Spec.Containers)
 	require.Equal(t, "nvcr.io/nvidia/gpu-operator-validator:test", ds.Spec.Template.Spec.Containers[0].Image)
 }
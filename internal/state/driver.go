@@ -45,6 +45,7 @@ import (
 	driverconfig "github.com/NVIDIA/gpu-operator/internal/config"
 	"github.com/NVIDIA/gpu-operator/internal/consts"
 	"github.com/NVIDIA/gpu-operator/internal/image"
+	"github.com/NVIDIA/gpu-operator/internal/pause"
 	"github.com/NVIDIA/gpu-operator/internal/render"
 	"github.com/NVIDIA/gpu-operator/internal/utils"
 )
@@ -98,6 +99,8 @@ type driverRenderData struct {
 	Precompiled       *precompiledSpec
 	AdditionalConfigs *additionalConfigs
 	HostRoot          string
+	// GPUHealthEnabled is true if the GPU health checks taint unhealthy GPU nodes
+	GPUHealthEnabled bool
 }
 
 // ConfigDigest computes a hash of all driver-install-relevant fields.
@@ -137,31 +140,29 @@ func (s *stateDriver) Sync(ctx context.Context, customResource interface{}, info
 		return SyncStateError, fmt.Errorf("NVIDIADriver CR not provided as input to Sync()")
 	}
 
-	objs, err := s.getManifestObjects(ctx, cr, infoCatalog)
+	objs, nodePoolStatuses, err := s.getManifestObjects(ctx, cr, infoCatalog)
 	if err != nil {
 		return SyncStateNotReady, fmt.Errorf("failed to create k8s objects from manifests: %w", err)
 	}
 
-	err = s.cleanupStaleDriverDaemonsets(ctx, cr, objs)
-	if err != nil {
-		return SyncStateNotReady, fmt.Errorf("failed to cleanup stale driver DaemonSets: %w", err)
+	if !pause.IsPaused(cr, s.name) {
+		err = s.cleanupStaleDriverDaemonsets(ctx, cr, objs)
+		if err != nil {
+			return SyncStateNotReady, fmt.Errorf("failed to cleanup stale driver DaemonSets: %w", err)
+		}
 	}
 
 	// Create objects if they don't exist, Update objects if they do exist
-	err = s.createOrUpdateObjs(ctx, func(obj *unstructured.Unstructured) error {
-		if err := controllerutil.SetControllerReference(cr, obj, s.scheme); err != nil {
-			return fmt.Errorf("failed to set controller reference for object: %v", err)
-		}
-		return nil
-	}, objs)
+	syncState, err := s.syncObjects(ctx, cr, objs)
 	if err != nil {
-		return SyncStateNotReady, fmt.Errorf("failed to create/update objects: %v", err)
+		return syncState, err
 	}
 
-	// Check objects status
-	syncState, err := s.getSyncState(ctx, objs)
+	// Report the per node pool rollout in the CR status. The status is persisted
+	// by the controller together with the overall state of the instance.
+	cr.Status.NodePools, err = s.getNodePoolStatuses(ctx, nodePoolStatuses)
 	if err != nil {
-		return SyncStateNotReady, fmt.Errorf("failed to get sync state: %v", err)
+		return SyncStateNotReady, fmt.Errorf("failed to get node pool status: %w", err)
 	}
 	return syncState, nil
 }
@@ -183,6 +184,19 @@ func (s *stateDriver) GetWatchSources(mgr ctrlManager) map[string]SyncingSource
 	return wr
 }
 
+func (s *stateDriver) renderDesiredObjects(ctx context.Context, customResource interface{}, infoCatalog InfoCatalog) ([]*unstructured.Unstructured, error) {
+	cr, ok := customResource.(*nvidiav1alpha1.NVIDIADriver)
+	if !ok {
+		return nil, fmt.Errorf("NVIDIADriver CR not provided as input to renderDesiredObjects()")
+	}
+
+	objs, _, err := s.getManifestObjects(ctx, cr, infoCatalog)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create k8s objects from manifests: %w", err)
+	}
+	return objs, s.prepareObjs(cr, objs)
+}
+
 func (s *stateDriver) cleanupStaleDriverDaemonsets(ctx context.Context, cr *nvidiav1alpha1.NVIDIADriver, desiredObjs []*unstructured.Unstructured) error {
 	logger := log.FromContext(ctx)
 	logger.V(consts.LogLevelInfo).Info("Cleaning up stale driver DaemonSets")
@@ -249,46 +263,51 @@ func (s *stateDriver) cleanupStaleDriverDaemonsets(ctx context.Context, cr *nvid
 	return nil
 }
 
-func (s *stateDriver) getManifestObjects(ctx context.Context, cr *nvidiav1alpha1.NVIDIADriver, infoCatalog InfoCatalog) ([]*unstructured.Unstructured, error) {
+// getManifestObjects renders the driver objects of every node pool the CR deploys to.
+// It also returns the status of each node pool, without the DaemonSet counts.
+func (s *stateDriver) getManifestObjects(ctx context.Context, cr *nvidiav1alpha1.NVIDIADriver, infoCatalog InfoCatalog) ([]*unstructured.Unstructured, []nvidiav1alpha1.NodePoolStatus, error) {
 	logger := log.FromContext(ctx)
 
 	info := infoCatalog.Get(InfoTypeHostRoot)
 	if info == nil {
-		return nil, fmt.Errorf("failed to get host root from info catalog")
+		return nil, nil, fmt.Errorf("failed to get host root from info catalog")
 	}
 	hostRoot, ok := info.(string)
 	if !ok {
-		return nil, fmt.Errorf("host root in info catalog has unexpected type %T", info)
+		return nil, nil, fmt.Errorf("host root in info catalog has unexpected type %T", info)
 	}
 
 	info = infoCatalog.Get(InfoTypeClusterInfo)
 	if info == nil {
-		return nil, fmt.Errorf("failed to get cluster info from info catalog")
+		return nil, nil, fmt.Errorf("failed to get cluster info from info catalog")
 	}
 	clusterInfo := info.(clusterinfo.Interface)
 
 	runtimeSpec, err := getRuntimeSpec(s.namespace, clusterInfo, &cr.Spec)
 	if err != nil {
-		return nil, fmt.Errorf("failed to construct cluster runtime spec: %w", err)
+		return nil, nil, fmt.Errorf("failed to construct cluster runtime spec: %w", err)
 	}
 
 	isOpenshift := runtimeSpec.OpenshiftVersion != ""
 	nodePools, err := getNodePools(ctx, s.client, cr, isOpenshift)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get node pools: %w", err)
+		return nil, nil, fmt.Errorf("failed to get node pools: %w", err)
 	}
 
 	gpuDirectRDMASpec := cr.Spec.GPUDirectRDMA
+	// the GPU health checks are disabled unless the catalog says otherwise
+	gpuHealthEnabled, _ := infoCatalog.Get(InfoTypeGPUHealth).(bool)
 
 	renderData := &driverRenderData{
-		GPUDirectRDMA: gpuDirectRDMASpec,
-		Runtime:       runtimeSpec,
-		HostRoot:      hostRoot,
+		GPUDirectRDMA:    gpuDirectRDMASpec,
+		Runtime:          runtimeSpec,
+		HostRoot:         hostRoot,
+		GPUHealthEnabled: gpuHealthEnabled,
 	}
 
 	if len(nodePools) == 0 {
 		logger.Info("No nodes matching the given node selector", "CR", cr.Name)
-		return []*unstructured.Unstructured{}, nil
+		return []*unstructured.Unstructured{}, nil, nil
 	}
 
 	openshiftDTKMap := clusterInfo.GetOpenshiftDriverToolkitImages()
@@ -296,12 +315,13 @@ func (s *stateDriver) getManifestObjects(ctx context.Context, cr *nvidiav1alpha1
 	// Render kubernetes objects for each node pool.
 	// We deploy one DaemonSet per node pool.
 	var objs []*unstructured.Unstructured
+	nodePoolStatuses := make([]nvidiav1alpha1.NodePoolStatus, 0, len(nodePools))
 	for _, nodePool := range nodePools {
 		// Construct a unique driver spec per node pool. Each node pool
 		// should have a unique nodeSelector and name.
 		driverSpec, err := getDriverSpec(cr, nodePool)
 		if err != nil {
-			return nil, fmt.Errorf("failed to construct driver spec: %w", err)
+			return nil, nil, fmt.Errorf("failed to construct driver spec: %w", err)
 		}
 		renderData.Driver = driverSpec
 
@@ -314,13 +334,13 @@ func (s *stateDriver) getManifestObjects(ctx context.Context, cr *nvidiav1alpha1
 
 		gdsSpec, err := getGDSSpec(&cr.Spec, nodePool)
 		if err != nil {
-			return nil, fmt.Errorf("failed to construct GDS spec: %w", err)
+			return nil, nil, fmt.Errorf("failed to construct GDS spec: %w", err)
 		}
 		renderData.GDS = gdsSpec
 
 		gdrcopySpec, err := getGDRCopySpec(&cr.Spec, nodePool)
 		if err != nil {
-			return nil, fmt.Errorf("failed to construct GDRCopy spec: %w", err)
+			return nil, nil, fmt.Errorf("failed to construct GDRCopy spec: %w", err)
 		}
 		renderData.GDRCopy = gdrcopySpec
 
@@ -340,17 +360,56 @@ func (s *stateDriver) getManifestObjects(ctx context.Context, cr *nvidiav1alpha1
 		manifestObjs, err := s.renderManifestObjects(ctx, renderData)
 		if err != nil {
 			logger.Error(err, "error rendering manifests for node pool", "NodePool", nodePool.name)
-			return nil, err
+			return nil, nil, err
 		}
 		manifestObjs, err = s.handleDefaultImagesInObjects(ctx, manifestObjs, cr, *renderData)
 		if err != nil {
 			logger.Error(err, "error handling default images in manifests", "NodePool", nodePool.name)
-			return nil, err
+			return nil, nil, err
 		}
 		objs = append(objs, manifestObjs...)
+		nodePoolStatuses = append(nodePoolStatuses, getNodePoolStatus(nodePool, renderData))
+	}
 
+	// Node pools are discovered in random order, keep the reported status stable
+	sort.Slice(nodePoolStatuses, func(i, j int) bool {
+		return nodePoolStatuses[i].Name < nodePoolStatuses[j].Name
+	})
+	return objs, nodePoolStatuses, nil
+}
+
+// getNodePoolStatus returns the status of a node pool as rendered, before the
+// DaemonSet counts are known
+func getNodePoolStatus(pool nodePool, renderData *driverRenderData) nvidiav1alpha1.NodePoolStatus {
+	return nvidiav1alpha1.NodePoolStatus{
+		Name:         pool.name,
+		OSTag:        pool.osTag,
+		Kernel:       pool.kernel,
+		DaemonSet:    renderData.Driver.AppName,
+		Image:        renderData.Driver.ImagePath,
+		ConfigDigest: renderData.ConfigDigest(),
 	}
-	return objs, nil
+}
+
+// getNodePoolStatuses fills in the scheduling counts of the driver DaemonSet of every node pool
+func (s *stateDriver) getNodePoolStatuses(ctx context.Context, nodePoolStatuses []nvidiav1alpha1.NodePoolStatus) ([]nvidiav1alpha1.NodePoolStatus, error) {
+	if len(nodePoolStatuses) == 0 {
+		return nil, nil
+	}
+
+	for i := range nodePoolStatuses {
+		ds := &appsv1.DaemonSet{}
+		err := s.client.Get(ctx, types.NamespacedName{Namespace: s.namespace, Name: nodePoolStatuses[i].DaemonSet}, ds)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get DaemonSet %q: %w", nodePoolStatuses[i].DaemonSet, err)
+		}
+		nodePoolStatuses[i].DesiredNumberScheduled = ds.Status.DesiredNumberScheduled
+		nodePoolStatuses[i].NumberReady = ds.Status.NumberReady
+	}
+	return nodePoolStatuses, nil
 }
 
 func (s *stateDriver) renderManifestObjects(ctx context.Context, renderData *driverRenderData) ([]*unstructured.Unstructured, error) {
@@ -27,6 +27,7 @@ import (
 	configv1 "github.com/openshift/api/config/v1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -149,6 +150,32 @@ func TestDriverRenderMinimal(t *testing.T) {
 	require.Equal(t, string(o), actual)
 }
 
+func TestDriverGPUUnhealthyToleration(t *testing.T) {
+	state, err := NewStateDriver(nil, "", nil, manifestDir)
+	require.NoError(t, err)
+	stateDriver, ok := state.(*stateDriver)
+	require.True(t, ok)
+
+	for _, enabled := range []bool{false, true} {
+		renderData := getMinimalDriverRenderData()
+		renderData.GPUHealthEnabled = enabled
+		objs, err := stateDriver.renderer.RenderObjects(
+			&render.TemplatingData{
+				Data: renderData,
+			})
+		require.NoError(t, err)
+
+		ds, err := getDaemonsetFromObjects(objs)
+		require.NoError(t, err)
+
+		tolerated := false
+		for _, toleration := range ds.Spec.Template.Spec.Tolerations {
+			tolerated = tolerated || toleration.Key == consts.GPUUnhealthyTaintKey
+		}
+		require.Equal(t, enabled, tolerated)
+	}
+}
+
 func TestDriverManagerResources(t *testing.T) {
 	state, err := NewStateDriver(nil, "", nil, manifestDir)
 	require.NoError(t, err)
@@ -238,7 +265,7 @@ func TestDriverRenderMissingHostRoot(t *testing.T) {
 	catalog := NewInfoCatalog()
 	catalog.Add(InfoTypeClusterInfo, testClusterInfo{})
 
-	_, err = stateDriver.getManifestObjects(context.Background(), &nvidiav1alpha1.NVIDIADriver{}, catalog)
+	_, _, err = stateDriver.getManifestObjects(context.Background(), &nvidiav1alpha1.NVIDIADriver{}, catalog)
 	require.Error(t, err, "rendering must fail when no host root is in the catalog")
 	require.Contains(t, err.Error(), "host root")
 }
@@ -756,6 +783,80 @@ func TestGetNodePoolsDoesNotAllowSelectorToOverrideOwnerLabel(t *testing.T) {
 	require.Equal(t, "driver-a", nodePools[0].nodeSelector[consts.NVIDIADriverOwnerLabel])
 }
 
+func TestDriverNodePoolStatuses(t *testing.T) {
+	require.NoError(t, corev1.AddToScheme(scheme.Scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme.Scheme))
+
+	newNode := func(name, osVersion, kernel string) *corev1.Node {
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				consts.GPUPresentLabel:        "true",
+				consts.NVIDIADriverOwnerLabel: "driver",
+				nfdOSReleaseIDLabelKey:        "ubuntu",
+				nfdOSVersionIDLabelKey:        osVersion,
+				nfdKernelLabelKey:             kernel,
+			},
+		}}
+	}
+
+	cr := &nvidiav1alpha1.NVIDIADriver{
+		ObjectMeta: metav1.ObjectMeta{Name: "driver", UID: apitypes.UID("test-uid-pools")},
+		Spec: nvidiav1alpha1.NVIDIADriverSpec{
+			DriverType:     nvidiav1alpha1.GPU,
+			UsePrecompiled: ptr.To(true),
+			Repository:     "nvcr.io/nvidia",
+			Image:          "driver",
+			Version:        "535",
+			Manager: nvidiav1alpha1.DriverManagerSpec{
+				Repository: "nvcr.io/nvidia/cloud-native",
+				Image:      "k8s-driver-manager",
+				Version:    "v0.6.2",
+			},
+		},
+	}
+
+	// Only the DaemonSet of the ubuntu24.04 pool has been created so far
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+		newNode("node-a", "24.04", "6.8.0-generic"),
+		newNode("node-b", "22.04", "5.15.0-generic"),
+	).Build()
+
+	state, err := NewStateDriver(k8sClient, "test-ns", scheme.Scheme, manifestDir)
+	require.NoError(t, err)
+	stateDriver, ok := state.(*stateDriver)
+	require.True(t, ok)
+
+	catalog := NewInfoCatalog()
+	catalog.Add(InfoTypeHostRoot, "/")
+	catalog.Add(InfoTypeClusterInfo, testClusterInfo{})
+
+	_, nodePoolStatuses, err := stateDriver.getManifestObjects(context.Background(), cr, catalog)
+	require.NoError(t, err)
+	require.Len(t, nodePoolStatuses, 2)
+
+	pool := nodePoolStatuses[1]
+	require.Equal(t, "ubuntu24.04-6.8.0-generic", pool.Name)
+	require.Equal(t, "ubuntu24.04", pool.OSTag)
+	require.Equal(t, "6.8.0-generic", pool.Kernel)
+	require.Equal(t, "nvcr.io/nvidia/driver:535-6.8.0-generic-ubuntu24.04", pool.Image)
+	require.NotEmpty(t, pool.ConfigDigest)
+	require.NotEqual(t, nodePoolStatuses[0].ConfigDigest, pool.ConfigDigest)
+	require.Equal(t, "ubuntu22.04-5.15.0-generic", nodePoolStatuses[0].Name)
+
+	ds := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: pool.DaemonSet, Namespace: "test-ns"},
+		Status:     appsv1.DaemonSetStatus{DesiredNumberScheduled: 1, NumberReady: 1},
+	}
+	require.NoError(t, k8sClient.Create(context.Background(), ds))
+
+	nodePoolStatuses, err = stateDriver.getNodePoolStatuses(context.Background(), nodePoolStatuses)
+	require.NoError(t, err)
+	require.Zero(t, nodePoolStatuses[0].DesiredNumberScheduled)
+	require.Equal(t, int32(1), nodePoolStatuses[1].DesiredNumberScheduled)
+	require.Equal(t, int32(1), nodePoolStatuses[1].NumberReady)
+}
+
 func TestDriverPrecompiled(t *testing.T) {
 	const (
 		testName = "driver-precompiled"
@@ -20,11 +20,14 @@ import (
 	"fmt"
 
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
+	nvidiav1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1"
 	nvidiav1alpha1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1alpha1"
 	"github.com/NVIDIA/gpu-operator/controllers/clusterinfo"
+	"github.com/NVIDIA/gpu-operator/internal/consts"
 )
 
 // Helpers shared by the GPUCluster operand states (DRA driver, DCGM, ...).
@@ -36,6 +39,21 @@ func dcgmEnabled(cr *nvidiav1alpha1.GPUCluster) bool {
 	return cr.Spec.DCGM != nil && cr.Spec.DCGM.Enabled != nil && *cr.Spec.DCGM.Enabled
 }
 
+// gpuClusterDaemonsets returns the common DaemonSet configuration of the operands. They
+// tolerate the taint of unhealthy GPU nodes when these are tainted, the remediation of the
+// nodes restarts the operands.
+func gpuClusterDaemonsets(cr *nvidiav1alpha1.GPUCluster) nvidiav1.DaemonsetsSpec {
+	daemonsets := *cr.Spec.Daemonsets.DeepCopy()
+	if cr.Spec.GPUHealth.IsEnabled() {
+		daemonsets.Tolerations = append(daemonsets.Tolerations, corev1.Toleration{
+			Key:      consts.GPUUnhealthyTaintKey,
+			Operator: corev1.TolerationOpExists,
+			Effect:   corev1.TaintEffectNoSchedule,
+		})
+	}
+	return daemonsets
+}
+
 // gpuClusterDaemonSetSource watches DaemonSets and enqueues the owning
 // GPUCluster. Every operand state returns the same source; the manager
 // deduplicates them by key.
@@ -21,6 +21,7 @@ type InfoType uint
 const (
 	InfoTypeClusterInfo = iota
 	InfoTypeHostRoot
+	InfoTypeGPUHealth
 )
 
 func NewInfoCatalog() InfoCatalog {
@@ -19,7 +19,9 @@ package state
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -28,6 +30,10 @@ import (
 	"github.com/NVIDIA/gpu-operator/internal/consts"
 )
 
+// DefaultManifestsDir is the directory the state manifests are installed to in the
+// operator image.
+const DefaultManifestsDir = "/opt/gpu-operator/manifests"
+
 type Manager interface {
 	GetWatchSources(ctrlManager) []SyncingSource
 	SyncState(ctx context.Context, customResource interface{}, infoCatalog InfoCatalog) Results
@@ -42,7 +48,7 @@ type stateManager struct {
 var _ Manager = (*stateManager)(nil)
 
 func NewManager(crdKind string, namespace string, k8sClient client.Client, scheme *runtime.Scheme) (Manager, error) {
-	states, err := newStates(crdKind, namespace, k8sClient, scheme)
+	states, err := newStates(crdKind, namespace, k8sClient, scheme, DefaultManifestsDir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to add states: %v", err)
 	}
@@ -110,20 +116,55 @@ func (m *stateManager) SyncState(ctx context.Context, customResource interface{}
 	return managerResult
 }
 
-func newStates(crdKind string, namespace string, k8sClient client.Client, scheme *runtime.Scheme) ([]State, error) {
+// renderableState is a State whose desired objects can be rendered without applying
+// them to the cluster.
+type renderableState interface {
+	State
+	// renderDesiredObjects returns the objects Sync would apply for customResource,
+	// including the owner reference, labels and annotations set on apply.
+	renderDesiredObjects(ctx context.Context, customResource interface{}, infoCatalog InfoCatalog) ([]*unstructured.Unstructured, error)
+}
+
+// RenderObjects returns the objects the states of crdKind would apply for
+// customResource, in the order they are applied, without creating or updating anything.
+// k8sClient is only read from, e.g. to discover the GPU node pools. The manifests are
+// loaded from manifestsDir.
+func RenderObjects(ctx context.Context, crdKind string, namespace string, k8sClient client.Client, scheme *runtime.Scheme,
+	manifestsDir string, customResource interface{}, infoCatalog InfoCatalog) ([]*unstructured.Unstructured, error) {
+	states, err := newStates(crdKind, namespace, k8sClient, scheme, manifestsDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add states: %w", err)
+	}
+
+	var objs []*unstructured.Unstructured
+	for _, state := range states {
+		rs, ok := state.(renderableState)
+		if !ok {
+			return nil, fmt.Errorf("state %s does not support rendering", state.Name())
+		}
+		stateObjs, err := rs.renderDesiredObjects(ctx, customResource, infoCatalog)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render state %s: %w", state.Name(), err)
+		}
+		objs = append(objs, stateObjs...)
+	}
+	return objs, nil
+}
+
+func newStates(crdKind string, namespace string, k8sClient client.Client, scheme *runtime.Scheme, manifestsDir string) ([]State, error) {
 	switch crdKind {
 	case nvidiav1alpha1.NVIDIADriverCRDName:
-		return newNVIDIADriverStates(k8sClient, namespace, scheme)
+		return newNVIDIADriverStates(k8sClient, namespace, scheme, manifestsDir)
 	case nvidiav1alpha1.GPUClusterCRDName:
-		return newGPUClusterStates(k8sClient, namespace, scheme)
+		return newGPUClusterStates(k8sClient, namespace, scheme, manifestsDir)
 	default:
 		break
 	}
 	return nil, fmt.Errorf("unsupported CRD for state manager factory: %s", crdKind)
 }
 
-func newNVIDIADriverStates(k8sClient client.Client, namespace string, scheme *runtime.Scheme) ([]State, error) {
-	driverState, err := NewStateDriver(k8sClient, namespace, scheme, "/opt/gpu-operator/manifests/state-driver")
+func newNVIDIADriverStates(k8sClient client.Client, namespace string, scheme *runtime.Scheme, manifestsDir string) ([]State, error) {
+	driverState, err := NewStateDriver(k8sClient, namespace, scheme, filepath.Join(manifestsDir, "state-driver"))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create NVIDIA driver state: %v", err)
 	}
@@ -132,21 +173,21 @@ func newNVIDIADriverStates(k8sClient client.Client, namespace string, scheme *ru
 }
 
 // newGPUClusterStates returns the states reconciled for a GPUCluster.
-func newGPUClusterStates(k8sClient client.Client, namespace string, scheme *runtime.Scheme) ([]State, error) {
+func newGPUClusterStates(k8sClient client.Client, namespace string, scheme *runtime.Scheme, manifestsDir string) ([]State, error) {
 	operands := []struct {
 		name        string
 		manifestDir string
 		newState    func(client.Client, string, *runtime.Scheme, string) (State, error)
 	}{
-		{"DRA driver", "/opt/gpu-operator/manifests/state-dra-driver", NewStateDRADriver},
-		{"DCGM", "/opt/gpu-operator/manifests/state-dcgm", NewStateDCGM},
-		{"DCGM Exporter", "/opt/gpu-operator/manifests/state-dcgm-exporter", NewStateDCGMExporter},
-		{"DRA validator", "/opt/gpu-operator/manifests/state-dra-validation", NewStateDRAValidation},
+		{"DRA driver", "state-dra-driver", NewStateDRADriver},
+		{"DCGM", "state-dcgm", NewStateDCGM},
+		{"DCGM Exporter", "state-dcgm-exporter", NewStateDCGMExporter},
+		{"DRA validator", "state-dra-validation", NewStateDRAValidation},
 	}
 
 	states := make([]State, 0, len(operands))
 	for _, operand := range operands {
-		state, err := operand.newState(k8sClient, namespace, scheme, operand.manifestDir)
+		state, err := operand.newState(k8sClient, namespace, scheme, filepath.Join(manifestsDir, operand.manifestDir))
 		if err != nil {
 			return nil, fmt.Errorf("failed to create %s state: %v", operand.name, err)
 		}
@@ -0,0 +1,66 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package state
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	nvidiav1alpha1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1alpha1"
+	"github.com/NVIDIA/gpu-operator/internal/consts"
+)
+
+func TestRenderObjects(t *testing.T) {
+	t.Setenv("VALIDATOR_IMAGE", "nvcr.io/nvidia/gpu-operator-validator:test")
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, nvidiav1alpha1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	cr := sampleGPUCluster()
+	cr.Spec.SetDefaults()
+	objs, err := RenderObjects(context.Background(), nvidiav1alpha1.GPUClusterCRDName, "test-operator", c, scheme,
+		"../../manifests", cr, draSupportedCatalog())
+	require.NoError(t, err)
+	require.NotEmpty(t, objs)
+
+	states := map[string]bool{}
+	for _, obj := range objs {
+		states[obj.GetLabels()[consts.StateLabel]] = true
+		require.Len(t, obj.GetOwnerReferences(), 1)
+		require.Equal(t, cr.Name, obj.GetOwnerReferences()[0].Name)
+		if obj.GetKind() == "DaemonSet" {
+			require.NotEmpty(t, obj.GetAnnotations()[consts.NvidiaAnnotationHashKey])
+		}
+	}
+	// dcgm and dcgm-exporter are disabled by default and render nothing
+	require.Equal(t, map[string]bool{"state-dra-driver": true, "state-dra-validation": true}, states)
+
+	// Nothing was applied to the cluster
+	list := &appsv1.DaemonSetList{}
+	require.NoError(t, c.List(context.Background(), list))
+	require.Empty(t, list.Items)
+
+	_, err = RenderObjects(context.Background(), "Unknown", "test-operator", c, scheme, "../../manifests", cr, draSupportedCatalog())
+	require.ErrorContains(t, err, "unsupported CRD for state manager factory")
+}
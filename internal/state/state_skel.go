@@ -35,6 +35,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/NVIDIA/gpu-operator/internal/consts"
+	"github.com/NVIDIA/gpu-operator/internal/pause"
 	"github.com/NVIDIA/gpu-operator/internal/render"
 	"github.com/NVIDIA/gpu-operator/internal/utils"
 )
@@ -100,16 +101,22 @@ func (s *stateSkel) renderObjects(ctx context.Context, data interface{}) ([]*uns
 
 // syncObjects creates or updates the rendered objects and returns the aggregated sync
 // state. Owner references make every object (including cluster-scoped ones) garbage
-// collected when the owning CR is deleted.
+// collected when the owning CR is deleted. While the state is paused by the
+// reconcile-paused annotation on the owner, the objects are left as they are and only
+// the sync state is computed.
 func (s *stateSkel) syncObjects(ctx context.Context, owner metav1.Object, objs []*unstructured.Unstructured) (SyncState, error) {
-	err := s.createOrUpdateObjs(ctx, func(obj *unstructured.Unstructured) error {
-		if err := controllerutil.SetControllerReference(owner, obj, s.scheme); err != nil {
-			return fmt.Errorf("failed to set controller reference for object: %w", err)
+	if pause.IsPaused(owner, s.name) {
+		log.FromContext(ctx).V(consts.LogLevelInfo).Info("Reconciliation is paused, skipping apply of objects", "State:", s.name)
+	} else {
+		err := s.applyObjs(ctx, func(obj *unstructured.Unstructured) error {
+			if err := controllerutil.SetControllerReference(owner, obj, s.scheme); err != nil {
+				return fmt.Errorf("failed to set controller reference for object: %w", err)
+			}
+			return nil
+		}, objs)
+		if err != nil {
+			return SyncStateNotReady, fmt.Errorf("failed to apply objects: %w", err)
 		}
-		return nil
-	}, objs)
-	if err != nil {
-		return SyncStateNotReady, fmt.Errorf("failed to create/update objects: %w", err)
 	}
 
 	syncState, err := s.getSyncState(ctx, objs)
@@ -255,19 +262,15 @@ func (s *stateSkel) getObj(ctx context.Context, obj *unstructured.Unstructured)
 	return err
 }
 
-func (s *stateSkel) createObj(ctx context.Context, obj *unstructured.Unstructured) error {
+func (s *stateSkel) applyObj(ctx context.Context, obj *unstructured.Unstructured) error {
 	reqLogger := log.FromContext(ctx)
 
 	s.checkDeleteSupported(ctx, obj)
-	reqLogger.V(consts.LogLevelInfo).Info("Creating Object", "Namespace:", obj.GetNamespace(), "Name:", obj.GetName())
-	toCreate := obj.DeepCopy()
-	if err := s.client.Create(ctx, toCreate); err != nil {
-		if apierrors.IsAlreadyExists(err) {
-			reqLogger.V(consts.LogLevelInfo).Info("Object Already Exists")
-		}
+	reqLogger.V(consts.LogLevelInfo).Info("Applying Object", "Namespace:", obj.GetNamespace(), "Name:", obj.GetName())
+	if err := utils.ApplyObject(ctx, s.client, obj.DeepCopy()); err != nil {
 		return err
 	}
-	reqLogger.V(consts.LogLevelInfo).Info("Object created successfully")
+	reqLogger.V(consts.LogLevelInfo).Info("Object applied successfully")
 	return nil
 }
 
@@ -284,21 +287,11 @@ func (s *stateSkel) checkDeleteSupported(ctx context.Context, obj *unstructured.
 		"Namespace:", obj.GetNamespace(), "Name:", obj.GetName(), "GVK", obj.GroupVersionKind())
 }
 
-func (s *stateSkel) updateObj(ctx context.Context, obj *unstructured.Unstructured) error {
-	reqLogger := log.FromContext(ctx)
-	reqLogger.V(consts.LogLevelInfo).Info("Updating Object", "Namespace:", obj.GetNamespace(), "Name:", obj.GetName())
-
-	// Note: Some objects may require update of the resource version
-	// TODO: using Patch preserves runtime attributes. In the future consider using patch if relevant
-	desired := obj.DeepCopy()
-	if err := s.client.Update(ctx, desired); err != nil {
-		return fmt.Errorf("failed to update resource: %w", err)
-	}
-	reqLogger.V(consts.LogLevelInfo).Info("Object updated successfully")
-	return nil
-}
-
-func (s *stateSkel) createOrUpdateObjs(
+// applyObjs creates or updates objs with a server-side apply. The operator only owns
+// the fields it renders, so the fields other controllers set on the objects are kept.
+// DaemonSets whose hash is unchanged are not applied again: an apply may still change
+// their pod template, through the defaults of a newer API server, and roll their pods.
+func (s *stateSkel) applyObjs(
 	ctx context.Context,
 	setControllerReference func(obj *unstructured.Unstructured) error,
 	objs []*unstructured.Unstructured) error {
@@ -306,56 +299,64 @@ func (s *stateSkel) createOrUpdateObjs(
 	for _, desiredObj := range objs {
 		reqLogger.V(consts.LogLevelInfo).Info("Handling manifest object", "Kind:", desiredObj.GetKind(),
 			"Name", desiredObj.GetName())
-		// Set controller reference for object to allow cleanup on CR deletion
-		if err := setControllerReference(desiredObj); err != nil {
-			return fmt.Errorf("failed to set controller reference for object: %w", err)
+		desiredObjectHash, err := s.prepareObj(setControllerReference, desiredObj)
+		if err != nil {
+			return err
 		}
 
-		s.addStateSpecificLabels(desiredObj)
-
-		var desiredObjectHash string
 		if desiredObj.GetKind() == "DaemonSet" {
-			desiredObjectHash = utils.GetObjectHash(desiredObj)
-			annotations := desiredObj.GetAnnotations()
-			if annotations == nil {
-				annotations = make(map[string]string)
+			currentObj := desiredObj.DeepCopy()
+			err := s.getObj(ctx, currentObj)
+			if err != nil && !apierrors.IsNotFound(err) {
+				return err
+			}
+			if err == nil && currentObj.GetAnnotations()[consts.NvidiaAnnotationHashKey] == desiredObjectHash {
+				reqLogger.V(consts.LogLevelDebug).Info("Object is unchanged, so skipping apply",
+					"Kind", desiredObj.GetKind(), "Name", desiredObj.GetName())
+				continue
 			}
-			annotations[consts.NvidiaAnnotationHashKey] = desiredObjectHash
-			desiredObj.SetAnnotations(annotations)
 		}
 
-		err := s.createObj(ctx, desiredObj)
-		if err == nil {
-			// object created successfully
-			continue
-		}
-		if !apierrors.IsAlreadyExists(err) {
-			// Some error occurred
+		if err := s.applyObj(ctx, desiredObj); err != nil {
 			return err
 		}
+	}
+	return nil
+}
 
-		currentObj := desiredObj.DeepCopy()
-		if err := s.getObj(ctx, currentObj); err != nil {
-			// Some error occurred
-			return err
-		}
+// prepareObj completes a rendered object the way it is applied to the cluster: it sets
+// the controller reference, adds the state label and, for DaemonSets, annotates the
+// object with its hash. The hash is returned, empty for other kinds.
+func (s *stateSkel) prepareObj(
+	setControllerReference func(obj *unstructured.Unstructured) error,
+	obj *unstructured.Unstructured) (string, error) {
+	// Set controller reference for object to allow cleanup on CR deletion
+	if err := setControllerReference(obj); err != nil {
+		return "", fmt.Errorf("failed to set controller reference for object: %w", err)
+	}
 
-		if desiredObj.GetKind() == "DaemonSet" {
-			if currentObjHash, ok := currentObj.GetAnnotations()[consts.NvidiaAnnotationHashKey]; ok {
-				if desiredObjectHash == currentObjHash {
-					reqLogger.V(consts.LogLevelDebug).Info("Object is unchanged, so skipping update",
-						"Kind", desiredObj.GetKind(), "Name", desiredObj.GetName())
-					continue
-				}
-			}
-		}
+	s.addStateSpecificLabels(obj)
 
-		if err := s.mergeObjects(desiredObj, currentObj); err != nil {
-			return err
-		}
+	if obj.GetKind() != "DaemonSet" {
+		return "", nil
+	}
+	hash := utils.GetObjectHash(obj)
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[consts.NvidiaAnnotationHashKey] = hash
+	obj.SetAnnotations(annotations)
+	return hash, nil
+}
 
-		// Object found, Update it
-		if err := s.updateObj(ctx, desiredObj); err != nil {
+// prepareObjs prepares objs as owned by owner, see prepareObj.
+func (s *stateSkel) prepareObjs(owner metav1.Object, objs []*unstructured.Unstructured) error {
+	for _, obj := range objs {
+		_, err := s.prepareObj(func(obj *unstructured.Unstructured) error {
+			return controllerutil.SetControllerReference(owner, obj, s.scheme)
+		}, obj)
+		if err != nil {
 			return err
 		}
 	}
@@ -371,8 +372,12 @@ func (s *stateSkel) addStateSpecificLabels(obj *unstructured.Unstructured) {
 	obj.SetLabels(labels)
 }
 
-func (s *stateSkel) handleStateObjectsDeletion(ctx context.Context) (SyncState, error) {
+func (s *stateSkel) handleStateObjectsDeletion(ctx context.Context, owner metav1.Object) (SyncState, error) {
 	reqLogger := log.FromContext(ctx)
+	if pause.IsPaused(owner, s.name) {
+		reqLogger.V(consts.LogLevelInfo).Info("Reconciliation is paused, skipping deletion of objects", "State:", s.name)
+		return SyncStateIgnore, nil
+	}
 	reqLogger.V(consts.LogLevelInfo).Info(
 		"State spec in CR is nil, deleting existing objects if needed", "State:", s.name)
 	found, err := s.deleteStateRelatedObjects(ctx)
@@ -431,44 +436,6 @@ func (s *stateSkel) deleteStateRelatedObjects(ctx context.Context) (bool, error)
 	return found, nil
 }
 
-func (s *stateSkel) mergeObjects(updated, current *unstructured.Unstructured) error {
-	// Set resource version
-	// ResourceVersion must be passed unmodified back to the server.
-	// ResourceVersion helps the kubernetes API server to implement optimistic concurrency for PUT operations
-	// when two PUT requests are specifying the resourceVersion, one of the PUTs will fail.
-	updated.SetResourceVersion(current.GetResourceVersion())
-
-	gvk := updated.GroupVersionKind()
-	if gvk.Group == "" && gvk.Kind == "ServiceAccount" {
-		return s.mergeServiceAccount(updated, current)
-	}
-	return nil
-}
-
-// For Service Account, keep secrets if exists
-func (s *stateSkel) mergeServiceAccount(updated, current *unstructured.Unstructured) error {
-	curSecrets, ok, err := unstructured.NestedSlice(current.Object, "secrets")
-	if err != nil {
-		return err
-	}
-	if ok {
-		if err := unstructured.SetNestedField(updated.Object, curSecrets, "secrets"); err != nil {
-			return err
-		}
-	}
-
-	curImagePullSecrets, ok, err := unstructured.NestedSlice(current.Object, "imagePullSecrets")
-	if err != nil {
-		return err
-	}
-	if ok {
-		if err := unstructured.SetNestedField(updated.Object, curImagePullSecrets, "imagePullSecrets"); err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
 // Iterate over objects and check for their readiness
 func (s *stateSkel) getSyncState(ctx context.Context, objs []*unstructured.Unstructured) (SyncState, error) {
 	reqLogger := log.FromContext(ctx)
@@ -22,9 +22,15 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	nvidiav1alpha1 "github.com/NVIDIA/gpu-operator/api/nvidia/v1alpha1"
+	"github.com/NVIDIA/gpu-operator/internal/consts"
 )
 
 func toUnstructuredDaemonSet(t *testing.T, ds *appsv1.DaemonSet) *unstructured.Unstructured {
@@ -117,3 +123,89 @@ func TestIsDaemonSetReady(t *testing.T) {
 		})
 	}
 }
+
+func TestSyncObjectsPaused(t *testing.T) {
+	testCases := []struct {
+		name        string
+		annotation  string
+		expectedVal string
+	}{
+		{name: "not paused", annotation: "", expectedVal: "desired"},
+		{name: "all components paused", annotation: "true", expectedVal: "patched"},
+		{name: "state paused", annotation: "state-other, state-test", expectedVal: "patched"},
+		{name: "other state paused", annotation: "state-other", expectedVal: "desired"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			require.NoError(t, nvidiav1alpha1.AddToScheme(scheme))
+			require.NoError(t, corev1.AddToScheme(scheme))
+
+			current := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-config", Namespace: "test-operator"},
+				Data:       map[string]string{"key": "patched"},
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(current).Build()
+			s := &stateSkel{name: "state-test", namespace: "test-operator", client: c, scheme: scheme}
+
+			owner := sampleGPUCluster()
+			owner.UID = "test-uid"
+			owner.Annotations = map[string]string{consts.ReconcilePausedAnnotation: tc.annotation}
+
+			desired, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&corev1.ConfigMap{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+				ObjectMeta: metav1.ObjectMeta{Name: "test-config", Namespace: "test-operator"},
+				Data:       map[string]string{"key": "desired"},
+			})
+			require.NoError(t, err)
+
+			syncState, err := s.syncObjects(t.Context(), owner, []*unstructured.Unstructured{{Object: desired}})
+			require.NoError(t, err)
+			// the status is computed whether or not the state is paused
+			require.EqualValues(t, SyncStateReady, syncState)
+
+			got := &corev1.ConfigMap{}
+			require.NoError(t, c.Get(t.Context(), client.ObjectKeyFromObject(current), got))
+			require.Equal(t, tc.expectedVal, got.Data["key"])
+		})
+	}
+}
+
+func TestApplyObjsUnchangedDaemonSet(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+	s := &stateSkel{name: "state-test", namespace: "test-operator", client: c, scheme: scheme}
+	noOwner := func(*unstructured.Unstructured) error { return nil }
+
+	desired := func(image string) []*unstructured.Unstructured {
+		return []*unstructured.Unstructured{toUnstructuredDaemonSet(t, &appsv1.DaemonSet{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "DaemonSet"},
+			ObjectMeta: metav1.ObjectMeta{Name: "test-ds", Namespace: "test-operator"},
+			Spec: appsv1.DaemonSetSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "test", Image: image}}},
+				},
+			},
+		})}
+	}
+	image := func() string {
+		ds := &appsv1.DaemonSet{}
+		require.NoError(t, c.Get(t.Context(), client.ObjectKey{Namespace: "test-operator", Name: "test-ds"}, ds))
+		return ds.Spec.Template.Spec.Containers[0].Image
+	}
+
+	require.NoError(t, s.applyObjs(t.Context(), noOwner, desired("image:v1")))
+	ds := &appsv1.DaemonSet{}
+	require.NoError(t, c.Get(t.Context(), client.ObjectKey{Namespace: "test-operator", Name: "test-ds"}, ds))
+	ds.Spec.Template.Spec.Containers[0].Image = "image:edited"
+	require.NoError(t, c.Update(t.Context(), ds))
+
+	// the hash of the desired DaemonSet is unchanged, so it is not applied
+	require.NoError(t, s.applyObjs(t.Context(), noOwner, desired("image:v1")))
+	require.Equal(t, "image:edited", image())
+
+	require.NoError(t, s.applyObjs(t.Context(), noOwner, desired("image:v2")))
+	require.Equal(t, "image:v2", image())
+}
@@ -27,11 +27,21 @@ import (
 	"slices"
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/davecgh/go-spew/spew"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/csaupgrade"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/NVIDIA/gpu-operator/internal/consts"
 )
 
 // GetFilesWithSuffix returns all files under a given base directory that have a specific suffix
@@ -178,3 +188,113 @@ func EnsureFinalizer(ctx context.Context, c client.Client, o client.Object, fina
 	controllerutil.AddFinalizer(o, finalizer)
 	return c.Patch(ctx, o, client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{}))
 }
+
+// ToUnstructured returns obj converted to an unstructured object
+func ToUnstructured(obj runtime.Object) (*unstructured.Unstructured, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	return &unstructured.Unstructured{Object: content}, nil
+}
+
+// managedFieldsCacheClient is a client recording the keys of the objects whose managed
+// fields ApplyObject already upgraded, see upgradeManagedFields.
+type managedFieldsCacheClient struct {
+	client.Client
+	upgraded sync.Map
+}
+
+// WithManagedFieldsCache returns c recording the objects whose managed fields ApplyObject
+// upgraded, so that they are upgraded once for the lifetime of the client. The managed
+// fields of the objects applied with other clients, such as dry-run clients or the fake
+// clients rendering manifests, are checked on every apply and never recorded.
+func WithManagedFieldsCache(c client.Client) client.Client {
+	return &managedFieldsCacheClient{Client: c}
+}
+
+// upgradeManagedFields hands the fields the operator owns in the live object of u through
+// its former create and update requests over to its apply field manager. The fields the
+// operator stops rendering are then removed by the next apply, instead of being kept by
+// the Update entry of the objects created before the operator used server-side apply.
+// It returns false if the object does not exist yet.
+func upgradeManagedFields(ctx context.Context, c client.Client, u *unstructured.Unstructured) (bool, error) {
+	live := &unstructured.Unstructured{}
+	live.SetGroupVersionKind(u.GroupVersionKind())
+	err := c.Get(ctx, client.ObjectKeyFromObject(u), live)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	patch, err := csaupgrade.UpgradeManagedFieldsPatch(live, sets.New(consts.FieldManager), consts.FieldManager)
+	if err != nil || patch == nil {
+		return err == nil, err
+	}
+	if err := c.Patch(ctx, live, client.RawPatch(types.JSONPatchType, patch)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ApplyObject creates or updates obj with a server-side apply by the operator field
+// manager. Only the fields set in obj are owned by the operator: fields set by other
+// controllers, such as the secrets of a ServiceAccount or the cluster IP of a Service,
+// are left as they are, while conflicting changes to the fields owned by the operator
+// are overridden. obj is updated with the applied object.
+//
+// The managed fields of an object are upgraded before it is first applied, so that the
+// objects created by earlier operator versions are fully owned by the apply. Clients
+// returned by WithManagedFieldsCache only upgrade them once.
+func ApplyObject(ctx context.Context, c client.Client, obj client.Object) error {
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+	if err != nil {
+		return err
+	}
+
+	u := &unstructured.Unstructured{}
+	if uObj, ok := obj.(*unstructured.Unstructured); ok {
+		u = uObj.DeepCopy()
+	} else {
+		u, err = ToUnstructured(obj)
+		if err != nil {
+			return fmt.Errorf("failed to convert %s %s: %w", gvk.Kind, obj.GetName(), err)
+		}
+	}
+	u.SetGroupVersionKind(gvk)
+	// The fields set by the API server are not part of the applied configuration
+	u.SetResourceVersion("")
+	u.SetUID("")
+	u.SetManagedFields(nil)
+	unstructured.RemoveNestedField(u.Object, "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(u.Object, "status")
+
+	key := gvk.String() + "/" + client.ObjectKeyFromObject(u).String()
+	cache, cached := c.(*managedFieldsCacheClient)
+	upgraded := false
+	if cached {
+		_, upgraded = cache.upgraded.Load(key)
+	}
+	if !upgraded {
+		upgraded, err = upgradeManagedFields(ctx, c, u)
+		if err != nil {
+			return fmt.Errorf("failed to upgrade the managed fields of %s %s: %w", gvk.Kind, obj.GetName(), err)
+		}
+		if cached && upgraded {
+			cache.upgraded.Store(key, struct{}{})
+		}
+	}
+
+	err = c.Apply(ctx, client.ApplyConfigurationFromUnstructured(u), client.FieldOwner(consts.FieldManager), client.ForceOwnership)
+	if err != nil {
+		return fmt.Errorf("failed to apply %s %s: %w", gvk.Kind, obj.GetName(), err)
+	}
+
+	if uObj, ok := obj.(*unstructured.Unstructured); ok {
+		u.DeepCopyInto(uObj)
+		return nil
+	}
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, obj)
+}
@@ -25,8 +25,11 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/NVIDIA/gpu-operator/internal/consts"
 )
 
 func TestGetObjectHash(t *testing.T) {
@@ -244,3 +247,112 @@ func TestEnsureFinalizer(t *testing.T) {
 		assert.False(t, controllerutil.ContainsFinalizer(obj, testFinalizer))
 	})
 }
+
+func TestApplyObject(t *testing.T) {
+	s := scheme.Scheme
+
+	newServiceAccount := func() *corev1.ServiceAccount {
+		return &corev1.ServiceAccount{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "nvidia-driver",
+				Namespace: "gpu-operator",
+				Labels:    map[string]string{"app": "nvidia-driver"},
+			},
+		}
+	}
+
+	t.Run("creates missing object", func(t *testing.T) {
+		c := fake.NewClientBuilder().WithScheme(s).WithReturnManagedFields().Build()
+
+		obj := newServiceAccount()
+		err := ApplyObject(context.Background(), c, obj)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, obj.ResourceVersion)
+
+		found := &corev1.ServiceAccount{}
+		assert.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(obj), found))
+		assert.Equal(t, "nvidia-driver", found.Labels["app"])
+		assert.Equal(t, consts.FieldManager, found.ManagedFields[0].Manager)
+	})
+
+	t.Run("keeps fields set by other managers", func(t *testing.T) {
+		c := fake.NewClientBuilder().WithScheme(s).Build()
+		assert.NoError(t, ApplyObject(context.Background(), c, newServiceAccount()))
+
+		// the cluster adds the pull secrets of the ServiceAccount
+		current := &corev1.ServiceAccount{}
+		assert.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(newServiceAccount()), current))
+		current.ImagePullSecrets = []corev1.LocalObjectReference{{Name: "nvidia-driver-dockercfg"}}
+		current.Labels["extra"] = "label"
+		assert.NoError(t, c.Update(context.Background(), current, client.FieldOwner("openshift-controller-manager")))
+
+		updated := newServiceAccount()
+		updated.Labels["app"] = "nvidia-driver-v2"
+		assert.NoError(t, ApplyObject(context.Background(), c, updated))
+
+		found := &corev1.ServiceAccount{}
+		assert.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(updated), found))
+		assert.Equal(t, "nvidia-driver-v2", found.Labels["app"])
+		assert.Equal(t, "label", found.Labels["extra"])
+		assert.Equal(t, []corev1.LocalObjectReference{{Name: "nvidia-driver-dockercfg"}}, found.ImagePullSecrets)
+	})
+
+	t.Run("overrides conflicting changes", func(t *testing.T) {
+		c := fake.NewClientBuilder().WithScheme(s).Build()
+		assert.NoError(t, ApplyObject(context.Background(), c, newServiceAccount()))
+
+		current := &corev1.ServiceAccount{}
+		assert.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(newServiceAccount()), current))
+		current.Labels["app"] = "edited"
+		assert.NoError(t, c.Update(context.Background(), current, client.FieldOwner("kubectl-edit")))
+
+		assert.NoError(t, ApplyObject(context.Background(), c, newServiceAccount()))
+
+		found := &corev1.ServiceAccount{}
+		assert.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(current), found))
+		assert.Equal(t, "nvidia-driver", found.Labels["app"])
+	})
+
+	t.Run("takes over fields of objects created with an update", func(t *testing.T) {
+		c := fake.NewClientBuilder().WithScheme(s).WithReturnManagedFields().Build()
+
+		// an earlier operator version created the object with a label it no longer renders
+		created := newServiceAccount()
+		created.Name = "nvidia-driver-upgraded"
+		created.Labels["stale"] = "label"
+		assert.NoError(t, c.Create(context.Background(), created, client.FieldOwner(consts.FieldManager)))
+
+		applied := newServiceAccount()
+		applied.Name = created.Name
+		assert.NoError(t, ApplyObject(context.Background(), c, applied))
+
+		found := &corev1.ServiceAccount{}
+		assert.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(created), found))
+		assert.Equal(t, map[string]string{"app": "nvidia-driver"}, found.Labels)
+		assert.Len(t, found.ManagedFields, 1)
+		assert.Equal(t, metav1.ManagedFieldsOperationApply, found.ManagedFields[0].Operation)
+	})
+
+	t.Run("records the objects upgraded through a caching client", func(t *testing.T) {
+		c := WithManagedFieldsCache(fake.NewClientBuilder().WithScheme(s).Build())
+		cache := c.(*managedFieldsCacheClient)
+		key := "/v1, Kind=ServiceAccount/gpu-operator/nvidia-driver"
+
+		// a missing object is created by the apply, not upgraded
+		assert.NoError(t, ApplyObject(context.Background(), c, newServiceAccount()))
+		_, ok := cache.upgraded.Load(key)
+		assert.False(t, ok)
+
+		assert.NoError(t, ApplyObject(context.Background(), c, newServiceAccount()))
+		_, ok = cache.upgraded.Load(key)
+		assert.True(t, ok)
+
+		// a dry-run client wrapping the caching client records nothing
+		other := newServiceAccount()
+		other.Name = "nvidia-driver-dry-run"
+		assert.NoError(t, c.Create(context.Background(), other.DeepCopy()))
+		assert.NoError(t, ApplyObject(context.Background(), client.NewDryRunClient(c), other))
+		_, ok = cache.upgraded.Load("/v1, Kind=ServiceAccount/gpu-operator/nvidia-driver-dry-run")
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,254 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+// Package validation defines the JSON reports the nvidia-validator writes for the
+// validation of each component on a node, and the summary of those reports the node
+// status exporter publishes on the node for the operator.
+package validation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	// ReportFileSuffix is the suffix of the report files written in the validations
+	// directory, named after the validated component
+	ReportFileSuffix = "-report.json"
+	// SummaryAnnotation is the node status exporter pod annotation holding the JSON
+	// NodeSummary of the validation reports of its node
+	SummaryAnnotation = "nvidia.com/gpu-operator.validation-summary"
+	// RevalidationFailedAnnotation is the operator validator pod annotation holding the comma
+	// separated list of the components failing their periodic revalidation
+	RevalidationFailedAnnotation = "nvidia.com/gpu.revalidation.failed"
+
+	// MaxOutputBytes is the size of the command output excerpts kept in the checks
+	MaxOutputBytes = 4096
+	// maxSummaryErrorBytes is the size of the errors kept in the summaries
+	maxSummaryErrorBytes = 256
+)
+
+// Result is the result of a validation
+type Result string
+
+const (
+	// ResultRunning is the result of a validation in progress
+	ResultRunning Result = "running"
+	// ResultPassed is the result of a successful validation
+	ResultPassed Result = "passed"
+	// ResultFailed is the result of a failed validation
+	ResultFailed Result = "failed"
+)
+
+// ErrorClass classifies the error a validation failed with
+type ErrorClass string
+
+const (
+	// ErrorClassCommandFailed is the class of validations failed by a check command
+	ErrorClassCommandFailed ErrorClass = "CommandFailed"
+	// ErrorClassTimeout is the class of validations which timed out
+	ErrorClassTimeout ErrorClass = "Timeout"
+	// ErrorClassNotFound is the class of validations failed by a missing file
+	ErrorClassNotFound ErrorClass = "NotFound"
+	// ErrorClassValidationFailed is the class of the other failed validations
+	ErrorClassValidationFailed ErrorClass = "ValidationFailed"
+)
+
+// Report is the report of the validation of a component on a node
+type Report struct {
+	Component      string     `json:"component"`
+	Node           string     `json:"node,omitempty"`
+	Result         Result     `json:"result"`
+	StartTime      time.Time  `json:"startTime"`
+	CompletionTime *time.Time `json:"completionTime,omitempty"`
+	// DriverVersion is the version of the NVIDIA kernel module loaded on the node
+	DriverVersion string     `json:"driverVersion,omitempty"`
+	ErrorClass    ErrorClass `json:"errorClass,omitempty"`
+	Error         string     `json:"error,omitempty"`
+	Checks        []Check    `json:"checks,omitempty"`
+}
+
+// Check is a check run by a validation
+type Check struct {
+	Name           string    `json:"name"`
+	StartTime      time.Time `json:"startTime"`
+	CompletionTime time.Time `json:"completionTime"`
+	// Attempts is the number of times the check ran, when retried until it passes
+	Attempts int32 `json:"attempts"`
+	Passed   bool  `json:"passed"`
+	// Output is the end of the output of the last attempt of the check
+	Output string `json:"output,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// NewReport returns the report of the validation of component on node started at now
+func NewReport(component, node string, now time.Time) *Report {
+	return &Report{
+		Component: component,
+		Node:      node,
+		Result:    ResultRunning,
+		StartTime: now.UTC(),
+	}
+}
+
+// AddCheck records in the report an attempt of the check name run from start to end,
+// which failed with err if not nil. Retries of a check replace its previous attempt.
+func (r *Report) AddCheck(name string, start, end time.Time, output string, err error) {
+	check := Check{
+		Name:           name,
+		StartTime:      start.UTC(),
+		CompletionTime: end.UTC(),
+		Attempts:       1,
+		Passed:         err == nil,
+		Output:         output,
+	}
+	if err != nil {
+		check.Error = err.Error()
+	}
+	if last := len(r.Checks) - 1; last >= 0 && r.Checks[last].Name == name {
+		check.StartTime = r.Checks[last].StartTime
+		check.Attempts += r.Checks[last].Attempts
+		r.Checks[last] = check
+		return
+	}
+	r.Checks = append(r.Checks, check)
+}
+
+// Complete completes the report at now with the result of the validation, which failed
+// with err if not nil
+func (r *Report) Complete(now time.Time, err error) {
+	completionTime := now.UTC()
+	r.CompletionTime = &completionTime
+	if err == nil {
+		r.Result = ResultPassed
+		r.ErrorClass = ""
+		r.Error = ""
+		return
+	}
+	r.Result = ResultFailed
+	r.ErrorClass = r.classify(err)
+	r.Error = err.Error()
+}
+
+// classify returns the class of err, the error the validation failed with
+func (r *Report) classify(err error) ErrorClass {
+	var exitErr *exec.ExitError
+	switch {
+	case errors.Is(err, context.DeadlineExceeded) || wait.Interrupted(err):
+		return ErrorClassTimeout
+	case errors.As(err, &exitErr):
+		return ErrorClassCommandFailed
+	case errors.Is(err, fs.ErrNotExist):
+		return ErrorClassNotFound
+	}
+	// Errors of failed commands are not always wrapped
+	if last := len(r.Checks) - 1; last >= 0 && !r.Checks[last].Passed {
+		return ErrorClassCommandFailed
+	}
+	return ErrorClassValidationFailed
+}
+
+// Summary returns the summary of the report
+func (r *Report) Summary() ComponentSummary {
+	summary := ComponentSummary{
+		Result:         r.Result,
+		CompletionTime: r.CompletionTime,
+		DriverVersion:  r.DriverVersion,
+		ErrorClass:     r.ErrorClass,
+		Error:          r.Error,
+	}
+	if len(summary.Error) > maxSummaryErrorBytes {
+		summary.Error = summary.Error[:maxSummaryErrorBytes]
+	}
+	return summary
+}
+
+// ReportFileName returns the name of the report file of component
+func ReportFileName(component string) string {
+	return component + ReportFileSuffix
+}
+
+// ReadReports returns the validation reports found in dir, sorted by component
+func ReadReports(dir string) ([]*Report, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+ReportFileSuffix))
+	if err != nil {
+		return nil, err
+	}
+	var reports []*Report
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		report := &Report{}
+		if err := json.Unmarshal(data, report); err != nil {
+			return nil, err
+		}
+		if report.Component == "" {
+			report.Component = strings.TrimSuffix(filepath.Base(path), ReportFileSuffix)
+		}
+		reports = append(reports, report)
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].Component < reports[j].Component
+	})
+	return reports, nil
+}
+
+// ComponentSummary is the summary of the validation report of a component
+type ComponentSummary struct {
+	Result         Result     `json:"result"`
+	CompletionTime *time.Time `json:"completionTime,omitempty"`
+	DriverVersion  string     `json:"driverVersion,omitempty"`
+	ErrorClass     ErrorClass `json:"errorClass,omitempty"`
+	// Error is the beginning of the error the validation failed with
+	Error string `json:"error,omitempty"`
+}
+
+// NodeSummary is the summary of the validation reports of a node, by component
+type NodeSummary map[string]ComponentSummary
+
+// Summarize returns the summary of reports
+func Summarize(reports []*Report) NodeSummary {
+	summary := make(NodeSummary, len(reports))
+	for _, report := range reports {
+		summary[report.Component] = report.Summary()
+	}
+	return summary
+}
+
+// ParseNodeSummary returns the summary of the validation reports of a node, from the
+// value of its SummaryAnnotation
+func ParseNodeSummary(value string) (NodeSummary, error) {
+	summary := NodeSummary{}
+	if err := json.Unmarshal([]byte(value), &summary); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
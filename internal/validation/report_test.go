@@ -0,0 +1,161 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package validation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddCheck(t *testing.T) {
+	start := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	report := NewReport("toolkit", "node-a", start)
+	report.AddCheck("nvidia-smi", start, start.Add(time.Second), "no devices", errors.New("exit status 9"))
+	report.AddCheck("nvidia-smi", start.Add(5*time.Second), start.Add(6*time.Second), "GPU 0", nil)
+	report.AddCheck("stat /run/nvidia/validations", start.Add(7*time.Second), start.Add(8*time.Second), "", nil)
+
+	require.Equal(t, []Check{
+		{
+			Name:           "nvidia-smi",
+			StartTime:      start,
+			CompletionTime: start.Add(6 * time.Second),
+			Attempts:       2,
+			Passed:         true,
+			Output:         "GPU 0",
+		},
+		{
+			Name:           "stat /run/nvidia/validations",
+			StartTime:      start.Add(7 * time.Second),
+			CompletionTime: start.Add(8 * time.Second),
+			Attempts:       1,
+			Passed:         true,
+		},
+	}, report.Checks)
+}
+
+func TestComplete(t *testing.T) {
+	exitErr := exec.Command("sh", "-c", "exit 3").Run()
+	require.Error(t, exitErr)
+
+	testCases := []struct {
+		description        string
+		err                error
+		failedCheck        bool
+		expectedResult     Result
+		expectedErrorClass ErrorClass
+	}{
+		{
+			description:    "passed",
+			expectedResult: ResultPassed,
+		},
+		{
+			description:        "command failed",
+			err:                fmt.Errorf("error checking driver container status: %w", exitErr),
+			expectedResult:     ResultFailed,
+			expectedErrorClass: ErrorClassCommandFailed,
+		},
+		{
+			description:        "unwrapped command error",
+			err:                fmt.Errorf("%s", exitErr),
+			failedCheck:        true,
+			expectedResult:     ResultFailed,
+			expectedErrorClass: ErrorClassCommandFailed,
+		},
+		{
+			description:        "timeout",
+			err:                fmt.Errorf("vGPU Manager parent devices not ready: %w", context.DeadlineExceeded),
+			expectedResult:     ResultFailed,
+			expectedErrorClass: ErrorClassTimeout,
+		},
+		{
+			description:        "missing file",
+			err:                fmt.Errorf("reading status file: %w", os.ErrNotExist),
+			expectedResult:     ResultFailed,
+			expectedErrorClass: ErrorClassNotFound,
+		},
+		{
+			description:        "other error",
+			err:                errors.New("no vGPU devices found"),
+			expectedResult:     ResultFailed,
+			expectedErrorClass: ErrorClassValidationFailed,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			now := time.Now()
+			report := NewReport("driver", "node-a", now)
+			if tc.failedCheck {
+				report.AddCheck("nvidia-smi", now, now, "", exitErr)
+			}
+			report.Complete(now, tc.err)
+
+			require.Equal(t, tc.expectedResult, report.Result)
+			require.Equal(t, tc.expectedErrorClass, report.ErrorClass)
+			require.NotNil(t, report.CompletionTime)
+			if tc.err != nil {
+				require.Equal(t, tc.err.Error(), report.Error)
+			}
+		})
+	}
+}
+
+func TestReadReports(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	for _, component := range []string{"toolkit", "driver"} {
+		report := NewReport(component, "node-a", now)
+		report.DriverVersion = "570.124.06"
+		report.Complete(now, nil)
+		data, err := json.Marshal(report)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, ReportFileName(component)), data, 0600))
+	}
+	failed := NewReport("cuda", "node-a", now)
+	failed.Complete(now, errors.New(strings.Repeat("x", 2*maxSummaryErrorBytes)))
+	data, err := json.Marshal(failed)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ReportFileName("cuda")), data, 0600))
+	// status files are not reports
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "driver-ready"), nil, 0600))
+
+	reports, err := ReadReports(dir)
+	require.NoError(t, err)
+	require.Len(t, reports, 3)
+	require.Equal(t, "cuda", reports[0].Component)
+	require.Equal(t, "driver", reports[1].Component)
+	require.Equal(t, "toolkit", reports[2].Component)
+
+	summary, err := json.Marshal(Summarize(reports))
+	require.NoError(t, err)
+	parsed, err := ParseNodeSummary(string(summary))
+	require.NoError(t, err)
+	require.Equal(t, ResultPassed, parsed["driver"].Result)
+	require.Equal(t, "570.124.06", parsed["driver"].DriverVersion)
+	require.Equal(t, ResultFailed, parsed["cuda"].Result)
+	require.Equal(t, ErrorClassValidationFailed, parsed["cuda"].ErrorClass)
+	require.Len(t, parsed["cuda"].Error, maxSummaryErrorBytes)
+}
@@ -59,6 +59,7 @@ func (nsv *nodeSelectorValidator) Validate(ctx context.Context, cr *nvidiav1alph
 	if err != nil {
 		return err
 	}
+	drivers.Items = withCandidate(drivers.Items, cr)
 
 	defaultDriverNames := []string{}
 	for _, driver := range drivers.Items {
@@ -102,6 +103,19 @@ func (nsv *nodeSelectorValidator) Validate(ctx context.Context, cr *nvidiav1alph
 	return nil
 }
 
+// withCandidate returns the listed drivers with cr taking the place of its stored copy.
+// An admission request carries an object that is either not persisted yet (create) or
+// differs from the persisted one (update), so the listed items alone are not enough.
+func withCandidate(items []nvidiav1alpha1.NVIDIADriver, cr *nvidiav1alpha1.NVIDIADriver) []nvidiav1alpha1.NVIDIADriver {
+	for i := range items {
+		if items[i].Name == cr.Name {
+			items[i] = *cr
+			return items
+		}
+	}
+	return append(items, *cr)
+}
+
 // getNVIDIADriverSelectedNodes returns selected nodes based on the nodeselector labels set for a given NVIDIADriver instance
 func (nsv *nodeSelectorValidator) getNVIDIADriverSelectedNodes(ctx context.Context, cr nvidiav1alpha1.NVIDIADriver) (*corev1.NodeList, error) {
 	nodeList := &corev1.NodeList{}
@@ -224,3 +224,35 @@ func TestCheckNodeSelectorDoesNotIgnoreDefaultNameWithoutDefaultField(t *testing
 	assert.Contains(t, err.Error(), consts.DefaultNVIDIADriverName)
 	assert.Contains(t, err.Error(), "specificDriver")
 }
+
+func TestCheckNodeSelectorIncludesUnpersistedDriver(t *testing.T) {
+	node := makeTestNode(map[string]string{"os-version": "ubuntu20.04"})
+	existingDriver := makeTestDriver("", node.Labels, false)
+	// Not seeded into the client, as for an admission request on create.
+	requestedDriver := makeTestDriver("conflictingDriver", node.Labels, false)
+
+	s := scheme.Scheme
+	err := nvidiav1alpha1.AddToScheme(s)
+	require.NoError(t, err)
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(node, existingDriver).Build()
+	nsv := NewNodeSelectorValidator(c)
+
+	err = nsv.Validate(context.Background(), requestedDriver)
+	require.EqualError(t, err, "multiple NVIDIADrivers match the same node my-test-node: [conflictingDriver my-nvidia-driver]")
+}
+
+func TestCheckNodeSelectorUsesUpdatedDriver(t *testing.T) {
+	defaultDriver := makeTestDriver("default-a", nil, true)
+	storedDriver := makeTestDriver("default-b", map[string]string{"nodepool": "b"}, false)
+	// The update turns the stored driver into a second default driver.
+	updatedDriver := makeTestDriver("default-b", nil, true)
+
+	s := scheme.Scheme
+	err := nvidiav1alpha1.AddToScheme(s)
+	require.NoError(t, err)
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(defaultDriver, storedDriver).Build()
+	nsv := NewNodeSelectorValidator(c)
+
+	err = nsv.Validate(context.Background(), updatedDriver)
+	require.ErrorIs(t, err, ErrMultipleDefaultNVIDIADrivers)
+}